@@ -0,0 +1,438 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// AmendQueue defaults.
+const (
+	// DefaultAmendInterval is the minimum delay between two amendments
+	// submitted by an AmendQueue while the API has not reported a rate-limit
+	// budget.
+	DefaultAmendInterval = time.Second
+
+	// DefaultAmendMinRemaining is the rate-limit budget below which an
+	// AmendQueue waits for the window to reset. An amendment costs at least
+	// four requests: the status, the cancel, its confirmation and the create.
+	DefaultAmendMinRemaining = 4
+)
+
+// AmendQueueOptions configures an AmendQueue.
+type AmendQueueOptions struct {
+	// Interval is an optional minimum delay between two submitted amendments.
+	// Amendments are otherwise paced by the rate-limit budget reported by
+	// RateLimitStatus; while the API has not reported one,
+	// DefaultAmendInterval applies.
+	Interval time.Duration
+
+	// MinRemaining is the rate-limit budget below which the queue waits for
+	// the rate-limit window to reset before the next amendment. Defaults to
+	// DefaultAmendMinRemaining.
+	MinRemaining int
+
+	// Identifiers hands out the identifiers of replacement orders. If nil,
+	// the replacements of an order with identifier "x" are identified as
+	// "x-r1", "x-r2" and so on, and replacements of orders without an
+	// identifier get none.
+	Identifiers *NonceGenerator
+
+	// OnResult is invoked from the queue goroutine after every submitted or
+	// dropped amendment. It must not block for long.
+	OnResult func(result AmendResult)
+}
+
+// AmendResult reports the outcome of a single amendment.
+type AmendResult struct {
+	// OrderId is the id the amendment was requested for.
+	OrderId int
+
+	// ReplacedOrderId is the id of the order that was cancelled. It differs
+	// from OrderId when the order was already replaced by earlier amendments.
+	ReplacedOrderId int
+
+	// Order is the replacement order, the unchanged order if Skipped is true,
+	// or nil if the amendment failed.
+	Order *t.OrderStatus
+
+	// FilledBaseAmount is the base amount filled by the original order and
+	// all of its replacements that were cancelled so far.
+	FilledBaseAmount string
+
+	// Coalesced is the number of amendments for the order that were merged
+	// into this one, including the submitted one.
+	Coalesced int
+
+	// Skipped is true if the order already had the desired price and amount,
+	// so nothing was submitted.
+	Skipped bool
+
+	// Err is the error of the amendment, if any. An amendment fails when the
+	// order is no longer active, e.g. because it was filled. It wraps
+	// ErrReplaceAborted if the order was filled while it was cancelled, or
+	// if nothing remains to replace after subtracting the fills.
+	Err error
+}
+
+// amendChain tracks the replacements of an order.
+type amendChain struct {
+	current    int    // id of the latest replacement
+	filled     string // base amount filled by the cancelled orders
+	identifier string // identifier of the original order, once known
+	identified bool   // whether identifier is known
+	replaced   int    // number of replacements so far
+}
+
+// pendingAmendment is the latest desired state of an order.
+type pendingAmendment struct {
+	price      string
+	baseAmount string
+	coalesced  int
+}
+
+// AmendQueue re-prices orders in the background. Rapid successive amendments
+// of the same order are coalesced into the latest desired price and amount,
+// and amendments are submitted no faster than the configured interval, so
+// quoting strategies do not waste requests on prices that are already stale.
+//
+// An amendment replaces the order: the order is cancelled, the cancel is
+// confirmed, and a new order with the same symbol, side and type and a new
+// identifier is created. The base amount is the total the original order
+// should trade, so the replacement is reduced by everything the original order
+// and its earlier replacements filled. Later amendments of the original id are
+// applied to the latest replacement.
+type AmendQueue struct {
+	client *Client
+	opts   AmendQueueOptions
+
+	mu      sync.Mutex
+	pending map[int]*pendingAmendment
+	order   []int               // order ids with a pending amendment, oldest first
+	chains  map[int]*amendChain // original order id -> its replacements
+	wake    chan struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAmendQueue starts an AmendQueue that submits amendments through the
+// client until the context is done or Close is called.
+//
+// Parameters:
+//   - ctx: Controls the lifetime of the queue.
+//   - opts: The pacing, the identifiers of replacements and an optional
+//     result callback.
+//
+// Returns:
+//   - A pointer to the running `AmendQueue`.
+//
+// Example:
+//
+//	queue := client.NewAmendQueue(ctx, bitpin.AmendQueueOptions{
+//	    Identifiers: bitpin.NewNonceGenerator(st, "amend", bitpin.NonceOptions{Prefix: "q-"}),
+//	    OnResult: func(r bitpin.AmendResult) {
+//	        if r.Err != nil {
+//	            log.Printf("amend %d: %v", r.OrderId, r.Err)
+//	        }
+//	    },
+//	})
+//	defer queue.Close()
+//
+//	for price := range quotes {
+//	    queue.Amend(orderId, price, "0.01")
+//	}
+func (c *Client) NewAmendQueue(ctx context.Context, opts AmendQueueOptions) *AmendQueue {
+	if opts.MinRemaining <= 0 {
+		opts.MinRemaining = DefaultAmendMinRemaining
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	q := &AmendQueue{
+		client:  c,
+		opts:    opts,
+		pending: make(map[int]*pendingAmendment),
+		chains:  make(map[int]*amendChain),
+		wake:    make(chan struct{}, 1),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go q.run(ctx)
+	return q
+}
+
+// Amend requests that an order be re-priced to the given price and base
+// amount. It returns immediately. If an amendment of the same order is still
+// waiting, it is replaced by this one.
+func (q *AmendQueue) Amend(orderId int, price, baseAmount string) {
+	q.mu.Lock()
+	if p, ok := q.pending[orderId]; ok {
+		p.price, p.baseAmount = price, baseAmount
+		p.coalesced++
+	} else {
+		q.pending[orderId] = &pendingAmendment{price: price, baseAmount: baseAmount, coalesced: 1}
+		q.order = append(q.order, orderId)
+	}
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Current returns the id of the latest replacement of an order, or the id
+// itself if it has not been replaced by the queue.
+func (q *AmendQueue) Current(orderId int) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if chain, ok := q.chains[orderId]; ok {
+		return chain.current
+	}
+	return orderId
+}
+
+// Pending returns the number of orders with an amendment waiting to be
+// submitted.
+func (q *AmendQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.order)
+}
+
+// Close stops the queue and waits for an amendment in flight to finish.
+// Amendments that were not submitted yet are dropped.
+func (q *AmendQueue) Close() {
+	q.cancel()
+	<-q.done
+}
+
+// run submits pending amendments, paced by the rate-limit budget.
+func (q *AmendQueue) run(ctx context.Context) {
+	defer close(q.done)
+
+	var last time.Time
+	for {
+		orderId, amendment, ok := q.next()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.wake:
+				continue
+			}
+		}
+
+		if wait := q.delay(last); wait > 0 {
+			// Put the amendment back so newer requests coalesce into it
+			// while waiting for the budget.
+			q.requeue(orderId, amendment)
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.client.after(wait):
+				continue
+			}
+		}
+
+		last = q.client.now()
+		result := q.submit(ctx, orderId, amendment)
+		if ctx.Err() != nil {
+			return
+		}
+		if q.opts.OnResult != nil {
+			q.opts.OnResult(result)
+		}
+	}
+}
+
+// delay returns how long to wait before the next amendment, given when the
+// last one was submitted.
+func (q *AmendQueue) delay(last time.Time) time.Duration {
+	now := q.client.now()
+	wait := q.opts.Interval - now.Sub(last)
+
+	status := q.client.RateLimitStatus()
+	switch {
+	case !status.Known:
+		wait = max(wait, DefaultAmendInterval-now.Sub(last))
+	case status.Remaining >= 0 && status.Remaining < q.opts.MinRemaining:
+		if status.Reset.IsZero() {
+			wait = max(wait, DefaultAmendInterval-now.Sub(last))
+		} else {
+			wait = max(wait, status.Reset.Sub(now))
+		}
+	}
+	return wait
+}
+
+// next removes and returns the oldest pending amendment.
+func (q *AmendQueue) next() (int, *pendingAmendment, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.order) == 0 {
+		return 0, nil, false
+	}
+	orderId := q.order[0]
+	q.order = q.order[1:]
+	amendment := q.pending[orderId]
+	delete(q.pending, orderId)
+	return orderId, amendment, true
+}
+
+// requeue puts an amendment back at the front of the queue, merging it with
+// any amendment of the same order that arrived in the meantime.
+func (q *AmendQueue) requeue(orderId int, amendment *pendingAmendment) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if newer, ok := q.pending[orderId]; ok {
+		newer.coalesced += amendment.coalesced
+		for i, id := range q.order {
+			if id == orderId {
+				q.order = append(q.order[:i], q.order[i+1:]...)
+				break
+			}
+		}
+	} else {
+		q.pending[orderId] = amendment
+	}
+	q.order = append([]int{orderId}, q.order...)
+}
+
+// submit replaces the latest order for orderId with the desired price and amount.
+func (q *AmendQueue) submit(ctx context.Context, orderId int, amendment *pendingAmendment) AmendResult {
+	q.mu.Lock()
+	chain, ok := q.chains[orderId]
+	if !ok {
+		chain = &amendChain{current: orderId, filled: "0"}
+	}
+	target, filled := chain.current, chain.filled
+	q.mu.Unlock()
+
+	result := AmendResult{OrderId: orderId, ReplacedOrderId: target, FilledBaseAmount: filled, Coalesced: amendment.coalesced}
+	amended, err := q.client.amendOrder(ctx, target, amendment.price, amendment.baseAmount, filled, func(order *t.OrderStatus) (string, error) {
+		return q.identifier(ctx, chain, order)
+	})
+	result.Order, result.Skipped, result.Err = amended.order, amended.skipped, err
+	if amended.cancel == nil {
+		return result
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	chain.filled = amountSum(filled, amended.cancel.FilledBaseAmount)
+	if amended.order != nil {
+		chain.current = amended.order.Id
+	}
+	q.chains[orderId] = chain
+	result.FilledBaseAmount = chain.filled
+	return result
+}
+
+// identifier returns the identifier of the next replacement in a chain.
+func (q *AmendQueue) identifier(ctx context.Context, chain *amendChain, order *t.OrderStatus) (string, error) {
+	if q.opts.Identifiers != nil {
+		return q.opts.Identifiers.NextIdentifier(ctx)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !chain.identified {
+		chain.identifier, chain.identified = order.Identifier, true
+	}
+	if chain.identifier == "" {
+		return "", nil
+	}
+	chain.replaced++
+	return fmt.Sprintf("%s-r%d", chain.identifier, chain.replaced), nil
+}
+
+// amendment is the outcome of amendOrder.
+type amendment struct {
+	// order is the replacement, or the unchanged order if skipped is true.
+	order   *t.OrderStatus
+	skipped bool
+
+	// cancel is the confirmed cancel of the amended order, or nil if it was
+	// not cancelled.
+	cancel *CancelResult
+}
+
+// amendOrder replaces an active order with one at the given price and base
+// amount minus the fills, holding the symbol lock for the whole replacement.
+// filled is the base amount the orders replaced before this one filled. The
+// amendment is skipped if the order already has the desired price and amount.
+// identifier is called for the identifier of the replacement before the order
+// is cancelled.
+func (c *Client) amendOrder(ctx context.Context, orderId int, price, baseAmount, filled string, identifier func(order *t.OrderStatus) (string, error)) (amendment, error) {
+	unlock, err := c.orderLocks.lock(ctx, c.orderSymbol(ctx, orderId))
+	if err != nil {
+		return amendment{}, err
+	}
+	defer unlock()
+
+	order, err := c.GetOrderWithContext(ctx, orderId)
+	if err != nil {
+		return amendment{}, err
+	}
+	if IsTerminalOrderState(order.State) {
+		return amendment{}, &GoBitpinError{
+			Message: fmt.Sprintf("cannot amend order %d in state %q", orderId, order.State),
+		}
+	}
+	remaining := amountDiff(baseAmount, filled)
+	if u.AmountsEqual(order.Price, price, -1) && u.AmountsEqual(order.BaseAmount, remaining, -1) {
+		return amendment{order: order, skipped: true}, nil
+	}
+	id, err := identifier(order)
+	if err != nil {
+		return amendment{}, err
+	}
+
+	final, err := c.cancelActiveLocked(ctx, orderId)
+	if err != nil {
+		return amendment{}, err
+	}
+	cancel, err := c.confirmCancel(ctx, orderId, order, final)
+	if err != nil {
+		return amendment{}, err
+	}
+	result := amendment{cancel: cancel}
+	if cancel.Outcome == CancelOutcomeFilled {
+		return result, &GoBitpinError{
+			Message: fmt.Sprintf("order %d was filled before it could be amended", orderId),
+			Err:     ErrReplaceAborted,
+		}
+	}
+
+	remaining = amountDiff(remaining, cancel.FilledBaseAmount)
+	if isZeroAmount(remaining) {
+		return result, &GoBitpinError{
+			Message: fmt.Sprintf("order %d filled %s, nothing remains to amend", orderId, cancel.FilledBaseAmount),
+			Err:     ErrReplaceAborted,
+		}
+	}
+	if strings.Contains(remaining, ".") {
+		remaining = strings.TrimSuffix(strings.TrimRight(remaining, "0"), ".")
+	}
+
+	result.order, err = c.createOrderLocked(ctx, t.CreateOrderParams{
+		Symbol:     order.Symbol,
+		Type:       order.Type,
+		Side:       order.Side,
+		BaseAmount: remaining,
+		Price:      price,
+		Identifier: id,
+	})
+	if err != nil {
+		return result, &GoBitpinError{
+			Message: fmt.Sprintf("order %d was cancelled but its replacement failed", orderId),
+			Err:     err,
+		}
+	}
+	return result, nil
+}
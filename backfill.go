@@ -0,0 +1,326 @@
+package bitpin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/rzabhd80/go-sdk-bitpin/store"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// Backfill defaults.
+const (
+	// DefaultBackfillPageSize is the number of trades requested per page.
+	DefaultBackfillPageSize = 100
+
+	// DefaultBackfillMinRemaining is the rate-limit budget below which the
+	// backfiller waits for the window to reset.
+	DefaultBackfillMinRemaining = 2
+
+	// DefaultBackfillRetryWait is how long the backfiller waits after a 429
+	// Too Many Requests response without a Retry-After header.
+	DefaultBackfillRetryWait = 5 * time.Second
+
+	// DefaultBackfillInterval is the pause between passes of Follow.
+	DefaultBackfillInterval = 10 * time.Second
+)
+
+// TradeBatch is a page of trades delivered to a TradeSink, oldest first.
+type TradeBatch struct {
+	// Symbol is the market the trades belong to.
+	Symbol string
+
+	// Trades are the new trades, sorted by ascending trade ID.
+	Trades []*t.Trade
+
+	// FetchedAt is when the page was received. Public trades carry no
+	// timestamp, so it is the best available approximation of their time.
+	FetchedAt time.Time
+}
+
+// TradeSink receives the trades collected by a Backfiller.
+type TradeSink interface {
+	// WriteTrades stores a batch of trades. The checkpoint only advances after
+	// it returns nil, so a batch is delivered again after a failure or crash.
+	// Sinks should therefore ignore trades they already hold.
+	WriteTrades(ctx context.Context, batch TradeBatch) error
+}
+
+// TradeSinkFunc adapts a function to the TradeSink interface.
+type TradeSinkFunc func(ctx context.Context, batch TradeBatch) error
+
+// WriteTrades implements TradeSink.
+func (f TradeSinkFunc) WriteTrades(ctx context.Context, batch TradeBatch) error {
+	return f(ctx, batch)
+}
+
+// StoreTradeSink appends every trade as a JSON record to a log of a
+// store.Store, named "trades/<symbol>" by default.
+type StoreTradeSink struct {
+	store  store.Store
+	prefix string
+}
+
+// NewStoreTradeSink returns a sink writing to logs named prefix + symbol. An
+// empty prefix defaults to "trades/".
+func NewStoreTradeSink(st store.Store, prefix string) *StoreTradeSink {
+	if prefix == "" {
+		prefix = "trades/"
+	}
+	return &StoreTradeSink{store: st, prefix: prefix}
+}
+
+// WriteTrades implements TradeSink.
+func (s *StoreTradeSink) WriteTrades(ctx context.Context, batch TradeBatch) error {
+	for _, trade := range batch.Trades {
+		record, err := json.Marshal(trade)
+		if err != nil {
+			return err
+		}
+		if _, err := s.store.Append(ctx, s.prefix+batch.Symbol, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackfillOptions configures a Backfiller.
+type BackfillOptions struct {
+	// Symbol is the market to collect trades for.
+	Symbol string
+
+	// PageSize is the number of trades requested per page. Defaults to
+	// DefaultBackfillPageSize.
+	PageSize int
+
+	// Checkpoints persists the ID of the last delivered trade, so a restarted
+	// backfiller resumes where it stopped. When nil, the checkpoint is only
+	// kept in memory.
+	Checkpoints store.Store
+
+	// CheckpointKey is the key of the checkpoint in Checkpoints. Defaults to
+	// "backfill/trades/<symbol>".
+	CheckpointKey string
+
+	// MinRemaining is the rate-limit budget below which the backfiller waits
+	// for the rate-limit window to reset before the next request. Defaults to
+	// DefaultBackfillMinRemaining.
+	MinRemaining int
+
+	// RetryWait is how long to wait after a 429 response that does not say
+	// when to retry. Defaults to DefaultBackfillRetryWait.
+	RetryWait time.Duration
+
+	// Backoff replaces RetryWait, for example to back off exponentially on
+	// consecutive 429 responses. A Retry-After sent by the API still takes
+	// precedence.
+	Backoff Backoff
+}
+
+// BackfillStats summarizes a backfill pass.
+type BackfillStats struct {
+	// Pages is the number of pages requested.
+	Pages int
+
+	// Trades is the number of trades delivered to the sink.
+	Trades int
+
+	// Checkpoint is the ID of the last delivered trade.
+	Checkpoint string
+
+	// Waited is the time spent waiting for the rate limit.
+	Waited time.Duration
+}
+
+// Backfiller collects the trades of a market page by page and delivers them to
+// a sink, checkpointing its progress after every page.
+//
+// The trades endpoint only serves recent trades. Pages are requested with
+// SinceId set to the checkpoint; if the API returns the newest trades first and
+// more than PageSize trades happened since the checkpoint, the older ones are
+// no longer available. Run the backfiller often enough, or use Follow, to keep
+// the dataset complete.
+type Backfiller struct {
+	client *Client
+	sink   TradeSink
+	opts   BackfillOptions
+
+	checkpoint string
+	loaded     bool
+}
+
+// NewBackfiller creates a backfiller that delivers the trades of
+// opts.Symbol to sink.
+//
+// Example:
+//
+//	st, _ := store.NewFile("/var/lib/research")
+//	bf := client.NewBackfiller(bitpin.NewStoreTradeSink(st, ""), bitpin.BackfillOptions{
+//	    Symbol:      "BTC_USDT",
+//	    Checkpoints: st,
+//	})
+//	stats, err := bf.Run(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%d new trades, now at %s\n", stats.Trades, stats.Checkpoint)
+func (c *Client) NewBackfiller(sink TradeSink, opts BackfillOptions) *Backfiller {
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultBackfillPageSize
+	}
+	if opts.CheckpointKey == "" {
+		opts.CheckpointKey = "backfill/trades/" + opts.Symbol
+	}
+	if opts.MinRemaining <= 0 {
+		opts.MinRemaining = DefaultBackfillMinRemaining
+	}
+	if opts.RetryWait <= 0 {
+		opts.RetryWait = DefaultBackfillRetryWait
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = ConstantBackoff{Delay: opts.RetryWait}
+	}
+	return &Backfiller{client: c, sink: sink, opts: opts}
+}
+
+// Checkpoint returns the ID of the last delivered trade, loading it from the
+// checkpoint store on first use. It is empty before the first delivery.
+func (b *Backfiller) Checkpoint(ctx context.Context) (string, error) {
+	if b.loaded || b.opts.Checkpoints == nil {
+		return b.checkpoint, nil
+	}
+	value, err := b.opts.Checkpoints.Get(ctx, b.opts.CheckpointKey)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return "", &GoBitpinError{Message: "failed to load backfill checkpoint", Err: err}
+	}
+	b.checkpoint, b.loaded = string(value), true
+	return b.checkpoint, nil
+}
+
+// Run requests pages of trades newer than the checkpoint until the market is
+// caught up, delivering each page to the sink and advancing the checkpoint.
+// Before each request the rate-limit budget is checked, and 429 responses are
+// retried after the requested wait.
+func (b *Backfiller) Run(ctx context.Context) (BackfillStats, error) {
+	var stats BackfillStats
+	checkpoint, err := b.Checkpoint(ctx)
+	if err != nil {
+		return stats, err
+	}
+	stats.Checkpoint = checkpoint
+
+	for {
+		trades, err := b.fetch(ctx, &stats)
+		if err != nil {
+			return stats, err
+		}
+		stats.Pages++
+		if len(trades) == 0 {
+			return stats, nil
+		}
+
+		batch := TradeBatch{Symbol: b.opts.Symbol, Trades: trades, FetchedAt: b.client.now()}
+		if err := b.sink.WriteTrades(ctx, batch); err != nil {
+			return stats, &GoBitpinError{Message: fmt.Sprintf("trade sink failed for %s", b.opts.Symbol), Err: err}
+		}
+		if err := b.saveCheckpoint(ctx, trades[len(trades)-1].Id); err != nil {
+			return stats, err
+		}
+		stats.Trades += len(trades)
+		stats.Checkpoint = b.checkpoint
+
+		if len(trades) < b.opts.PageSize {
+			return stats, nil
+		}
+	}
+}
+
+// Follow runs a pass every interval until ctx is done, keeping the dataset up
+// to date. A non-positive interval defaults to DefaultBackfillInterval. It
+// returns nil when ctx is done, or the first error of a pass.
+func (b *Backfiller) Follow(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultBackfillInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := b.Run(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetch requests the next page, waiting for the rate limit as needed. The
+// trades are returned sorted by ascending ID.
+func (b *Backfiller) fetch(ctx context.Context, stats *BackfillStats) ([]*t.Trade, error) {
+	for attempt := 1; ; attempt++ {
+		status := b.client.RateLimitStatus()
+		if status.Known && status.Remaining >= 0 && status.Remaining < b.opts.MinRemaining {
+			if err := b.wait(ctx, status.Reset.Sub(b.client.now()), stats); err != nil {
+				return nil, err
+			}
+		}
+
+		trades, err := b.client.getRecentTradesWithParams(ctx, b.opts.Symbol, t.GetRecentTradesParams{
+			SinceId: b.checkpoint,
+			Limit:   b.opts.PageSize,
+		})
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+			wait := b.opts.Backoff.NextDelay(attempt, err)
+			if status := b.client.RateLimitStatus(); status.RetryAfter > 0 {
+				wait = status.RetryAfter
+			}
+			if err := b.wait(ctx, wait, stats); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Slice(trades, func(i, j int) bool { return tradeIdAfter(trades[j].Id, trades[i].Id) })
+		return trades, nil
+	}
+}
+
+// wait sleeps for d or until ctx is done.
+func (b *Backfiller) wait(ctx context.Context, d time.Duration, stats *BackfillStats) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-b.client.after(d):
+		stats.Waited += d
+		return nil
+	}
+}
+
+// saveCheckpoint records the ID of the last delivered trade.
+func (b *Backfiller) saveCheckpoint(ctx context.Context, id string) error {
+	b.checkpoint, b.loaded = id, true
+	if b.opts.Checkpoints == nil {
+		return nil
+	}
+	if err := b.opts.Checkpoints.Set(ctx, b.opts.CheckpointKey, []byte(id)); err != nil {
+		return &GoBitpinError{Message: "failed to save backfill checkpoint", Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package bitpin
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff decides how long to wait before retrying a failed operation. It is
+// used to reconnect streams and to retry rate-limited backfill requests, and
+// can be implemented to align the SDK with the retry policies of an
+// infrastructure.
+type Backoff interface {
+	// NextDelay returns the delay before the given attempt, counted from 1 for
+	// the first retry. err is the error that caused the retry, or nil if it is
+	// unknown.
+	NextDelay(attempt int, err error) time.Duration
+}
+
+// ExponentialBackoff multiplies the delay by Multiplier after every attempt,
+// from Initial up to Max, with optional random jitter.
+type ExponentialBackoff struct {
+	// Initial is the delay before the first retry.
+	Initial time.Duration
+
+	// Max caps the delay. Zero means no cap.
+	Max time.Duration
+
+	// Multiplier grows the delay after every attempt. Values less than or
+	// equal to 1 default to 2.
+	Multiplier float64
+
+	// Jitter randomizes every delay by up to this fraction in either
+	// direction; 0.2 gives delays between 80% and 120% of the computed one.
+	// Jitter keeps many clients from retrying in lockstep.
+	Jitter float64
+}
+
+// NextDelay implements Backoff.
+func (b ExponentialBackoff) NextDelay(attempt int, _ error) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier <= 1 {
+		multiplier = 2
+	}
+	// Without a cap, the delay is kept well within the range of a Duration.
+	limit := float64(math.MaxInt64 / 4)
+	if b.Max > 0 {
+		limit = float64(b.Max)
+	}
+	delay := float64(b.Initial)
+	for i := 1; i < attempt && delay < limit; i++ {
+		delay *= multiplier
+	}
+	delay = min(delay, limit)
+	if b.Jitter > 0 {
+		delay *= 1 - b.Jitter + 2*b.Jitter*rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// ConstantBackoff waits the same delay before every attempt.
+type ConstantBackoff struct {
+	// Delay is the wait before every attempt.
+	Delay time.Duration
+}
+
+// NextDelay implements Backoff.
+func (b ConstantBackoff) NextDelay(int, error) time.Duration {
+	return b.Delay
+}
+
+// DecorrelatedJitterBackoff picks every delay at random between Base and three
+// times the previous delay, capped at Max. It spreads retries more evenly than
+// exponential backoff with jitter. The previous delay is reset when attempt 1
+// is requested, so a value must not be shared by concurrent retry loops.
+type DecorrelatedJitterBackoff struct {
+	// Base is the smallest delay.
+	Base time.Duration
+
+	// Max caps the delay.
+	Max time.Duration
+
+	mu   sync.Mutex
+	prev time.Duration
+}
+
+// NextDelay implements Backoff.
+func (b *DecorrelatedJitterBackoff) NextDelay(attempt int, _ error) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if attempt <= 1 || b.prev < b.Base {
+		b.prev = b.Base
+	}
+	delay := b.Base
+	if upper := 3 * b.prev; upper > b.Base {
+		delay += time.Duration(rand.Int63n(int64(upper - b.Base)))
+	}
+	if b.Max > 0 {
+		delay = min(delay, b.Max)
+	}
+	b.prev = delay
+	return delay
+}
@@ -0,0 +1,232 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// DefaultBalanceCheckTTL is how long wallets fetched for the balance check are
+// reused.
+const DefaultBalanceCheckTTL = 5 * time.Second
+
+// ErrInsufficientBalance is returned without sending the order when the balance
+// check finds that the free balance does not cover an order
+var ErrInsufficientBalance = &GoBitpinError{Message: "insufficient balance"}
+
+// InsufficientBalanceError describes the shortfall found by the balance check.
+// It wraps ErrInsufficientBalance.
+type InsufficientBalanceError struct {
+	GoBitpinError
+
+	// Asset is the asset the order spends.
+	Asset string
+
+	// Required is the amount the order spends, including the estimated fee.
+	Required string
+
+	// Available is the free balance, the wallet balance minus its frozen
+	// amount.
+	Available string
+
+	// Shortfall is the amount missing, Required minus Available.
+	Shortfall string
+}
+
+// BalanceCheckOptions configures the pre-order balance check of a client.
+//
+// The spent amount is the quote amount of buys and the base amount of sells.
+// Market orders given in the other unit have no price to convert with and are
+// not checked. The check is local and best effort: orders placed concurrently
+// on other markets may spend the same balance.
+type BalanceCheckOptions struct {
+	// FeeRate is the estimated fee as a fraction (0.002 means 0.2%). It is
+	// added to the spent amount.
+	FeeRate float64
+
+	// WalletTTL is how long fetched wallets are reused before they are fetched
+	// again. The cache is also dropped after every order the client places.
+	// Defaults to DefaultBalanceCheckTTL.
+	WalletTTL time.Duration
+
+	// Service is the wallet service orders spend from. Defaults to "spot".
+	Service string
+}
+
+// balanceChecker verifies orders against cached wallets. A nil checker
+// accepts every order.
+type balanceChecker struct {
+	opts  BalanceCheckOptions
+	clock Clock
+
+	mu      sync.Mutex
+	wallets t.Wallets
+	at      time.Time
+}
+
+// newBalanceChecker returns a checker with defaults applied that ages the
+// cached wallets on clock, or nil if opts is nil.
+func newBalanceChecker(opts *BalanceCheckOptions, clock Clock) *balanceChecker {
+	if opts == nil {
+		return nil
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+	b := &balanceChecker{opts: *opts, clock: clock}
+	if b.opts.WalletTTL <= 0 {
+		b.opts.WalletTTL = DefaultBalanceCheckTTL
+	}
+	if b.opts.Service == "" {
+		b.opts.Service = "spot"
+	}
+	return b
+}
+
+// invalidate drops the cached wallets.
+func (b *balanceChecker) invalidate() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.wallets = nil
+}
+
+// check returns an InsufficientBalanceError if the free balance does not cover
+// the order.
+func (b *balanceChecker) check(ctx context.Context, c *Client, params t.CreateOrderParams) error {
+	if b == nil {
+		return nil
+	}
+	asset, spent, ok := orderSpend(params)
+	if !ok {
+		return nil
+	}
+	// The fee rate is parsed from its shortest decimal form, so 0.002 is exact.
+	fee, ok := new(big.Rat).SetString(strconv.FormatFloat(b.opts.FeeRate, 'f', -1, 64))
+	if !ok || !validFeeRate(b.opts.FeeRate) {
+		return &GoBitpinError{Message: fmt.Sprintf("invalid balance check fee rate %v", b.opts.FeeRate)}
+	}
+	required := new(big.Rat).Mul(spent, fee.Add(fee, big.NewRat(1, 1)))
+
+	wallets, err := b.load(ctx, c)
+	if err != nil {
+		return &GoBitpinError{Message: "failed to fetch wallets for the balance check", Err: err}
+	}
+	available := new(big.Rat)
+	for _, wallet := range wallets {
+		if !u.SameCurrency(wallet.Asset, asset) || !strings.EqualFold(wallet.Service, b.opts.Service) {
+			continue
+		}
+		if free, ok := freeBalance(wallet); ok {
+			available = free
+		}
+		break
+	}
+
+	if required.Cmp(available) <= 0 {
+		return nil
+	}
+	shortfall := trimRat(new(big.Rat).Sub(required, available))
+	return &InsufficientBalanceError{
+		GoBitpinError: GoBitpinError{
+			Message: fmt.Sprintf("order needs %s %s, %s more than the free balance", trimRat(required), asset, shortfall),
+			Err:     ErrInsufficientBalance,
+		},
+		Asset:     asset,
+		Required:  trimRat(required),
+		Available: trimRat(available),
+		Shortfall: shortfall,
+	}
+}
+
+// load returns the cached wallets, fetching them if they are older than the
+// TTL.
+func (b *balanceChecker) load(ctx context.Context, c *Client) (t.Wallets, error) {
+	b.mu.Lock()
+	if b.wallets != nil && b.clock.Now().Sub(b.at) < b.opts.WalletTTL {
+		wallets := b.wallets
+		b.mu.Unlock()
+		return wallets, nil
+	}
+	b.mu.Unlock()
+
+	var wallets t.Wallets
+	if err := c.call(ctx, "GET", OpWallets, nil, true, t.GetWalletParams{}, &wallets); err != nil {
+		return nil, err
+	}
+	if wallets == nil {
+		wallets = t.Wallets{}
+	}
+
+	b.mu.Lock()
+	b.wallets, b.at = wallets, b.clock.Now()
+	b.mu.Unlock()
+	return wallets, nil
+}
+
+// orderSpend returns the asset and the amount an order spends, or false if it
+// cannot be determined without a price.
+func orderSpend(params t.CreateOrderParams) (string, *big.Rat, bool) {
+	base, quote, ok := strings.Cut(u.CanonicalSymbol(params.Symbol), "_")
+	if !ok {
+		return "", nil, false
+	}
+	baseAmount, hasBase := positiveAmount(params.BaseAmount)
+	quoteAmount, hasQuote := positiveAmount(params.QuoteAmount)
+	price, hasPrice := positiveAmount(params.Price)
+
+	switch t.OrderSide(strings.ToLower(string(params.Side))) {
+	case t.SideBuy:
+		if hasQuote {
+			return quote, quoteAmount, true
+		}
+		if hasBase && hasPrice {
+			return quote, baseAmount.Mul(baseAmount, price), true
+		}
+	case t.SideSell:
+		if hasBase {
+			return base, baseAmount, true
+		}
+		if hasQuote && hasPrice {
+			return base, quoteAmount.Quo(quoteAmount, price), true
+		}
+	}
+	return "", nil, false
+}
+
+// positiveAmount parses a decimal amount, reporting false unless it is
+// positive.
+func positiveAmount(amount string) (*big.Rat, bool) {
+	if amount == "" {
+		return nil, false
+	}
+	r, err := u.ParseAmount(amount)
+	if err != nil || r.Sign() <= 0 {
+		return nil, false
+	}
+	return r, true
+}
+
+// freeBalance returns the balance of a wallet minus its frozen amount.
+func freeBalance(wallet t.Wallet) (*big.Rat, bool) {
+	balance, err := u.ParseAmount(wallet.Balance)
+	if err != nil {
+		return nil, false
+	}
+	frozen := new(big.Rat)
+	if wallet.Frozen != "" {
+		if frozen, err = u.ParseAmount(wallet.Frozen); err != nil {
+			return nil, false
+		}
+	}
+	return balance.Sub(balance, frozen), true
+}
@@ -0,0 +1,187 @@
+// Package bars down-samples trade and price snapshot data into bars (candles)
+// for research. Ticks can be aggregated in memory or read from a log of a
+// store.Store, and grouped into time bars, volume bars, or tick bars.
+package bars
+
+import (
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// Tick is a single trade or price snapshot.
+type Tick struct {
+	// Time is when the trade happened or the snapshot was taken.
+	Time time.Time `json:"time"`
+
+	// Price is the trade or snapshot price.
+	Price float64 `json:"price"`
+
+	// Amount is the traded base amount. It is zero for price snapshots.
+	Amount float64 `json:"amount,omitempty"`
+
+	// QuoteAmount is the traded quote amount. When zero, Price * Amount is used.
+	QuoteAmount float64 `json:"quote_amount,omitempty"`
+}
+
+// quote returns the quote amount of the tick.
+func (k Tick) quote() float64 {
+	if k.QuoteAmount != 0 {
+		return k.QuoteAmount
+	}
+	return k.Price * k.Amount
+}
+
+// Aggregator groups ticks into bars. Ticks must be added in time order.
+type Aggregator interface {
+	// Add adds a tick and returns the bars it completed, if any.
+	Add(tick Tick) []t.Candle
+
+	// Flush returns the bar in progress, if it contains any ticks, and
+	// starts a new one.
+	Flush() (t.Candle, bool)
+}
+
+// Aggregate runs ticks through an aggregator and returns the completed bars.
+// If partial is true, the bar in progress at the end is included as well.
+func Aggregate(ticks []Tick, agg Aggregator, partial bool) t.Candles {
+	var candles t.Candles
+	for _, tick := range ticks {
+		candles = append(candles, agg.Add(tick)...)
+	}
+	if partial {
+		if candle, ok := agg.Flush(); ok {
+			candles = append(candles, candle)
+		}
+	}
+	return candles
+}
+
+// builder accumulates ticks into a single bar.
+type builder struct {
+	candle t.Candle
+	empty  bool
+}
+
+func newBuilder() builder {
+	return builder{empty: true}
+}
+
+// add adds a tick to the bar.
+func (b *builder) add(tick Tick) {
+	c := &b.candle
+	if b.empty {
+		*c = t.Candle{Start: tick.Time, Open: tick.Price, High: tick.Price, Low: tick.Price}
+		b.empty = false
+	}
+	c.High = max(c.High, tick.Price)
+	c.Low = min(c.Low, tick.Price)
+	c.Close = tick.Price
+	c.End = tick.Time
+	c.Volume += tick.Amount
+	c.QuoteVolume += tick.quote()
+	c.Trades++
+}
+
+// take returns the bar and resets the builder.
+func (b *builder) take() (t.Candle, bool) {
+	if b.empty {
+		return t.Candle{}, false
+	}
+	candle := b.candle
+	*b = newBuilder()
+	return candle, true
+}
+
+// TimeBars groups ticks into bars of a fixed duration, aligned to multiples of
+// the interval since the Unix epoch (e.g. 15m bars start at :00, :15, :30 and
+// :45). Intervals without ticks produce no bar.
+type TimeBars struct {
+	interval time.Duration
+	current  builder
+	start    time.Time
+}
+
+// NewTimeBars returns an aggregator that produces bars of the given interval.
+func NewTimeBars(interval time.Duration) *TimeBars {
+	return &TimeBars{interval: interval, current: newBuilder()}
+}
+
+// Add implements Aggregator.
+func (a *TimeBars) Add(tick Tick) []t.Candle {
+	start := tick.Time.Truncate(a.interval)
+	var done []t.Candle
+	if !a.current.empty && !start.Equal(a.start) {
+		if candle, ok := a.Flush(); ok {
+			done = append(done, candle)
+		}
+	}
+	a.start = start
+	a.current.add(tick)
+	return done
+}
+
+// Flush implements Aggregator.
+func (a *TimeBars) Flush() (t.Candle, bool) {
+	candle, ok := a.current.take()
+	if ok {
+		candle.Start = a.start
+		candle.End = a.start.Add(a.interval)
+	}
+	return candle, ok
+}
+
+// VolumeBars closes a bar as soon as its traded base amount reaches a
+// threshold. Trades are not split, so a bar may exceed the threshold by the
+// amount of its last trade.
+type VolumeBars struct {
+	threshold float64
+	current   builder
+}
+
+// NewVolumeBars returns an aggregator that produces bars of at least the given
+// base volume each.
+func NewVolumeBars(volume float64) *VolumeBars {
+	return &VolumeBars{threshold: volume, current: newBuilder()}
+}
+
+// Add implements Aggregator.
+func (a *VolumeBars) Add(tick Tick) []t.Candle {
+	a.current.add(tick)
+	if a.current.candle.Volume < a.threshold {
+		return nil
+	}
+	candle, _ := a.current.take()
+	return []t.Candle{candle}
+}
+
+// Flush implements Aggregator.
+func (a *VolumeBars) Flush() (t.Candle, bool) {
+	return a.current.take()
+}
+
+// TickBars closes a bar after a fixed number of ticks.
+type TickBars struct {
+	count   int
+	current builder
+}
+
+// NewTickBars returns an aggregator that produces bars of n ticks each.
+func NewTickBars(n int) *TickBars {
+	return &TickBars{count: max(n, 1), current: newBuilder()}
+}
+
+// Add implements Aggregator.
+func (a *TickBars) Add(tick Tick) []t.Candle {
+	a.current.add(tick)
+	if a.current.candle.Trades < a.count {
+		return nil
+	}
+	candle, _ := a.current.take()
+	return []t.Candle{candle}
+}
+
+// Flush implements Aggregator.
+func (a *TickBars) Flush() (t.Candle, bool) {
+	return a.current.take()
+}
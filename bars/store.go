@@ -0,0 +1,111 @@
+package bars
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rzabhd80/go-sdk-bitpin/store"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// TickFromTrade converts a public trade into a tick. Trades returned by the
+// API carry no timestamp, so the time the trade was observed is passed in.
+func TickFromTrade(trade *t.Trade, at time.Time) (Tick, error) {
+	price, err := strconv.ParseFloat(trade.Price, 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("trade %s: invalid price %q", trade.Id, trade.Price)
+	}
+	amount, err := strconv.ParseFloat(trade.BaseAmount, 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("trade %s: invalid base amount %q", trade.Id, trade.BaseAmount)
+	}
+	quote, _ := strconv.ParseFloat(trade.QuoteAmount, 64)
+	return Tick{Time: at, Price: price, Amount: amount, QuoteAmount: quote}, nil
+}
+
+// TickFromUserTrade converts a fill of the authenticated user into a tick.
+func TickFromUserTrade(trade *t.UserTrade) (Tick, error) {
+	return TickFromTrade(&t.Trade{
+		Id:          strconv.Itoa(trade.Id),
+		Price:       trade.Price,
+		BaseAmount:  trade.BaseAmount,
+		QuoteAmount: trade.QuoteAmount,
+	}, trade.CreatedAt)
+}
+
+// TickFromTicker converts a ticker into a price snapshot taken at the given
+// time. The snapshot has no volume.
+func TickFromTicker(ticker *t.Ticker, at time.Time) (Tick, error) {
+	price, err := strconv.ParseFloat(ticker.Price, 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("ticker %s: invalid price %q", ticker.Symbol, ticker.Price)
+	}
+	return Tick{Time: at, Price: price}, nil
+}
+
+// AppendTick stores a tick as a JSON record at the end of the named log of st
+// and returns its sequence number.
+func AppendTick(ctx context.Context, st store.Store, log string, tick Tick) (uint64, error) {
+	record, err := json.Marshal(tick)
+	if err != nil {
+		return 0, err
+	}
+	return st.Append(ctx, log, record)
+}
+
+// ReadTicks calls fn for every tick stored in the named log of st after the
+// given sequence number, in order.
+func ReadTicks(ctx context.Context, st store.Store, log string, after uint64, fn func(seq uint64, tick Tick) error) error {
+	return st.Read(ctx, log, after, func(seq uint64, record []byte) error {
+		var tick Tick
+		if err := json.Unmarshal(record, &tick); err != nil {
+			return fmt.Errorf("bars: %s record %d: %w", log, seq, err)
+		}
+		return fn(seq, tick)
+	})
+}
+
+// Query selects the ticks of a log that are down-sampled.
+type Query struct {
+	// From and To restrict the ticks to the time range [From, To). Zero
+	// values leave the range open.
+	From time.Time
+	To   time.Time
+
+	// Partial includes the bar in progress after the last tick.
+	Partial bool
+}
+
+// Downsample reads the ticks stored in the named log of st and aggregates
+// them into bars.
+//
+// Example:
+//
+//	candles, err := bars.Downsample(ctx, st, "ticks/BTC_USDT",
+//	    bars.NewTimeBars(15*time.Minute), bars.Query{From: yesterday})
+func Downsample(ctx context.Context, st store.Store, log string, agg Aggregator, q Query) (t.Candles, error) {
+	var candles t.Candles
+	err := ReadTicks(ctx, st, log, 0, func(_ uint64, tick Tick) error {
+		if !q.From.IsZero() && tick.Time.Before(q.From) {
+			return nil
+		}
+		if !q.To.IsZero() && !tick.Time.Before(q.To) {
+			return nil
+		}
+		candles = append(candles, agg.Add(tick)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if q.Partial {
+		if candle, ok := agg.Flush(); ok {
+			candles = append(candles, candle)
+		}
+	}
+	return candles, nil
+}
@@ -0,0 +1,79 @@
+package bitpintest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a fake clock for tests. Its time only moves when Advance is called,
+// so token expiry, retry delays and throttles can be tested without sleeping.
+// It implements bitpin.Clock.
+//
+// Example:
+//
+//	clock := bitpintest.NewClock(time.Now())
+//	client, _ := bitpin.NewClientWithOptions(bitpin.WithClock(clock), ...)
+//	clock.Advance(2 * time.Hour) // the access token is now expired
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []clockWaiter
+}
+
+// clockWaiter is a channel returned by After that has not fired yet.
+type clockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewClock returns a fake clock set to start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the time of the clock once Advance
+// has moved it by d. It fires right away if d is not positive.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, clockWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d and fires every After channel that is
+// due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// Waiters returns the number of After channels that have not fired yet. Tests
+// can poll it to know that the code under test is waiting before calling
+// Advance.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
@@ -0,0 +1,154 @@
+package bitpintest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// bookDepth is the number of levels generated on each side of the book.
+const bookDepth = 10
+
+func (s *Server) handleCurrencies(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeCacheable(w, r, s.currencies)
+}
+
+func (s *Server) handleMarkets(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeCacheable(w, r, s.markets)
+}
+
+func (s *Server) handleTickers(w http.ResponseWriter, r *http.Request) {
+	symbol := r.URL.Query().Get("symbol")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tickers := t.Tickers{}
+	for _, market := range s.markets {
+		if symbol != "" && market.Symbol != symbol {
+			continue
+		}
+		price := s.prices[market.Symbol]
+		tickers = append(tickers, t.Ticker{
+			Symbol:    market.Symbol,
+			Price:     formatDecimal(price, market.PricePrecision),
+			Low:       formatDecimal(price*0.98, market.PricePrecision),
+			High:      formatDecimal(price*1.02, market.PricePrecision),
+			Timestamp: float64(time.Now().Unix()),
+		})
+	}
+	writeJSON(w, http.StatusOK, tickers)
+}
+
+func (s *Server) handleOrderBook(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	market, ok := s.market(r.PathValue("symbol"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.book(market))
+}
+
+func (s *Server) handleMatches(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	sinceId, _ := strconv.Atoi(query.Get("since_id"))
+	since, _ := strconv.ParseInt(query.Get("since"), 10, 64)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.market(r.PathValue("symbol")); !ok {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+
+	trades := []*t.Trade{}
+	all := s.trades[r.PathValue("symbol")]
+	for i := len(all) - 1; i >= 0; i-- {
+		id, _ := strconv.Atoi(all[i].Id)
+		if (sinceId > 0 && id <= sinceId) || (since > 0 && all[i].CreatedAt.Unix() <= since) {
+			continue
+		}
+		trades = append(trades, all[i])
+		if limit > 0 && len(trades) == limit {
+			break
+		}
+	}
+	writeJSON(w, http.StatusOK, trades)
+}
+
+func (s *Server) handleWallets(w http.ResponseWriter, r *http.Request) {
+	assets := r.URL.Query()["assets"]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wallets := t.Wallets{}
+	for _, currency := range s.currencies {
+		wallet, ok := s.wallets[currency.Currency]
+		if !ok || (len(assets) > 0 && !contains(assets, currency.Currency)) {
+			continue
+		}
+		wallets = append(wallets, *wallet)
+	}
+	writeJSON(w, http.StatusOK, wallets)
+}
+
+// market returns the market with the given symbol.
+func (s *Server) market(symbol string) (t.Market, bool) {
+	for _, market := range s.markets {
+		if market.Symbol == symbol {
+			return market, true
+		}
+	}
+	return t.Market{}, false
+}
+
+// book generates an order book around the current price of a market.
+func (s *Server) book(market t.Market) t.OrderBook {
+	price := s.prices[market.Symbol]
+	lot := 20000 / price
+	if market.Quote == "IRT" {
+		lot *= s.prices["USDT_IRT"]
+	}
+
+	book := t.OrderBook{Asks: [][]string{}, Bids: [][]string{}}
+	for i := 0; i < bookDepth; i++ {
+		offset := 0.0005 + float64(i)*0.001
+		amount := formatDecimal(lot*float64(i+1), market.BaseAmountPrecision)
+		book.Asks = append(book.Asks, []string{formatDecimal(price*(1+offset), market.PricePrecision), amount})
+		book.Bids = append(book.Bids, []string{formatDecimal(price*(1-offset), market.PricePrecision), amount})
+	}
+	return book
+}
+
+// formatDecimal formats a value with a fixed number of decimals.
+func formatDecimal(value float64, decimals int) string {
+	return strconv.FormatFloat(value, 'f', decimals, 64)
+}
+
+// decimals returns the number of digits after the decimal point of a string.
+func decimals(value string) int {
+	_, frac, ok := strings.Cut(value, ".")
+	if !ok {
+		return 0
+	}
+	return len(frac)
+}
+
+// contains reports whether list contains value.
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
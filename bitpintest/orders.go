@@ -0,0 +1,370 @@
+package bitpintest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// commissionRate is the fee charged on every fill, in the received currency.
+const commissionRate = 0.002
+
+func (s *Server) handleCreateOrder(w http.ResponseWriter, r *http.Request) {
+	var params t.CreateOrderParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	market, ok := s.market(params.Symbol)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"symbol": {"Invalid symbol."}})
+		return
+	}
+	if fields := validate(market, params); len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, fields)
+		return
+	}
+
+	s.nextId++
+	order := &t.OrderStatus{
+		Id:                s.nextId,
+		Symbol:            params.Symbol,
+		Type:              params.Type,
+		Side:              params.Side,
+		BaseAmount:        params.BaseAmount,
+		QuoteAmount:       params.QuoteAmount,
+		Price:             params.Price,
+		StopPrice:         params.StopPrice,
+		OcoTargetPrice:    params.OcoTargetPrice,
+		Identifier:        params.Identifier,
+		State:             t.StateActive,
+		CreatedAt:         time.Now().UTC(),
+		DealedBaseAmount:  "0",
+		DealedQuoteAmount: "0",
+		Commission:        "0",
+	}
+
+	book := s.book(market)
+	switch {
+	case params.Type == t.TypeMarket:
+		s.fill(market, order, touch(book, params.Side))
+	case crosses(book, params.Side, params.Price):
+		s.fill(market, order, touch(book, params.Side))
+	}
+
+	if order.State == t.StateActive && !s.reserve(market, order) {
+		writeError(w, http.StatusBadRequest, "Insufficient balance.")
+		return
+	}
+
+	s.orders = append(s.orders, order)
+	writeJSON(w, http.StatusCreated, order)
+}
+
+func (s *Server) handleListOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	orders := t.OrderStatuses{}
+	for i := len(s.orders) - 1; i >= 0; i-- {
+		order := s.orders[i]
+		if !matchesOrder(order, query) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		orders = append(orders, *order)
+		if limit > 0 && len(orders) == limit {
+			break
+		}
+	}
+	writeJSON(w, http.StatusOK, orders)
+}
+
+func (s *Server) handleGetOrder(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.order(r.PathValue("id"))
+	if order == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, order)
+}
+
+func (s *Server) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.order(r.PathValue("id"))
+	if order == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	if order.State != t.StateActive {
+		writeError(w, http.StatusBadRequest, "Order is not active.")
+		return
+	}
+
+	market, _ := s.market(order.Symbol)
+	s.release(market, order)
+	order.State = t.StateCanceled
+	order.ClosedAt = time.Now().UTC().Format(time.RFC3339)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleFills(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fills := t.UserTrades{}
+	for i := len(s.fills) - 1; i >= 0; i-- {
+		fill := s.fills[i]
+		if (query.Get("symbol") != "" && fill.Symbol != query.Get("symbol")) ||
+			(query.Get("side") != "" && string(fill.Side) != query.Get("side")) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		fills = append(fills, fill)
+		if limit > 0 && len(fills) == limit {
+			break
+		}
+	}
+	writeJSON(w, http.StatusOK, fills)
+}
+
+// order returns the order with the given ID.
+func (s *Server) order(id string) *t.OrderStatus {
+	for _, order := range s.orders {
+		if strconv.Itoa(order.Id) == id {
+			return order
+		}
+	}
+	return nil
+}
+
+// matchResting fills resting limit orders that cross the current book.
+func (s *Server) matchResting(symbol string) {
+	market, ok := s.market(symbol)
+	if !ok {
+		return
+	}
+	book := s.book(market)
+	for _, order := range s.orders {
+		if order.Symbol != symbol || order.State != t.StateActive || !crosses(book, order.Side, order.Price) {
+			continue
+		}
+		s.release(market, order)
+		price, _ := strconv.ParseFloat(order.Price, 64)
+		s.fill(market, order, price)
+	}
+}
+
+// fill executes the full order at the given price, updates the wallets, and
+// records the fill and the public trade.
+func (s *Server) fill(market t.Market, order *t.OrderStatus, price float64) {
+	base, _ := strconv.ParseFloat(order.BaseAmount, 64)
+	if base == 0 {
+		quote, _ := strconv.ParseFloat(order.QuoteAmount, 64)
+		base = quote / price
+	}
+	quote := base * price
+
+	baseWallet, quoteWallet := s.wallets[market.Base], s.wallets[market.Quote]
+	var commission float64
+	var commissionCurrency string
+	if order.Side == t.SideBuy {
+		commission = base * commissionRate
+		commissionCurrency = market.Base
+		addBalance(baseWallet, base-commission)
+		addBalance(quoteWallet, -quote)
+	} else {
+		commission = quote * commissionRate
+		commissionCurrency = market.Quote
+		addBalance(baseWallet, -base)
+		addBalance(quoteWallet, quote-commission)
+	}
+
+	baseStr := formatDecimal(base, market.BaseAmountPrecision)
+	quoteStr := formatDecimal(quote, market.QuoteAmountPrecision)
+	priceStr := formatDecimal(price, market.PricePrecision)
+	now := time.Now().UTC()
+
+	order.State = t.StateClosed
+	order.ClosedAt = now.Format(time.RFC3339)
+	order.DealedBaseAmount = baseStr
+	order.DealedQuoteAmount = quoteStr
+	order.Commission = strconv.FormatFloat(commission, 'f', -1, 64)
+
+	s.nextId++
+	s.fills = append(s.fills, t.UserTrade{
+		Id:                 s.nextId,
+		Symbol:             order.Symbol,
+		BaseAmount:         baseStr,
+		QuoteAmount:        quoteStr,
+		Price:              priceStr,
+		CreatedAt:          now,
+		Commission:         order.Commission,
+		Side:               order.Side,
+		CommissionCurrency: commissionCurrency,
+		OrderId:            order.Id,
+		Identifier:         order.Identifier,
+	})
+	s.trades[order.Symbol] = append(s.trades[order.Symbol], &t.Trade{
+		Id:          strconv.Itoa(s.nextId),
+		Price:       priceStr,
+		BaseAmount:  baseStr,
+		QuoteAmount: quoteStr,
+		Side:        order.Side,
+		CreatedAt:   now,
+	})
+}
+
+// reserve freezes the funds of a resting limit order. It returns false if the
+// wallet does not hold enough free balance.
+func (s *Server) reserve(market t.Market, order *t.OrderStatus) bool {
+	wallet, amount := s.lockedFunds(market, order)
+	balance, _ := strconv.ParseFloat(wallet.Balance, 64)
+	frozen, _ := strconv.ParseFloat(wallet.Frozen, 64)
+	if balance-frozen < amount {
+		return false
+	}
+	wallet.Frozen = strconv.FormatFloat(frozen+amount, 'f', -1, 64)
+	return true
+}
+
+// release unfreezes the funds of a resting limit order.
+func (s *Server) release(market t.Market, order *t.OrderStatus) {
+	wallet, amount := s.lockedFunds(market, order)
+	frozen, _ := strconv.ParseFloat(wallet.Frozen, 64)
+	wallet.Frozen = strconv.FormatFloat(max(frozen-amount, 0), 'f', -1, 64)
+}
+
+// lockedFunds returns the wallet and amount a resting order keeps frozen.
+func (s *Server) lockedFunds(market t.Market, order *t.OrderStatus) (*t.Wallet, float64) {
+	base, _ := strconv.ParseFloat(order.BaseAmount, 64)
+	if order.Side == t.SideSell {
+		return s.wallets[market.Base], base
+	}
+	price, _ := strconv.ParseFloat(order.Price, 64)
+	return s.wallets[market.Quote], base * price
+}
+
+// validate checks an order against the market rules and returns field errors.
+func validate(market t.Market, params t.CreateOrderParams) map[string][]string {
+	fields := make(map[string][]string)
+	if !params.Side.Valid() {
+		fields["side"] = []string{fmt.Sprintf("%q is not a valid choice.", params.Side)}
+	}
+	switch params.Type {
+	case t.TypeLimit:
+		if params.Price == "" {
+			fields["price"] = []string{"This field is required."}
+		}
+		if params.BaseAmount == "" {
+			fields["base_amount"] = []string{"This field is required."}
+		}
+	case t.TypeMarket:
+		if (params.BaseAmount == "") == (params.QuoteAmount == "") {
+			fields["base_amount"] = []string{"Exactly one of base_amount and quote_amount is required."}
+		}
+	default:
+		fields["type"] = []string{fmt.Sprintf("%q is not a valid choice.", params.Type)}
+	}
+	var minimum *t.ValidationError
+	if errors.As(params.ValidateMarket(market), &minimum) {
+		for field, messages := range minimum.Fields {
+			fields[field] = append(fields[field], messages...)
+		}
+	}
+	if decimals(params.Price) > market.PricePrecision {
+		fields["price"] = []string{fmt.Sprintf("Ensure that there are no more than %d decimal places.", market.PricePrecision)}
+	}
+	if decimals(params.BaseAmount) > market.BaseAmountPrecision {
+		fields["base_amount"] = []string{fmt.Sprintf("Ensure that there are no more than %d decimal places.", market.BaseAmountPrecision)}
+	}
+	if decimals(params.QuoteAmount) > market.QuoteAmountPrecision {
+		fields["quote_amount"] = []string{fmt.Sprintf("Ensure that there are no more than %d decimal places.", market.QuoteAmountPrecision)}
+	}
+	return fields
+}
+
+// matchesOrder reports whether an order matches the list filters of a query.
+func matchesOrder(order *t.OrderStatus, query map[string][]string) bool {
+	get := func(key string) string {
+		if values := query[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	if v := get("symbol"); v != "" && order.Symbol != v {
+		return false
+	}
+	if v := get("side"); v != "" && string(order.Side) != v {
+		return false
+	}
+	if v := get("type"); v != "" && string(order.Type) != v {
+		return false
+	}
+	if v := get("state"); v != "" && string(order.State) != v {
+		return false
+	}
+	if v := get("identifier"); v != "" && order.Identifier != v {
+		return false
+	}
+	if v := get("identifiers_in"); v != "" && !contains(strings.Split(v, ","), order.Identifier) {
+		return false
+	}
+	if v := get("ids_in"); v != "" && !contains(strings.Split(v, ","), strconv.Itoa(order.Id)) {
+		return false
+	}
+	return true
+}
+
+// touch returns the best opposite price for an order side.
+func touch(book t.OrderBook, side t.OrderSide) float64 {
+	levels := book.Asks
+	if side == t.SideSell {
+		levels = book.Bids
+	}
+	price, _ := strconv.ParseFloat(levels[0][0], 64)
+	return price
+}
+
+// crosses reports whether a limit price is marketable against the book.
+func crosses(book t.OrderBook, side t.OrderSide, limit string) bool {
+	price, err := strconv.ParseFloat(limit, 64)
+	if err != nil {
+		return false
+	}
+	if side == t.SideSell {
+		return price <= touch(book, side)
+	}
+	return price >= touch(book, side)
+}
+
+// addBalance adds delta to the balance of a wallet.
+func addBalance(wallet *t.Wallet, delta float64) {
+	balance, _ := strconv.ParseFloat(wallet.Balance, 64)
+	wallet.Balance = strconv.FormatFloat(balance+delta, 'f', -1, 64)
+}
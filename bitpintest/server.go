@@ -0,0 +1,354 @@
+// Package bitpintest provides an in-process fake of the Bitpin REST API for
+// examples, integration tests, and local development.
+//
+// The fake keeps markets, tickers, order books, wallets, orders, and fills in
+// memory. Orders that cross the book are filled immediately at the touch price;
+// other limit orders stay active until they are cancelled. The markets and
+// order endpoints are also served under /api/v2/ in the v2 schema.
+//
+// Example:
+//
+//	srv := bitpintest.NewServer()
+//	defer srv.Close()
+//
+//	client, err := bitpin.NewClient(bitpin.ClientOptions{
+//	    BaseUrl:   srv.URL,
+//	    ApiKey:    bitpintest.ApiKey,
+//	    SecretKey: bitpintest.SecretKey,
+//	})
+package bitpintest
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// Credentials accepted by the fake server.
+const (
+	// ApiKey is the API key accepted by the fake server.
+	ApiKey = "test-api-key"
+
+	// SecretKey is the secret key accepted by the fake server.
+	SecretKey = "test-secret-key"
+)
+
+// RateLimit is the number of requests per minute the fake server reports in
+// its X-RateLimit headers. Requests are counted but never rejected.
+const RateLimit = 1200
+
+// Server is a running fake Bitpin API.
+type Server struct {
+	// URL is the base URL of the fake server, suitable for ClientOptions.BaseUrl.
+	URL string
+
+	srv *httptest.Server
+
+	mu         sync.Mutex
+	currencies t.Currencies
+	markets    t.Markets
+	prices     map[string]float64
+	wallets    map[string]*t.Wallet
+	orders     []*t.OrderStatus
+	fills      []t.UserTrade
+	trades     map[string][]*t.Trade
+	access     map[string]bool
+	refresh    map[string]bool
+	nextId     int
+
+	windowStart time.Time // start of the current rate-limit window
+	windowUsed  int       // requests made in the current window
+}
+
+// NewServer starts a fake server seeded with a few markets, prices, and a
+// funded spot wallet. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		prices:  make(map[string]float64),
+		wallets: make(map[string]*t.Wallet),
+		trades:  make(map[string][]*t.Trade),
+		access:  make(map[string]bool),
+		refresh: make(map[string]bool),
+		nextId:  1000,
+	}
+	s.seed()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/usr/authenticate/", s.handleAuthenticate)
+	mux.HandleFunc("POST /api/v1/usr/refresh_token/", s.handleRefresh)
+	mux.HandleFunc("GET /api/v1/mkt/currencies/", s.handleCurrencies)
+	mux.HandleFunc("GET /api/v1/mkt/markets/", s.handleMarkets)
+	mux.HandleFunc("GET /api/v1/mkt/tickers/", s.handleTickers)
+	mux.HandleFunc("GET /api/v1/mth/orderbook/{symbol}/", s.handleOrderBook)
+	mux.HandleFunc("GET /api/v1/mth/matches/{symbol}/", s.handleMatches)
+	mux.HandleFunc("GET /api/v1/wlt/wallets/", s.authed(s.handleWallets))
+	mux.HandleFunc("POST /api/v1/odr/orders/", s.authed(s.handleCreateOrder))
+	mux.HandleFunc("GET /api/v1/odr/orders/", s.authed(s.handleListOrders))
+	mux.HandleFunc("GET /api/v1/odr/orders/{id}/", s.authed(s.handleGetOrder))
+	mux.HandleFunc("DELETE /api/v1/odr/orders/{id}/", s.authed(s.handleCancelOrder))
+	mux.HandleFunc("GET /api/v1/odr/fills/", s.authed(s.handleFills))
+	mux.HandleFunc("GET /api/v2/mkt/markets/", s.handleMarketsV2)
+	mux.HandleFunc("POST /api/v2/odr/orders/", s.authed(v2Orders(s.handleCreateOrder)))
+	mux.HandleFunc("GET /api/v2/odr/orders/", s.authed(v2Orders(s.handleListOrders)))
+	mux.HandleFunc("GET /api/v2/odr/orders/{id}/", s.authed(v2Orders(s.handleGetOrder)))
+	mux.HandleFunc("DELETE /api/v2/odr/orders/{id}/", s.authed(s.handleCancelOrder))
+
+	s.srv = httptest.NewServer(s.rateLimited(gzipped(mux)))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// RevokeAccessTokens invalidates every issued access token, as the exchange
+// does when a session is ended on its side. Later authenticated requests with
+// an old access token are rejected with 401 Unauthorized.
+func (s *Server) RevokeAccessTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.access = make(map[string]bool)
+}
+
+// RevokeRefreshTokens invalidates every issued refresh token, so clients must
+// authenticate again with their API key and secret key.
+func (s *Server) RevokeRefreshTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refresh = make(map[string]bool)
+}
+
+// SetPrice moves the price of a market. The order book is rebuilt around the
+// new price and resting limit orders that now cross it are filled.
+func (s *Server) SetPrice(symbol string, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prices[symbol] = price
+	s.matchResting(symbol)
+}
+
+// Price returns the current price of a market.
+func (s *Server) Price(symbol string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.prices[symbol]
+}
+
+// SetBalance sets the balance of the spot wallet of an asset, as a deposit or
+// withdrawal would. Only wallets of the seeded currencies are listed.
+func (s *Server) SetBalance(asset, balance string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wallet, ok := s.wallets[asset]
+	if !ok {
+		wallet = &t.Wallet{Id: len(s.wallets) + 1, Asset: asset, Frozen: "0", Service: "spot"}
+		s.wallets[asset] = wallet
+	}
+	wallet.Balance = balance
+}
+
+// seed fills the server with its initial data.
+func (s *Server) seed() {
+	s.currencies = t.Currencies{
+		{Currency: "BTC", Name: "Bitcoin", Tradable: true, Precision: "8"},
+		{Currency: "ETH", Name: "Ethereum", Tradable: true, Precision: "8"},
+		{Currency: "USDT", Name: "Tether", Tradable: true, Precision: "2"},
+		{Currency: "IRT", Name: "Toman", Tradable: true, Precision: "0"},
+	}
+	s.markets = t.Markets{
+		{Symbol: "BTC_USDT", Name: "Bitcoin/Tether", Base: "BTC", Quote: "USDT", Tradable: true, PricePrecision: 2, BaseAmountPrecision: 6, QuoteAmountPrecision: 2, MinQuoteAmount: "5"},
+		{Symbol: "ETH_USDT", Name: "Ethereum/Tether", Base: "ETH", Quote: "USDT", Tradable: true, PricePrecision: 2, BaseAmountPrecision: 5, QuoteAmountPrecision: 2, MinQuoteAmount: "5"},
+		{Symbol: "USDT_IRT", Name: "Tether/Toman", Base: "USDT", Quote: "IRT", Tradable: true, PricePrecision: 0, BaseAmountPrecision: 2, QuoteAmountPrecision: 0, MinQuoteAmount: "300000"},
+		{Symbol: "BTC_IRT", Name: "Bitcoin/Toman", Base: "BTC", Quote: "IRT", Tradable: true, PricePrecision: 0, BaseAmountPrecision: 6, QuoteAmountPrecision: 0, MinQuoteAmount: "300000"},
+	}
+	s.prices["BTC_USDT"] = 60000
+	s.prices["ETH_USDT"] = 3000
+	s.prices["USDT_IRT"] = 60000
+	s.prices["BTC_IRT"] = 3600000000
+
+	for i, asset := range []string{"BTC", "ETH", "USDT", "IRT"} {
+		balance := map[string]string{"BTC": "1", "ETH": "10", "USDT": "100000", "IRT": "1000000000"}[asset]
+		s.wallets[asset] = &t.Wallet{Id: i + 1, Asset: asset, Balance: balance, Frozen: "0", Service: "spot"}
+	}
+}
+
+// writeJSON writes a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeCacheable writes v as a JSON response with an ETag derived from its
+// content, or 304 Not Modified if the request already holds that version.
+func writeCacheable(w http.ResponseWriter, r *http.Request, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// rateLimited counts requests per minute and reports the remaining budget in
+// X-RateLimit headers.
+func (s *Server) rateLimited(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		now := time.Now()
+		if now.Sub(s.windowStart) >= time.Minute {
+			s.windowStart = now.Truncate(time.Minute)
+			s.windowUsed = 0
+		}
+		s.windowUsed++
+		remaining := max(RateLimit-s.windowUsed, 0)
+		reset := s.windowStart.Add(time.Minute)
+		s.mu.Unlock()
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(RateLimit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// gzipped compresses responses for clients that accept gzip, like the real API.
+func gzipped(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		gw := &gzipWriter{ResponseWriter: w}
+		defer gw.close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// gzipWriter compresses the body of every response that has one.
+type gzipWriter struct {
+	http.ResponseWriter
+	zw          *gzip.Writer
+	wroteHeader bool
+}
+
+func (g *gzipWriter) WriteHeader(status int) {
+	g.wroteHeader = true
+	if status != http.StatusNoContent && status != http.StatusNotModified {
+		g.Header().Set("Content-Encoding", "gzip")
+		g.Header().Del("Content-Length")
+		g.zw = gzip.NewWriter(g.ResponseWriter)
+	}
+	g.ResponseWriter.WriteHeader(status)
+}
+
+func (g *gzipWriter) Write(p []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
+	if g.zw == nil {
+		return g.ResponseWriter.Write(p)
+	}
+	return g.zw.Write(p)
+}
+
+func (g *gzipWriter) close() {
+	if g.zw != nil {
+		_ = g.zw.Close()
+	}
+}
+
+// writeError writes an error response in the format used by the API.
+func writeError(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]string{"detail": detail})
+}
+
+// issueToken creates a signed JWT with the claims inspected by the SDK.
+func issueToken(tokenType string, ttl time.Duration) string {
+	claims := jwt.MapClaims{
+		"token_type":        tokenType,
+		"exp":               time.Now().Add(ttl).Unix(),
+		"jti":               time.Now().Format(time.RFC3339Nano),
+		"user_id":           1,
+		"ip":                []string{"127.0.0.1"},
+		"api_credential_id": 1,
+	}
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(SecretKey))
+	return token
+}
+
+// authed wraps a handler with bearer token validation.
+func (s *Server) authed(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		s.mu.Lock()
+		ok := len(auth) > 7 && s.access[auth[7:]]
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "Authentication credentials were not provided.")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleAuthenticate(w http.ResponseWriter, r *http.Request) {
+	var params t.AuthenticationParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+	if params.ApiKey != ApiKey || params.SecretKey != SecretKey {
+		writeError(w, http.StatusUnauthorized, "Invalid credentials.")
+		return
+	}
+
+	resp := t.AuthenticationResponse{
+		Access:  issueToken("access", 15*time.Minute),
+		Refresh: issueToken("refresh", 24*time.Hour),
+	}
+	s.mu.Lock()
+	s.access[resp.Access] = true
+	s.refresh[resp.Refresh] = true
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	var params t.RefreshTokenParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.refresh[params.Refresh] {
+		writeError(w, http.StatusUnauthorized, "Token is invalid or expired")
+		return
+	}
+	resp := t.RefreshTokenResponse{Access: issueToken("access", 15*time.Minute)}
+	s.access[resp.Access] = true
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,119 @@
+package bitpintest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+func (s *Server) handleMarketsV2(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	markets := make([]t.MarketV2, len(s.markets))
+	for i, market := range s.markets {
+		markets[i] = marketV2(market)
+	}
+	writeCacheable(w, r, markets)
+}
+
+// v2Orders serves a v1 order handler in the v2 schema. The request body is
+// converted to the v1 schema and the orders of the response to the v2 schema.
+func v2Orders(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var params t.CreateOrderParamsV2
+			if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid body")
+				return
+			}
+			body, _ := json.Marshal(t.CreateOrderParams{
+				Symbol:         params.Symbol,
+				Type:           params.Type,
+				Side:           params.Side,
+				BaseAmount:     params.BaseAmount,
+				QuoteAmount:    params.QuoteAmount,
+				Price:          params.Price,
+				StopPrice:      params.StopPrice,
+				OcoTargetPrice: params.OcoTargetPrice,
+				Identifier:     params.ClientOrderId,
+			})
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		rec := httptest.NewRecorder()
+		next(rec, r)
+		if rec.Code != http.StatusOK && rec.Code != http.StatusCreated {
+			for key, values := range rec.Header() {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(rec.Code)
+			_, _ = w.Write(rec.Body.Bytes())
+			return
+		}
+
+		var order t.OrderStatus
+		if err := json.Unmarshal(rec.Body.Bytes(), &order); err == nil {
+			writeJSON(w, rec.Code, orderV2(order))
+			return
+		}
+		var orders []t.OrderStatus
+		if err := json.Unmarshal(rec.Body.Bytes(), &orders); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		converted := make([]t.OrderStatusV2, len(orders))
+		for i, order := range orders {
+			converted[i] = orderV2(order)
+		}
+		writeJSON(w, rec.Code, converted)
+	}
+}
+
+func marketV2(market t.Market) t.MarketV2 {
+	status := "halted"
+	if market.Tradable {
+		status = t.MarketStatusActive
+	}
+	return t.MarketV2{
+		Symbol: market.Symbol,
+		Name:   market.Name,
+		Base:   market.Base,
+		Quote:  market.Quote,
+		Status: status,
+		Precision: t.MarketV2Precision{
+			Price:       market.PricePrecision,
+			BaseAmount:  market.BaseAmountPrecision,
+			QuoteAmount: market.QuoteAmountPrecision,
+		},
+		Limits: t.MarketV2Limits{
+			MinBaseAmount:  market.MinBaseAmount,
+			MinQuoteAmount: market.MinQuoteAmount,
+		},
+	}
+}
+
+func orderV2(order t.OrderStatus) t.OrderStatusV2 {
+	return t.OrderStatusV2{
+		Id:                order.Id,
+		Symbol:            order.Symbol,
+		Type:              order.Type,
+		Side:              order.Side,
+		BaseAmount:        order.BaseAmount,
+		QuoteAmount:       order.QuoteAmount,
+		Price:             order.Price,
+		StopPrice:         order.StopPrice,
+		OcoTargetPrice:    order.OcoTargetPrice,
+		CreatedAt:         order.CreatedAt,
+		ClosedAt:          order.ClosedAt,
+		Commission:        order.Commission,
+		State:             order.State,
+		ClientOrderId:     order.Identifier,
+		FilledBaseAmount:  order.DealedBaseAmount,
+		FilledQuoteAmount: order.DealedQuoteAmount,
+		CancelRequested:   order.ReqToCancel,
+	}
+}
@@ -0,0 +1,200 @@
+package bitpin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults.
+const (
+	// DefaultBreakerFailureThreshold is the number of consecutive failures
+	// that opens the circuit.
+	DefaultBreakerFailureThreshold = 5
+
+	// DefaultBreakerOpenTimeout is how long the circuit stays open before
+	// probe requests are let through.
+	DefaultBreakerOpenTimeout = 30 * time.Second
+
+	// DefaultBreakerHalfOpenProbes is the number of concurrent probe requests
+	// allowed while the circuit is half-open.
+	DefaultBreakerHalfOpenProbes = 1
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit
+// breaker is open
+var ErrCircuitOpen = &GoBitpinError{Message: "circuit breaker is open"}
+
+// CircuitState is the state of the circuit breaker.
+type CircuitState string
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = "closed"
+
+	// CircuitOpen fails every request locally with ErrCircuitOpen.
+	CircuitOpen CircuitState = "open"
+
+	// CircuitHalfOpen lets a limited number of probe requests through. A
+	// successful probe closes the circuit; a failed one opens it again.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreakerOptions configures the circuit breaker of a client. Requests
+// count as failed when they cannot be sent or read, or when the API responds
+// with a 5xx status. Other responses, including 4xx errors, count as successes,
+// since they show the API is up.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit. Defaults to DefaultBreakerFailureThreshold.
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open before it becomes
+	// half-open. Defaults to DefaultBreakerOpenTimeout.
+	OpenTimeout time.Duration
+
+	// HalfOpenProbes is the number of requests let through concurrently while
+	// the circuit is half-open. Defaults to DefaultBreakerHalfOpenProbes.
+	HalfOpenProbes int
+
+	// OnStateChange is invoked in its own goroutine after every state
+	// transition, e.g. to alert when the circuit opens.
+	OnStateChange func(from, to CircuitState)
+}
+
+// circuitBreaker implements the closed, open and half-open states. A nil
+// breaker lets every request through.
+type circuitBreaker struct {
+	opts  CircuitBreakerOptions
+	clock Clock
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int       // consecutive failures while closed
+	openedAt time.Time // when the circuit last opened
+	probes   int       // probes in flight while half-open
+}
+
+// newCircuitBreaker returns a breaker with defaults applied, or nil if opts is
+// nil.
+func newCircuitBreaker(opts *CircuitBreakerOptions, clock Clock) *circuitBreaker {
+	if opts == nil {
+		return nil
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+	b := &circuitBreaker{opts: *opts, state: CircuitClosed, clock: clock}
+	if b.opts.FailureThreshold <= 0 {
+		b.opts.FailureThreshold = DefaultBreakerFailureThreshold
+	}
+	if b.opts.OpenTimeout <= 0 {
+		b.opts.OpenTimeout = DefaultBreakerOpenTimeout
+	}
+	if b.opts.HalfOpenProbes <= 0 {
+		b.opts.HalfOpenProbes = DefaultBreakerHalfOpenProbes
+	}
+	return b
+}
+
+// allow reports whether a request may be sent. If it may, the returned function
+// must be called with the outcome of the request.
+func (b *circuitBreaker) allow() (func(failed bool), error) {
+	if b == nil {
+		return func(bool) {}, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen {
+		retryAt := b.openedAt.Add(b.opts.OpenTimeout)
+		if b.clock.Now().Before(retryAt) {
+			return nil, &GoBitpinError{
+				Message: fmt.Sprintf("request not sent, retry after %s", retryAt.Format(time.RFC3339)),
+				Err:     ErrCircuitOpen,
+			}
+		}
+		b.transition(CircuitHalfOpen)
+	}
+
+	if b.state == CircuitHalfOpen {
+		if b.probes >= b.opts.HalfOpenProbes {
+			return nil, &GoBitpinError{
+				Message: "request not sent, waiting for probe requests",
+				Err:     ErrCircuitOpen,
+			}
+		}
+		b.probes++
+		return func(failed bool) { b.record(failed, true) }, nil
+	}
+	return func(failed bool) { b.record(failed, false) }, nil
+}
+
+// record updates the state with the outcome of a request.
+func (b *circuitBreaker) record(failed, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probes--
+	}
+
+	switch {
+	case !failed:
+		b.failures = 0
+		if b.state == CircuitHalfOpen {
+			b.transition(CircuitClosed)
+		}
+	case b.state == CircuitHalfOpen:
+		b.openedAt = b.clock.Now()
+		b.transition(CircuitOpen)
+	case b.state == CircuitClosed:
+		b.failures++
+		if b.failures >= b.opts.FailureThreshold {
+			b.openedAt = b.clock.Now()
+			b.transition(CircuitOpen)
+		}
+	}
+}
+
+// transition changes the state and notifies OnStateChange. b.mu must be held.
+func (b *circuitBreaker) transition(to CircuitState) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	if to != CircuitClosed {
+		b.failures = 0
+	}
+	if b.opts.OnStateChange != nil {
+		go b.opts.OnStateChange(from, to)
+	}
+}
+
+// breakerFailure reports whether the outcome of a request counts as a failure
+// of the API. Requests aborted by the caller's context do not count.
+func breakerFailure(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil || !errors.Is(err, ctx.Err())
+	}
+	return resp.StatusCode >= 500
+}
+
+// CircuitState returns the current state of the circuit breaker. It is always
+// CircuitClosed if no breaker is configured.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	if c.breaker.state == CircuitOpen && c.breaker.clock.Now().Sub(c.breaker.openedAt) >= c.breaker.opts.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return c.breaker.state
+}
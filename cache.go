@@ -0,0 +1,155 @@
+package bitpin
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// metadataCache caches the results of the market metadata endpoints, together
+// with the validators needed to revalidate them with conditional requests.
+type metadataCache struct {
+	mu sync.Mutex
+
+	markets    cacheEntry[t.Market]
+	currencies cacheEntry[t.Currency]
+}
+
+// cacheEntry is a cached list response.
+type cacheEntry[E any] struct {
+	items []E
+	at    time.Time
+
+	// etag and lastModified are the validators returned with the items.
+	etag         string
+	lastModified string
+}
+
+// fresh returns a copy of the cached items if they are younger than ttl at
+// now.
+func (e *cacheEntry[E]) fresh(ttl time.Duration, now time.Time) ([]E, bool) {
+	if ttl <= 0 || e.items == nil || now.Sub(e.at) > ttl {
+		return nil, false
+	}
+	return append([]E(nil), e.items...), true
+}
+
+// validators returns the conditional request headers for the cached items, or
+// nil if there is nothing to revalidate.
+func (e *cacheEntry[E]) validators() http.Header {
+	if e.items == nil || (e.etag == "" && e.lastModified == "") {
+		return nil
+	}
+	header := http.Header{}
+	if e.etag != "" {
+		header.Set("If-None-Match", e.etag)
+	}
+	if e.lastModified != "" {
+		header.Set("If-Modified-Since", e.lastModified)
+	}
+	return header
+}
+
+// store caches a copy of the given items, received at now, and the validators
+// of the response they came from.
+func (e *cacheEntry[E]) store(items []E, header http.Header, now time.Time) {
+	e.items = append([]E(nil), items...)
+	e.at = now
+	e.etag = header.Get("ETag")
+	e.lastModified = header.Get("Last-Modified")
+}
+
+// fetchMetadata returns the items of a metadata endpoint, served from entry
+// while they are younger than MetadataTTL. Once they are older, the request
+// carries the stored validators and a 304 Not Modified response renews the
+// cached items instead of downloading them again.
+func fetchMetadata[E any](ctx context.Context, c *Client, op Operation, entry *cacheEntry[E]) ([]E, error) {
+	c.metadata.mu.Lock()
+	if items, ok := entry.fresh(c.MetadataTTL, c.now()); ok {
+		c.metadata.mu.Unlock()
+		return items, nil
+	}
+	var header http.Header
+	if !c.DisableConditionalRequests {
+		header = entry.validators()
+	}
+	c.metadata.mu.Unlock()
+
+	endpoint := c.route(ctx, op)
+	url := c.createApiURI(endpoint.resolve(nil), endpoint.version())
+	raw, err := c.requestRaw(ctx, "GET", url, false, nil, header)
+	if err != nil {
+		return nil, err
+	}
+
+	c.metadata.mu.Lock()
+	if raw.StatusCode == http.StatusNotModified && entry.items != nil {
+		entry.at = c.now()
+		items := append([]E(nil), entry.items...)
+		c.metadata.mu.Unlock()
+		return items, nil
+	}
+	c.metadata.mu.Unlock()
+
+	if raw.StatusCode == http.StatusNotModified {
+		// The cache was invalidated while the request was in flight.
+		raw, err = c.requestRaw(ctx, "GET", url, false, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var items []E
+	var target interface{} = &items
+	finish := func() {}
+	if endpoint.version() == Version2 {
+		_, target, finish = adaptV2(nil, &items)
+	}
+	if err := u.UnmarshalLenient(raw.Body, target); err != nil {
+		return nil, &RequestError{
+			GoBitpinError: GoBitpinError{
+				Message: "failed to unmarshal response",
+				Err:     err,
+			},
+			Operation: "parsing response",
+			RequestID: raw.RequestID,
+		}
+	}
+	c.inspectDrift(url, raw.Body, target)
+	finish()
+
+	revalidate := !c.DisableConditionalRequests &&
+		(raw.Header.Get("ETag") != "" || raw.Header.Get("Last-Modified") != "")
+	if items != nil && (c.MetadataTTL > 0 || revalidate) {
+		c.metadata.mu.Lock()
+		entry.store(items, raw.Header, c.now())
+		c.metadata.mu.Unlock()
+	}
+	return items, nil
+}
+
+// InvalidateMetadata drops the cached markets and currencies, so the next call
+// to GetMarkets or GetCurrencies fetches fresh data from the API.
+func (c *Client) InvalidateMetadata() {
+	c.metadata.mu.Lock()
+	defer c.metadata.mu.Unlock()
+	c.metadata.markets = cacheEntry[t.Market]{}
+	c.metadata.currencies = cacheEntry[t.Currency]{}
+}
+
+// cachedMarket returns the cached metadata of a market without sending a
+// request, regardless of its age. It reports false if markets are not cached.
+func (c *Client) cachedMarket(symbol string) (t.Market, bool) {
+	c.metadata.mu.Lock()
+	defer c.metadata.mu.Unlock()
+	for _, market := range c.metadata.markets.items {
+		if u.SameSymbol(market.Symbol, symbol) {
+			return market, true
+		}
+	}
+	return t.Market{}, false
+}
@@ -0,0 +1,222 @@
+package bitpin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// CancelOutcome describes how an order ended after a cancel request.
+type CancelOutcome string
+
+const (
+	// CancelOutcomeCanceled means the order was cancelled without any fills.
+	CancelOutcomeCanceled CancelOutcome = "canceled"
+
+	// CancelOutcomePartiallyFilled means the order was cancelled after part of
+	// it was filled.
+	CancelOutcomePartiallyFilled CancelOutcome = "partially_filled"
+
+	// CancelOutcomeFilled means the order was completely filled before the
+	// cancel took effect.
+	CancelOutcomeFilled CancelOutcome = "filled"
+
+	// CancelOutcomeOther means the order reached another terminal state, such
+	// as "expired" or "rejected". See CancelResult.Order.State.
+	CancelOutcomeOther CancelOutcome = "other"
+)
+
+// CancelResult reports what actually happened to an order after a cancel.
+type CancelResult struct {
+	// Outcome summarizes the final state of the order.
+	Outcome CancelOutcome
+
+	// Order is the order in its terminal state.
+	Order *t.OrderStatus
+
+	// FilledBaseAmount is the total filled base amount of the order.
+	FilledBaseAmount string
+
+	// RacedBaseAmount is the base amount that was filled between the last
+	// status check before the cancel and the terminal state, i.e. fills that
+	// raced the cancel request. It is "0" when nothing raced.
+	RacedBaseAmount string
+
+	// AlreadyTerminal is true if the order was already filled or cancelled when
+	// the cancel was requested, so no cancel was sent or it was rejected.
+	AlreadyTerminal bool
+}
+
+// CancelOrderAndConfirm cancels an order and waits until the exchange confirms
+// that it reached a terminal state, reporting what actually happened.
+// `CancelOrder` returns as soon as the DELETE request succeeds, while the order
+// may only be flagged with `req_to_cancel` and can still be filled.
+//
+// Parameters:
+//   - ctx: Controls how long to wait for the confirmation.
+//   - orderId: The unique identifier of the order to cancel.
+//
+// Returns:
+//   - A pointer to a `CancelResult` with the outcome, the terminal order, and
+//     the fills that raced the cancel.
+//   - An error if a request fails or the context is done before the order
+//     reaches a terminal state.
+//
+// Behavior:
+//   - Fetches the order first and skips the cancel if it is already terminal.
+//   - Sends the DELETE request while holding the symbol lock of the order.
+//   - If the DELETE request fails, the order is fetched again; if it turns out
+//     to be terminal (e.g. it was filled in the meantime), the result is
+//     returned instead of the error.
+//   - Polls the order with `WaitForOrder` using the default interval until it
+//     is terminal.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	result, err := client.CancelOrderAndConfirm(ctx, 123456)
+//	if err != nil {
+//	    log.Fatalf("Cancel not confirmed: %v", err)
+//	}
+//	switch result.Outcome {
+//	case bitpin.CancelOutcomeFilled:
+//	    log.Printf("order filled before the cancel took effect")
+//	case bitpin.CancelOutcomePartiallyFilled:
+//	    log.Printf("cancelled after filling %s", result.FilledBaseAmount)
+//	}
+//
+// Dependencies:
+//   - Relies on `GetOrderWithContext`, `cancelOrderLocked` and `WaitForOrder`.
+func (c *Client) CancelOrderAndConfirm(ctx context.Context, orderId int) (*CancelResult, error) {
+	before, final, err := c.requestCancel(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	return c.confirmCancel(ctx, orderId, before, final)
+}
+
+// requestCancel fetches the order and cancels it unless it is already
+// terminal. It returns the order as seen before the cancel and, if the order
+// turned out to be terminal instead of being cancelled, its terminal state.
+func (c *Client) requestCancel(ctx context.Context, orderId int) (before, terminal *t.OrderStatus, err error) {
+	unlock, err := c.orderLocks.lock(ctx, c.orderSymbol(ctx, orderId))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	before, err = c.GetOrderWithContext(ctx, orderId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if IsTerminalOrderState(before.State) {
+		return before, before, nil
+	}
+	terminal, err = c.cancelActiveLocked(ctx, orderId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, terminal, nil
+}
+
+// cancelActiveLocked cancels an order that was just seen active. If the
+// cancel is rejected because the order reached a terminal state in the
+// meantime, that state is returned instead of the error. The caller must hold
+// the symbol lock of the order.
+func (c *Client) cancelActiveLocked(ctx context.Context, orderId int) (*t.OrderStatus, error) {
+	cancelErr := c.cancelOrderLocked(ctx, orderId)
+	if cancelErr == nil {
+		return nil, nil
+	}
+
+	// The exchange rejects cancels of orders that were filled in the meantime.
+	var apiErr *APIError
+	if !errors.As(cancelErr, &apiErr) {
+		return nil, cancelErr
+	}
+	after, err := c.GetOrderWithContext(ctx, orderId)
+	if err != nil || !IsTerminalOrderState(after.State) {
+		return nil, cancelErr
+	}
+	return after, nil
+}
+
+// confirmCancel waits until a cancelled order is terminal, unless its
+// terminal state is already known, and reports the outcome.
+func (c *Client) confirmCancel(ctx context.Context, orderId int, before, final *t.OrderStatus) (*CancelResult, error) {
+	alreadyTerminal := final != nil
+	if !alreadyTerminal {
+		var err error
+		final, err = c.WaitForOrder(ctx, orderId, WaitOptions{})
+		if err != nil {
+			return nil, &GoBitpinError{
+				Message: fmt.Sprintf("cancel of order %d not confirmed", orderId),
+				Err:     err,
+			}
+		}
+	}
+
+	result := &CancelResult{
+		Outcome:          cancelOutcome(final),
+		Order:            final,
+		FilledBaseAmount: final.DealedBaseAmount,
+		RacedBaseAmount:  amountDiff(final.DealedBaseAmount, before.DealedBaseAmount),
+		AlreadyTerminal:  alreadyTerminal,
+	}
+	if result.FilledBaseAmount == "" {
+		result.FilledBaseAmount = "0"
+	}
+	return result, nil
+}
+
+// cancelOutcome classifies a terminal order.
+func cancelOutcome(order *t.OrderStatus) CancelOutcome {
+	switch t.OrderState(strings.ToLower(string(order.State))) {
+	case t.StateCanceled, "cancelled":
+		if isZeroAmount(order.DealedBaseAmount) {
+			return CancelOutcomeCanceled
+		}
+		return CancelOutcomePartiallyFilled
+	case t.StateClosed, t.StateFilled, t.StateDone:
+		return CancelOutcomeFilled
+	default:
+		return CancelOutcomeOther
+	}
+}
+
+// amountDiff returns a - b for decimal amount strings, treating empty or
+// invalid amounts as zero.
+func amountDiff(a, b string) string {
+	x, ok := new(big.Rat).SetString(a)
+	if !ok {
+		x = new(big.Rat)
+	}
+	y, ok := new(big.Rat).SetString(b)
+	if !ok {
+		y = new(big.Rat)
+	}
+	diff := x.Sub(x, y)
+	if diff.Sign() < 0 {
+		diff.SetInt64(0)
+	}
+	return diff.FloatString(max(decimalPlaces(a), decimalPlaces(b)))
+}
+
+// amountSum returns a + b for decimal amount strings, treating empty or
+// invalid amounts as zero.
+func amountSum(a, b string) string {
+	x, ok := new(big.Rat).SetString(a)
+	if !ok {
+		x = new(big.Rat)
+	}
+	y, ok := new(big.Rat).SetString(b)
+	if !ok {
+		y = new(big.Rat)
+	}
+	return x.Add(x, y).FloatString(max(decimalPlaces(a), decimalPlaces(b)))
+}
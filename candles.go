@@ -0,0 +1,166 @@
+package bitpin
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rzabhd80/go-sdk-bitpin/bars"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// DefaultCandleHistory is the number of completed candles a CandleBuilder
+// keeps when no limit is given.
+const DefaultCandleHistory = 500
+
+// CandleBuilder aggregates public trades into OHLCV candles of any interval,
+// including intervals the exchange does not offer. Candles are aligned to
+// multiples of the interval since the Unix epoch, as in bars.TimeBars.
+//
+// Public trades carry no timestamp, so every trade is stamped with the time it
+// was observed. Candles are therefore accurate to the polling interval of the
+// trade source. It is safe for concurrent use.
+type CandleBuilder struct {
+	interval time.Duration
+	history  int
+
+	mu      sync.Mutex
+	agg     *bars.TimeBars
+	floor   time.Time // trades observed earlier are moved to this time
+	current time.Time // start of the candle in progress
+	open    bool
+	candles t.Candles
+}
+
+// NewCandleBuilder creates a builder of candles of the given interval that
+// keeps the last history completed candles. A non-positive history defaults
+// to DefaultCandleHistory.
+//
+// Example:
+//
+//	builder := bitpin.NewCandleBuilder(3*time.Minute, 0)
+//	for _, trade := range trades {
+//	    for _, candle := range builder.AddTrade(trade, observedAt) {
+//	        fmt.Printf("%s close %f volume %f\n", candle.Start, candle.Close, candle.Volume)
+//	    }
+//	}
+func NewCandleBuilder(interval time.Duration, history int) *CandleBuilder {
+	if history <= 0 {
+		history = DefaultCandleHistory
+	}
+	return &CandleBuilder{interval: interval, history: history, agg: bars.NewTimeBars(interval)}
+}
+
+// AddTrade adds a trade observed at the given time and returns the candles it
+// completed. Trades with malformed amounts are ignored.
+func (b *CandleBuilder) AddTrade(trade *t.Trade, at time.Time) []t.Candle {
+	tick, err := bars.TickFromTrade(trade, at)
+	if err != nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if at.Before(b.floor) {
+		// Keep completed candles final when trades arrive late.
+		at = b.floor
+		tick.Time = at
+	}
+	done := b.agg.Add(tick)
+	b.current, b.open = at.Truncate(b.interval), true
+	b.floor = b.current
+	b.record(done)
+	return done
+}
+
+// Advance completes the candle in progress if its interval has ended at now,
+// and returns it. Without it, a candle only completes when the first trade of
+// a later interval arrives.
+func (b *CandleBuilder) Advance(now time.Time) (t.Candle, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open || now.Before(b.current.Add(b.interval)) {
+		return t.Candle{}, false
+	}
+	candle, ok := b.agg.Flush()
+	b.open = false
+	b.floor = b.current.Add(b.interval)
+	if ok {
+		b.record([]t.Candle{candle})
+	}
+	return candle, ok
+}
+
+// Candles returns the completed candles, oldest first.
+func (b *CandleBuilder) Candles() t.Candles {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append(t.Candles(nil), b.candles...)
+}
+
+// record appends completed candles, dropping the oldest beyond the history.
+func (b *CandleBuilder) record(done []t.Candle) {
+	b.candles = append(b.candles, done...)
+	if extra := len(b.candles) - b.history; extra > 0 {
+		b.candles = append(t.Candles(nil), b.candles[extra:]...)
+	}
+}
+
+// Candles subscribes to candles of a market built from its trades channel.
+//
+// Parameters:
+//   - ctx: The subscription is closed when the context is done.
+//   - symbol: The market symbol, e.g. "BTC_IRT".
+//   - interval: The candle interval, e.g. 3*time.Minute.
+//
+// Returns:
+//   - A `Subscription` delivering every candle once its interval has ended.
+//     Intervals without trades produce no candle.
+//   - An error if the symbol is empty, the interval is not positive, or the
+//     stream is closed.
+//
+// Behavior:
+//   - Trades are stamped with the time the stream observed them, so candle
+//     boundaries are accurate to StreamOptions.Interval.
+//   - A candle is delivered at the end of its interval even if no later trade
+//     arrives.
+//
+// Example:
+//
+//	sub, err := stream.Candles(ctx, "BTC_IRT", 3*time.Minute)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for candle := range sub.C {
+//	    log.Printf("%s O %f H %f L %f C %f V %f", candle.Start, candle.Open, candle.High, candle.Low, candle.Close, candle.Volume)
+//	}
+func (s *Stream) Candles(ctx context.Context, symbol string, interval time.Duration) (*Subscription[t.Candle], error) {
+	if interval <= 0 {
+		return nil, &GoBitpinError{Message: "candle interval must be positive"}
+	}
+	builder := NewCandleBuilder(interval, 1)
+	channel := StreamChannel{Kind: StreamTrades, Symbol: symbol}
+	sub, err := subscribeFunc(ctx, s, channel, func(event StreamEvent, push func(t.Candle)) {
+		for _, candle := range builder.AddTrade(event.Trade, event.Time) {
+			push(candle)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for {
+			next := s.client.now().Truncate(interval).Add(interval)
+			select {
+			case <-sub.stop:
+				return
+			case now := <-s.client.after(next.Sub(s.client.now())):
+				if candle, ok := builder.Advance(now); ok {
+					sub.push(candle)
+				}
+			}
+		}
+	}()
+	return sub, nil
+}
@@ -2,12 +2,19 @@ package bitpin
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	t "github.com/rzabhd80/go-sdk-bitpin/types"
@@ -34,10 +41,64 @@ type ClientOptions struct {
 	// Timeout specifies the request timeout duration for the HTTP client.
 	Timeout time.Duration
 
+	// Transport is the transport used by the default HTTP client, for example
+	// a tuned *http.Transport. It is ignored when HttpClient is set. When nil,
+	// a clone of http.DefaultTransport is used if any of the tuning options
+	// below are set, and http.DefaultTransport otherwise.
+	Transport http.RoundTripper
+
+	// MaxIdleConnsPerHost is the number of idle keep-alive connections kept
+	// per host. Raising it above the Go default of 2 avoids new TCP and TLS
+	// handshakes when many requests are issued concurrently.
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout is how long an idle keep-alive connection is kept open.
+	IdleConnTimeout time.Duration
+
+	// TLSHandshakeTimeout limits the time spent on TLS handshakes.
+	TLSHandshakeTimeout time.Duration
+
+	// TLS configures the CA certificates, minimum version and pinned keys of
+	// the default transport. It is ignored when HttpClient or Transport is
+	// set. Nil keeps the Go defaults.
+	TLS *TLSOptions
+
+	// DialContext dials the connections of the default transport instead of
+	// a net.Dialer, for example to route them through a specific interface or
+	// tunnel, or to resolve names with DNS over HTTPS. It is ignored when
+	// HttpClient or Transport is set.
+	DialContext DialFunc
+
+	// Resolver resolves host names for the default transport, for example a
+	// DNSServerResolver when the system resolver is unreliable. It cannot be
+	// combined with DialContext, which resolves names itself.
+	Resolver *net.Resolver
+
+	// PinnedHosts maps host names to the IP addresses they are dialed at,
+	// bypassing DNS, such as {"api.bitpin.ir": "203.0.113.10"}. Certificates
+	// are still verified against the host name.
+	PinnedHosts map[string]string
+
+	// DisableCompression stops the client from requesting gzip-compressed
+	// responses. Compression is on by default, which noticeably shrinks large
+	// payloads such as GetTickers and GetMarkets.
+	DisableCompression bool
+
+	// DisableConditionalRequests stops GetMarkets and GetCurrencies from
+	// revalidating their last response with If-None-Match and
+	// If-Modified-Since. When enabled (the default), a 304 Not Modified
+	// response returns the previously received data.
+	DisableConditionalRequests bool
+
 	// BaseUrl is the base URL of the API. Defaults to the constant BaseUrl
 	// if not provided.
 	BaseUrl string
 
+	// Endpoints overrides the path and version of operations, for example to
+	// follow an endpoint the API moved before the SDK is updated. Operations
+	// that are not listed keep their entry in DefaultEndpoints.
+	Endpoints map[Operation]Endpoint
+
 	// AccessToken is the token used for authenticated API requests.
 	AccessToken string
 
@@ -50,11 +111,140 @@ type ClientOptions struct {
 	// SecretKey is the secret key for authentication.
 	SecretKey string
 
+	// Credentials supplies the API key and secret key on demand, instead of
+	// ApiKey and SecretKey. The client asks it every time it authenticates,
+	// so the secrets are not held by the caller or the client, and rotated
+	// secrets are picked up.
+	Credentials CredentialProvider
+
 	// AutoAuth enables automatic authentication if no valid tokens are provided.
 	AutoAuth bool
 
-	// AutoRefresh enables automatic refreshing of the access token when it expires.
+	// AutoRefresh enables automatic refreshing of the access token when it
+	// expires, and renewing the tokens and retrying once when an authenticated
+	// request is rejected with 401 Unauthorized.
 	AutoRefresh bool
+
+	// OnDeprecatedCall is invoked every time a deprecated method is called on the
+	// client. It can be used to log or record remaining call sites before the
+	// deprecated methods are removed. Usage is counted even when nil.
+	OnDeprecatedCall func(notice DeprecationNotice)
+
+	// OnTokenRefresh is invoked whenever the client obtains new tokens, by
+	// authenticating or by refreshing, with the claims of the previous and the
+	// new tokens. It can be used to persist tokens, record metrics or alert
+	// when tokens are renewed more often than expected. It runs before the
+	// request that triggered the renewal continues, so it should return
+	// quickly. Clients derived with WithOverrides share the hook of their
+	// parent, which owns the tokens.
+	OnTokenRefresh func(event TokenRefreshEvent)
+
+	// OnRequest is invoked after every request sent to the API, including
+	// failed ones, with its request ID, status and duration. It can be used to
+	// log or measure API calls. It must not block.
+	OnRequest func(event RequestEvent)
+
+	// Debug logs a dump of every request and its response to Logger at debug
+	// level: the method, URL, headers, bodies and duration. Authorization
+	// headers, cookies, credentials and tokens are masked. Dumps can still
+	// contain account data such as balances and orders, so Debug should not
+	// be left on in production.
+	Debug bool
+
+	// Logger receives the dumps of Debug. Defaults to a text logger writing to
+	// standard error.
+	Logger *slog.Logger
+
+	// TraceRequests measures the network phases of every request (DNS,
+	// connect, TLS handshake and time to first byte) with net/http/httptrace.
+	// The timings are reported in RequestEvent.Timings and in the dumps of
+	// Debug.
+	TraceRequests bool
+
+	// Clock is the source of time for token expiry checks, retry delays, the
+	// circuit breaker, the order throttle and the rate-limit status. Defaults
+	// to SystemClock. Tests can inject a fake clock to control time.
+	Clock Clock
+
+	// UseAPIv2 sends the operations listed by V2Operations to the v2 API.
+	// Responses are converted to the v1 types, so existing code keeps working.
+	// ContextWithAPIVersion selects the version of a single call instead.
+	UseAPIv2 bool
+
+	// ClientTrace is attached to every request, for callers that need the raw
+	// httptrace hooks. Its hooks run in addition to the timing hooks of
+	// TraceRequests and to any trace already carried by the context.
+	ClientTrace *httptrace.ClientTrace
+
+	// TokenStorage persists tokens between runs. Stored tokens are loaded when
+	// AccessToken and RefreshToken are not given, and new tokens are saved after
+	// every authentication and refresh.
+	TokenStorage TokenStorage
+
+	// Failover lists mirrors of the base URL that requests are sent to while
+	// it is unreachable, and how long an unreachable URL is skipped. Nil
+	// disables failover.
+	Failover *FailoverOptions
+
+	// CircuitBreaker enables a circuit breaker that fails requests locally
+	// with ErrCircuitOpen after repeated API failures, instead of sending them
+	// to an API that is down. Nil disables it.
+	CircuitBreaker *CircuitBreakerOptions
+
+	// BalanceCheck enables a pre-order check that fails orders locally with
+	// ErrInsufficientBalance when the free balance, taken from recently
+	// fetched wallets, does not cover them. Nil disables it.
+	BalanceCheck *BalanceCheckOptions
+
+	// OrderThrottle limits the open orders per market and the rate at which
+	// orders are submitted on a market. Orders it rejects fail locally with
+	// an OrderThrottledError wrapping ErrOrderThrottled. Nil disables it.
+	OrderThrottle *OrderThrottleOptions
+
+	// Retry retries GET requests that fail with a retryable error, such as a
+	// network error or a 5xx response. Nil disables retries.
+	Retry *RetryOptions
+
+	// EgressIPCheck enables a check, run by NewClient after the client has
+	// tokens, that the public IP address of the client is in the IP allowlist
+	// of its access token. Nil disables it.
+	EgressIPCheck *EgressIPCheckOptions
+
+	// FetchConcurrency limits the number of parallel requests made by
+	// multi-symbol helpers such as GetOrderBooks. Defaults to
+	// DefaultFetchConcurrency.
+	FetchConcurrency int
+
+	// MetadataTTL enables caching of GetMarkets and GetCurrencies results for
+	// the given duration. Cached metadata is also used by helpers that need
+	// market precision. Zero disables the cache.
+	MetadataTTL time.Duration
+
+	// DetectDrift enables the API drift detector, which records unknown
+	// response fields, unknown enum values and links to unknown endpoints.
+	// See DriftReport.
+	DetectDrift bool
+
+	// OnDrift is invoked the first time each API behavior difference is
+	// detected. It is only used when DetectDrift is enabled.
+	OnDrift func(finding DriftFinding)
+
+	// TranslateErrors replaces Persian messages in API errors with normalized
+	// English ones. The original messages stay available in
+	// APIError.OriginalDetails.
+	TranslateErrors bool
+
+	// ErrorTranslations adds translations of Persian error messages, keyed by
+	// a phrase the message contains. They take precedence over the built-in
+	// table and are only used when TranslateErrors is set.
+	ErrorTranslations map[string]string
+
+	// disableAuth rejects authenticated requests; see WithoutAuth.
+	disableAuth bool
+
+	// explicit records the boolean settings given by options, so WithOverrides
+	// can tell a setting that was turned off from one that was not given.
+	explicit optionFlag
 }
 
 // Client represents the API client for interacting with the Bitpin Market API.
@@ -68,7 +258,9 @@ type Client struct {
 	// Defaults to the constant BaseUrl.
 	BaseUrl string
 
-	// AccessToken is the token used for authenticated API requests.
+	// AccessToken is the token used for authenticated API requests. The client
+	// replaces it when it renews its tokens, so while requests are in flight
+	// read the tokens with TokenInfo or the OnTokenRefresh hook instead.
 	AccessToken string
 
 	// RefreshToken is the token used to obtain a new AccessToken when it expires.
@@ -80,8 +272,133 @@ type Client struct {
 	// SecretKey is the secret key for authentication.
 	SecretKey string
 
-	// AutoRefresh enables automatic refreshing of the access token when it expires.
+	// AutoRefresh enables automatic refreshing of the access token when it
+	// expires, and renewing the tokens and retrying once when an authenticated
+	// request is rejected with 401 Unauthorized.
 	AutoRefresh bool
+
+	// OnDeprecatedCall is invoked every time a deprecated method is called.
+	OnDeprecatedCall func(notice DeprecationNotice)
+
+	// OnTokenRefresh is invoked whenever the tokens change.
+	OnTokenRefresh func(event TokenRefreshEvent)
+
+	// OnRequest is invoked after every request sent to the API.
+	OnRequest func(event RequestEvent)
+
+	// Debug logs sanitized dumps of every request and response.
+	Debug bool
+
+	// TraceRequests measures the network phases of every request.
+	TraceRequests bool
+
+	// UseAPIv2 sends the operations available in the v2 API to it.
+	UseAPIv2 bool
+
+	// ClientTrace is attached to every request. It is optional.
+	ClientTrace *httptrace.ClientTrace
+
+	// TokenStorage persists tokens between runs. It is optional.
+	TokenStorage TokenStorage
+
+	// DisableCompression stops the client from requesting gzip-compressed responses.
+	DisableCompression bool
+
+	// DisableConditionalRequests stops the client from revalidating cached
+	// metadata with ETag and Last-Modified validators.
+	DisableConditionalRequests bool
+
+	// FetchConcurrency limits the parallel requests of multi-symbol helpers.
+	FetchConcurrency int
+
+	// MetadataTTL is how long market and currency metadata is cached.
+	// Zero disables the cache.
+	MetadataTTL time.Duration
+
+	// DetectDrift enables recording of API behavior differences.
+	DetectDrift bool
+
+	// OnDrift is invoked the first time each API behavior difference is detected.
+	OnDrift func(finding DriftFinding)
+
+	// TranslateErrors replaces Persian messages in API errors with English ones.
+	TranslateErrors bool
+
+	// ErrorTranslations adds translations of Persian error messages.
+	ErrorTranslations map[string]string
+
+	// deprecations tracks how often each deprecated method has been called.
+	deprecations deprecationTracker
+
+	// orderLocks serializes order operations per symbol. It is shared with
+	// derived clients; a nil orderLocks does not serialize anything.
+	orderLocks *symbolLocks
+
+	// metadata caches markets and currencies and their validators.
+	metadata metadataCache
+
+	// drift collects API behavior differences when DetectDrift is set.
+	drift driftDetector
+
+	// rateLimit tracks the rate-limit headers of responses.
+	rateLimit rateLimitTracker
+
+	// breaker fails requests fast during outages. It is nil when disabled.
+	breaker *circuitBreaker
+
+	// failover moves requests to mirrors of the base URL while it is
+	// unreachable. It is nil when disabled.
+	failover *failover
+
+	// skew tracks the offset between the local and the API clock.
+	skew clockTracker
+
+	// clock is the source of time of the client. Nil selects SystemClock.
+	clock Clock
+
+	// balances checks orders against cached wallets. It is nil when disabled.
+	balances *balanceChecker
+
+	// throttle guards order submissions per market. It is nil when disabled.
+	throttle *orderThrottle
+
+	// retry configures retries of failed requests. It is nil when disabled.
+	retry *RetryOptions
+
+	// logger receives the dumps of Debug. Nil selects the default logger.
+	logger *slog.Logger
+
+	// egressCheck configures CheckEgressIP. It is nil unless EgressIPCheck
+	// was given.
+	egressCheck *EgressIPCheckOptions
+
+	// egressClient sends the lookups of CheckEgressIP when HttpClient pins
+	// the API certificate, which the lookup service cannot match. It is nil
+	// otherwise, and HttpClient is used.
+	egressClient *http.Client
+
+	// credentials supplies the API key and secret key when ApiKey and
+	// SecretKey are not set. It is optional.
+	credentials CredentialProvider
+
+	// endpoints maps operations to their path and version. Nil selects
+	// DefaultEndpoints.
+	endpoints map[Operation]Endpoint
+
+	// owner holds the tokens of a client derived with WithOverrides. It is nil
+	// for clients that hold their own tokens.
+	owner *Client
+
+	// tokenMu guards AccessToken and RefreshToken of a client that holds its
+	// own tokens.
+	tokenMu sync.RWMutex
+
+	// renewMu lets a single request renew the tokens at a time. The others
+	// wait for it and then use the renewed tokens.
+	renewMu sync.Mutex
+
+	// noAuth rejects authenticated requests with ErrAuthDisabled.
+	noAuth bool
 }
 
 // NewClient initializes a new API client with the provided options.
@@ -99,12 +416,24 @@ type Client struct {
 //     auto-refresh errors.
 //
 // Behavior:
+//   - Options that contradict each other are accepted for compatibility, and
+//     a warning is logged to `opts.Logger`. Settings that have no effect, such
+//     as `opts.TLS` or `opts.Timeout` next to a custom `opts.HttpClient`, are
+//     ignored. NewClientWithOptions rejects them instead.
 //   - If `opts.BaseUrl` is provided, it overrides the default `BaseUrl`.
+//   - If `opts.Failover` is provided, requests move to its mirrors while the
+//     base URL is unreachable; see ActiveBaseURL.
 //   - If `opts.HttpClient` is not provided, a default HTTP client with the
-//     specified timeout is created.
+//     specified timeout is created. Its transport is `opts.Transport`, or a
+//     clone of `http.DefaultTransport` tuned by the connection pool options.
 //   - AccessToken and RefreshToken are set from the options.
 //   - If `AutoRefresh` is enabled, the client attempts to refresh tokens on initialization.
-//   - If both `ApiKey` and `SecretKey` are provided, the client attempts to authenticate.
+//   - If `TokenStorage` is set, stored tokens fill in missing AccessToken and
+//     RefreshToken values.
+//   - If both `ApiKey` and `SecretKey` are provided, the client attempts to authenticate,
+//     unless a complete set of tokens was restored from `TokenStorage`.
+//   - If `EgressIPCheck` is set and the client has an access token, its
+//     egress IP is checked against the token IP allowlist; see CheckEgressIP.
 //
 // Example:
 //
@@ -119,41 +448,136 @@ type Client struct {
 //	    log.Fatalf("Failed to create client: %v", err)
 //	}
 func NewClient(opts ClientOptions) (*Client, error) {
+	if err := validateClientOptions(&opts); err != nil {
+		debugLogger(opts.Logger).LogAttrs(context.Background(), slog.LevelWarn, "bitpin client options conflict, conflicting settings are ignored",
+			slog.String("error", err.Error()))
+	}
 	client := &Client{
-		AutoRefresh: opts.AutoRefresh,
-		BaseUrl:     BaseUrl,
+		AutoRefresh:                opts.AutoRefresh,
+		BaseUrl:                    BaseUrl,
+		OnDeprecatedCall:           opts.OnDeprecatedCall,
+		TokenStorage:               opts.TokenStorage,
+		MetadataTTL:                opts.MetadataTTL,
+		FetchConcurrency:           opts.FetchConcurrency,
+		DetectDrift:                opts.DetectDrift,
+		DisableCompression:         opts.DisableCompression,
+		DisableConditionalRequests: opts.DisableConditionalRequests,
+		OnDrift:                    opts.OnDrift,
+		OnRequest:                  opts.OnRequest,
+		OnTokenRefresh:             opts.OnTokenRefresh,
+		Debug:                      opts.Debug,
+		TraceRequests:              opts.TraceRequests,
+		UseAPIv2:                   opts.UseAPIv2,
+		ClientTrace:                opts.ClientTrace,
+		TranslateErrors:            opts.TranslateErrors,
+		ErrorTranslations:          opts.ErrorTranslations,
 	}
+	client.clock = opts.Clock
+	client.drift.since = client.now()
+	client.breaker = newCircuitBreaker(opts.CircuitBreaker, client.clock)
+	client.balances = newBalanceChecker(opts.BalanceCheck, client.clock)
+	client.throttle = newOrderThrottle(opts.OrderThrottle, client.clock)
+	client.retry = newRetryOptions(opts.Retry)
+	client.orderLocks = &symbolLocks{}
+	client.logger = opts.Logger
+	client.egressCheck = opts.EgressIPCheck
+	client.endpoints = newEndpoints(opts.Endpoints)
+	client.noAuth = opts.disableAuth
 
 	if opts.BaseUrl != "" {
 		client.BaseUrl = opts.BaseUrl
 	}
+	client.failover = newFailover(client.BaseUrl, opts.Failover, client.clock)
 
 	if opts.HttpClient != nil {
 		client.HttpClient = opts.HttpClient
 	} else {
+		transport, err := newTransport(opts)
+		if err != nil {
+			return nil, err
+		}
 		client.HttpClient = &http.Client{
-			Timeout: opts.Timeout,
+			Timeout:   opts.Timeout,
+			Transport: transport,
 		}
 	}
+	egressClient, err := newEgressClient(opts, client.HttpClient)
+	if err != nil {
+		return nil, err
+	}
+	client.egressClient = egressClient
 
 	client.AccessToken = opts.AccessToken
 	client.RefreshToken = opts.RefreshToken
 	client.ApiKey = opts.ApiKey
 	client.SecretKey = opts.SecretKey
+	client.credentials = opts.Credentials
+
+	if err := client.loadTokens(); err != nil {
+		return nil, err
+	}
+	restored := opts.TokenStorage != nil && client.AccessToken != "" && client.RefreshToken != ""
 
-	if err := client.handleAutoRefresh(); err != nil {
+	if err := client.handleAutoRefresh(context.Background()); err != nil {
 		return nil, err
 	}
 
-	if opts.ApiKey != "" && opts.SecretKey != "" {
-		if _, err := client.Authenticate(opts.ApiKey, opts.SecretKey); err != nil {
+	if client.hasCredentials() && !restored && !opts.disableAuth {
+		if err := client.reauthenticate(context.Background()); err != nil {
 			return nil, err
 		}
 	}
 
+	if err := client.checkEgressIP(context.Background()); err != nil {
+		return nil, err
+	}
+
 	return client, nil
 }
 
+// newTransport returns the transport of the default HTTP client: the
+// configured transport, a tuned clone of http.DefaultTransport, or nil to use
+// http.DefaultTransport unchanged. It fails if the TLS options are invalid.
+func newTransport(opts ClientOptions) (http.RoundTripper, error) {
+	if opts.Transport != nil {
+		return opts.Transport, nil
+	}
+	if !tunesTransport(opts) {
+		return nil, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+		if transport.MaxIdleConns > 0 && transport.MaxIdleConns < opts.MaxIdleConnsPerHost {
+			transport.MaxIdleConns = opts.MaxIdleConnsPerHost
+		}
+	}
+	if opts.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	if opts.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = opts.TLSHandshakeTimeout
+	}
+	if opts.TLS != nil {
+		config, err := newTLSConfig(opts.TLS)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = config
+	}
+	if dial := newDialer(opts); dial != nil {
+		transport.DialContext = dial
+	}
+	return transport, nil
+}
+
+// tunesTransport reports whether opts change the default transport.
+func tunesTransport(opts ClientOptions) bool {
+	return opts.MaxIdleConnsPerHost != 0 || opts.IdleConnTimeout != 0 || opts.TLSHandshakeTimeout != 0 || opts.TLS != nil ||
+		opts.DialContext != nil || opts.Resolver != nil || len(opts.PinnedHosts) > 0
+}
+
 // assertAuth checks the authentication state of the given client by verifying
 // the presence of both the access token and the refresh token.
 //
@@ -176,13 +600,14 @@ func NewClient(opts ClientOptions) (*Client, error) {
 //   - If `client.RefreshToken` is empty, returns an error: "refresh token is empty".
 //   - Otherwise, returns nil to indicate the client is authenticated.
 func assertAuth(client *Client) error {
-	if client.AccessToken == "" {
+	access, refresh := client.tokens()
+	if access == "" {
 		return &GoBitpinError{
 			Message: "access token is empty",
 			Err:     nil,
 		}
 	}
-	if client.RefreshToken == "" {
+	if refresh == "" {
 		return &GoBitpinError{
 			Message: "refresh token is empty",
 			Err:     nil,
@@ -230,7 +655,8 @@ func (c *Client) createApiURI(endpoint string, version string) string {
 //     or re-authenticating. Returns nil if all tokens are valid or successfully refreshed.
 //
 // Behavior:
-//   - If the access token is provided, it is decoded and checked for expiration.
+//   - If the access token is provided, it is decoded and checked for expiration
+//     against the API's clock (see `Now`), so local clock skew is corrected.
 //   - If expired, the `RefreshAccessToken` method is called to refresh it.
 //   - If the refresh token is provided, it is decoded and checked for expiration.
 //   - If expired, and API credentials (`ApiKey` and `SecretKey`) are available,
@@ -239,7 +665,7 @@ func (c *Client) createApiURI(endpoint string, version string) string {
 //
 // Example:
 //
-//	err := client.handleAutoRefresh()
+//	err := client.handleAutoRefresh(ctx)
 //	if err != nil {
 //	    log.Fatalf("Auto-refresh failed: %v", err)
 //	}
@@ -255,13 +681,21 @@ func (c *Client) createApiURI(endpoint string, version string) string {
 //   - "error decoding refresh token: %v" if the refresh token cannot be decoded.
 //   - "API key and/or secret key are empty" if re-authentication is required but credentials are missing.
 //   - "error re-authenticating: %v" if re-authentication fails.
-func (c *Client) handleAutoRefresh() error {
-	if c.AccessToken != "" {
-		decoded, err := u.DecodeJWT(c.AccessToken)
+func (c *Client) handleAutoRefresh(ctx context.Context) error {
+	if renew, err := c.tokensExpired(); err != nil || !renew {
+		return err
+	}
+
+	// Renew once for all the requests that found the tokens expired, and
+	// check again in case another request renewed them while this one waited.
+	c.renewMu.Lock()
+	defer c.renewMu.Unlock()
+	if access, _ := c.tokens(); access != "" {
+		expired, err := tokenExpired(access, c.Now())
 		if err != nil {
 			return err
 		}
-		if decoded.IsExpired() {
+		if expired {
 			err = c.RefreshAccessToken()
 			if err != nil {
 				return err
@@ -269,22 +703,13 @@ func (c *Client) handleAutoRefresh() error {
 		}
 	}
 
-	if c.RefreshToken != "" {
-		decoded, err := u.DecodeJWT(c.RefreshToken)
+	if _, refresh := c.tokens(); refresh != "" {
+		expired, err := tokenExpired(refresh, c.Now())
 		if err != nil {
 			return err
 		}
-
-		if decoded.IsExpired() {
-			if c.ApiKey == "" || c.SecretKey == "" {
-				return &GoBitpinError{
-					Message: "API key and/or secret key are empty",
-					Err:     nil,
-				}
-			}
-
-			_, err = c.Authenticate(c.ApiKey, c.SecretKey)
-			if err != nil {
+		if expired {
+			if err := c.reauthenticate(ctx); err != nil {
 				return err
 			}
 		}
@@ -293,8 +718,64 @@ func (c *Client) handleAutoRefresh() error {
 	return nil
 }
 
+// tokensExpired reports whether the access token or the refresh token of the
+// client has expired.
+func (c *Client) tokensExpired() (bool, error) {
+	access, refresh := c.tokens()
+	for _, token := range []string{access, refresh} {
+		if token == "" {
+			continue
+		}
+		if expired, err := tokenExpired(token, c.Now()); err != nil || expired {
+			return expired, err
+		}
+	}
+	return false, nil
+}
+
+// tokenExpired reports whether token had expired at now.
+func tokenExpired(token string, now time.Time) (bool, error) {
+	decoded, err := u.DecodeJWT(token)
+	if err != nil {
+		return false, err
+	}
+	return decoded.IsExpiredAt(now), nil
+}
+
+// renewAfterUnauthorized renews the tokens after a request authenticated with
+// the access token used was rejected with 401 Unauthorized. If the access token
+// has been replaced since, for example by a concurrent request, nothing is
+// done. Otherwise the access token is refreshed, and if that fails the client
+// re-authenticates with its API key and secret key or its CredentialProvider.
+// Renewals are serialized, so concurrent rejected requests renew only once.
+func (c *Client) renewAfterUnauthorized(ctx context.Context, used string) error {
+	c.renewMu.Lock()
+	defer c.renewMu.Unlock()
+	access, refresh := c.tokens()
+	if access != used {
+		return nil
+	}
+
+	var refreshErr error
+	if refresh != "" {
+		if refreshErr = c.RefreshAccessToken(); refreshErr == nil {
+			if access, _ = c.tokens(); access != used {
+				return nil
+			}
+		}
+	}
+
+	if !c.hasCredentials() {
+		return &GoBitpinError{
+			Message: ErrMissingCredentials.Message,
+			Err:     refreshErr,
+		}
+	}
+	return c.reauthenticate(ctx)
+}
+
 // Request sends an HTTP request to the specified URL and handles the response.
-// It supports both GET and POST methods, optional authentication, and automatic
+// It supports any HTTP method, optional authentication, and automatic
 // token refresh. The request body can be serialized from a struct, and the response
 // can be unmarshaled into a given result object.
 //
@@ -304,8 +785,9 @@ func (c *Client) handleAutoRefresh() error {
 //   - auth: A boolean indicating whether the request requires authentication.
 //     If true, the method adds an Authorization header with the access token
 //     and handles automatic token refresh if enabled.
-//   - body: An optional request body. For GET requests, it is converted into URL
-//     parameters; for POST requests, it is marshaled to JSON.
+//   - body: An optional request body. For GET and HEAD requests, it is converted
+//     into URL parameters; for other methods, such as POST, PUT, PATCH and
+//     DELETE, it is marshaled to JSON.
 //   - result: A pointer to a variable where the response body should be unmarshaled.
 //     If nil, the response body is not unmarshaled.
 //
@@ -323,10 +805,15 @@ func (c *Client) handleAutoRefresh() error {
 //	}
 //
 // Behavior:
-//   - For GET requests, the body is converted into URL parameters using `StructToURLParams`.
-//   - For POST requests, the body is marshaled to JSON.
+//   - For GET requests, the body is converted into URL parameters using `StructToURLParams`,
+//     or its own `EncodeValues` method if it implements `utils.ParamEncoder`.
+//   - For POST, PUT, PATCH, DELETE and other methods, the body is marshaled to JSON.
+//     A `*MultipartForm` body is streamed as multipart/form-data instead.
 //   - Adds the `Authorization` header if `auth` is true and the client has valid tokens.
 //   - Refreshes tokens automatically if `AutoRefresh` is enabled and tokens are expired.
+//   - If `AutoRefresh` is enabled and the API rejects the access token with 401
+//     Unauthorized, renews the tokens (refreshing, or re-authenticating with the
+//     API key and secret key) and retries the request once.
 //   - Handles non-2xx HTTP responses by returning an `APIError` containing the status
 //     code and error message.
 //   - Unmarshals the response body into the `result` parameter if provided.
@@ -347,115 +834,332 @@ func (c *Client) handleAutoRefresh() error {
 //
 // Request sends an HTTP request to the specified URL and handles the response
 func (c *Client) Request(method string, url string, auth bool, body interface{}, result interface{}) error {
+	return c.RequestWithContext(context.Background(), method, url, auth, body, result)
+}
+
+// RequestWithContext is like `Request` but carries a context. The context
+// controls cancellation and deadlines of the underlying HTTP request.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	var result MyResponseStruct
+//	err := client.RequestWithContext(ctx, "GET", "https://api.example.com/resource", false, nil, &result)
+func (c *Client) RequestWithContext(ctx context.Context, method string, url string, auth bool, body interface{}, result interface{}) error {
+	raw, err := c.RequestRawWithContext(ctx, method, url, auth, body)
+	if err != nil {
+		return err
+	}
+
+	if result != nil {
+		if err = u.UnmarshalLenient(raw.Body, result); err != nil {
+			return &RequestError{
+				GoBitpinError: GoBitpinError{
+					Message: "failed to unmarshal response",
+					Err:     err,
+				},
+				Operation: "parsing response",
+				RequestID: raw.RequestID,
+			}
+		}
+		c.inspectDrift(url, raw.Body, result)
+	}
+
+	return nil
+}
+
+// RequestRaw sends an HTTP request to the specified URL and returns the untouched
+// response instead of unmarshaling it. It accepts the same arguments as `Request`
+// and applies the same body encoding, authentication, and token refresh rules.
+//
+// Parameters:
+//   - method: The HTTP method for the request, such as "GET" or "POST".
+//   - url: The full URL for the API endpoint.
+//   - auth: A boolean indicating whether the request requires authentication.
+//   - body: An optional request body. For GET and HEAD requests, it is converted
+//     into URL parameters; for other methods, such as POST, PUT, PATCH and
+//     DELETE, it is marshaled to JSON.
+//
+// Returns:
+//   - A pointer to a `RawResponse` holding the status code, headers, and body of
+//     the response. It is nil only when no response was received.
+//   - An error if the request could not be sent or read, or an `APIError` if the
+//     response status code indicates an error (non-2xx). In the latter case the
+//     `RawResponse` is still returned so the payload can be inspected.
+//
+// Example:
+//
+//	raw, err := client.RequestRaw("GET", "https://api.bitpin.ir/api/v1/mkt/tickers/", false, nil)
+//	if err != nil {
+//	    log.Fatalf("API request failed: %v", err)
+//	}
+//	fmt.Printf("Status: %d, Body: %s\n", raw.StatusCode, raw.Body)
+func (c *Client) RequestRaw(method string, url string, auth bool, body interface{}) (*RawResponse, error) {
+	return c.RequestRawWithContext(context.Background(), method, url, auth, body)
+}
+
+// RequestRawWithContext is like `RequestRaw` but carries a context. The context
+// controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) RequestRawWithContext(ctx context.Context, method string, url string, auth bool, body interface{}) (*RawResponse, error) {
+	return c.requestRaw(ctx, method, url, auth, body, nil)
+}
+
+// requestRaw implements RequestRawWithContext. The given header is added to the
+// request; when it carries conditional headers, a 304 Not Modified response is
+// returned without an error.
+//
+// Every call is sent with a request ID, taken from the context or generated.
+//
+// If AutoRefresh is enabled and an authenticated request is answered with 401
+// Unauthorized, the tokens are renewed and the request is sent once more. The
+// original 401 error is returned if the renewal fails.
+func (c *Client) requestRaw(ctx context.Context, method string, url string, auth bool, body interface{}, header http.Header) (*RawResponse, error) {
+	ctx = withRequestID(ctx)
+	raw, used, err := c.sendRetrying(ctx, method, url, auth, body, header)
+	if !auth || !c.AutoRefresh || raw == nil || raw.StatusCode != http.StatusUnauthorized || ctx.Err() != nil {
+		return raw, err
+	}
+	if renewErr := c.tokenOwner().renewAfterUnauthorized(ctx, used); renewErr != nil || !replayable(body) {
+		return raw, err
+	}
+	raw, _, err = c.sendRetrying(ctx, method, url, auth, body, header)
+	return raw, err
+}
+
+// sendRaw sends a single request. It also returns the access token the request
+// was authenticated with, which is empty for public requests.
+func (c *Client) sendRaw(ctx context.Context, method string, url string, auth bool, body interface{}, header http.Header) (*RawResponse, string, error) {
 	var reqBody []byte
+	var stream io.Reader
+	var used string
 	var err error
+	id := RequestIDFromContext(ctx)
+	contentType := "application/json"
+
+	form, isForm := body.(*MultipartForm)
+	if isForm && sendsQuery(method) {
+		return nil, "", &RequestError{
+			GoBitpinError: GoBitpinError{Message: fmt.Sprintf("a multipart form cannot be sent with %s", method)},
+			Operation:     "preparing request body",
+			RequestID:     id,
+		}
+	}
 
-	if method == "GET" {
+	if isForm {
+		stream, contentType, err = form.reader()
+		if err != nil {
+			return nil, "", &RequestError{
+				GoBitpinError: GoBitpinError{
+					Message: "failed to encode multipart form",
+					Err:     err,
+				},
+				Operation: "preparing request body",
+				RequestID: id,
+			}
+		}
+	} else if sendsQuery(method) {
 		if body != nil {
 			urlParams, err := u.StructToURLParams(body)
 			if err != nil {
-				return &RequestError{
+				return nil, "", &RequestError{
 					GoBitpinError: GoBitpinError{
 						Message: "failed to convert struct to URL params",
 						Err:     err,
 					},
 					Operation: "preparing request parameters",
+					RequestID: id,
 				}
 			}
 			url += "?" + urlParams
 		}
-	}
-
-	if method == "POST" {
-		if body != nil {
-			reqBody, err = json.Marshal(body)
-			if err != nil {
-				return &RequestError{
-					GoBitpinError: GoBitpinError{
-						Message: "failed to marshal request body",
-						Err:     err,
-					},
-					Operation: "preparing request body",
-				}
+	} else if body != nil {
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, "", &RequestError{
+				GoBitpinError: GoBitpinError{
+					Message: "failed to marshal request body",
+					Err:     err,
+				},
+				Operation: "preparing request body",
+				RequestID: id,
 			}
 		}
 	}
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(reqBody))
+	var bodyReader io.Reader = bytes.NewBuffer(reqBody)
+	if stream != nil {
+		bodyReader = stream
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
-		return &RequestError{
+		return nil, "", &RequestError{
 			GoBitpinError: GoBitpinError{
 				Message: "failed to create request",
 				Err:     err,
 			},
 			Operation: "creating request",
+			RequestID: id,
 		}
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(RequestIDHeader, id)
+	if isForm && form.rewindable() {
+		req.GetBody = func() (io.ReadCloser, error) {
+			if !form.rewind() {
+				return nil, fmt.Errorf("failed to rewind multipart form")
+			}
+			stream, _, err := form.reader()
+			return io.NopCloser(stream), err
+		}
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if c.DisableCompression {
+		req.Header.Set("Accept-Encoding", "identity")
+	} else {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
 
 	if auth {
+		if c.noAuth {
+			return nil, "", &AuthError{
+				GoBitpinError: GoBitpinError{
+					Message: fmt.Sprintf("%s %s requires authentication", method, url),
+					Err:     ErrAuthDisabled,
+				},
+				RequestID: id,
+			}
+		}
+
+		tokens := c.tokenOwner()
 		if c.AutoRefresh {
-			if err := c.handleAutoRefresh(); err != nil {
-				return &GoBitpinError{
-					Message: "failed to refresh authentication",
-					Err:     err,
+			if err := tokens.handleAutoRefresh(ctx); err != nil {
+				return nil, "", &AuthError{
+					GoBitpinError: GoBitpinError{
+						Message: "failed to refresh authentication",
+						Err:     err,
+					},
+					RequestID: id,
 				}
 			}
 		}
 
-		if err := assertAuth(c); err != nil {
-			return &GoBitpinError{
-				Message: "authentication validation failed",
-				Err:     err,
+		if err := assertAuth(tokens); err != nil {
+			return nil, "", &AuthError{
+				GoBitpinError: GoBitpinError{
+					Message: "authentication validation failed",
+					Err:     err,
+				},
+				RequestID: id,
 			}
 		}
 
-		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+		used, _ = tokens.tokens()
+		req.Header.Set("Authorization", "Bearer "+used)
 	}
 
-	resp, err := c.HttpClient.Do(req)
+	done, err := c.breaker.allow()
+	if err != nil {
+		return nil, "", err
+	}
+	req, trace := c.traceRequest(req)
+	sent := time.Now()
+	resp, req, err := c.do(req)
+	done(breakerFailure(ctx, resp, err))
 	if err != nil {
-		return &RequestError{
+		reqErr := &RequestError{
 			GoBitpinError: GoBitpinError{
 				Message: "failed to send request",
 				Err:     err,
 			},
 			Operation: "sending request",
+			RequestID: id,
 		}
+		c.observeRequest(req, reqBody, sent, trace, nil, nil, reqErr)
+		return nil, "", reqErr
 	}
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(resp.Body)
 
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := readBody(resp)
 	if err != nil {
-		return &RequestError{
+		reqErr := &RequestError{
 			GoBitpinError: GoBitpinError{
 				Message: "failed to read response body",
 				Err:     err,
 			},
 			Operation: "reading response",
+			RequestID: id,
 		}
+		c.observeRequest(req, reqBody, sent, trace, resp, nil, reqErr)
+		return nil, "", reqErr
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return parseErrorResponse(resp.StatusCode, respBody)
+	c.rateLimit.observe(c.clock, resp.StatusCode, resp.Header)
+	c.skew.observe(sent, time.Now(), resp.Header.Get("Date"))
+
+	raw := &RawResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBody,
+		RequestID:  id,
 	}
 
-	if result != nil {
-		if err = json.Unmarshal(respBody, result); err != nil {
-			return &RequestError{
-				GoBitpinError: GoBitpinError{
-					Message: "failed to unmarshal response",
-					Err:     err,
-				},
-				Operation: "parsing response",
-			}
+	if resp.StatusCode == http.StatusNotModified && header != nil {
+		c.observeRequest(req, reqBody, sent, trace, resp, respBody, nil)
+		return raw, used, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := newAPIError(req, resp, respBody)
+		if c.TranslateErrors {
+			apiErr.translate(c.ErrorTranslations)
 		}
+		c.observeRequest(req, reqBody, sent, trace, resp, respBody, apiErr)
+		return raw, used, apiErr
 	}
 
-	return nil
+	c.observeRequest(req, reqBody, sent, trace, resp, respBody, nil)
+	return raw, used, nil
+}
+
+// sendsQuery reports whether requests with the given method carry their
+// parameters in the URL query. Requests with other methods, such as POST, PUT,
+// PATCH and DELETE, carry them in a JSON body.
+func sendsQuery(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD":
+		return true
+	}
+	return false
+}
+
+// readBody reads the response body, decompressing it if the server sent it
+// gzip-encoded. The encoding headers are removed from decompressed responses.
+func readBody(resp *http.Response) ([]byte, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return io.ReadAll(resp.Body)
+	}
+
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = zr.Close()
+	}()
+
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	return body, nil
 }
 
 // ApiRequest is a helper method for making API requests to a specific endpoint with the
@@ -470,8 +1174,9 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 //   - auth: A boolean indicating whether the request requires authentication.
 //     If true, the method adds an Authorization header with the access token
 //     and handles automatic token refresh if enabled.
-//   - body: An optional request body. For GET requests, it is converted into URL
-//     parameters; for POST requests, it is marshaled to JSON.
+//   - body: An optional request body. For GET and HEAD requests, it is converted
+//     into URL parameters; for other methods, such as POST, PUT, PATCH and
+//     DELETE, it is marshaled to JSON.
 //   - result: A pointer to a variable where the response body should be unmarshaled.
 //     If nil, the response body is not unmarshaled.
 //
@@ -495,8 +1200,45 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 //   - `createApiURI` for constructing the full API URL.
 //   - `Request` for handling the HTTP request and processing the response.
 func (c *Client) ApiRequest(method, endpoint string, version string, auth bool, body interface{}, result interface{}) error {
+	return c.ApiRequestWithContext(context.Background(), method, endpoint, version, auth, body, result)
+}
+
+// ApiRequestWithContext is like `ApiRequest` but carries a context. The context
+// controls cancellation and deadlines of the underlying HTTP request.
+//
+// Example:
+//
+//	var order t.OrderStatus
+//	err := client.ApiRequestWithContext(ctx, "GET", "/odr/orders/123/", "v1", true, nil, &order)
+func (c *Client) ApiRequestWithContext(ctx context.Context, method, endpoint string, version string, auth bool, body interface{}, result interface{}) error {
+	url := c.createApiURI(endpoint, version)
+	return c.RequestWithContext(ctx, method, url, auth, body, result)
+}
+
+// ApiRequestRaw is the raw counterpart of `ApiRequest`. It builds the full API URL
+// for the given endpoint and version and delegates to `RequestRaw`, returning the
+// untouched response payload, status code, and headers.
+//
+// This is useful for endpoints whose schema the SDK types do not fully capture yet,
+// or when the response headers are needed alongside the body.
+//
+// Example:
+//
+//	raw, err := client.ApiRequestRaw("GET", "/mkt/markets/", "v1", false, nil)
+//	if err != nil {
+//	    log.Fatalf("API request failed: %v", err)
+//	}
+//	var markets []map[string]interface{}
+//	_ = raw.JSON(&markets)
+func (c *Client) ApiRequestRaw(method, endpoint string, version string, auth bool, body interface{}) (*RawResponse, error) {
+	return c.ApiRequestRawWithContext(context.Background(), method, endpoint, version, auth, body)
+}
+
+// ApiRequestRawWithContext is like `ApiRequestRaw` but carries a context. The
+// context controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) ApiRequestRawWithContext(ctx context.Context, method, endpoint string, version string, auth bool, body interface{}) (*RawResponse, error) {
 	url := c.createApiURI(endpoint, version)
-	return c.Request(method, url, auth, body, result)
+	return c.RequestRawWithContext(ctx, method, url, auth, body)
 }
 
 // Authenticate authenticates the client using the provided API key and secret key.
@@ -519,6 +1261,8 @@ func (c *Client) ApiRequest(method, endpoint string, version string, auth bool,
 //     and secret key in the request body.
 //   - If the request succeeds, updates the client's `AccessToken` and `RefreshToken`
 //     with the tokens from the response.
+//   - If `TokenStorage` is set, the new tokens are saved. A storage failure is
+//     returned together with the successful response.
 //   - If the request fails, checks for specific API errors (e.g., 401 or 429) and
 //     returns detailed error messages. For other errors, wraps and returns them.
 //
@@ -540,6 +1284,9 @@ func (c *Client) ApiRequest(method, endpoint string, version string, auth bool,
 //   - "authentication failed: rate limit exceeded" for 429 Too Many Requests responses.
 //   - "authentication failed: %v" for other API or request errors.
 func (c *Client) Authenticate(apiKey, secretKey string) (*t.AuthenticationResponse, error) {
+	if c.owner != nil {
+		return c.owner.Authenticate(apiKey, secretKey)
+	}
 	if apiKey == "" || secretKey == "" {
 		return nil, &GoBitpinError{
 			Message: "API key and/or secret key are empty",
@@ -553,7 +1300,7 @@ func (c *Client) Authenticate(apiKey, secretKey string) (*t.AuthenticationRespon
 	}
 
 	var authResponse t.AuthenticationResponse
-	err := c.ApiRequest("POST", "/usr/authenticate/", Version, false, reqBody, &authResponse)
+	err := c.call(context.Background(), "POST", OpAuthenticate, nil, false, reqBody, &authResponse)
 
 	if err != nil {
 		// Check for specific API errors here
@@ -572,8 +1319,12 @@ func (c *Client) Authenticate(apiKey, secretKey string) (*t.AuthenticationRespon
 	}
 
 	// Update the client's tokens with the newly received ones
-	c.AccessToken = authResponse.Access
-	c.RefreshToken = authResponse.Refresh
+	previousAccess, previousRefresh := c.setTokens(authResponse.Access, authResponse.Refresh)
+	c.notifyTokenRefresh(TokenAuthenticated, previousAccess, previousRefresh)
+
+	if err := c.saveTokens(); err != nil {
+		return &authResponse, err
+	}
 
 	return &authResponse, nil
 }
@@ -616,20 +1367,25 @@ func (c *Client) Authenticate(apiKey, secretKey string) (*t.AuthenticationRespon
 //	    "access": "<new-access-token>"
 //	}
 func (c *Client) RefreshAccessToken() error {
+	if c.owner != nil {
+		return c.owner.RefreshAccessToken()
+	}
+	_, refresh := c.tokens()
 	reqBody := map[string]string{
-		"refresh": c.RefreshToken,
+		"refresh": refresh,
 	}
 
 	var refreshResponse t.RefreshTokenResponse
-	err := c.ApiRequest("POST", "/usr/refresh_token/", Version, false, reqBody, &refreshResponse)
+	err := c.call(context.Background(), "POST", OpRefreshToken, nil, false, reqBody, &refreshResponse)
 	if err != nil {
 		return err
 	}
 
 	// Update the bitpin_client's access token with the newly received one
-	c.AccessToken = refreshResponse.Access
+	previousAccess, previousRefresh := c.setTokens(refreshResponse.Access, "")
+	c.notifyTokenRefresh(TokenRefreshed, previousAccess, previousRefresh)
 
-	return nil
+	return c.saveTokens()
 }
 
 // GetCurrencies retrieves a list of available currencies from the API.
@@ -644,6 +1400,12 @@ func (c *Client) RefreshAccessToken() error {
 // Behavior:
 //   - Sends a GET request to the `/mkt/currencies/` endpoint.
 //   - Does not require authentication (`auth` is set to false).
+//   - If `MetadataTTL` is set, a cached copy younger than the TTL is returned
+//     without a request.
+//   - Once the cached copy is older, or when no TTL is set, the request carries
+//     the `ETag` and `Last-Modified` validators of the last response. A
+//     `304 Not Modified` answer returns the cached copy without downloading it
+//     again.
 //   - Unmarshals the response into a `Currencies` struct.
 //
 // Example:
@@ -680,12 +1442,18 @@ func (c *Client) RefreshAccessToken() error {
 //	    }
 //	]
 func (c *Client) GetCurrencies() (*t.Currencies, error) {
-	var currencies *t.Currencies
-	err := c.ApiRequest("GET", "/mkt/currencies/", Version, false, nil, &currencies)
+	return c.GetCurrenciesWithContext(context.Background())
+}
+
+// GetCurrenciesWithContext is like `GetCurrencies` but carries a context. The
+// context controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetCurrenciesWithContext(ctx context.Context) (*t.Currencies, error) {
+	items, err := fetchMetadata(ctx, c, OpCurrencies, &c.metadata.currencies)
 	if err != nil {
 		return nil, err
 	}
-	return currencies, nil
+	currencies := t.Currencies(items)
+	return &currencies, nil
 }
 
 // GetMarkets retrieves a list of available markets from the API.
@@ -700,6 +1468,12 @@ func (c *Client) GetCurrencies() (*t.Currencies, error) {
 // Behavior:
 //   - Sends a GET request to the `/mkt/markets/` endpoint.
 //   - Does not require authentication (`auth` is set to false).
+//   - If `MetadataTTL` is set, a cached copy younger than the TTL is returned
+//     without a request.
+//   - Once the cached copy is older, or when no TTL is set, the request carries
+//     the `ETag` and `Last-Modified` validators of the last response. A
+//     `304 Not Modified` answer returns the cached copy without downloading it
+//     again.
 //   - Unmarshals the response into a `Markets` struct.
 //
 // Example:
@@ -744,12 +1518,70 @@ func (c *Client) GetCurrencies() (*t.Currencies, error) {
 //	    }
 //	]
 func (c *Client) GetMarkets() (*t.Markets, error) {
-	var markets *t.Markets
-	err := c.ApiRequest("GET", "/mkt/markets/", Version, false, nil, &markets)
+	return c.GetMarketsWithContext(context.Background())
+}
+
+// GetMarketsWithContext is like `GetMarkets` but carries a context. The context
+// controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetMarketsWithContext(ctx context.Context) (*t.Markets, error) {
+	items, err := fetchMetadata(ctx, c, OpMarkets, &c.metadata.markets)
 	if err != nil {
 		return nil, err
 	}
-	return markets, nil
+	markets := t.Markets(items)
+	return &markets, nil
+}
+
+// GetMarket returns the metadata of a single market.
+// It looks the market up in the result of `GetMarkets`, so it is served from the
+// metadata cache when `MetadataTTL` is set.
+//
+// Parameters:
+//   - symbol: The market symbol. Case, the separator ("_", "-", "/" or none), and
+//     the Toman code (IRT or TMN) do not matter, so "btc-usdt" and "BTCUSDT"
+//     both find the "BTC_USDT" market.
+//
+// Returns:
+//   - A pointer to the matching `Market`.
+//   - An error wrapping `ErrMarketNotFound` if no market matches the symbol, or
+//     any error returned by `GetMarkets`.
+//
+// Example:
+//
+//	market, err := client.GetMarket("BTC_USDT")
+//	if errors.Is(err, bitpin.ErrMarketNotFound) {
+//	    log.Fatal("unknown market")
+//	}
+//	fmt.Printf("Price precision: %d\n", market.PricePrecision)
+//
+// Dependencies:
+//   - Relies on `GetMarkets` for fetching the market list.
+//   - Uses `utils.SameSymbol` for matching symbols.
+func (c *Client) GetMarket(symbol string) (*t.Market, error) {
+	return c.GetMarketWithContext(context.Background(), symbol)
+}
+
+// GetMarketWithContext is like `GetMarket` but carries a context. The context
+// controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetMarketWithContext(ctx context.Context, symbol string) (*t.Market, error) {
+	markets, err := c.GetMarketsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if markets != nil {
+		for i := range *markets {
+			market := &(*markets)[i]
+			if u.SameSymbol(market.Symbol, symbol) || u.SameSymbol(market.Base+"_"+market.Quote, symbol) {
+				return market, nil
+			}
+		}
+	}
+
+	return nil, &GoBitpinError{
+		Message: fmt.Sprintf("no market for symbol %q", symbol),
+		Err:     ErrMarketNotFound,
+	}
 }
 
 // GetTickers retrieves a list of market tickers from the API.
@@ -805,14 +1637,73 @@ func (c *Client) GetMarkets() (*t.Markets, error) {
 //	    }
 //	]
 func (c *Client) GetTickers() (*t.Tickers, error) {
+	return c.GetTickersWithContext(context.Background())
+}
+
+// GetTickersWithContext is like `GetTickers` but carries a context. The context
+// controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetTickersWithContext(ctx context.Context) (*t.Tickers, error) {
 	var tickers *t.Tickers
-	err := c.ApiRequest("GET", "/mkt/tickers/", Version, false, nil, &tickers)
+	err := c.call(ctx, "GET", OpTickers, nil, false, nil, &tickers)
 	if err != nil {
 		return nil, err
 	}
 	return tickers, nil
 }
 
+// GetTicker retrieves the ticker of a single market from the API.
+// It sends a GET request to the `/mkt/tickers/` endpoint with the `symbol` filter
+// so the server only returns the requested market.
+//
+// Parameters:
+//   - symbol: A string representing the trading symbol, such as "BTC_USDT".
+//
+// Returns:
+//   - A pointer to a `Ticker` struct with the real-time data of the market.
+//   - An error wrapping `ErrTickerNotFound` if the response does not contain the
+//     symbol, or any error returned by the request.
+//
+// Behavior:
+//   - Sends a GET request to the `/mkt/tickers/` endpoint with `symbol` set.
+//   - Does not require authentication (`auth` is set to false).
+//   - Picks the entry matching `symbol` from the response, so the result is
+//     correct even if the server ignores the filter.
+//
+// Example:
+//
+//	ticker, err := client.GetTicker("BTC_USDT")
+//	if err != nil {
+//	    log.Fatalf("Failed to fetch ticker: %v", err)
+//	}
+//	fmt.Printf("Price: %s\n", ticker.Price)
+//
+// Dependencies:
+//   - Relies on `ApiRequest` for HTTP request handling and response processing.
+func (c *Client) GetTicker(symbol string) (*t.Ticker, error) {
+	return c.GetTickerWithContext(context.Background(), symbol)
+}
+
+// GetTickerWithContext is like `GetTicker` but carries a context. The context
+// controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetTickerWithContext(ctx context.Context, symbol string) (*t.Ticker, error) {
+	var tickers t.Tickers
+	err := c.call(ctx, "GET", OpTickers, nil, false, t.GetTickersParams{Symbol: symbol}, &tickers)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tickers {
+		if u.SameSymbol(tickers[i].Symbol, symbol) {
+			return &tickers[i], nil
+		}
+	}
+
+	return nil, &GoBitpinError{
+		Message: fmt.Sprintf("no ticker for symbol %q", symbol),
+		Err:     ErrTickerNotFound,
+	}
+}
+
 // GetOrderBook retrieves the order book for a specific trading symbol from the API.
 // It sends a GET request to the `/mth/orderbook/<symbol>/` endpoint and returns
 // detailed order book information, including asks and bids.
@@ -853,8 +1744,14 @@ func (c *Client) GetTickers() (*t.Tickers, error) {
 //	    "bids": [["39990.00", "0.3"], ["39980.00", "1.0"]]
 //	}
 func (c *Client) GetOrderBook(symbol string) (*t.OrderBook, error) {
+	return c.GetOrderBookWithContext(context.Background(), symbol)
+}
+
+// GetOrderBookWithContext is like `GetOrderBook` but carries a context. The
+// context controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetOrderBookWithContext(ctx context.Context, symbol string) (*t.OrderBook, error) {
 	var orderBook *t.OrderBook
-	err := c.ApiRequest("GET", fmt.Sprintf("/mth/orderbook/%s/", symbol), Version, false, nil, &orderBook)
+	err := c.call(ctx, "GET", OpOrderBook, symbol, false, nil, &orderBook)
 	if err != nil {
 		return nil, err
 	}
@@ -926,14 +1823,95 @@ func (c *Client) GetOrderBook(symbol string) (*t.OrderBook, error) {
 //	    }
 //	]
 func (c *Client) GetRecentTrades(symbol string) (*[]*t.Trade, error) {
+	return c.GetRecentTradesWithContext(context.Background(), symbol)
+}
+
+// GetRecentTradesWithContext is like `GetRecentTrades` but carries a context.
+// The context controls cancellation and deadlines of the underlying HTTP
+// request.
+func (c *Client) GetRecentTradesWithContext(ctx context.Context, symbol string) (*[]*t.Trade, error) {
 	var trades *[]*t.Trade
-	err := c.ApiRequest("GET", fmt.Sprintf("/mth/matches/%s/", symbol), Version, false, nil, &trades)
+	err := c.call(ctx, "GET", OpMatches, symbol, false, nil, &trades)
 	if err != nil {
 		return nil, err
 	}
 	return trades, nil
 }
 
+// GetRecentTradesWithParams retrieves recent trades for a trading symbol with
+// pagination and incremental syncing filters.
+// It sends a GET request to the `/mth/matches/<symbol>/` endpoint with the
+// given parameters as query string.
+//
+// Parameters:
+//   - symbol: A string representing the trading symbol, such as "BTC_USDT".
+//   - params: A `GetRecentTradesParams` struct with optional `Limit`, `SinceId`,
+//     and `Since` filters.
+//
+// Returns:
+//   - A slice of `Trade` pointers matching the filters. Unlike GetRecentTrades,
+//     which keeps returning a pointer to a slice for compatibility, the slice is
+//     returned directly, as list endpoints will in v2.
+//   - An error if the request fails or the response cannot be processed.
+//
+// Behavior:
+//   - Sends the filters to the server as `limit`, `since_id`, and `since`.
+//   - Applies the filters locally as well, so that only trades newer than
+//     `SinceId` and executed after `Since` are returned and never more than
+//     `Limit` of them, even if the server ignores the filters. Numeric IDs are
+//     compared numerically. Trades without an execution time are kept.
+//
+// Example:
+//
+//	lastId := ""
+//	for {
+//	    trades, err := client.GetRecentTradesWithParams("BTC_USDT", t.GetRecentTradesParams{
+//	        SinceId: lastId,
+//	        Limit:   100,
+//	    })
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    for _, trade := range trades {
+//	        store(trade)
+//	        lastId = trade.Id
+//	    }
+//	    time.Sleep(5 * time.Second)
+//	}
+func (c *Client) GetRecentTradesWithParams(symbol string, params t.GetRecentTradesParams) ([]*t.Trade, error) {
+	return c.getRecentTradesWithParams(context.Background(), symbol, params)
+}
+
+// getRecentTradesWithParams is the context-aware implementation of
+// `GetRecentTradesWithParams`.
+func (c *Client) getRecentTradesWithParams(ctx context.Context, symbol string, params t.GetRecentTradesParams) ([]*t.Trade, error) {
+	var trades []*t.Trade
+	err := c.call(ctx, "GET", OpMatches, symbol, false, params, &trades)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.SinceId != "" || params.Since > 0 {
+		filtered := trades[:0]
+		for _, trade := range trades {
+			if params.SinceId != "" && !tradeIdAfter(trade.Id, params.SinceId) {
+				continue
+			}
+			if params.Since > 0 && !trade.CreatedAt.IsZero() && trade.CreatedAt.Unix() <= params.Since {
+				continue
+			}
+			filtered = append(filtered, trade)
+		}
+		trades = filtered
+	}
+
+	if params.Limit > 0 && len(trades) > params.Limit {
+		trades = trades[:params.Limit]
+	}
+
+	return trades, nil
+}
+
 // GetWallets retrieves a list of wallets for the authenticated user from the API.
 // It sends a GET request to the `/wlt/wallets/` endpoint and returns wallet information
 // based on the provided parameters.
@@ -996,14 +1974,88 @@ func (c *Client) GetRecentTrades(symbol string) (*[]*t.Trade, error) {
 //	    }
 //	]
 func (c *Client) GetWallets(params t.GetWalletParams) (*t.Wallets, error) {
+	return c.GetWalletsWithContext(context.Background(), params)
+}
+
+// GetWalletsWithContext is like `GetWallets` but carries a context. The context
+// controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetWalletsWithContext(ctx context.Context, params t.GetWalletParams) (*t.Wallets, error) {
 	var wallets *t.Wallets
-	err := c.ApiRequest("GET", "/wlt/wallets/", Version, true, params, &wallets)
+	err := c.call(ctx, "GET", OpWallets, nil, true, params, &wallets)
 	if err != nil {
 		return nil, err
 	}
 	return wallets, nil
 }
 
+// GetWallet retrieves the wallet of a single asset.
+// It sends a GET request to the `/wlt/wallets/` endpoint filtered by the asset,
+// so only the matching wallets are transferred.
+//
+// Parameters:
+//   - asset: The asset of the wallet, such as "BTC". Case and the Toman code
+//     (IRT or TMN) do not matter.
+//   - service: The service of the wallet, such as "spot". If empty, the wallet
+//     of any service is returned, preferring the spot wallet.
+//
+// Returns:
+//   - A pointer to the matching `Wallet`.
+//   - An error wrapping `ErrWalletNotFound` if the user has no such wallet, or
+//     any error returned by `GetWallets`.
+//
+// Example:
+//
+//	wallet, err := client.GetWallet("USDT", "spot")
+//	if errors.Is(err, bitpin.ErrWalletNotFound) {
+//	    log.Fatal("no USDT wallet")
+//	}
+//	fmt.Printf("Balance: %s, Frozen: %s\n", wallet.Balance, wallet.Frozen)
+//
+// Dependencies:
+//   - Relies on `GetWallets` for fetching the filtered wallets.
+//   - Uses `utils.SameCurrency` for matching assets.
+func (c *Client) GetWallet(asset string, service string) (*t.Wallet, error) {
+	return c.GetWalletWithContext(context.Background(), asset, service)
+}
+
+// GetWalletWithContext is like `GetWallet` but carries a context. The context
+// controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetWalletWithContext(ctx context.Context, asset string, service string) (*t.Wallet, error) {
+	asset = u.CanonicalCurrency(asset)
+	wallets, err := c.GetWalletsWithContext(ctx, t.GetWalletParams{Assets: []string{asset}, Service: service})
+	if err != nil {
+		return nil, err
+	}
+
+	var found *t.Wallet
+	if wallets != nil {
+		for i := range *wallets {
+			wallet := &(*wallets)[i]
+			if !u.SameCurrency(wallet.Asset, asset) {
+				continue
+			}
+			if service != "" && !strings.EqualFold(wallet.Service, service) {
+				continue
+			}
+			if found == nil || (service == "" && strings.EqualFold(wallet.Service, "spot")) {
+				found = wallet
+			}
+		}
+	}
+	if found != nil {
+		return found, nil
+	}
+
+	message := fmt.Sprintf("no wallet for asset %q", asset)
+	if service != "" {
+		message = fmt.Sprintf("no %s wallet for asset %q", service, asset)
+	}
+	return nil, &GoBitpinError{
+		Message: message,
+		Err:     ErrWalletNotFound,
+	}
+}
+
 // CreateOrder submits a new order to the API based on the provided parameters.
 // It sends a POST request to the `/odr/orders/` endpoint and returns the status
 // of the created order.
@@ -1019,6 +2071,22 @@ func (c *Client) GetWallets(params t.GetWalletParams) (*t.Wallets, error) {
 //     cannot be processed.
 //
 // Behavior:
+//   - Validates the parameters locally with `params.Validate()` and returns
+//     without sending a request if they are invalid.
+//   - If market metadata is cached (see `MetadataTTL`), also rejects orders
+//     below the market's minimum order size with `params.ValidateMarket`.
+//   - If `OrderThrottle` is set, rejects orders on markets that already have
+//     the maximum number of open orders, or that had an order submitted less
+//     than the minimum interval ago, with an `OrderThrottledError` wrapping
+//     `ErrOrderThrottled`.
+//   - If `BalanceCheck` is set, rejects orders whose spent amount plus the
+//     estimated fee exceeds the free balance with an `InsufficientBalanceError`
+//     wrapping `ErrInsufficientBalance`.
+//   - Emulates `TimeInForce`: IOC and FOK orders have their remainder cancelled
+//     right after submission, and the order is returned in its terminal state.
+//     FOK orders the order book cannot fill are not submitted, and FOK orders
+//     that fill only partially are returned with an error; both wrap
+//     `ErrFillOrKill`.
 //   - Sends a POST request to the `/odr/orders/` endpoint with the order details in the body.
 //   - Requires authentication (`auth` is set to true).
 //   - Unmarshals the response into an `OrderStatus` struct.
@@ -1027,8 +2095,8 @@ func (c *Client) GetWallets(params t.GetWalletParams) (*t.Wallets, error) {
 //
 //	params := t.CreateOrderParams{
 //	    Symbol:     "BTC_USDT",
-//	    Type:       "limit",
-//	    Side:       "buy",
+//	    Type:       t.TypeLimit,
+//	    Side:       t.SideBuy,
 //	    Price:      "40000",
 //	    BaseAmount: "0.01",
 //	}
@@ -1042,6 +2110,8 @@ func (c *Client) GetWallets(params t.GetWalletParams) (*t.Wallets, error) {
 //   - Relies on `ApiRequest` for HTTP request handling and response processing.
 //
 // Errors:
+//   - A wrapped `*t.ValidationError` listing the invalid fields if local
+//     validation fails.
 //   - "error creating order: %v" if the request fails or the response cannot be unmarshaled.
 //   - Returns authentication errors if the client is not properly authenticated.
 //
@@ -1078,11 +2148,68 @@ func (c *Client) GetWallets(params t.GetWalletParams) (*t.Wallets, error) {
 //	    "commission": "0.01"
 //	}
 func (c *Client) CreateOrder(params t.CreateOrderParams) (*t.OrderStatus, error) {
+	return c.CreateOrderWithContext(context.Background(), params)
+}
+
+// CreateOrderWithContext is like `CreateOrder` but carries a context. The
+// context controls cancellation and deadlines of the underlying HTTP requests.
+//
+// Order operations on the same symbol are serialized, so concurrent creates and
+// cancels for one market reach the API in the order they were issued.
+func (c *Client) CreateOrderWithContext(ctx context.Context, params t.CreateOrderParams) (*t.OrderStatus, error) {
+	unlock, err := c.orderLocks.lock(ctx, params.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	order, err := c.createOrderLocked(ctx, params)
+	unlock()
+	if err != nil || !emulatesTimeInForce(params) {
+		return order, err
+	}
+	return c.enforceTimeInForce(ctx, order, params)
+}
+
+// createOrderLocked creates an order while the caller holds the symbol lock.
+func (c *Client) createOrderLocked(ctx context.Context, params t.CreateOrderParams) (*t.OrderStatus, error) {
+	if err := params.Validate(); err != nil {
+		return nil, &GoBitpinError{
+			Message: "order parameters are invalid",
+			Err:     err,
+		}
+	}
+	if market, ok := c.cachedMarket(params.Symbol); ok {
+		if err := params.ValidateMarket(market); err != nil {
+			return nil, &GoBitpinError{
+				Message: "order is below the market minimum",
+				Err:     err,
+			}
+		}
+	}
+	if err := c.throttle.check(ctx, c, params); err != nil {
+		return nil, err
+	}
+	if err := c.balances.check(ctx, c, params); err != nil {
+		return nil, err
+	}
+	if params.TimeInForce == t.TimeInForceFOK {
+		if err := c.checkFillOrKill(ctx, params); err != nil {
+			return nil, err
+		}
+	}
+
 	var orderStatus *t.OrderStatus
-	err := c.ApiRequest("POST", "/odr/orders/", Version, true, params, &orderStatus)
+	c.throttle.record(params.Symbol)
+	err := c.call(ctx, "POST", OpOrders, nil, true, params, &orderStatus)
+	c.balances.invalidate()
 	if err != nil {
 		return nil, err
 	}
+	if orderStatus == nil {
+		return nil, &GoBitpinError{
+			Message: fmt.Sprintf("empty response for the new %s order", params.Symbol),
+		}
+	}
+	c.orderLocks.remember(orderStatus.Id, orderStatus.Symbol)
 	return orderStatus, nil
 }
 
@@ -1123,10 +2250,31 @@ func (c *Client) CreateOrder(params t.CreateOrderParams) (*t.OrderStatus, error)
 //
 //	HTTP Status 404 Not Found
 func (c *Client) CancelOrder(orderId int) error {
-	err := c.ApiRequest("DELETE", fmt.Sprintf("/odr/orders/%d/", orderId), Version, true, nil, nil)
+	return c.CancelOrderWithContext(context.Background(), orderId)
+}
+
+// CancelOrderWithContext is like `CancelOrder` but carries a context. The
+// context controls cancellation and deadlines of the underlying HTTP requests.
+//
+// It takes the symbol lock of the order, so it is serialized with other
+// operations on the same market. Orders this client has not seen are fetched
+// first to find their symbol.
+func (c *Client) CancelOrderWithContext(ctx context.Context, orderId int) error {
+	unlock, err := c.orderLocks.lock(ctx, c.orderSymbol(ctx, orderId))
 	if err != nil {
 		return err
 	}
+	defer unlock()
+	return c.cancelOrderLocked(ctx, orderId)
+}
+
+// cancelOrderLocked cancels an order while the caller holds the symbol lock.
+func (c *Client) cancelOrderLocked(ctx context.Context, orderId int) error {
+	err := c.call(ctx, "DELETE", OpOrder, orderId, true, nil, nil)
+	if err != nil {
+		return err
+	}
+	c.orderLocks.forget(orderId)
 	return nil
 }
 
@@ -1208,8 +2356,15 @@ func (c *Client) CancelOrder(orderId int) error {
 //	    }
 //	]
 func (c *Client) GetOrdersHistory(params t.GetOrdersHistoryParams) (*t.OrderStatuses, error) {
+	return c.GetOrdersHistoryWithContext(context.Background(), params)
+}
+
+// GetOrdersHistoryWithContext is like `GetOrdersHistory` but carries a context.
+// The context controls cancellation and deadlines of the underlying HTTP
+// request.
+func (c *Client) GetOrdersHistoryWithContext(ctx context.Context, params t.GetOrdersHistoryParams) (*t.OrderStatuses, error) {
 	var orders *t.OrderStatuses
-	err := c.ApiRequest("GET", "/odr/orders/", Version, true, params, &orders)
+	err := c.call(ctx, "GET", OpOrders, nil, true, params, &orders)
 	if err != nil {
 		return nil, err
 	}
@@ -1294,15 +2449,138 @@ func (c *Client) GetOrdersHistory(params t.GetOrdersHistoryParams) (*t.OrderStat
 //	    }
 //	]
 func (c *Client) GetOpenOrders(params t.GetOrdersHistoryParams) (*t.OrderStatuses, error) {
+	return c.GetOpenOrdersWithContext(context.Background(), params)
+}
+
+// GetOpenOrdersWithContext is like `GetOpenOrders` but carries a context. The
+// context controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetOpenOrdersWithContext(ctx context.Context, params t.GetOrdersHistoryParams) (*t.OrderStatuses, error) {
 	var orders *t.OrderStatuses
-	params.State = "active" // Automatically filter for active (open) orders
-	err := c.ApiRequest("GET", "/odr/orders/", Version, true, params, &orders)
+	params.State = t.StateActive // Automatically filter for active (open) orders
+	err := c.call(ctx, "GET", OpOrders, nil, true, params, &orders)
 	if err != nil {
 		return nil, err
 	}
 	return orders, nil
 }
 
+// GetOrder retrieves the status of a single order by its order ID.
+// It sends a GET request to the `/odr/orders/<orderId>/` endpoint and returns
+// the details of the order.
+//
+// Parameters:
+//   - orderId: The unique identifier of the order to fetch.
+//
+// Returns:
+//   - A pointer to an `OrderStatus` struct containing the status and details of
+//     the order.
+//   - An error if the request fails, the user is not authenticated, or the response
+//     cannot be processed.
+//
+// Behavior:
+//   - Sends a GET request to the `/odr/orders/<orderId>/` endpoint.
+//   - Requires authentication (`auth` is set to true).
+//   - Unmarshals the response into an `OrderStatus` struct.
+//
+// Example:
+//
+//	order, err := client.GetOrder(123456)
+//	if err != nil {
+//	    log.Fatalf("Failed to fetch order: %v", err)
+//	}
+//	fmt.Printf("Order ID: %d, Status: %s, Filled: %s\n", order.Id, order.State, order.DealedBaseAmount)
+//
+// Dependencies:
+//   - Relies on `ApiRequest` for HTTP request handling and response processing.
+//
+// Errors:
+//   - Returns an `APIError` with status 404 if the order does not exist.
+func (c *Client) GetOrder(orderId int) (*t.OrderStatus, error) {
+	return c.GetOrderWithContext(context.Background(), orderId)
+}
+
+// GetOrderWithContext is like `GetOrder` but carries a context. The context
+// controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetOrderWithContext(ctx context.Context, orderId int) (*t.OrderStatus, error) {
+	var order *t.OrderStatus
+	err := c.call(ctx, "GET", OpOrder, orderId, true, nil, &order)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, &GoBitpinError{
+			Message: fmt.Sprintf("empty response for order %d", orderId),
+		}
+	}
+	if IsTerminalOrderState(order.State) {
+		c.orderLocks.forget(order.Id)
+	} else {
+		c.orderLocks.remember(order.Id, order.Symbol)
+	}
+	return order, nil
+}
+
+// GetOrderByIdentifier retrieves a single order by its client-side identifier.
+// It sends a GET request to the `/odr/orders/` endpoint using the `identifiers_in`
+// filter and returns the matching order.
+//
+// Parameters:
+//   - identifier: The client-provided identifier that was set in
+//     `CreateOrderParams.Identifier` when the order was placed.
+//
+// Returns:
+//   - A pointer to an `OrderStatus` struct for the matching order.
+//   - An error wrapping `ErrOrderNotFound` if no order carries the identifier, or
+//     any error returned by the request.
+//
+// Behavior:
+//   - Sends a GET request to the `/odr/orders/` endpoint with `identifiers_in` set.
+//   - Requires authentication (`auth` is set to true).
+//   - Only an order whose `Identifier` matches exactly is returned.
+//
+// Example:
+//
+//	order, err := client.GetOrderByIdentifier("rebalance-2024-01-01")
+//	if errors.Is(err, bitpin.ErrOrderNotFound) {
+//	    // the order was never accepted by the exchange
+//	}
+//
+// Dependencies:
+//   - Relies on `GetOrdersHistory` for querying orders.
+func (c *Client) GetOrderByIdentifier(identifier string) (*t.OrderStatus, error) {
+	return c.GetOrderByIdentifierWithContext(context.Background(), identifier)
+}
+
+// GetOrderByIdentifierWithContext is like `GetOrderByIdentifier` but carries a
+// context. The context controls cancellation and deadlines of the underlying
+// HTTP request.
+func (c *Client) GetOrderByIdentifierWithContext(ctx context.Context, identifier string) (*t.OrderStatus, error) {
+	if identifier == "" {
+		return nil, &GoBitpinError{
+			Message: "identifier is empty",
+			Err:     nil,
+		}
+	}
+
+	orders, err := c.GetOrdersHistoryWithContext(ctx, t.GetOrdersHistoryParams{IdentifiersIn: identifier})
+	if err != nil {
+		return nil, err
+	}
+
+	if orders != nil {
+		for i := range *orders {
+			if (*orders)[i].Identifier == identifier {
+				return &(*orders)[i], nil
+			}
+		}
+	}
+
+	return nil, &GoBitpinError{
+		Message: fmt.Sprintf("no order with identifier %q", identifier),
+		Err:     ErrOrderNotFound,
+	}
+}
+
 // GetOrderStatuses retrieves the statuses of multiple orders using their order IDs.
 // It sends a GET request to the `/odr/orders/<orderIds>/` endpoint and returns the
 // statuses of the specified orders.
@@ -1356,9 +2634,17 @@ func (c *Client) GetOpenOrders(params t.GetOrdersHistoryParams) (*t.OrderStatuse
 //	    "order_id": 654321,
 //	    "identifier": "user123"
 //	}
+//
+// Note: the response is decoded into a single `OrderStatus`, so this method only
+// works reliably when one order ID is given. Use `GetOrder` to fetch a single
+// order.
+//
+// Deprecated: Use GetOrder for a single order or GetOrdersHistory with the
+// `IdsIn` filter for several orders.
 func (c *Client) GetOrderStatuses(orderIds []string) (*t.OrderStatus, error) {
+	c.deprecated("GetOrderStatuses", "GetOrder")
 	var orders *t.OrderStatus
-	err := c.ApiRequest("GET", fmt.Sprintf("/odr/orders/%v/", strings.Join(orderIds, ",")), Version, true, nil, &orders)
+	err := c.call(context.Background(), "GET", OpOrder, strings.Join(orderIds, ","), true, nil, &orders)
 	if err != nil {
 		return nil, err
 	}
@@ -1437,10 +2723,27 @@ func (c *Client) GetOrderStatuses(orderIds []string) (*t.OrderStatus, error) {
 //	    }
 //	]
 func (c *Client) GetUserTrades(params t.GetUserTradesParams) (*t.UserTrades, error) {
+	return c.GetUserTradesWithContext(context.Background(), params)
+}
+
+// GetUserTradesWithContext is like `GetUserTrades` but carries a context. The
+// context controls cancellation and deadlines of the underlying HTTP request.
+func (c *Client) GetUserTradesWithContext(ctx context.Context, params t.GetUserTradesParams) (*t.UserTrades, error) {
 	var trades *t.UserTrades
-	err := c.ApiRequest("GET", "/odr/fills/", Version, true, params, &trades)
+	err := c.call(ctx, "GET", OpFills, nil, true, params, &trades)
 	if err != nil {
 		return nil, err
 	}
 	return trades, nil
 }
+
+// tradeIdAfter reports whether trade ID a is newer than trade ID b. IDs are
+// compared numerically when both are integers and lexically otherwise.
+func tradeIdAfter(a, b string) bool {
+	x, errA := strconv.ParseInt(a, 10, 64)
+	y, errB := strconv.ParseInt(b, 10, 64)
+	if errA == nil && errB == nil {
+		return x > y
+	}
+	return a > b
+}
@@ -0,0 +1,140 @@
+package bitpin
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of a client. Token expiry checks, retry and
+// reconnect delays, the circuit breaker, the order throttle, the rate-limit
+// status, the timestamps of stream and watcher events, candle boundaries and
+// the pacing of sliced orders read the time from it, so tests can control it
+// instead of sleeping.
+// The offset to the API clock is still measured with the wall clock, since it
+// compares local time with the Date header of real responses.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel that receives the current time once d has
+	// elapsed, like time.After.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock of the standard library. It is used when
+// ClientOptions.Clock is nil.
+var SystemClock Clock = systemClock{}
+
+// systemClock implements Clock with the time package.
+type systemClock struct{}
+
+// Now implements Clock.
+func (systemClock) Now() time.Time { return time.Now() }
+
+// After implements Clock.
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// now returns the current time of the Clock of the client.
+func (c *Client) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// after waits for d on the Clock of the client, like time.After.
+func (c *Client) after(d time.Duration) <-chan time.Time {
+	if c.clock == nil {
+		return time.After(d)
+	}
+	return c.clock.After(d)
+}
+
+// clockSamples is the number of recent offset measurements the clock offset
+// is derived from.
+const clockSamples = 8
+
+// clockTracker estimates the offset between the local clock and the clock of
+// the API from the Date header of responses.
+type clockTracker struct {
+	mu      sync.Mutex
+	samples []time.Duration // most recent offsets, oldest first
+	offset  time.Duration   // median of samples
+}
+
+// observe records the offset measured by a request sent at sent and answered
+// at received. The Date header has a resolution of one second, so the server
+// time is taken as the middle of the second it names, and the local time as
+// the middle of the round trip.
+func (k *clockTracker) observe(sent, received time.Time, date string) {
+	if date == "" {
+		return
+	}
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+	local := sent.Add(received.Sub(sent) / 2)
+	offset := serverTime.Add(500 * time.Millisecond).Sub(local)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.samples = append(k.samples, offset)
+	if len(k.samples) > clockSamples {
+		k.samples = k.samples[1:]
+	}
+
+	sorted := append([]time.Duration(nil), k.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	k.offset = sorted[len(sorted)/2]
+}
+
+// ClockOffset returns the measured offset of the API clock relative to the
+// local clock: positive when the local clock is behind. It is derived from the
+// Date header of recent responses, so it is zero until the first response and
+// accurate to about half a second.
+func (c *Client) ClockOffset() time.Duration {
+	c.skew.mu.Lock()
+	defer c.skew.mu.Unlock()
+	return c.skew.offset
+}
+
+// Now returns the current time on the API's clock: the time of the Clock of
+// the client corrected by ClockOffset. Token expiry is checked against it, so
+// a skewed local clock does not cause tokens to be refreshed too early or too
+// late.
+func (c *Client) Now() time.Time {
+	return c.now().Add(c.ClockOffset())
+}
+
+// GetServerTime requests the current time of the API and updates the clock
+// offset. The API has no dedicated time endpoint, so the standard HTTP Date
+// header of a small public endpoint is used, which has a resolution of one
+// second.
+//
+// Returns:
+//   - The estimated current time on the API's clock.
+//   - An error if the request fails or the response has no Date header.
+//
+// Example:
+//
+//	serverTime, err := client.GetServerTime(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if skew := client.ClockOffset(); skew.Abs() > 5*time.Second {
+//	    log.Printf("local clock is off by %s, server time is %s", skew, serverTime)
+//	}
+func (c *Client) GetServerTime(ctx context.Context) (time.Time, error) {
+	raw, err := c.pingRequest(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if raw.Header.Get("Date") == "" {
+		return time.Time{}, &GoBitpinError{Message: "response has no Date header"}
+	}
+	return c.Now(), nil
+}
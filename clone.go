@@ -0,0 +1,242 @@
+package bitpin
+
+import (
+	"maps"
+	"net/http"
+)
+
+// ErrAuthDisabled is returned for authenticated requests of a client created
+// with WithoutAuth
+var ErrAuthDisabled = &GoBitpinError{Message: "authentication is disabled for this client"}
+
+// tokenOwner returns the client that holds the tokens used by c.
+func (c *Client) tokenOwner() *Client {
+	if c.owner != nil {
+		return c.owner
+	}
+	return c
+}
+
+// Clone returns a client with the same settings that shares the HTTP
+// transport, the token state, the circuit breaker and the order locks of c. It is equivalent to
+// WithOverrides without options.
+func (c *Client) Clone() *Client {
+	derived, _ := c.WithOverrides()
+	return derived
+}
+
+// WithOverrides derives a client with some settings changed. The derived
+// client is cheap to create: it sends requests through the same connection
+// pool and shares the tokens of c, so a token refresh by either client is seen
+// by both and no additional authentication takes place.
+//
+// Parameters:
+//   - options: The settings to change, such as WithTimeout, WithBaseURL or
+//     WithoutAuth. Settings not given are copied from c.
+//
+// Returns:
+//   - The derived client.
+//   - An error if an option is invalid, if the overrides are rejected by the
+//     same validation as NewClientWithOptions, or if they try to change the
+//     credentials, the tokens, AutoAuth or OnTokenRefresh, which belong to
+//     the shared token state. Create a separate client with NewClient for
+//     other credentials.
+//
+// Behavior:
+//   - Boolean settings such as Debug, AutoRefresh or DetectDrift are
+//     inherited unless an option gives them. Their counterparts, such as
+//     WithoutDebug, WithoutAutoRefresh or WithCompression, turn a setting of
+//     c off. WithoutAuth cannot be undone by a derived client.
+//   - WithTimeout creates a new `http.Client` around the same transport.
+//     WithHTTPClient, WithTransport and the connection pool options replace
+//     the transport instead, so connections are no longer shared.
+//   - The circuit breaker is shared unless WithBaseURL or WithCircuitBreaker
+//     is given, since the health of another host is tracked separately.
+//   - Failover is shared unless WithFailover is given. WithBaseURL alone
+//     disables it, since the mirrors belong to the base URL of the parent.
+//   - The wallets cached by the balance check are shared unless
+//     WithBalanceCheck is given.
+//   - The order throttle is shared unless WithOrderThrottle is given, so the
+//     minimum interval applies across the clients.
+//   - Order operations are serialized per symbol across the clients, so a
+//     cancellation through one waits for an amendment through another.
+//   - WithEndpoint overrides are added to the endpoints of the parent.
+//   - The metadata cache, drift findings and rate-limit status are not
+//     shared.
+//
+// Example:
+//
+//	trading, _ := bitpin.NewClient(bitpin.ClientOptions{ApiKey: key, SecretKey: secret, AutoRefresh: true})
+//
+//	// A client for market-data polling with a short timeout and no access to
+//	// the account.
+//	public, err := trading.WithOverrides(bitpin.WithTimeout(2*time.Second), bitpin.WithoutAuth())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	tickers, _ := public.GetTickers()
+func (c *Client) WithOverrides(options ...Option) (*Client, error) {
+	var o ClientOptions
+	for _, option := range options {
+		if err := option(&o); err != nil {
+			return nil, err
+		}
+	}
+	if o.ApiKey != "" || o.SecretKey != "" || o.AccessToken != "" || o.RefreshToken != "" || o.TokenStorage != nil || o.Credentials != nil || o.OnTokenRefresh != nil || o.AutoAuth {
+		return nil, &GoBitpinError{Message: "invalid overrides: credentials and tokens are shared with the parent client"}
+	}
+
+	// Validate the overrides together with the settings they depend on that
+	// are inherited from c.
+	merged := o
+	if merged.BaseUrl == "" {
+		merged.BaseUrl = c.BaseUrl
+	}
+	merged.inherit(flagAutoRefresh, &merged.AutoRefresh, c.AutoRefresh)
+	merged.inherit(flagDisableCompression, &merged.DisableCompression, c.DisableCompression)
+	merged.inherit(flagDisableConditionalRequests, &merged.DisableConditionalRequests, c.DisableConditionalRequests)
+	merged.inherit(flagDetectDrift, &merged.DetectDrift, c.DetectDrift)
+	merged.inherit(flagTranslateErrors, &merged.TranslateErrors, c.TranslateErrors)
+	merged.inherit(flagDebug, &merged.Debug, c.Debug)
+	merged.inherit(flagTraceRequests, &merged.TraceRequests, c.TraceRequests)
+	merged.inherit(flagUseAPIv2, &merged.UseAPIv2, c.UseAPIv2)
+	if err := validateClientOptions(&merged); err != nil {
+		return nil, err
+	}
+
+	derived := &Client{
+		HttpClient:                 c.HttpClient,
+		BaseUrl:                    c.BaseUrl,
+		ApiKey:                     c.ApiKey,
+		SecretKey:                  c.SecretKey,
+		credentials:                c.credentials,
+		AutoRefresh:                merged.AutoRefresh,
+		OnDeprecatedCall:           c.OnDeprecatedCall,
+		TokenStorage:               c.TokenStorage,
+		DisableCompression:         merged.DisableCompression,
+		DisableConditionalRequests: merged.DisableConditionalRequests,
+		FetchConcurrency:           c.FetchConcurrency,
+		MetadataTTL:                c.MetadataTTL,
+		DetectDrift:                merged.DetectDrift,
+		OnDrift:                    c.OnDrift,
+		OnRequest:                  c.OnRequest,
+		OnTokenRefresh:             c.OnTokenRefresh,
+		Debug:                      merged.Debug,
+		TraceRequests:              merged.TraceRequests,
+		UseAPIv2:                   merged.UseAPIv2,
+		ClientTrace:                c.ClientTrace,
+		logger:                     c.logger,
+		egressCheck:                c.egressCheck,
+		egressClient:               c.egressClient,
+		endpoints:                  c.endpoints,
+		clock:                      c.clock,
+		TranslateErrors:            merged.TranslateErrors,
+		ErrorTranslations:          c.ErrorTranslations,
+		breaker:                    c.breaker,
+		failover:                   c.failover,
+		balances:                   c.balances,
+		throttle:                   c.throttle,
+		retry:                      c.retry,
+		orderLocks:                 c.orderLocks,
+		owner:                      c.tokenOwner(),
+		noAuth:                     c.noAuth || o.disableAuth,
+	}
+	base := c.HttpClient
+	if base == nil {
+		base = http.DefaultClient
+	}
+	switch {
+	case o.HttpClient != nil:
+		derived.HttpClient = o.HttpClient
+		derived.egressClient = nil
+	case o.Transport != nil || tunesTransport(o):
+		transport, err := newTransport(o)
+		if err != nil {
+			return nil, err
+		}
+		httpClient := *base
+		httpClient.Transport = transport
+		if o.Timeout != 0 {
+			httpClient.Timeout = o.Timeout
+		}
+		derived.HttpClient = &httpClient
+		if derived.egressClient, err = newEgressClient(o, derived.HttpClient); err != nil {
+			return nil, err
+		}
+	case o.Timeout != 0:
+		httpClient := *base
+		httpClient.Timeout = o.Timeout
+		derived.HttpClient = &httpClient
+	}
+
+	if o.Clock != nil {
+		derived.clock = o.Clock
+	}
+	derived.drift.since = derived.now()
+	if o.BaseUrl != "" {
+		derived.BaseUrl = o.BaseUrl
+		derived.breaker = nil
+		derived.failover = nil
+	}
+	if o.Failover != nil {
+		derived.failover = newFailover(derived.BaseUrl, o.Failover, derived.clock)
+	}
+	if o.CircuitBreaker != nil {
+		derived.breaker = newCircuitBreaker(o.CircuitBreaker, derived.clock)
+	} else if o.BaseUrl != "" && c.breaker != nil {
+		opts := c.breaker.opts
+		derived.breaker = newCircuitBreaker(&opts, derived.clock)
+	}
+	if o.BalanceCheck != nil {
+		derived.balances = newBalanceChecker(o.BalanceCheck, derived.clock)
+	}
+	if o.OrderThrottle != nil {
+		derived.throttle = newOrderThrottle(o.OrderThrottle, derived.clock)
+	}
+	if o.Retry != nil {
+		derived.retry = newRetryOptions(o.Retry)
+	}
+	if o.MetadataTTL != 0 {
+		derived.MetadataTTL = o.MetadataTTL
+	}
+	if o.FetchConcurrency != 0 {
+		derived.FetchConcurrency = o.FetchConcurrency
+	}
+	if o.explicit&flagDetectDrift != 0 {
+		derived.OnDrift = o.OnDrift
+	}
+	if o.explicit&flagTranslateErrors != 0 {
+		derived.ErrorTranslations = o.ErrorTranslations
+	}
+	if o.OnDeprecatedCall != nil {
+		derived.OnDeprecatedCall = o.OnDeprecatedCall
+	}
+	if o.OnRequest != nil {
+		derived.OnRequest = o.OnRequest
+	}
+	if o.Logger != nil {
+		derived.logger = o.Logger
+	}
+	if o.EgressIPCheck != nil {
+		derived.egressCheck = o.EgressIPCheck
+	}
+	if o.explicit&flagTraceRequests != 0 {
+		derived.ClientTrace = o.ClientTrace
+	}
+	if len(o.Endpoints) > 0 {
+		derived.endpoints = maps.Clone(c.endpoints)
+		if derived.endpoints == nil {
+			derived.endpoints = DefaultEndpoints()
+		}
+		maps.Copy(derived.endpoints, o.Endpoints)
+	}
+	return derived, nil
+}
+
+// inherit sets a boolean setting to the one of the parent client unless an
+// option gave it.
+func (opts *ClientOptions) inherit(flag optionFlag, field *bool, parent bool) {
+	if opts.explicit&flag == 0 {
+		*field = parent
+	}
+}
@@ -0,0 +1,324 @@
+// Command bitpin-migrate rewrites code that uses the Go Bitpin SDK with the
+// gofmt -r rules shipped with the SDK. It is the tool behind the rule sets
+// described in docs/V2_MIGRATION_PLAN.md.
+//
+// Two rule sets are built in:
+//
+//	context   rewrites v1 calls to their context-aware variants, such as
+//	          GetMarkets() to GetMarketsWithContext(ctx)
+//	v2        rewrites context-aware v1 calls to the services of the v2
+//	          client, such as GetMarketsWithContext(ctx) to Market.List(ctx)
+//
+// "all" applies both in order. A path to a file with one gofmt -r rule per
+// line may be given instead.
+//
+// Without -w nothing is changed: a migration guide listing the files and the
+// rules that apply to them is written to standard output.
+//
+// Usage:
+//
+//	bitpin-migrate [-rules context|v2|all|file] [-w] [path ...]
+//
+// Paths are files or directories, which are walked recursively; the "./..."
+// form of the go command is accepted. The default path is the current
+// directory. gofmt must be in PATH or in the bin directory of GOROOT.
+package main
+
+import (
+	"bytes"
+	"embed"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed rules/*.rules
+var builtin embed.FS
+
+// ruleSets lists the built-in rule files of each -rules value, in the order
+// they are applied.
+var ruleSets = map[string][]string{
+	"context": {"rules/context.rules"},
+	"v2":      {"rules/v2.rules"},
+	"all":     {"rules/context.rules", "rules/v2.rules"},
+}
+
+// rule is a single gofmt -r rewrite rule.
+type rule struct {
+	// text is the rule as passed to gofmt -r.
+	text string
+
+	// name is the method or function name of the pattern. Files that do not
+	// mention it are not passed to gofmt.
+	name string
+}
+
+// ruleFile is a parsed rule file.
+type ruleFile struct {
+	name  string
+	notes string
+	rules []rule
+}
+
+// change records the rules that rewrote a file.
+type change struct {
+	path  string
+	rules []rule
+}
+
+func main() {
+	flags := flag.NewFlagSet("bitpin-migrate", flag.ExitOnError)
+	set := flags.String("rules", "context", "rule set: context, v2, all, or a path to a rule file")
+	write := flags.Bool("w", false, "rewrite the files instead of printing a migration guide")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bitpin-migrate [-rules context|v2|all|file] [-w] [path ...]")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	if err := run(os.Stdout, *set, *write, flags.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "bitpin-migrate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run applies the rules of set to the Go files under paths.
+func run(out io.Writer, set string, write bool, paths []string) error {
+	gofmt, err := findGofmt()
+	if err != nil {
+		return err
+	}
+	files, err := loadRules(set)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		for _, r := range file.rules {
+			if _, err := rewrite(gofmt, r, []byte("package p\n")); err != nil {
+				return fmt.Errorf("%s: invalid rule %q: %w", file.name, r.text, err)
+			}
+		}
+	}
+
+	sources, err := goFiles(paths)
+	if err != nil {
+		return err
+	}
+	var changes []change
+	for _, path := range sources {
+		c, err := migrate(gofmt, files, path, write)
+		if err != nil {
+			return err
+		}
+		if len(c.rules) > 0 {
+			changes = append(changes, c)
+		}
+	}
+
+	if write {
+		for _, c := range changes {
+			fmt.Fprintf(out, "%s: %d rules applied\n", c.path, len(c.rules))
+		}
+		return nil
+	}
+	printGuide(out, files, changes)
+	return nil
+}
+
+// migrate applies the rules to a single file and writes it back if write is
+// set.
+func migrate(gofmt string, files []ruleFile, path string, write bool) (change, error) {
+	c := change{path: path}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+
+	current := src
+	for _, file := range files {
+		for _, r := range file.rules {
+			if !bytes.Contains(current, []byte("."+r.name+"(")) {
+				continue
+			}
+			next, err := rewrite(gofmt, r, current)
+			if err != nil {
+				return c, fmt.Errorf("%s: %w", path, err)
+			}
+			if !bytes.Equal(next, current) {
+				c.rules = append(c.rules, r)
+				current = next
+			}
+		}
+	}
+
+	if !write || len(c.rules) == 0 {
+		return c, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return c, err
+	}
+	return c, os.WriteFile(path, current, info.Mode().Perm())
+}
+
+// rewrite runs gofmt -r with a single rule on src.
+func rewrite(gofmt string, r rule, src []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(gofmt, "-r", r.text)
+	cmd.Stdin = bytes.NewReader(src)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("gofmt: %s", msg)
+		}
+		return nil, fmt.Errorf("gofmt: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// findGofmt returns the path of the gofmt binary.
+func findGofmt() (string, error) {
+	if path, err := exec.LookPath("gofmt"); err == nil {
+		return path, nil
+	}
+	goroot, err := exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		return "", fmt.Errorf("gofmt not found in PATH")
+	}
+	path := filepath.Join(strings.TrimSpace(string(goroot)), "bin", "gofmt")
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("gofmt not found in PATH or %s", filepath.Dir(path))
+	}
+	return path, nil
+}
+
+// loadRules reads the rule files of a built-in set, or the rule file at the
+// path given as set.
+func loadRules(set string) ([]ruleFile, error) {
+	var files []ruleFile
+	if names, ok := ruleSets[set]; ok {
+		for _, name := range names {
+			data, err := builtin.ReadFile(name)
+			if err != nil {
+				return nil, err
+			}
+			file, err := parseRules(strings.TrimSuffix(filepath.Base(name), ".rules"), data)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+		return files, nil
+	}
+
+	data, err := os.ReadFile(set)
+	if err != nil {
+		return nil, fmt.Errorf("unknown rule set %q: %w", set, err)
+	}
+	file, err := parseRules(set, data)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, file), nil
+}
+
+// parseRules parses a rule file. Every non-empty line that does not start with
+// "#" is a gofmt -r rule; the comment lines at the top of the file are the
+// notes printed in the migration guide.
+func parseRules(name string, data []byte) (ruleFile, error) {
+	file := ruleFile{name: name}
+	var notes []string
+	header := true
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			if header {
+				notes = append(notes, strings.TrimSpace(strings.TrimPrefix(line, "#")))
+			}
+			continue
+		}
+		header = false
+		if line == "" {
+			continue
+		}
+
+		pattern, _, ok := strings.Cut(line, "->")
+		pattern = strings.TrimSpace(pattern)
+		open := strings.Index(pattern, "(")
+		if !ok || open <= 0 {
+			return file, fmt.Errorf("%s:%d: expected \"pattern -> replacement\" with a call as pattern", name, i+1)
+		}
+		callee := pattern[:open]
+		file.rules = append(file.rules, rule{text: line, name: callee[strings.LastIndex(callee, ".")+1:]})
+	}
+	file.notes = strings.TrimSpace(strings.Join(notes, "\n"))
+	return file, nil
+}
+
+// goFiles returns the Go files under paths. Directories named vendor or
+// testdata and those starting with "." or "_" are skipped.
+func goFiles(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	var files []string
+	for _, root := range paths {
+		root = strings.TrimSuffix(root, "...")
+		root = filepath.Clean(strings.TrimSuffix(root, "/"))
+		if root == "" {
+			root = "."
+		}
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".go") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// printGuide writes the migration guide of a dry run in Markdown.
+func printGuide(out io.Writer, files []ruleFile, changes []change) {
+	fmt.Fprintln(out, "# Bitpin SDK Migration Guide")
+	fmt.Fprintln(out)
+	for _, file := range files {
+		fmt.Fprintf(out, "## Rule set %s\n\n", file.name)
+		if file.notes != "" {
+			fmt.Fprintf(out, "%s\n\n", file.notes)
+		}
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "No call sites to rewrite.")
+		return
+	}
+	fmt.Fprintln(out, "## Call Sites")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Run again with -w to apply these rules, then review the diff and build the code.")
+	for _, c := range changes {
+		fmt.Fprintf(out, "\n### %s\n\n", filepath.ToSlash(c.path))
+		for _, r := range c.rules {
+			fmt.Fprintf(out, "- `%s`\n", r.text)
+		}
+	}
+}
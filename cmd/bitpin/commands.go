@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+func runMarkets(a *app, args []string) error {
+	if err := parseFlags("markets", args, nil); err != nil {
+		return err
+	}
+	markets, err := a.client.GetMarkets()
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, m := range *markets {
+		rows = append(rows, []string{
+			m.Symbol, m.Base, m.Quote, strconv.FormatBool(m.Tradable),
+			strconv.Itoa(m.PricePrecision), strconv.Itoa(m.BaseAmountPrecision), strconv.Itoa(m.QuoteAmountPrecision),
+		})
+	}
+	return a.print(markets, []string{"SYMBOL", "BASE", "QUOTE", "TRADABLE", "PRICE_PREC", "BASE_PREC", "QUOTE_PREC"}, rows)
+}
+
+func runTickers(a *app, args []string) error {
+	flags, err := parseArgs("tickers", args, nil)
+	if err != nil {
+		return err
+	}
+	tickers, err := a.client.GetTickers()
+	if err != nil {
+		return err
+	}
+
+	selected := t.Tickers{}
+	for _, ticker := range *tickers {
+		if matchesAny(ticker.Symbol, flags.Args()) {
+			selected = append(selected, ticker)
+		}
+	}
+
+	var rows [][]string
+	for _, tk := range selected {
+		rows = append(rows, []string{
+			tk.Symbol, tk.Price, strconv.FormatFloat(tk.DailyChangePrice, 'f', -1, 64), tk.Low, tk.High,
+		})
+	}
+	return a.print(selected, []string{"SYMBOL", "PRICE", "24H_CHANGE", "LOW", "HIGH"}, rows)
+}
+
+func runOrderBook(a *app, args []string) error {
+	var depth int
+	flags, err := parseArgs("orderbook", args, func(f *flag.FlagSet) {
+		f.IntVar(&depth, "depth", 10, "number of levels per side")
+	})
+	if err != nil {
+		return err
+	}
+	symbol, err := oneArg(flags, "symbol")
+	if err != nil {
+		return err
+	}
+
+	book, err := a.client.GetOrderBook(symbol)
+	if err != nil {
+		return err
+	}
+	if depth > 0 {
+		book.Asks = book.Asks[:min(depth, len(book.Asks))]
+		book.Bids = book.Bids[:min(depth, len(book.Bids))]
+	}
+
+	var rows [][]string
+	for i := len(book.Asks) - 1; i >= 0; i-- {
+		rows = append(rows, append([]string{"ask"}, book.Asks[i]...))
+	}
+	for _, bid := range book.Bids {
+		rows = append(rows, append([]string{"bid"}, bid...))
+	}
+	return a.print(book, []string{"SIDE", "PRICE", "AMOUNT"}, rows)
+}
+
+func runTrades(a *app, args []string) error {
+	var limit int
+	flags, err := parseArgs("trades", args, func(f *flag.FlagSet) {
+		f.IntVar(&limit, "limit", 20, "maximum number of trades")
+	})
+	if err != nil {
+		return err
+	}
+	symbol, err := oneArg(flags, "symbol")
+	if err != nil {
+		return err
+	}
+
+	trades, err := a.client.GetRecentTradesWithParams(symbol, t.GetRecentTradesParams{Limit: limit})
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, tr := range trades {
+		rows = append(rows, []string{tr.Id, string(tr.Side), tr.Price, tr.BaseAmount, tr.QuoteAmount})
+	}
+	return a.print(trades, []string{"ID", "SIDE", "PRICE", "BASE_AMOUNT", "QUOTE_AMOUNT"}, rows)
+}
+
+func runBalances(a *app, args []string) error {
+	var all bool
+	flags, err := parseArgs("balances", args, func(f *flag.FlagSet) {
+		f.BoolVar(&all, "all", false, "include empty wallets")
+	})
+	if err != nil {
+		return err
+	}
+
+	wallets, err := a.client.GetWallets(t.GetWalletParams{})
+	if err != nil {
+		return err
+	}
+
+	selected := t.Wallets{}
+	for _, w := range *wallets {
+		if !matchesAny(w.Asset, flags.Args()) {
+			continue
+		}
+		if !all && isZero(w.Balance) && isZero(w.Frozen) {
+			continue
+		}
+		selected = append(selected, w)
+	}
+
+	var rows [][]string
+	for _, w := range selected {
+		rows = append(rows, []string{w.Asset, w.Service, w.Balance, w.Frozen})
+	}
+	return a.print(selected, []string{"ASSET", "SERVICE", "BALANCE", "FROZEN"}, rows)
+}
+
+func runPlace(a *app, args []string) error {
+	var params t.CreateOrderParams
+	_, err := parseArgs("place", args, func(f *flag.FlagSet) {
+		f.StringVar(&params.Symbol, "symbol", "", "market symbol, e.g. BTC_USDT")
+		f.StringVar((*string)(&params.Side), "side", "", "buy or sell")
+		f.StringVar((*string)(&params.Type), "type", "limit", "limit or market")
+		f.StringVar(&params.BaseAmount, "amount", "", "base amount")
+		f.StringVar(&params.QuoteAmount, "quote", "", "quote amount, instead of -amount")
+		f.StringVar(&params.Price, "price", "", "limit price")
+		f.StringVar(&params.Identifier, "id", "", "client order identifier")
+	})
+	if err != nil {
+		return err
+	}
+	if params.Symbol == "" || params.Side == "" {
+		return fmt.Errorf("place: -symbol and -side are required")
+	}
+	if params.BaseAmount == "" && params.QuoteAmount == "" {
+		return fmt.Errorf("place: -amount or -quote is required")
+	}
+
+	order, err := a.client.CreateOrder(params)
+	if err != nil {
+		return err
+	}
+	return a.printOrders(t.OrderStatuses{*order}, order)
+}
+
+func runCancel(a *app, args []string) error {
+	var confirm bool
+	flags, err := parseArgs("cancel", args, func(f *flag.FlagSet) {
+		f.BoolVar(&confirm, "confirm", false, "wait until the cancel is settled and report the outcome")
+	})
+	if err != nil {
+		return err
+	}
+	id, err := orderIdArg(flags)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		if err := a.client.CancelOrder(id); err != nil {
+			return err
+		}
+		return a.print(map[string]interface{}{"id": id, "canceled": true}, []string{"ID", "RESULT"}, [][]string{{strconv.Itoa(id), "cancel requested"}})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*a.timeout)
+	defer cancel()
+	result, err := a.client.CancelOrderAndConfirm(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.print(result, []string{"ID", "OUTCOME", "FILLED", "RACED"},
+		[][]string{{strconv.Itoa(id), string(result.Outcome), result.FilledBaseAmount, result.RacedBaseAmount}})
+}
+
+func runOrder(a *app, args []string) error {
+	flags, err := parseArgs("order", args, nil)
+	if err != nil {
+		return err
+	}
+	id, err := orderIdArg(flags)
+	if err != nil {
+		return err
+	}
+
+	order, err := a.client.GetOrder(id)
+	if err != nil {
+		return err
+	}
+	return a.printOrders(t.OrderStatuses{*order}, order)
+}
+
+func runOrders(a *app, args []string) error {
+	var params t.GetOrdersHistoryParams
+	_, err := parseArgs("orders", args, func(f *flag.FlagSet) {
+		f.StringVar(&params.Symbol, "symbol", "", "market symbol")
+		f.StringVar((*string)(&params.State), "state", "", "order state, e.g. active")
+		f.StringVar((*string)(&params.Side), "side", "", "buy or sell")
+		f.IntVar(&params.Limit, "limit", 20, "maximum number of orders")
+	})
+	if err != nil {
+		return err
+	}
+
+	orders, err := a.client.GetOrdersHistory(params)
+	if err != nil {
+		return err
+	}
+	return a.printOrders(*orders, orders)
+}
+
+func runFills(a *app, args []string) error {
+	var params t.GetUserTradesParams
+	_, err := parseArgs("fills", args, func(f *flag.FlagSet) {
+		f.StringVar(&params.Symbol, "symbol", "", "market symbol")
+		f.IntVar(&params.Limit, "limit", 20, "maximum number of fills")
+	})
+	if err != nil {
+		return err
+	}
+
+	fills, err := a.client.GetUserTrades(params)
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, f := range *fills {
+		rows = append(rows, []string{
+			strconv.Itoa(f.Id), f.CreatedAt.Format("2006-01-02 15:04:05"), f.Symbol, string(f.Side), f.Price,
+			f.BaseAmount, f.Commission + " " + f.CommissionCurrency, strconv.Itoa(f.OrderId),
+		})
+	}
+	return a.print(fills, []string{"ID", "TIME", "SYMBOL", "SIDE", "PRICE", "AMOUNT", "FEE", "ORDER"}, rows)
+}
+
+// printOrders prints orders as a table, or v as JSON.
+func (a *app) printOrders(orders t.OrderStatuses, v interface{}) error {
+	var rows [][]string
+	for _, o := range orders {
+		rows = append(rows, []string{
+			strconv.Itoa(o.Id), o.CreatedAt.Format("2006-01-02 15:04:05"), o.Symbol, string(o.Type), string(o.Side), string(o.State),
+			o.Price, o.BaseAmount, o.DealedBaseAmount, o.Identifier,
+		})
+	}
+	return a.print(v, []string{"ID", "CREATED", "SYMBOL", "TYPE", "SIDE", "STATE", "PRICE", "AMOUNT", "FILLED", "IDENTIFIER"}, rows)
+}
+
+// print writes v as indented JSON, or header and rows as an aligned table.
+func (a *app) print(v interface{}, header []string, rows [][]string) error {
+	if a.format == "json" {
+		enc := json.NewEncoder(a.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// parseFlags parses the flags of a command that takes no positional arguments.
+func parseFlags(name string, args []string, define func(*flag.FlagSet)) error {
+	flags, err := parseArgs(name, args, define)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("%s: unexpected arguments %v", name, flags.Args())
+	}
+	return nil
+}
+
+// parseArgs parses the flags of a command defined by define.
+func parseArgs(name string, args []string, define func(*flag.FlagSet)) (*flag.FlagSet, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: bitpin %s\n", commands[name].usage)
+		flags.PrintDefaults()
+	}
+	if define != nil {
+		define(flags)
+	}
+	return flags, flags.Parse(args)
+}
+
+// oneArg returns the single positional argument of a command.
+func oneArg(flags *flag.FlagSet, what string) (string, error) {
+	if flags.NArg() != 1 {
+		return "", fmt.Errorf("%s: expected exactly one %s", flags.Name(), what)
+	}
+	return flags.Arg(0), nil
+}
+
+// orderIdArg returns the order id given as the single positional argument.
+func orderIdArg(flags *flag.FlagSet) (int, error) {
+	arg, err := oneArg(flags, "order id")
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid order id %q", flags.Name(), arg)
+	}
+	return id, nil
+}
+
+// matchesAny reports whether value matches one of the filters, or whether
+// there are no filters. Symbols are compared in canonical form.
+func matchesAny(value string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		if u.SameSymbol(value, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// isZero reports whether a decimal string is empty or zero.
+func isZero(amount string) bool {
+	f, err := strconv.ParseFloat(amount, 64)
+	return amount == "" || (err == nil && f == 0)
+}
@@ -0,0 +1,170 @@
+// Command bitpin is a command-line client for the Bitpin API built on the SDK.
+// It exposes the main market, wallet and order operations with JSON or table
+// output, and is meant for operations work and quick debugging of API access.
+//
+// Credentials are read from the environment:
+//
+//	BITPIN_API_KEY      API key, required for wallet and order commands
+//	BITPIN_SECRET_KEY   secret key, required for wallet and order commands
+//	BITPIN_BASE_URL     optional base URL overriding the production API
+//
+// Usage:
+//
+//	bitpin [-o table|json] [-timeout 10s] [-fake] <command> [arguments]
+//
+// Run "bitpin help" for the list of commands.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	bitpin "github.com/rzabhd80/go-sdk-bitpin"
+	"github.com/rzabhd80/go-sdk-bitpin/bitpintest"
+)
+
+// command is a single CLI subcommand.
+type command struct {
+	// usage is the argument synopsis shown in help.
+	usage string
+
+	// summary is a one-line description shown in help.
+	summary string
+
+	// auth reports whether the command needs API credentials.
+	auth bool
+
+	// run executes the command with the arguments after its name.
+	run func(app *app, args []string) error
+}
+
+// commands maps command names to their implementations. It is filled in init,
+// since the commands refer back to it for their usage text.
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"markets":   {usage: "markets", summary: "list markets and their precision", run: runMarkets},
+		"tickers":   {usage: "tickers [symbol...]", summary: "show tickers, optionally only for the given symbols", run: runTickers},
+		"orderbook": {usage: "orderbook [-depth n] <symbol>", summary: "show the order book of a market", run: runOrderBook},
+		"trades":    {usage: "trades [-limit n] <symbol>", summary: "show recent trades of a market", run: runTrades},
+		"balances":  {usage: "balances [-all] [asset...]", summary: "show wallet balances", auth: true, run: runBalances},
+		"place":     {usage: "place -symbol s -side buy|sell [-type limit|market] -amount a|-quote q [-price p] [-id identifier]", summary: "place an order", auth: true, run: runPlace},
+		"cancel":    {usage: "cancel [-confirm] <order-id>", summary: "cancel an order", auth: true, run: runCancel},
+		"order":     {usage: "order <order-id>", summary: "show a single order", auth: true, run: runOrder},
+		"orders":    {usage: "orders [-symbol s] [-state s] [-side s] [-limit n]", summary: "show order history", auth: true, run: runOrders},
+		"fills":     {usage: "fills [-symbol s] [-limit n]", summary: "show your recent fills", auth: true, run: runFills},
+	}
+}
+
+// app holds the global settings shared by all commands.
+type app struct {
+	out     io.Writer
+	format  string
+	timeout time.Duration
+	fake    bool
+
+	exchange *bitpintest.Server
+	client   *bitpin.Client
+}
+
+func main() {
+	a := &app{out: os.Stdout}
+
+	flags := flag.NewFlagSet("bitpin", flag.ExitOnError)
+	flags.StringVar(&a.format, "o", "table", "output format: table or json")
+	flags.DurationVar(&a.timeout, "timeout", 10*time.Second, "request timeout")
+	flags.BoolVar(&a.fake, "fake", false, "run against an in-process fake exchange")
+	flags.Usage = func() { usage(flags.Output()) }
+	_ = flags.Parse(os.Args[1:])
+
+	if err := a.run(flags.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "bitpin: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches to the command named by the first argument.
+func (a *app) run(args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" {
+		usage(a.out)
+		return nil
+	}
+	if a.format != "table" && a.format != "json" {
+		return fmt.Errorf("unknown output format %q", a.format)
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q, run \"bitpin help\"", args[0])
+	}
+
+	defer a.close()
+	if err := a.connect(cmd.auth); err != nil {
+		return err
+	}
+	return cmd.run(a, args[1:])
+}
+
+// connect creates the API client, authenticating when the command needs it.
+func (a *app) connect(auth bool) error {
+	opts := bitpin.ClientOptions{
+		Timeout:     a.timeout,
+		AutoRefresh: true,
+		BaseUrl:     os.Getenv("BITPIN_BASE_URL"),
+	}
+
+	if a.fake {
+		a.exchange = bitpintest.NewServer()
+		opts.BaseUrl = a.exchange.URL
+		if auth {
+			opts.ApiKey = bitpintest.ApiKey
+			opts.SecretKey = bitpintest.SecretKey
+		}
+	} else if auth {
+		opts.ApiKey = os.Getenv("BITPIN_API_KEY")
+		opts.SecretKey = os.Getenv("BITPIN_SECRET_KEY")
+		if opts.ApiKey == "" || opts.SecretKey == "" {
+			return fmt.Errorf("BITPIN_API_KEY and BITPIN_SECRET_KEY must be set")
+		}
+	}
+
+	client, err := bitpin.NewClient(opts)
+	if err != nil {
+		return err
+	}
+	a.client = client
+	return nil
+}
+
+// close releases the fake exchange, if one was started.
+func (a *app) close() {
+	if a.exchange != nil {
+		a.exchange.Close()
+	}
+}
+
+// usage prints the global help text.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: bitpin [-o table|json] [-timeout 10s] [-fake] <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n        %s\n", commands[name].usage, commands[name].summary)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Environment:")
+	fmt.Fprintln(w, "  BITPIN_API_KEY, BITPIN_SECRET_KEY   credentials for wallet and order commands")
+	fmt.Fprintln(w, "  BITPIN_BASE_URL                     override the API base URL")
+}
@@ -0,0 +1,361 @@
+package bitpin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clientConfig is the schema of client configuration files.
+type clientConfig struct {
+	BaseUrl          string                 `json:"base_url"`
+	Timeout          configDuration         `json:"timeout"`
+	Credentials      credentialsConfig      `json:"credentials"`
+	AutoAuth         bool                   `json:"auto_auth"`
+	AutoRefresh      bool                   `json:"auto_refresh"`
+	Transport        transportConfig        `json:"transport"`
+	Metadata         metadataConfig         `json:"metadata"`
+	FetchConcurrency int                    `json:"fetch_concurrency"`
+	DetectDrift      bool                   `json:"detect_drift"`
+	Debug            bool                   `json:"debug"`
+	TraceRequests    bool                   `json:"trace_requests"`
+	APIv2            bool                   `json:"api_v2"`
+	Failover         *failoverConfig        `json:"failover"`
+	CircuitBreaker   *circuitBreakerConfig  `json:"circuit_breaker"`
+	BalanceCheck     *balanceCheckConfig    `json:"balance_check"`
+	OrderThrottle    *orderThrottleConfig   `json:"order_throttle"`
+	Retry            *retryConfig           `json:"retry"`
+	EgressIPCheck    *egressIPCheckConfig   `json:"egress_ip_check"`
+	Errors           errorsConfig           `json:"errors"`
+	Endpoints        map[Operation]Endpoint `json:"endpoints"`
+}
+
+// credentialsConfig references the API credentials. Each credential can be
+// given inline, by the name of an environment variable, or by the path of a
+// file holding it.
+type credentialsConfig struct {
+	ApiKey        string `json:"api_key"`
+	ApiKeyEnv     string `json:"api_key_env"`
+	ApiKeyFile    string `json:"api_key_file"`
+	SecretKey     string `json:"secret_key"`
+	SecretKeyEnv  string `json:"secret_key_env"`
+	SecretKeyFile string `json:"secret_key_file"`
+}
+
+type transportConfig struct {
+	MaxIdleConnsPerHost int               `json:"max_idle_conns_per_host"`
+	IdleConnTimeout     configDuration    `json:"idle_conn_timeout"`
+	TLSHandshakeTimeout configDuration    `json:"tls_handshake_timeout"`
+	DisableCompression  bool              `json:"disable_compression"`
+	TLS                 *tlsConfig        `json:"tls"`
+	DNSServer           string            `json:"dns_server"`
+	PinnedHosts         map[string]string `json:"pinned_hosts"`
+}
+
+type tlsConfig struct {
+	CAFile     string   `json:"ca_file"`
+	MinVersion string   `json:"min_version"`
+	PinnedKeys []string `json:"pinned_keys"`
+}
+
+type metadataConfig struct {
+	TTL                        configDuration `json:"ttl"`
+	DisableConditionalRequests bool           `json:"disable_conditional_requests"`
+}
+
+type circuitBreakerConfig struct {
+	FailureThreshold int            `json:"failure_threshold"`
+	OpenTimeout      configDuration `json:"open_timeout"`
+	HalfOpenProbes   int            `json:"half_open_probes"`
+}
+
+type balanceCheckConfig struct {
+	FeeRate   float64        `json:"fee_rate"`
+	WalletTTL configDuration `json:"wallet_ttl"`
+	Service   string         `json:"service"`
+}
+
+type orderThrottleConfig struct {
+	MaxOpenOrders int            `json:"max_open_orders"`
+	MinInterval   configDuration `json:"min_interval"`
+}
+
+type retryConfig struct {
+	MaxAttempts  int            `json:"max_attempts"`
+	InitialDelay configDuration `json:"initial_delay"`
+	MaxDelay     configDuration `json:"max_delay"`
+	Multiplier   float64        `json:"multiplier"`
+	Jitter       float64        `json:"jitter"`
+}
+
+type failoverConfig struct {
+	URLs         []string       `json:"urls"`
+	RecoverAfter configDuration `json:"recover_after"`
+}
+
+type egressIPCheckConfig struct {
+	URL    string `json:"url"`
+	Strict bool   `json:"strict"`
+}
+
+type errorsConfig struct {
+	Translate    bool              `json:"translate"`
+	Translations map[string]string `json:"translations"`
+}
+
+// configDuration is a duration given as a Go duration string, such as "1m30s",
+// or as a number of seconds.
+type configDuration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *configDuration) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		*d = configDuration(seconds * float64(time.Second))
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		*d = configDuration(seconds * float64(time.Second))
+		return nil
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = configDuration(parsed)
+	return nil
+}
+
+// LoadClientOptions reads client options from a JSON or YAML configuration
+// file. The format is chosen by the file extension (".json", ".yaml" or
+// ".yml"). Unknown keys are rejected, so typos do not go unnoticed. YAML files
+// may use nested mappings, scalars, comments, and block or single-line flow
+// sequences of scalars; anchors and multi-line strings are not supported.
+// Unquoted values of string settings stay strings, so an all-digit API key
+// needs no quotes.
+//
+// Credentials should not be stored in the file itself. Instead, reference an
+// environment variable with api_key_env and secret_key_env, or a file with
+// api_key_file and secret_key_file. Relative file paths are resolved against
+// the directory of the configuration file.
+//
+// Example configuration:
+//
+//	base_url: https://api.bitpin.ir
+//	timeout: 10s
+//	auto_refresh: true
+//	api_v2: true
+//	credentials:
+//	  api_key_env: BITPIN_API_KEY
+//	  secret_key_file: secrets/bitpin_secret
+//	transport:
+//	  max_idle_conns_per_host: 16
+//	  idle_conn_timeout: 90s
+//	  tls:
+//	    ca_file: certs/corporate-ca.pem
+//	    min_version: "1.2"
+//	    pinned_keys: [sha256/AAAA..., sha256/BBBB...]
+//	failover:
+//	  urls:
+//	    - https://mirror1.example.com
+//	    - https://mirror2.example.com
+//	  recover_after: 1m
+//	retry:
+//	  max_attempts: 4
+//	  initial_delay: 200ms
+//	  max_delay: 5s
+//	  jitter: 0.2
+//	metadata:
+//	  ttl: 10m
+//	circuit_breaker:
+//	  failure_threshold: 5
+//	  open_timeout: 30s
+//	balance_check:
+//	  fee_rate: 0.002
+//	order_throttle:
+//	  max_open_orders: 10
+//	  min_interval: 500ms
+//	errors:
+//	  translate: true
+//	endpoints:
+//	  tickers:
+//	    path: /mkt/tickers/
+//	    version: v1
+//
+// Example:
+//
+//	opts, err := bitpin.LoadClientOptions("config/production.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client, err := bitpin.NewClient(opts)
+func LoadClientOptions(path string) (ClientOptions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ClientOptions{}, &GoBitpinError{Message: "failed to read client configuration", Err: err}
+	}
+
+	var document []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		document = data
+	case ".yaml", ".yml":
+		values, err := parseYAML(data)
+		if err != nil {
+			return ClientOptions{}, &GoBitpinError{Message: fmt.Sprintf("failed to parse %s", path), Err: err}
+		}
+		if document, err = json.Marshal(resolveYAML(values, reflect.TypeOf(clientConfig{}))); err != nil {
+			return ClientOptions{}, err
+		}
+	default:
+		return ClientOptions{}, &GoBitpinError{
+			Message: fmt.Sprintf("unsupported configuration format %q, use .json, .yaml or .yml", filepath.Ext(path)),
+		}
+	}
+
+	var cfg clientConfig
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return ClientOptions{}, &GoBitpinError{Message: fmt.Sprintf("invalid configuration in %s", path), Err: err}
+	}
+	return cfg.options(filepath.Dir(path))
+}
+
+// options converts the configuration into client options, resolving credential
+// references relative to dir.
+func (cfg *clientConfig) options(dir string) (ClientOptions, error) {
+	apiKey, err := resolveCredential("api_key", cfg.Credentials.ApiKey, cfg.Credentials.ApiKeyEnv, cfg.Credentials.ApiKeyFile, dir)
+	if err != nil {
+		return ClientOptions{}, err
+	}
+	secretKey, err := resolveCredential("secret_key", cfg.Credentials.SecretKey, cfg.Credentials.SecretKeyEnv, cfg.Credentials.SecretKeyFile, dir)
+	if err != nil {
+		return ClientOptions{}, err
+	}
+
+	opts := ClientOptions{
+		BaseUrl:                    cfg.BaseUrl,
+		Timeout:                    time.Duration(cfg.Timeout),
+		ApiKey:                     apiKey,
+		SecretKey:                  secretKey,
+		AutoAuth:                   cfg.AutoAuth,
+		AutoRefresh:                cfg.AutoRefresh,
+		MaxIdleConnsPerHost:        cfg.Transport.MaxIdleConnsPerHost,
+		IdleConnTimeout:            time.Duration(cfg.Transport.IdleConnTimeout),
+		TLSHandshakeTimeout:        time.Duration(cfg.Transport.TLSHandshakeTimeout),
+		DisableCompression:         cfg.Transport.DisableCompression,
+		MetadataTTL:                time.Duration(cfg.Metadata.TTL),
+		DisableConditionalRequests: cfg.Metadata.DisableConditionalRequests,
+		FetchConcurrency:           cfg.FetchConcurrency,
+		DetectDrift:                cfg.DetectDrift,
+		Debug:                      cfg.Debug,
+		TraceRequests:              cfg.TraceRequests,
+		UseAPIv2:                   cfg.APIv2,
+		TranslateErrors:            cfg.Errors.Translate,
+		ErrorTranslations:          cfg.Errors.Translations,
+	}
+	if fo := cfg.Failover; fo != nil {
+		opts.Failover = &FailoverOptions{URLs: fo.URLs, RecoverAfter: time.Duration(fo.RecoverAfter)}
+	}
+	if cb := cfg.CircuitBreaker; cb != nil {
+		opts.CircuitBreaker = &CircuitBreakerOptions{
+			FailureThreshold: cb.FailureThreshold,
+			OpenTimeout:      time.Duration(cb.OpenTimeout),
+			HalfOpenProbes:   cb.HalfOpenProbes,
+		}
+	}
+	if bc := cfg.BalanceCheck; bc != nil {
+		opts.BalanceCheck = &BalanceCheckOptions{
+			FeeRate:   bc.FeeRate,
+			WalletTTL: time.Duration(bc.WalletTTL),
+			Service:   bc.Service,
+		}
+	}
+	if tc := cfg.Transport.TLS; tc != nil {
+		minVersion, err := parseTLSVersion(tc.MinVersion)
+		if err != nil {
+			return ClientOptions{}, &GoBitpinError{Message: "invalid transport.tls.min_version", Err: err}
+		}
+		caFile := tc.CAFile
+		if caFile != "" && !filepath.IsAbs(caFile) {
+			caFile = filepath.Join(dir, caFile)
+		}
+		opts.TLS = &TLSOptions{CAFile: caFile, MinVersion: minVersion, PinnedKeys: tc.PinnedKeys}
+	}
+	if cfg.Transport.DNSServer != "" {
+		opts.Resolver = DNSServerResolver(cfg.Transport.DNSServer)
+	}
+	if len(cfg.Transport.PinnedHosts) > 0 {
+		opts.PinnedHosts = cfg.Transport.PinnedHosts
+	}
+	if len(cfg.Endpoints) > 0 {
+		opts.Endpoints = cfg.Endpoints
+	}
+	if ot := cfg.OrderThrottle; ot != nil {
+		opts.OrderThrottle = &OrderThrottleOptions{
+			MaxOpenOrders: ot.MaxOpenOrders,
+			MinInterval:   time.Duration(ot.MinInterval),
+		}
+	}
+	if rc := cfg.Retry; rc != nil {
+		opts.Retry = &RetryOptions{MaxAttempts: rc.MaxAttempts}
+		if rc.InitialDelay != 0 || rc.MaxDelay != 0 || rc.Multiplier != 0 || rc.Jitter != 0 {
+			opts.Retry.Backoff = ExponentialBackoff{
+				Initial:    time.Duration(rc.InitialDelay),
+				Max:        time.Duration(rc.MaxDelay),
+				Multiplier: rc.Multiplier,
+				Jitter:     rc.Jitter,
+			}
+		}
+	}
+	if ec := cfg.EgressIPCheck; ec != nil {
+		opts.EgressIPCheck = &EgressIPCheckOptions{URL: ec.URL, Strict: ec.Strict}
+	}
+	return opts, nil
+}
+
+// resolveCredential returns a credential given inline, by environment variable
+// or by file. At most one of the sources may be set.
+func resolveCredential(name, inline, env, file, dir string) (string, error) {
+	set := 0
+	for _, source := range []string{inline, env, file} {
+		if source != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return "", &GoBitpinError{
+			Message: fmt.Sprintf("credentials: only one of %s, %s_env and %s_file may be set", name, name, name),
+		}
+	}
+
+	switch {
+	case env != "":
+		value := strings.TrimSpace(os.Getenv(env))
+		if value == "" {
+			return "", &GoBitpinError{Message: fmt.Sprintf("credentials: %s_env refers to %s, which is not set", name, env)}
+		}
+		return value, nil
+	case file != "":
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", &GoBitpinError{Message: fmt.Sprintf("credentials: failed to read %s_file", name), Err: err}
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return inline, nil
+}
@@ -0,0 +1,199 @@
+package bitpin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// ErrNoConversionPath is returned when two assets are not connected by any
+// chain of markets with a known price
+var ErrNoConversionPath = &GoBitpinError{Message: "no conversion path"}
+
+// ConversionStep is a single market hop of a conversion path.
+type ConversionStep struct {
+	// Symbol is the market used for the hop, such as "DOGE_USDT".
+	Symbol string
+
+	// From and To are the assets before and after the hop.
+	From string
+	To   string
+
+	// Side is the order side that performs the hop: "sell" when From is the
+	// base asset of the market and "buy" when it is the quote asset.
+	Side t.OrderSide
+
+	// Rate is the amount of To received for one unit of From, based on the
+	// last traded price of the market.
+	Rate float64
+}
+
+// ConversionPath is a chain of markets that converts one asset into another.
+type ConversionPath struct {
+	// From and To are the source and target assets, in canonical form.
+	From string
+	To   string
+
+	// Steps are the market hops, in order. It is empty when From and To are
+	// the same asset.
+	Steps []ConversionStep
+
+	// Rate is the estimated amount of To received for one unit of From. It is
+	// the product of the step rates and ignores fees, spread and depth.
+	Rate float64
+}
+
+// Convert returns the estimated value of amount units of From in To.
+func (p *ConversionPath) Convert(amount float64) float64 {
+	return amount * p.Rate
+}
+
+// Converter finds conversion paths between assets from a snapshot of markets
+// and tickers. It is safe for concurrent use, since it never changes after
+// creation.
+type Converter struct {
+	// edges maps an asset to the hops that start at it, sorted by target asset.
+	edges map[string][]ConversionStep
+}
+
+// NewConverter builds a Converter from market metadata and tickers, such as
+// those returned by GetMarkets and GetTickers. Markets without a ticker or
+// with a non-positive price are ignored. Currency codes are canonicalized, so
+// TMN and IRT are treated as the same asset.
+//
+// Example:
+//
+//	markets, _ := client.GetMarkets()
+//	tickers, _ := client.GetTickers()
+//	conv := bitpin.NewConverter(*markets, *tickers)
+//	path, err := conv.Path("DOGE", "IRT")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("1 DOGE ~ %.0f IRT via %d markets\n", path.Rate, len(path.Steps))
+func NewConverter(markets t.Markets, tickers t.Tickers) *Converter {
+	prices := make(map[string]float64, len(tickers))
+	for _, ticker := range tickers {
+		price, err := strconv.ParseFloat(ticker.Price, 64)
+		if err != nil || price <= 0 {
+			continue
+		}
+		prices[u.CanonicalSymbol(ticker.Symbol)] = price
+	}
+
+	conv := &Converter{edges: make(map[string][]ConversionStep)}
+	for _, market := range markets {
+		price, ok := prices[u.CanonicalSymbol(market.Symbol)]
+		if !ok {
+			continue
+		}
+		base, quote := u.CanonicalCurrency(market.Base), u.CanonicalCurrency(market.Quote)
+		if base == "" || quote == "" || base == quote {
+			continue
+		}
+		conv.edges[base] = append(conv.edges[base], ConversionStep{
+			Symbol: market.Symbol, From: base, To: quote, Side: t.SideSell, Rate: price,
+		})
+		conv.edges[quote] = append(conv.edges[quote], ConversionStep{
+			Symbol: market.Symbol, From: quote, To: base, Side: t.SideBuy, Rate: 1 / price,
+		})
+	}
+	for _, steps := range conv.edges {
+		sort.Slice(steps, func(i, j int) bool {
+			if steps[i].To != steps[j].To {
+				return steps[i].To < steps[j].To
+			}
+			return steps[i].Symbol < steps[j].Symbol
+		})
+	}
+	return conv
+}
+
+// Converter fetches markets and tickers and returns a Converter built from
+// them. Markets are served from the metadata cache when MetadataTTL is set;
+// tickers are always fetched, since prices change constantly.
+func (c *Client) Converter() (*Converter, error) {
+	markets, err := c.GetMarkets()
+	if err != nil {
+		return nil, err
+	}
+	tickers, err := c.GetTickers()
+	if err != nil {
+		return nil, err
+	}
+
+	var m t.Markets
+	if markets != nil {
+		m = *markets
+	}
+	var tk t.Tickers
+	if tickers != nil {
+		tk = *tickers
+	}
+	return NewConverter(m, tk), nil
+}
+
+// Path returns the conversion path with the fewest hops between two assets.
+// Among paths with the same number of hops, the first in alphabetical order of
+// the intermediate assets is used, so results are deterministic.
+//
+// Returns an error wrapping ErrNoConversionPath if the assets are not
+// connected.
+func (c *Converter) Path(from, to string) (*ConversionPath, error) {
+	from, to = u.CanonicalCurrency(from), u.CanonicalCurrency(to)
+	path := &ConversionPath{From: from, To: to, Rate: 1}
+	if from == to {
+		return path, nil
+	}
+
+	// Breadth-first search; via records the hop used to reach each asset.
+	via := map[string]ConversionStep{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 && !hasKey(via, to) {
+		asset := queue[0]
+		queue = queue[1:]
+		for _, step := range c.edges[asset] {
+			if hasKey(via, step.To) {
+				continue
+			}
+			via[step.To] = step
+			queue = append(queue, step.To)
+		}
+	}
+
+	if !hasKey(via, to) {
+		return nil, &GoBitpinError{
+			Message: fmt.Sprintf("cannot convert %s to %s", from, to),
+			Err:     ErrNoConversionPath,
+		}
+	}
+
+	for asset := to; asset != from; asset = via[asset].From {
+		path.Steps = append(path.Steps, via[asset])
+	}
+	for i, j := 0, len(path.Steps)-1; i < j; i, j = i+1, j-1 {
+		path.Steps[i], path.Steps[j] = path.Steps[j], path.Steps[i]
+	}
+	for _, step := range path.Steps {
+		path.Rate *= step.Rate
+	}
+	return path, nil
+}
+
+// Convert returns the estimated value of amount units of from in to, using the
+// path returned by Path.
+func (c *Converter) Convert(amount float64, from, to string) (float64, error) {
+	path, err := c.Path(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return path.Convert(amount), nil
+}
+
+func hasKey(m map[string]ConversionStep, key string) bool {
+	_, ok := m[key]
+	return ok
+}
@@ -0,0 +1,303 @@
+package bitpin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrMissingCredentials is returned when the client has to authenticate but
+// has no API key and secret key
+var ErrMissingCredentials = &GoBitpinError{Message: "API key and/or secret key are empty"}
+
+// Credentials are the API key and secret key used to authenticate.
+type Credentials struct {
+	ApiKey    string
+	SecretKey string
+}
+
+// CredentialProvider supplies the API credentials of a client on demand, so
+// they are not kept in ClientOptions or in the client. The client asks the
+// provider every time it has to authenticate, so rotated secrets are picked
+// up without restarting.
+type CredentialProvider interface {
+	// Credentials returns the current API key and secret key.
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialProviderFunc adapts a function to a CredentialProvider.
+type CredentialProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials implements CredentialProvider.
+func (f CredentialProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// EnvCredentials reads the credentials from environment variables.
+type EnvCredentials struct {
+	// ApiKeyVar is the variable holding the API key. Defaults to
+	// BITPIN_API_KEY.
+	ApiKeyVar string
+
+	// SecretKeyVar is the variable holding the secret key. Defaults to
+	// BITPIN_SECRET_KEY.
+	SecretKeyVar string
+}
+
+// Credentials implements CredentialProvider.
+func (p EnvCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	apiKeyVar, secretKeyVar := p.ApiKeyVar, p.SecretKeyVar
+	if apiKeyVar == "" {
+		apiKeyVar = EnvPrefix + "API_KEY"
+	}
+	if secretKeyVar == "" {
+		secretKeyVar = EnvPrefix + "SECRET_KEY"
+	}
+	creds := Credentials{
+		ApiKey:    strings.TrimSpace(os.Getenv(apiKeyVar)),
+		SecretKey: strings.TrimSpace(os.Getenv(secretKeyVar)),
+	}
+	if creds.ApiKey == "" || creds.SecretKey == "" {
+		return Credentials{}, &GoBitpinError{
+			Message: fmt.Sprintf("%s and %s must be set", apiKeyVar, secretKeyVar),
+			Err:     ErrMissingCredentials,
+		}
+	}
+	return creds, nil
+}
+
+// FileCredentials reads the credentials from files, such as mounted secrets.
+// The files are read on every call, so rotated secrets are picked up.
+type FileCredentials struct {
+	// ApiKeyFile is the path of the file holding the API key.
+	ApiKeyFile string
+
+	// SecretKeyFile is the path of the file holding the secret key.
+	SecretKeyFile string
+}
+
+// Credentials implements CredentialProvider.
+func (p FileCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	var creds Credentials
+	for _, f := range []struct {
+		path  string
+		value *string
+	}{{p.ApiKeyFile, &creds.ApiKey}, {p.SecretKeyFile, &creds.SecretKey}} {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return Credentials{}, &GoBitpinError{Message: "failed to read credentials", Err: err}
+		}
+		*f.value = strings.TrimSpace(string(data))
+		if *f.value == "" {
+			return Credentials{}, &GoBitpinError{Message: fmt.Sprintf("%s is empty", f.path), Err: ErrMissingCredentials}
+		}
+	}
+	return creds, nil
+}
+
+// VaultCredentials reads the credentials from a secret of the KV version 2
+// secrets engine of HashiCorp Vault, using its HTTP API.
+type VaultCredentials struct {
+	// Address is the address of the Vault server, such as
+	// "https://vault.example.com:8200". Defaults to VAULT_ADDR.
+	Address string
+
+	// Token authenticates with Vault. Defaults to VAULT_TOKEN.
+	Token string
+
+	// Namespace is the Vault Enterprise namespace. It is optional.
+	Namespace string
+
+	// Mount is the mount path of the secrets engine. Defaults to "secret".
+	Mount string
+
+	// Path is the path of the secret within the mount, such as "bitpin/bot".
+	Path string
+
+	// ApiKeyField and SecretKeyField are the keys of the secret holding the
+	// credentials. They default to "api_key" and "secret_key".
+	ApiKeyField    string
+	SecretKeyField string
+
+	// HttpClient sends the requests to Vault. Defaults to
+	// http.DefaultClient.
+	HttpClient *http.Client
+}
+
+// Credentials implements CredentialProvider.
+func (p VaultCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	address, token := p.Address, p.Token
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if address == "" || token == "" || p.Path == "" {
+		return Credentials{}, &GoBitpinError{Message: "vault credentials: address, token and path are required"}
+	}
+	mount := p.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+
+	secretURL, err := url.JoinPath(address, "v1", mount, "data", p.Path)
+	if err != nil {
+		return Credentials{}, &GoBitpinError{Message: "vault credentials: invalid address", Err: err}
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", secretURL, nil)
+	if err != nil {
+		return Credentials{}, &GoBitpinError{Message: "vault credentials: invalid request", Err: err}
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if p.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Namespace)
+	}
+
+	httpClient := p.HttpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Credentials{}, &GoBitpinError{Message: "vault credentials: request failed", Err: err}
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return Credentials{}, &GoBitpinError{Message: fmt.Sprintf("vault credentials: %s answered %s", p.Path, resp.Status)}
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return Credentials{}, &GoBitpinError{Message: "vault credentials: invalid response", Err: err}
+	}
+	return credentialsFromFields(secret.Data.Data, p.ApiKeyField, p.SecretKeyField, "vault secret "+p.Path)
+}
+
+// credentialsFromFields extracts the credentials from the fields of a secret.
+func credentialsFromFields(fields map[string]interface{}, apiKeyField, secretKeyField, source string) (Credentials, error) {
+	if apiKeyField == "" {
+		apiKeyField = "api_key"
+	}
+	if secretKeyField == "" {
+		secretKeyField = "secret_key"
+	}
+	apiKey, _ := fields[apiKeyField].(string)
+	secretKey, _ := fields[secretKeyField].(string)
+	if apiKey == "" || secretKey == "" {
+		return Credentials{}, &GoBitpinError{
+			Message: fmt.Sprintf("%s has no %s and %s", source, apiKeyField, secretKeyField),
+			Err:     ErrMissingCredentials,
+		}
+	}
+	return Credentials{ApiKey: apiKey, SecretKey: secretKey}, nil
+}
+
+// KeyringCredentials reads the credentials from the keyring of the operating
+// system: the login keychain on macOS, through the security tool, and the
+// Secret Service (GNOME Keyring, KWallet) on Linux, through secret-tool. Other
+// systems are not supported.
+//
+// Store the credentials as two generic passwords of Service, one per account:
+//
+//	security add-generic-password -s bitpin -a api_key -w            # macOS
+//	secret-tool store --label=bitpin service bitpin account api_key  # Linux
+type KeyringCredentials struct {
+	// Service is the service the passwords are stored under. Defaults to
+	// "bitpin".
+	Service string
+
+	// ApiKeyAccount and SecretKeyAccount are the accounts of the passwords.
+	// They default to "api_key" and "secret_key".
+	ApiKeyAccount    string
+	SecretKeyAccount string
+}
+
+// Credentials implements CredentialProvider.
+func (p KeyringCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	service := p.Service
+	if service == "" {
+		service = "bitpin"
+	}
+	apiKeyAccount, secretKeyAccount := p.ApiKeyAccount, p.SecretKeyAccount
+	if apiKeyAccount == "" {
+		apiKeyAccount = "api_key"
+	}
+	if secretKeyAccount == "" {
+		secretKeyAccount = "secret_key"
+	}
+
+	apiKey, err := keyringPassword(ctx, service, apiKeyAccount)
+	if err != nil {
+		return Credentials{}, err
+	}
+	secretKey, err := keyringPassword(ctx, service, secretKeyAccount)
+	if err != nil {
+		return Credentials{}, err
+	}
+	return Credentials{ApiKey: apiKey, SecretKey: secretKey}, nil
+}
+
+// keyringPassword looks up a password in the keyring of the operating system.
+func keyringPassword(ctx context.Context, service, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.CommandContext(ctx, "secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return "", &GoBitpinError{Message: fmt.Sprintf("keyring credentials are not supported on %s", runtime.GOOS)}
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		message := fmt.Sprintf("failed to read %s/%s from the keyring", service, account)
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			message += " (" + detail + ")"
+		}
+		return "", &GoBitpinError{Message: message, Err: err}
+	}
+	password := strings.TrimSpace(string(out))
+	if password == "" {
+		return "", &GoBitpinError{Message: fmt.Sprintf("keyring entry %s/%s is empty", service, account), Err: ErrMissingCredentials}
+	}
+	return password, nil
+}
+
+// hasCredentials reports whether the client can authenticate on its own.
+func (c *Client) hasCredentials() bool {
+	return c.credentials != nil || (c.ApiKey != "" && c.SecretKey != "")
+}
+
+// reauthenticate authenticates with the API key and secret key of the client,
+// or with those of its CredentialProvider.
+func (c *Client) reauthenticate(ctx context.Context) error {
+	creds := Credentials{ApiKey: c.ApiKey, SecretKey: c.SecretKey}
+	if c.credentials != nil {
+		var err error
+		if creds, err = c.credentials.Credentials(ctx); err != nil {
+			return &GoBitpinError{Message: "failed to get credentials", Err: err}
+		}
+	}
+	if creds.ApiKey == "" || creds.SecretKey == "" {
+		return ErrMissingCredentials
+	}
+	_, err := c.Authenticate(creds.ApiKey, creds.SecretKey)
+	return err
+}
@@ -0,0 +1,402 @@
+package bitpin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rzabhd80/go-sdk-bitpin/store"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// DCAOptions configures a DCA plan.
+type DCAOptions struct {
+	// Symbol is the market to buy in, e.g. "BTC_IRT".
+	Symbol string
+
+	// QuoteAmount is the amount of quote currency spent per purchase.
+	QuoteAmount string
+
+	// Interval is the time between two purchases. Purchases happen at
+	// Start, Start+Interval, Start+2*Interval, and so on.
+	Interval time.Duration
+
+	// Start anchors the schedule. Defaults to the time the plan is created
+	// the first time; a restored plan keeps its original anchor.
+	Start time.Time
+
+	// Type is TypeMarket, the default, or TypeLimit. Limit purchases are placed
+	// at the best ask minus LimitOffsetPct percent and may not fill.
+	Type t.OrderType
+
+	// LimitOffsetPct is how far below the best ask, in percent, limit
+	// purchases are placed.
+	LimitOffsetPct float64
+
+	// State persists the plan, so a restarted process neither repeats nor
+	// skips purchases. When nil, the plan is only kept in memory.
+	State store.Store
+
+	// StateKey is the key of the plan in State. Defaults to "dca/<symbol>".
+	StateKey string
+}
+
+// DCAPurchase is a single purchase of a DCA plan.
+type DCAPurchase struct {
+	// Slot is the index of the scheduled purchase, starting at 0.
+	Slot int64 `json:"slot"`
+
+	// Identifier is the client-side identifier of the order.
+	Identifier string `json:"identifier"`
+
+	// OrderId is the ID of the order, or 0 if it was not placed.
+	OrderId int `json:"order_id"`
+
+	// State is the last known state of the order.
+	State t.OrderState `json:"state"`
+
+	// BaseAmount and QuoteAmount are the filled amounts of the order.
+	BaseAmount  string `json:"base_amount"`
+	QuoteAmount string `json:"quote_amount"`
+
+	// Time is when the purchase was placed.
+	Time time.Time `json:"time"`
+}
+
+// DCASummary is the accumulated position of a DCA plan.
+type DCASummary struct {
+	// Purchases is the number of orders placed.
+	Purchases int
+
+	// BaseAcquired is the total filled base amount.
+	BaseAcquired float64
+
+	// QuoteSpent is the total filled quote amount.
+	QuoteSpent float64
+
+	// AverageCost is QuoteSpent divided by BaseAcquired, or zero before the
+	// first fill.
+	AverageCost float64
+
+	// NextPurchase is when the next purchase is due.
+	NextPurchase time.Time
+}
+
+// dcaState is the persisted state of a plan.
+type dcaState struct {
+	Start     time.Time     `json:"start"`
+	NextSlot  int64         `json:"next_slot"`
+	Pending   *DCAPurchase  `json:"pending,omitempty"`
+	Purchases []DCAPurchase `json:"purchases"`
+}
+
+// DCA buys a fixed quote amount of a market on a schedule.
+//
+// Before an order is placed, the purchase is recorded as pending together with
+// the order identifier. A plan restored after a crash looks the pending order
+// up by its identifier instead of placing it again, so purchases are never
+// doubled.
+type DCA struct {
+	client *Client
+	opts   DCAOptions
+
+	mu     sync.Mutex
+	state  *dcaState
+	loaded bool
+}
+
+// NewDCA creates a DCA plan.
+//
+// Parameters:
+//   - opts: The market, amount, schedule, order type and persistence of the
+//     plan.
+//
+// Returns:
+//   - A pointer to a `DCA` plan. Call `Run` to execute it.
+//   - An error if the options are invalid.
+//
+// Example:
+//
+//	st, _ := store.NewFile("/var/lib/dca")
+//	plan, err := client.NewDCA(bitpin.DCAOptions{
+//	    Symbol:      "BTC_IRT",
+//	    QuoteAmount: "5000000",
+//	    Interval:    7 * 24 * time.Hour,
+//	    State:       st,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	go plan.Run(ctx)
+//
+//	summary, _ := plan.Summary(ctx)
+//	fmt.Printf("%d purchases, %f BTC at %f\n", summary.Purchases, summary.BaseAcquired, summary.AverageCost)
+func (c *Client) NewDCA(opts DCAOptions) (*DCA, error) {
+	if opts.Symbol == "" {
+		return nil, &GoBitpinError{Message: "DCA symbol is empty"}
+	}
+	if amount, err := strconv.ParseFloat(opts.QuoteAmount, 64); err != nil || amount <= 0 {
+		return nil, &GoBitpinError{Message: fmt.Sprintf("DCA quote amount %q is not a positive number", opts.QuoteAmount)}
+	}
+	if opts.Interval <= 0 {
+		return nil, &GoBitpinError{Message: "DCA interval must be positive"}
+	}
+	if opts.Type == "" {
+		opts.Type = t.TypeMarket
+	}
+	if opts.Type != t.TypeMarket && opts.Type != t.TypeLimit {
+		return nil, &GoBitpinError{Message: fmt.Sprintf("DCA order type must be %s or %s, got %q", t.TypeMarket, t.TypeLimit, opts.Type)}
+	}
+	if opts.StateKey == "" {
+		opts.StateKey = "dca/" + opts.Symbol
+	}
+	return &DCA{client: c, opts: opts}, nil
+}
+
+// Run executes the plan until ctx is done, placing every purchase when it is
+// due. Missed slots, for example while the process was stopped, result in a
+// single catch-up purchase rather than one per slot. It returns nil when ctx is
+// done, or the first error.
+func (d *DCA) Run(ctx context.Context) error {
+	for {
+		if _, err := d.BuyIfDue(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		d.mu.Lock()
+		next := d.slotTime(d.state.NextSlot)
+		d.mu.Unlock()
+		if err := d.client.sleepUntil(ctx, next); err != nil {
+			return nil
+		}
+	}
+}
+
+// BuyIfDue places the next purchase if it is due, and resolves a purchase left
+// pending by a previous run. It returns the purchase placed, or nil if none
+// was due.
+func (d *DCA) BuyIfDue(ctx context.Context) (*DCAPurchase, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.load(ctx); err != nil {
+		return nil, err
+	}
+	if d.state.Pending != nil {
+		if err := d.resolvePending(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	slot := d.currentSlot(d.client.now())
+	if slot < d.state.NextSlot {
+		return nil, nil
+	}
+
+	purchase := DCAPurchase{
+		Slot:       slot,
+		Identifier: fmt.Sprintf("dca-%s-%d-%d", d.opts.Symbol, d.state.Start.Unix(), slot),
+		Time:       d.client.now(),
+	}
+	d.state.Pending = &purchase
+	if err := d.save(ctx); err != nil {
+		return nil, err
+	}
+
+	params, err := d.orderParams(ctx, purchase.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	order, err := d.client.CreateOrderWithContext(ctx, params)
+	if err != nil {
+		return nil, &GoBitpinError{Message: fmt.Sprintf("DCA purchase %d of %s failed", slot, d.opts.Symbol), Err: err}
+	}
+	return d.complete(ctx, order)
+}
+
+// Summary returns the accumulated position, refreshing purchases whose orders
+// are not yet closed.
+func (d *DCA) Summary(ctx context.Context) (DCASummary, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var summary DCASummary
+	if err := d.load(ctx); err != nil {
+		return summary, err
+	}
+
+	refreshed := false
+	for i := range d.state.Purchases {
+		purchase := &d.state.Purchases[i]
+		if purchase.OrderId == 0 || IsTerminalOrderState(purchase.State) {
+			continue
+		}
+		order, err := d.client.GetOrderWithContext(ctx, purchase.OrderId)
+		if err != nil {
+			return summary, err
+		}
+		purchase.update(order)
+		refreshed = true
+	}
+	if refreshed {
+		if err := d.save(ctx); err != nil {
+			return summary, err
+		}
+	}
+
+	for _, purchase := range d.state.Purchases {
+		summary.Purchases++
+		base, _ := strconv.ParseFloat(purchase.BaseAmount, 64)
+		quote, _ := strconv.ParseFloat(purchase.QuoteAmount, 64)
+		summary.BaseAcquired += base
+		summary.QuoteSpent += quote
+	}
+	if summary.BaseAcquired > 0 {
+		summary.AverageCost = summary.QuoteSpent / summary.BaseAcquired
+	}
+	summary.NextPurchase = d.slotTime(max(d.state.NextSlot, d.currentSlot(d.client.now())))
+	return summary, nil
+}
+
+// Purchases returns the purchases placed so far, oldest first.
+func (d *DCA) Purchases(ctx context.Context) ([]DCAPurchase, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.load(ctx); err != nil {
+		return nil, err
+	}
+	return append([]DCAPurchase(nil), d.state.Purchases...), nil
+}
+
+// orderParams builds the order of a purchase.
+func (d *DCA) orderParams(ctx context.Context, identifier string) (t.CreateOrderParams, error) {
+	params := t.CreateOrderParams{
+		Symbol:     d.opts.Symbol,
+		Type:       d.opts.Type,
+		Side:       t.SideBuy,
+		Identifier: identifier,
+	}
+	if d.opts.Type == t.TypeMarket {
+		params.QuoteAmount = d.opts.QuoteAmount
+		return params, nil
+	}
+
+	market, err := d.client.GetMarket(d.opts.Symbol)
+	if err != nil {
+		return params, err
+	}
+	book, err := d.client.GetOrderBookWithContext(ctx, d.opts.Symbol)
+	if err != nil {
+		return params, err
+	}
+	ask, err := book.BestAsk()
+	if err != nil {
+		return params, &GoBitpinError{Message: fmt.Sprintf("cannot price DCA purchase of %s", d.opts.Symbol), Err: err}
+	}
+	quote, _ := strconv.ParseFloat(d.opts.QuoteAmount, 64)
+	price := ask.Price * (1 - d.opts.LimitOffsetPct/100)
+	params.Price = market.FormatPrice(price)
+	params.BaseAmount = market.FormatAmount(quote / price)
+	return params, nil
+}
+
+// resolvePending looks up the order of a purchase that was recorded as pending
+// but never completed, and completes it. If no order with its identifier
+// exists, the purchase is dropped so that it is placed again.
+func (d *DCA) resolvePending(ctx context.Context) error {
+	var orders t.OrderStatuses
+	params := t.GetOrdersHistoryParams{Symbol: d.opts.Symbol, IdentifiersIn: d.state.Pending.Identifier}
+	if err := d.client.call(ctx, "GET", OpOrders, nil, true, params, &orders); err != nil {
+		return err
+	}
+	for i := range orders {
+		if orders[i].Identifier == d.state.Pending.Identifier {
+			_, err := d.complete(ctx, &orders[i])
+			return err
+		}
+	}
+	d.state.Pending = nil
+	return d.save(ctx)
+}
+
+// complete records the order of the pending purchase.
+func (d *DCA) complete(ctx context.Context, order *t.OrderStatus) (*DCAPurchase, error) {
+	purchase := *d.state.Pending
+	purchase.OrderId = order.Id
+	purchase.update(order)
+
+	d.state.Purchases = append(d.state.Purchases, purchase)
+	d.state.NextSlot = purchase.Slot + 1
+	d.state.Pending = nil
+	if err := d.save(ctx); err != nil {
+		return nil, err
+	}
+	return &purchase, nil
+}
+
+// update copies the state and filled amounts of an order.
+func (p *DCAPurchase) update(order *t.OrderStatus) {
+	p.State = order.State
+	p.BaseAmount = order.DealedBaseAmount
+	p.QuoteAmount = order.DealedQuoteAmount
+}
+
+// currentSlot returns the index of the latest slot that is due at now.
+func (d *DCA) currentSlot(now time.Time) int64 {
+	if now.Before(d.state.Start) {
+		return -1
+	}
+	return int64(now.Sub(d.state.Start) / d.opts.Interval)
+}
+
+// slotTime returns when a slot is due.
+func (d *DCA) slotTime(slot int64) time.Time {
+	return d.state.Start.Add(time.Duration(slot) * d.opts.Interval)
+}
+
+// load reads the persisted state on first use.
+func (d *DCA) load(ctx context.Context) error {
+	if d.loaded {
+		return nil
+	}
+	state := &dcaState{Start: d.opts.Start}
+	if d.opts.State != nil {
+		value, err := d.opts.State.Get(ctx, d.opts.StateKey)
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+		case err != nil:
+			return &GoBitpinError{Message: "failed to load DCA state", Err: err}
+		default:
+			if err := json.Unmarshal(value, state); err != nil {
+				return &GoBitpinError{Message: "failed to decode DCA state", Err: err}
+			}
+		}
+	}
+	if state.Start.IsZero() {
+		state.Start = d.client.now()
+	}
+	d.state, d.loaded = state, true
+	return nil
+}
+
+// save persists the state.
+func (d *DCA) save(ctx context.Context) error {
+	if d.opts.State == nil {
+		return nil
+	}
+	value, err := json.Marshal(d.state)
+	if err != nil {
+		return &GoBitpinError{Message: "failed to encode DCA state", Err: err}
+	}
+	if err := d.opts.State.Set(ctx, d.opts.StateKey, value); err != nil {
+		return &GoBitpinError{Message: "failed to save DCA state", Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package bitpin
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxDumpBody is the number of body bytes included in a debug dump. Longer
+// bodies are truncated.
+const maxDumpBody = 64 << 10
+
+// sensitiveHeaders are masked in debug dumps.
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// sensitiveFields matches the JSON fields of credentials and tokens, whose
+// values are masked in debug dumps.
+var sensitiveFields = regexp.MustCompile(`("(?i:api_key|secret_key|access|refresh)"\s*:\s*)"[^"]*"`)
+
+// debugLogger returns the logger of debug dumps: the configured one, or a text
+// logger writing debug records to standard error.
+func debugLogger(logger *slog.Logger) *slog.Logger {
+	if logger != nil {
+		return logger
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// dumpRequest logs a request and its response, if any, at debug level.
+func (c *Client) dumpRequest(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, duration time.Duration, timings *RequestTimings, err error) {
+	if !c.Debug {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("request_id", req.Header.Get(RequestIDHeader)),
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Duration("duration", duration),
+		slog.String("request", dumpMessage(req.Method+" "+req.URL.RequestURI(), req.Header, reqBody)),
+	}
+	if timings != nil {
+		attrs = append(attrs, slog.Group("timings",
+			slog.Duration("dns", timings.DNS),
+			slog.Duration("connect", timings.Connect),
+			slog.Duration("tls", timings.TLSHandshake),
+			slog.Duration("ttfb", timings.TimeToFirstByte),
+			slog.Bool("reused", timings.ConnReused),
+		))
+	}
+	if resp != nil {
+		attrs = append(attrs,
+			slog.Int("status", resp.StatusCode),
+			slog.String("response", dumpMessage(resp.Proto+" "+resp.Status, resp.Header, respBody)),
+		)
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	debugLogger(c.logger).LogAttrs(req.Context(), slog.LevelDebug, "bitpin request", attrs...)
+}
+
+// dumpMessage formats a request or response like it appears on the wire, with
+// credentials and tokens masked.
+func dumpMessage(firstLine string, header http.Header, body []byte) string {
+	var b strings.Builder
+	b.WriteString(firstLine)
+	b.WriteString("\n")
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				value = maskHeader(value)
+			}
+			fmt.Fprintf(&b, "%s: %s\n", name, value)
+		}
+	}
+
+	if len(body) > 0 {
+		b.WriteString("\n")
+		truncated := 0
+		if len(body) > maxDumpBody {
+			truncated = len(body) - maxDumpBody
+			body = body[:maxDumpBody]
+		}
+		b.Write(sensitiveFields.ReplaceAll(body, []byte(`$1"***"`)))
+		if truncated > 0 {
+			fmt.Fprintf(&b, "\n... (%d bytes truncated)", truncated)
+		}
+	}
+	return b.String()
+}
+
+// maskHeader masks a header value, keeping the authentication scheme.
+func maskHeader(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok {
+		return scheme + " ***"
+	}
+	return "***"
+}
@@ -0,0 +1,78 @@
+package bitpin
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// DeprecationNotice describes a single call to a deprecated client method.
+type DeprecationNotice struct {
+	// Method is the name of the deprecated method, such as "GetOrderStatuses".
+	Method string
+
+	// Replacement is the name of the method that should be used instead.
+	Replacement string
+
+	// Caller is the "file:line" location of the code that called the
+	// deprecated method, or empty if it could not be determined.
+	Caller string
+}
+
+// String returns a human-readable description of the notice.
+func (n DeprecationNotice) String() string {
+	msg := fmt.Sprintf("%s is deprecated, use %s instead", n.Method, n.Replacement)
+	if n.Caller != "" {
+		msg += " (called from " + n.Caller + ")"
+	}
+	return msg
+}
+
+// deprecationTracker counts calls to deprecated methods.
+type deprecationTracker struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// deprecated records a call to a deprecated method and notifies the
+// OnDeprecatedCall hook, if one is set. It must be called directly from the
+// deprecated method so the reported caller points at user code.
+func (c *Client) deprecated(method, replacement string) {
+	notice := DeprecationNotice{
+		Method:      method,
+		Replacement: replacement,
+	}
+	if _, file, line, ok := runtime.Caller(2); ok {
+		notice.Caller = fmt.Sprintf("%s:%d", file, line)
+	}
+
+	c.deprecations.mu.Lock()
+	if c.deprecations.counts == nil {
+		c.deprecations.counts = make(map[string]int)
+	}
+	c.deprecations.counts[method]++
+	c.deprecations.mu.Unlock()
+
+	if c.OnDeprecatedCall != nil {
+		c.OnDeprecatedCall(notice)
+	}
+}
+
+// DeprecatedCalls returns how many times each deprecated method has been called
+// on this client, keyed by method name. The returned map is a copy.
+//
+// Example:
+//
+//	for method, count := range client.DeprecatedCalls() {
+//	    log.Printf("%s called %d times", method, count)
+//	}
+func (c *Client) DeprecatedCalls() map[string]int {
+	c.deprecations.mu.Lock()
+	defer c.deprecations.mu.Unlock()
+
+	counts := make(map[string]int, len(c.deprecations.counts))
+	for method, count := range c.deprecations.counts {
+		counts[method] = count
+	}
+	return counts
+}
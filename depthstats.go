@@ -0,0 +1,329 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// Default settings used by DepthStats.
+const (
+	// DefaultDepthStatsInterval is the default delay between two order book
+	// samples of a symbol.
+	DefaultDepthStatsInterval = 30 * time.Second
+
+	// DefaultDepthStatsWindow is the default period over which percentiles
+	// are computed.
+	DefaultDepthStatsWindow = 24 * time.Hour
+
+	// DefaultDepthStatsWithinPct is the default price band, in percent of the
+	// best price, within which depth is measured.
+	DefaultDepthStatsWithinPct = 1.0
+)
+
+// ErrNoDepthSamples is returned when no order book sample of a symbol has been
+// collected yet
+var ErrNoDepthSamples = &GoBitpinError{Message: "no depth samples"}
+
+// DepthMetric selects a liquidity measure tracked by DepthStats.
+type DepthMetric string
+
+const (
+	// DepthMetricSpread is the spread relative to the mid price, e.g. 0.001
+	// for 0.1%. Lower is more liquid.
+	DepthMetricSpread DepthMetric = "spread"
+
+	// DepthMetricBidDepth is the base amount bid within the price band.
+	DepthMetricBidDepth DepthMetric = "bid_depth"
+
+	// DepthMetricAskDepth is the base amount offered within the price band.
+	DepthMetricAskDepth DepthMetric = "ask_depth"
+
+	// DepthMetricDepth is the sum of bid and ask depth.
+	DepthMetricDepth DepthMetric = "depth"
+)
+
+// DepthSample is a single liquidity measurement of an order book.
+type DepthSample struct {
+	// Time is when the sample was taken.
+	Time time.Time
+
+	// Spread is the spread relative to the mid price.
+	Spread float64
+
+	// BidDepth and AskDepth are the base amounts within the price band on
+	// each side of the book.
+	BidDepth float64
+	AskDepth float64
+}
+
+// Value returns the value of a metric in the sample.
+func (s DepthSample) Value(metric DepthMetric) (float64, error) {
+	switch metric {
+	case DepthMetricSpread:
+		return s.Spread, nil
+	case DepthMetricBidDepth:
+		return s.BidDepth, nil
+	case DepthMetricAskDepth:
+		return s.AskDepth, nil
+	case DepthMetricDepth:
+		return s.BidDepth + s.AskDepth, nil
+	default:
+		return 0, &GoBitpinError{Message: fmt.Sprintf("unknown depth metric %q", metric)}
+	}
+}
+
+// DepthStatsOptions configures DepthStats.
+type DepthStatsOptions struct {
+	// Symbols are the markets to sample. Sampling is skipped when empty, in
+	// which case samples can still be fed with Observe.
+	Symbols []string
+
+	// Interval is the delay between two samples of each symbol.
+	// Defaults to DefaultDepthStatsInterval.
+	Interval time.Duration
+
+	// Window is the period over which percentiles are computed. Older samples
+	// are discarded. Defaults to DefaultDepthStatsWindow.
+	Window time.Duration
+
+	// WithinPct is the price band, in percent of the best price of each side,
+	// within which depth is measured. Defaults to DefaultDepthStatsWithinPct.
+	WithinPct float64
+}
+
+// DepthStats samples order books in the background and maintains per-symbol
+// spread and depth distributions over a rolling window. It answers questions
+// such as "is liquidity below its 10th percentile right now", which can be used
+// as a regime filter by strategies.
+type DepthStats struct {
+	// Errors receives sampling errors. Errors are dropped when nobody reads
+	// them; sampling continues after an error.
+	Errors <-chan error
+
+	client *Client
+	opts   DepthStatsOptions
+	errs   chan error
+
+	mu      sync.Mutex
+	samples map[string][]DepthSample
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewDepthStats starts sampling the order books of the given symbols.
+//
+// Parameters:
+//   - ctx: Sampling stops when the context is done.
+//   - opts: Symbols, sampling interval, window, and depth price band.
+//
+// Returns:
+//   - A pointer to a running `DepthStats`. Call `Close` to stop it.
+//
+// Example:
+//
+//	stats := client.NewDepthStats(ctx, bitpin.DepthStatsOptions{
+//	    Symbols:  []string{"BTC_USDT"},
+//	    Interval: time.Minute,
+//	})
+//	defer stats.Close()
+//
+//	thin, err := stats.BelowPercentile("BTC_USDT", bitpin.DepthMetricDepth, 10)
+//	if err == nil && thin {
+//	    log.Println("liquidity is unusually thin, pausing")
+//	}
+func (c *Client) NewDepthStats(ctx context.Context, opts DepthStatsOptions) *DepthStats {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultDepthStatsInterval
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultDepthStatsWindow
+	}
+	if opts.WithinPct <= 0 {
+		opts.WithinPct = DefaultDepthStatsWithinPct
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	errs := make(chan error, 1)
+	s := &DepthStats{
+		Errors:  errs,
+		client:  c,
+		opts:    opts,
+		errs:    errs,
+		samples: make(map[string][]DepthSample),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go s.run(ctx)
+	return s
+}
+
+// Close stops sampling and waits for the sampling goroutine to exit. The
+// collected statistics remain available.
+func (s *DepthStats) Close() {
+	s.once.Do(s.cancel)
+	<-s.done
+}
+
+// run samples every symbol once per interval.
+func (s *DepthStats) run(ctx context.Context) {
+	defer close(s.done)
+	if len(s.opts.Symbols) == 0 {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, symbol := range s.opts.Symbols {
+			book, err := s.client.GetOrderBookWithContext(ctx, symbol)
+			if err == nil {
+				err = s.Observe(symbol, book)
+			}
+			if err != nil && ctx.Err() == nil {
+				select {
+				case s.errs <- err:
+				default:
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Observe records a sample of the given order book, taken now. It can be used
+// to feed order books obtained elsewhere, e.g. from a stream.
+func (s *DepthStats) Observe(symbol string, book *t.OrderBook) error {
+	mid, err := book.MidPrice()
+	if err != nil {
+		return err
+	}
+	spread, err := book.Spread()
+	if err != nil {
+		return err
+	}
+	bids, err := book.TotalDepth(t.Bids, s.opts.WithinPct)
+	if err != nil {
+		return err
+	}
+	asks, err := book.TotalDepth(t.Asks, s.opts.WithinPct)
+	if err != nil {
+		return err
+	}
+
+	sample := DepthSample{Time: s.client.now(), Spread: spread / mid, BidDepth: bids, AskDepth: asks}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	samples := append(s.samples[symbol], sample)
+	cutoff := sample.Time.Add(-s.opts.Window)
+	drop := sort.Search(len(samples), func(i int) bool { return samples[i].Time.After(cutoff) })
+	s.samples[symbol] = samples[drop:]
+	return nil
+}
+
+// Latest returns the most recent sample of a symbol.
+func (s *DepthStats) Latest(symbol string) (DepthSample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	samples := s.samples[symbol]
+	if len(samples) == 0 {
+		return DepthSample{}, &GoBitpinError{Message: symbol, Err: ErrNoDepthSamples}
+	}
+	return samples[len(samples)-1], nil
+}
+
+// Samples returns the number of samples of a symbol within the window.
+func (s *DepthStats) Samples(symbol string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.samples[symbol])
+}
+
+// Percentile returns the p-th percentile (0 to 100) of a metric over the
+// window, interpolating linearly between samples.
+func (s *DepthStats) Percentile(symbol string, metric DepthMetric, p float64) (float64, error) {
+	values, err := s.values(symbol, metric)
+	if err != nil {
+		return 0, err
+	}
+	sort.Float64s(values)
+
+	p = math.Max(0, math.Min(100, p))
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return values[lower] + (values[upper]-values[lower])*(rank-float64(lower)), nil
+}
+
+// Rank returns the percentile rank (0 to 100) of the latest sample of a metric
+// within the window, i.e. the percentage of samples with a lower value.
+func (s *DepthStats) Rank(symbol string, metric DepthMetric) (float64, error) {
+	values, err := s.values(symbol, metric)
+	if err != nil {
+		return 0, err
+	}
+	current := values[len(values)-1]
+
+	var below int
+	for _, v := range values {
+		if v < current {
+			below++
+		}
+	}
+	return float64(below) / float64(len(values)) * 100, nil
+}
+
+// BelowPercentile reports whether the latest sample of a metric is below its
+// p-th percentile over the window. For depth metrics this means liquidity is
+// thinner than usual; for DepthMetricSpread it means the spread is tighter.
+func (s *DepthStats) BelowPercentile(symbol string, metric DepthMetric, p float64) (bool, error) {
+	latest, err := s.Latest(symbol)
+	if err != nil {
+		return false, err
+	}
+	current, err := latest.Value(metric)
+	if err != nil {
+		return false, err
+	}
+	threshold, err := s.Percentile(symbol, metric, p)
+	if err != nil {
+		return false, err
+	}
+	return current < threshold, nil
+}
+
+// values returns a copy of a metric over the window, oldest first.
+func (s *DepthStats) values(symbol string, metric DepthMetric) ([]float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	samples := s.samples[symbol]
+	if len(samples) == 0 {
+		return nil, &GoBitpinError{Message: symbol, Err: ErrNoDepthSamples}
+	}
+
+	values := make([]float64, len(samples))
+	for i, sample := range samples {
+		v, err := sample.Value(metric)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
@@ -0,0 +1,70 @@
+package bitpin
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+)
+
+// DialFunc dials a network connection, like net.Dialer.DialContext.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// DNSServerResolver returns a resolver that sends every DNS query to the given
+// server, such as "1.1.1.1:53", instead of the servers configured on the
+// system. The port defaults to 53.
+//
+// Example:
+//
+//	client, err := bitpin.NewClientWithOptions(
+//	    bitpin.WithResolver(bitpin.DNSServerResolver("9.9.9.9")),
+//	)
+func DNSServerResolver(server string) *net.Resolver {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// newDialer returns the dial function of the default transport for the
+// dialing options, or nil if none of them is set.
+func newDialer(opts ClientOptions) DialFunc {
+	if opts.DialContext == nil && opts.Resolver == nil && len(opts.PinnedHosts) == 0 {
+		return nil
+	}
+	dial := opts.DialContext
+	if dial == nil {
+		// The settings of the dialer of http.DefaultTransport.
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Resolver: opts.Resolver}
+		dial = dialer.DialContext
+	}
+	if len(opts.PinnedHosts) > 0 {
+		dial = pinnedDialer(dial, opts.PinnedHosts)
+	}
+	return dial
+}
+
+// pinnedDialer dials the hosts of pinned at their pinned IP address and every
+// other host with dial. TLS still verifies the certificate against the host
+// name, since the transport takes it from the request.
+func pinnedDialer(dial DialFunc, pinned map[string]string) DialFunc {
+	hosts := make(map[string]string, len(pinned))
+	for host, ip := range pinned {
+		hosts[strings.ToLower(host)] = ip
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err == nil {
+			if ip, ok := hosts[strings.ToLower(host)]; ok {
+				addr = net.JoinHostPort(ip, port)
+			}
+		}
+		return dial(ctx, network, addr)
+	}
+}
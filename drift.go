@@ -0,0 +1,338 @@
+package bitpin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// DriftKind classifies a difference between an API response and what the SDK
+// expects from it.
+type DriftKind string
+
+const (
+	// DriftUnknownField means a response contained a field that the SDK type
+	// it was decoded into does not declare.
+	DriftUnknownField DriftKind = "unknown_field"
+
+	// DriftUnknownEnum means a field with a known set of values, such as an
+	// order state or side, contained a value outside of that set.
+	DriftUnknownEnum DriftKind = "unknown_enum"
+
+	// DriftUnknownEndpoint means a pagination link in a response pointed at an
+	// endpoint the SDK does not know about.
+	DriftUnknownEndpoint DriftKind = "unknown_endpoint"
+)
+
+// DriftFinding is a single API behavior difference detected at runtime.
+// Repeated occurrences of the same difference are merged into one finding.
+type DriftFinding struct {
+	// Kind is the kind of difference.
+	Kind DriftKind
+
+	// Endpoint is the API path of the request that returned the response,
+	// with path parameters replaced by "{id}", e.g. "/odr/orders/{id}/".
+	Endpoint string
+
+	// Path is the location of the field in the response, such as
+	// "[].dealed_base_amount". Empty for DriftUnknownEndpoint.
+	Path string
+
+	// Value is the unexpected enum value or the unknown endpoint, including
+	// its API version, e.g. "/v2/odr/orders/". Empty for DriftUnknownField.
+	Value string
+
+	// Count is how many times the difference has been seen.
+	Count int
+
+	// FirstSeen and LastSeen are the times of the first and latest occurrence.
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// String returns a human-readable description of the finding.
+func (f DriftFinding) String() string {
+	switch f.Kind {
+	case DriftUnknownField:
+		return fmt.Sprintf("%s: unknown field %q (seen %d times)", f.Endpoint, f.Path, f.Count)
+	case DriftUnknownEnum:
+		return fmt.Sprintf("%s: unknown value %q for %q (seen %d times)", f.Endpoint, f.Value, f.Path, f.Count)
+	default:
+		return fmt.Sprintf("%s: link to unknown endpoint %q (seen %d times)", f.Endpoint, f.Value, f.Count)
+	}
+}
+
+// DriftReport is a snapshot of all differences detected by a client.
+type DriftReport struct {
+	// APIVersion is the API version the SDK was built against.
+	APIVersion string
+
+	// Since is when the client started detecting drift.
+	Since time.Time
+
+	// Findings lists the differences, ordered by endpoint, kind, path and value.
+	Findings []DriftFinding
+}
+
+// driftKnownEnums lists the values the SDK understands for enum-like fields,
+// keyed by "<TypeName>.<json field>". The order enums come from their typed
+// constants in the types package.
+var driftKnownEnums = map[string][]string{
+	"OrderStatus.state":    enumStrings(t.OrderStateValues()),
+	"OrderStatus.side":     enumStrings(t.OrderSideValues()),
+	"OrderStatus.type":     enumStrings(t.OrderTypeValues()),
+	"OrderStatusV2.status": enumStrings(t.OrderStateValues()),
+	"OrderStatusV2.side":   enumStrings(t.OrderSideValues()),
+	"OrderStatusV2.type":   enumStrings(t.OrderTypeValues()),
+	"MarketV2.status":      {"active", "halted", "inactive"},
+	"Trade.side":           enumStrings(t.OrderSideValues()),
+	"UserTrade.side":       enumStrings(t.OrderSideValues()),
+}
+
+// enumStrings converts the values of a string enum to plain strings.
+func enumStrings[E ~string](values []E) []string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = string(value)
+	}
+	return strs
+}
+
+// driftPaginationKeys are the response keys that hold pagination links.
+var driftPaginationKeys = map[string]bool{"next": true, "previous": true}
+
+// driftDetector collects drift findings for a client.
+type driftDetector struct {
+	mu       sync.Mutex
+	since    time.Time
+	findings map[DriftFinding]*DriftFinding
+}
+
+// inspectDrift compares a response body against the type it was decoded into
+// and records any differences. It is a no-op unless DetectDrift is enabled.
+func (c *Client) inspectDrift(rawURL string, body []byte, result interface{}) {
+	if !c.DetectDrift || result == nil {
+		return
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return
+	}
+
+	_, endpoint := driftEndpoint(rawURL)
+	var found []DriftFinding
+	report := func(kind DriftKind, path, value string) {
+		found = append(found, DriftFinding{Kind: kind, Endpoint: endpoint, Path: path, Value: value})
+	}
+	walkDrift(doc, reflect.TypeOf(result), "", c.knownEndpoint, report)
+
+	for _, f := range found {
+		c.recordDrift(f)
+	}
+}
+
+// walkDrift walks a decoded JSON value alongside the Go type it was decoded
+// into, reporting unknown fields, unknown enum values and unknown links.
+func walkDrift(doc interface{}, typ reflect.Type, path string, known func(version, path string) bool, report func(DriftKind, string, string)) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Implements(jsonUnmarshalerType) || reflect.PointerTo(typ).Implements(jsonUnmarshalerType) {
+		return
+	}
+
+	switch v := doc.(type) {
+	case []interface{}:
+		if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+			return
+		}
+		for _, item := range v {
+			walkDrift(item, typ.Elem(), path+"[]", known, report)
+		}
+
+	case map[string]interface{}:
+		switch typ.Kind() {
+		case reflect.Map:
+			for key, item := range v {
+				walkDrift(item, typ.Elem(), joinDriftPath(path, key), known, report)
+			}
+		case reflect.Struct:
+			fields := jsonFields(typ)
+			for key, item := range v {
+				fieldPath := joinDriftPath(path, key)
+				if link, ok := item.(string); ok && driftPaginationKeys[key] && link != "" {
+					if version, endpoint := driftEndpoint(link); !known(version, endpoint) {
+						report(DriftUnknownEndpoint, "", "/"+version+endpoint)
+					}
+				}
+
+				field, ok := fields[key]
+				if !ok {
+					report(DriftUnknownField, fieldPath, "")
+					continue
+				}
+				if values, ok := driftKnownEnums[typ.Name()+"."+key]; ok {
+					if value, ok := item.(string); ok && value != "" && !containsString(values, value) {
+						report(DriftUnknownEnum, fieldPath, value)
+					}
+				}
+				walkDrift(item, field.Type, fieldPath, known, report)
+			}
+		}
+	}
+}
+
+// recordDrift merges a finding into the detector and notifies OnDrift the
+// first time a difference is seen.
+func (c *Client) recordDrift(f DriftFinding) {
+	now := c.now()
+
+	c.drift.mu.Lock()
+	if c.drift.findings == nil {
+		c.drift.findings = make(map[DriftFinding]*DriftFinding)
+	}
+	existing, seen := c.drift.findings[f]
+	if !seen {
+		existing = &DriftFinding{Kind: f.Kind, Endpoint: f.Endpoint, Path: f.Path, Value: f.Value, FirstSeen: now}
+		c.drift.findings[f] = existing
+	}
+	existing.Count++
+	existing.LastSeen = now
+	snapshot := *existing
+	c.drift.mu.Unlock()
+
+	if !seen && c.OnDrift != nil {
+		c.OnDrift(snapshot)
+	}
+}
+
+// DriftReport returns every API behavior difference detected so far. It is
+// empty unless the client was created with DetectDrift enabled.
+//
+// Returns:
+//   - A `DriftReport` with the API version the SDK targets and the findings,
+//     sorted by endpoint, kind, path and value.
+//
+// Behavior:
+//   - Unknown fields are reported when a response contains keys that the SDK
+//     type does not declare, e.g. a new field on orders.
+//   - Unknown enum values are reported for order states, order types and
+//     trade sides outside of the values the SDK handles.
+//   - Unknown endpoints are reported when `next` or `previous` pagination
+//     links point at paths the SDK does not call or at another API version.
+//
+// Example:
+//
+//	client, _ := bitpin.NewClient(bitpin.ClientOptions{DetectDrift: true})
+//	// ... use the client ...
+//	for _, f := range client.DriftReport().Findings {
+//	    log.Println(f)
+//	}
+func (c *Client) DriftReport() DriftReport {
+	c.drift.mu.Lock()
+	defer c.drift.mu.Unlock()
+
+	report := DriftReport{APIVersion: Version, Since: c.drift.since}
+	for _, f := range c.drift.findings {
+		report.Findings = append(report.Findings, *f)
+	}
+	sort.Slice(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Value < b.Value
+	})
+	return report
+}
+
+// driftEndpoint splits a request URL or link into its API version and its
+// path relative to the versioned API root, replacing path parameters such as
+// order ids and symbols with "{id}".
+func driftEndpoint(rawURL string) (version, path string) {
+	path = rawURL
+	if parsed, err := url.Parse(rawURL); err == nil {
+		path = parsed.Path
+	}
+	if i := strings.Index(path, "/api/"); i >= 0 {
+		path = path[i+len("/api/"):]
+		version, path, _ = strings.Cut(path, "/")
+		path = "/" + path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if i >= 3 && segment != "" {
+			segments[i] = "{id}"
+		}
+	}
+	return version, strings.Join(segments, "/")
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// jsonFields maps the JSON names of a struct's fields, including those of
+// embedded structs, to the fields.
+func jsonFields(typ reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for n, f := range jsonFields(embedded) {
+					if _, ok := fields[n]; !ok {
+						fields[n] = f
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+func joinDriftPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// DefaultEgressIPURL is the service that reports the public IP address of the
+// client when EgressIPCheckOptions.URL is not set.
+const DefaultEgressIPURL = "https://api.ipify.org"
+
+// ErrEgressIPNotAllowed is returned by a strict egress IP check when the public
+// IP address of the client is not in the IP allowlist of its token
+var ErrEgressIPNotAllowed = &GoBitpinError{Message: "egress IP is not in the token IP allowlist"}
+
+// EgressIPCheckOptions configures the egress IP check, which compares the
+// public IP address requests leave from with the IP allowlist of the API key.
+// Keys restricted to other addresses authenticate fine but are rejected with
+// 401 Unauthorized, which is hard to tell apart from an expired session.
+type EgressIPCheckOptions struct {
+	// URL is a service answering GET requests with the caller's public IP
+	// address as plain text. Defaults to DefaultEgressIPURL.
+	URL string
+
+	// Strict makes NewClient fail with ErrEgressIPNotAllowed when the egress
+	// IP is not allowed, or when it cannot be determined. Otherwise a warning
+	// is logged to Logger and the client is returned.
+	Strict bool
+}
+
+// EgressIPStatus is the result of an egress IP check.
+type EgressIPStatus struct {
+	// IP is the public IP address the requests of the client leave from.
+	IP string
+
+	// Allowed is the IP allowlist of the access token. It is empty if the
+	// token is not restricted.
+	Allowed []string
+
+	// Match reports whether IP is allowed by the token.
+	Match bool
+}
+
+// CheckEgressIP resolves the public IP address the client's requests leave
+// from and checks it against the IP allowlist of its access token, to catch
+// API keys restricted to other addresses before their requests fail.
+//
+// Parameters:
+//   - ctx: The context of the lookup.
+//
+// Returns:
+//   - A pointer to an `EgressIPStatus` with the egress IP, the allowlist and
+//     whether they match.
+//   - An error if the client holds no access token, the token cannot be
+//     decoded or the egress IP cannot be determined.
+//
+// Behavior:
+//   - The IP is looked up with the HTTP client of the client, so it goes
+//     through the same proxy and network as API requests. The service is the
+//     URL of the `EgressIPCheck` options, or `DefaultEgressIPURL`.
+//   - The pinned keys of `TLSOptions` belong to the API, so the lookup uses
+//     a separate transport with the same settings but without the pins.
+//   - Allowlist entries may be addresses or CIDR prefixes. A token without an
+//     allowlist matches any address.
+//   - NewClient runs this check after authenticating when `EgressIPCheck` is
+//     set.
+//
+// Example:
+//
+//	status, err := client.CheckEgressIP(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if !status.Match {
+//	    log.Printf("requests leave from %s, but the API key only allows %v", status.IP, status.Allowed)
+//	}
+func (c *Client) CheckEgressIP(ctx context.Context) (*EgressIPStatus, error) {
+	access, _ := c.tokens()
+	claims, err := tokenClaims(access, c.Now())
+	if err != nil {
+		return nil, &GoBitpinError{Message: "failed to decode access token", Err: err}
+	}
+	if claims == nil {
+		return nil, &GoBitpinError{Message: "egress IP check requires an access token"}
+	}
+
+	lookupURL := DefaultEgressIPURL
+	if c.egressCheck != nil && c.egressCheck.URL != "" {
+		lookupURL = c.egressCheck.URL
+	}
+	ip, err := c.lookupEgressIP(ctx, lookupURL)
+	if err != nil {
+		return nil, err
+	}
+	return &EgressIPStatus{IP: ip.String(), Allowed: claims.IPs, Match: ipAllowed(ip, claims.IPs)}, nil
+}
+
+// lookupEgressIP asks the service at lookupURL for the public IP address of
+// the client.
+func (c *Client) lookupEgressIP(ctx context.Context, lookupURL string) (netip.Addr, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", lookupURL, nil)
+	if err != nil {
+		return netip.Addr{}, &GoBitpinError{Message: "egress IP check: invalid URL", Err: err}
+	}
+	httpClient := c.HttpClient
+	if c.egressClient != nil {
+		httpClient = c.egressClient
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return netip.Addr{}, &GoBitpinError{Message: "egress IP check: lookup failed", Err: err}
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return netip.Addr{}, &GoBitpinError{Message: fmt.Sprintf("egress IP check: %s answered %s", lookupURL, resp.Status)}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return netip.Addr{}, &GoBitpinError{Message: "egress IP check: failed to read response", Err: err}
+	}
+	ip, err := netip.ParseAddr(strings.TrimSpace(string(body)))
+	if err != nil {
+		return netip.Addr{}, &GoBitpinError{Message: fmt.Sprintf("egress IP check: %s did not answer with an IP address", lookupURL), Err: err}
+	}
+	return ip.Unmap(), nil
+}
+
+// newEgressClient returns the HTTP client for egress IP lookups when the TLS
+// options pin keys, or nil if the lookups can use httpClient. It has the
+// transport settings and timeout of httpClient, without the pins. The TLS
+// options are ignored next to a custom HTTP client or transport, and so are
+// their pins.
+func newEgressClient(opts ClientOptions, httpClient *http.Client) (*http.Client, error) {
+	if opts.HttpClient != nil || opts.Transport != nil || opts.TLS == nil || len(opts.TLS.PinnedKeys) == 0 {
+		return nil, nil
+	}
+	unpinned := *opts.TLS
+	unpinned.PinnedKeys = nil
+	opts.TLS = &unpinned
+	transport, err := newTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Timeout: httpClient.Timeout, Transport: transport}, nil
+}
+
+// ipAllowed reports whether ip matches an entry of allowed, an address or a
+// CIDR prefix. An empty allowlist allows every address.
+func ipAllowed(ip netip.Addr, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, entry := range allowed {
+		entry = strings.TrimSpace(entry)
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			if prefix.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil && addr.Unmap() == ip {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEgressIP runs the egress IP check of NewClient. Mismatches and failed
+// lookups are logged as warnings, or returned as errors in strict mode.
+func (c *Client) checkEgressIP(ctx context.Context) error {
+	if c.egressCheck == nil {
+		return nil
+	}
+	if access, _ := c.tokens(); access == "" {
+		return nil
+	}
+	status, err := c.CheckEgressIP(ctx)
+	if err != nil {
+		if c.egressCheck.Strict {
+			return err
+		}
+		debugLogger(c.logger).LogAttrs(ctx, slog.LevelWarn, "bitpin egress IP check failed", slog.String("error", err.Error()))
+		return nil
+	}
+	if status.Match {
+		return nil
+	}
+	if c.egressCheck.Strict {
+		return &GoBitpinError{
+			Message: fmt.Sprintf("egress IP %s is not in the token IP allowlist %v", status.IP, status.Allowed),
+			Err:     ErrEgressIPNotAllowed,
+		}
+	}
+	debugLogger(c.logger).LogAttrs(ctx, slog.LevelWarn, "bitpin egress IP is not in the token IP allowlist",
+		slog.String("ip", status.IP),
+		slog.Any("allowed", status.Allowed),
+	)
+	return nil
+}
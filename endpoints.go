@@ -0,0 +1,126 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// Operation names a logical API operation, independently of the path and
+// version it is served under.
+type Operation string
+
+// Operations used by the SDK.
+const (
+	OpAuthenticate Operation = "authenticate"
+	OpRefreshToken Operation = "refresh_token"
+	OpCurrencies   Operation = "currencies"
+	OpMarkets      Operation = "markets"
+	OpTickers      Operation = "tickers"
+	OpOrderBook    Operation = "order_book"
+	OpMatches      Operation = "matches"
+	OpWallets      Operation = "wallets"
+	OpOrders       Operation = "orders"
+	OpOrder        Operation = "order"
+	OpFills        Operation = "fills"
+)
+
+// Endpoint is where an operation is served.
+type Endpoint struct {
+	// Path is relative to the versioned API root, such as "/odr/orders/".
+	// Operations on a single market or order contain an "{id}" placeholder.
+	Path string `json:"path"`
+
+	// Version is the API version, such as "v1". Empty means Version.
+	Version string `json:"version"`
+}
+
+// resolve returns the path with its "{id}" placeholder replaced by id.
+func (e Endpoint) resolve(id interface{}) string {
+	if id == nil {
+		return e.Path
+	}
+	return strings.ReplaceAll(e.Path, "{id}", fmt.Sprint(id))
+}
+
+// version returns the API version of the endpoint.
+func (e Endpoint) version() string {
+	if e.Version == "" {
+		return Version
+	}
+	return e.Version
+}
+
+// defaultEndpoints is the routing table of the API.
+var defaultEndpoints = map[Operation]Endpoint{
+	OpAuthenticate: {Path: "/usr/authenticate/", Version: Version},
+	OpRefreshToken: {Path: "/usr/refresh_token/", Version: Version},
+	OpCurrencies:   {Path: "/mkt/currencies/", Version: Version},
+	OpMarkets:      {Path: "/mkt/markets/", Version: Version},
+	OpTickers:      {Path: "/mkt/tickers/", Version: Version},
+	OpOrderBook:    {Path: "/mth/orderbook/{id}/", Version: Version},
+	OpMatches:      {Path: "/mth/matches/{id}/", Version: Version},
+	OpWallets:      {Path: "/wlt/wallets/", Version: Version},
+	OpOrders:       {Path: "/odr/orders/", Version: Version},
+	OpOrder:        {Path: "/odr/orders/{id}/", Version: Version},
+	OpFills:        {Path: "/odr/fills/", Version: Version},
+}
+
+// DefaultEndpoints returns a copy of the routing table used when no endpoint
+// is overridden.
+func DefaultEndpoints() map[Operation]Endpoint {
+	return maps.Clone(defaultEndpoints)
+}
+
+// newEndpoints merges overrides into the default routing table.
+func newEndpoints(overrides map[Operation]Endpoint) map[Operation]Endpoint {
+	if len(overrides) == 0 {
+		return defaultEndpoints
+	}
+	endpoints := maps.Clone(defaultEndpoints)
+	maps.Copy(endpoints, overrides)
+	return endpoints
+}
+
+// Endpoint returns the path and version the client uses for an operation.
+func (c *Client) Endpoint(op Operation) Endpoint {
+	endpoints := c.endpoints
+	if endpoints == nil {
+		endpoints = defaultEndpoints
+	}
+	return endpoints[op]
+}
+
+// apiURI returns the URL of an operation. id replaces the "{id}" placeholder
+// of the path and may be nil.
+func (c *Client) apiURI(ctx context.Context, op Operation, id interface{}) string {
+	endpoint := c.route(ctx, op)
+	return c.createApiURI(endpoint.resolve(id), endpoint.version())
+}
+
+// call sends a request to the endpoint of an operation, like
+// ApiRequestWithContext. id replaces the "{id}" placeholder of the path and
+// may be nil. Requests routed to the v2 API are converted by adaptV2.
+func (c *Client) call(ctx context.Context, method string, op Operation, id interface{}, auth bool, body interface{}, result interface{}) error {
+	endpoint := c.route(ctx, op)
+	return c.requestVersion(ctx, method, endpoint.resolve(id), endpoint.version(), auth, body, result)
+}
+
+// knownEndpoint reports whether a path relative to the root of an API version
+// is served by one of the operations of the client. Path parameters must be
+// given as "{id}".
+func (c *Client) knownEndpoint(version, path string) bool {
+	endpoints := c.endpoints
+	if endpoints == nil {
+		endpoints = defaultEndpoints
+	}
+	for _, table := range []map[Operation]Endpoint{endpoints, v2Endpoints} {
+		for _, endpoint := range table {
+			if endpoint.version() == version && endpoint.Path == path {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package bitpin
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix is the prefix of the environment variables read by
+// ClientOptionsFromEnv.
+const EnvPrefix = "BITPIN_"
+
+// ClientOptionsFromEnv builds client options from environment variables. Unset
+// variables leave the corresponding option at its zero value, so the usual
+// defaults apply.
+//
+// Variables:
+//
+//	BITPIN_API_KEY                      ApiKey
+//	BITPIN_SECRET_KEY                   SecretKey
+//	BITPIN_ACCESS_TOKEN                 AccessToken
+//	BITPIN_REFRESH_TOKEN                RefreshToken
+//	BITPIN_BASE_URL                     BaseUrl
+//	BITPIN_FAILOVER_URLS                Failover.URLs, comma-separated
+//	BITPIN_TIMEOUT                      Timeout, e.g. "10s" or "10" (seconds)
+//	BITPIN_AUTO_AUTH                    AutoAuth, e.g. "true" or "1"
+//	BITPIN_AUTO_REFRESH                 AutoRefresh
+//	BITPIN_MAX_IDLE_CONNS_PER_HOST      MaxIdleConnsPerHost
+//	BITPIN_IDLE_CONN_TIMEOUT            IdleConnTimeout
+//	BITPIN_TLS_HANDSHAKE_TIMEOUT        TLSHandshakeTimeout
+//	BITPIN_DISABLE_COMPRESSION          DisableCompression
+//	BITPIN_DISABLE_CONDITIONAL_REQUESTS DisableConditionalRequests
+//	BITPIN_METADATA_TTL                 MetadataTTL
+//	BITPIN_FETCH_CONCURRENCY            FetchConcurrency
+//	BITPIN_DETECT_DRIFT                 DetectDrift
+//	BITPIN_TRANSLATE_ERRORS             TranslateErrors
+//	BITPIN_DEBUG                        Debug
+//	BITPIN_TRACE_REQUESTS               TraceRequests
+//	BITPIN_API_V2                       UseAPIv2
+//
+// Returns an error naming the variable if a value cannot be parsed.
+func ClientOptionsFromEnv() (ClientOptions, error) {
+	env := envReader{lookup: os.LookupEnv}
+	opts := ClientOptions{
+		ApiKey:                     env.string("API_KEY"),
+		SecretKey:                  env.string("SECRET_KEY"),
+		AccessToken:                env.string("ACCESS_TOKEN"),
+		RefreshToken:               env.string("REFRESH_TOKEN"),
+		BaseUrl:                    env.string("BASE_URL"),
+		Timeout:                    env.duration("TIMEOUT"),
+		AutoAuth:                   env.bool("AUTO_AUTH"),
+		AutoRefresh:                env.bool("AUTO_REFRESH"),
+		MaxIdleConnsPerHost:        env.int("MAX_IDLE_CONNS_PER_HOST"),
+		IdleConnTimeout:            env.duration("IDLE_CONN_TIMEOUT"),
+		TLSHandshakeTimeout:        env.duration("TLS_HANDSHAKE_TIMEOUT"),
+		DisableCompression:         env.bool("DISABLE_COMPRESSION"),
+		DisableConditionalRequests: env.bool("DISABLE_CONDITIONAL_REQUESTS"),
+		MetadataTTL:                env.duration("METADATA_TTL"),
+		FetchConcurrency:           env.int("FETCH_CONCURRENCY"),
+		DetectDrift:                env.bool("DETECT_DRIFT"),
+		TranslateErrors:            env.bool("TRANSLATE_ERRORS"),
+		Debug:                      env.bool("DEBUG"),
+		TraceRequests:              env.bool("TRACE_REQUESTS"),
+		UseAPIv2:                   env.bool("API_V2"),
+	}
+	if mirrors := env.string("FAILOVER_URLS"); mirrors != "" {
+		opts.Failover = &FailoverOptions{}
+		for _, mirror := range strings.Split(mirrors, ",") {
+			if mirror = strings.TrimSpace(mirror); mirror != "" {
+				opts.Failover.URLs = append(opts.Failover.URLs, mirror)
+			}
+		}
+	}
+	if env.err != nil {
+		return ClientOptions{}, env.err
+	}
+	return opts, nil
+}
+
+// NewClientFromEnv creates a client configured from environment variables; see
+// ClientOptionsFromEnv for the variables that are read.
+//
+// Example:
+//
+//	// BITPIN_API_KEY=... BITPIN_SECRET_KEY=... BITPIN_TIMEOUT=5s ./mybot
+//	client, err := bitpin.NewClientFromEnv()
+//	if err != nil {
+//	    log.Fatalf("Failed to create client: %v", err)
+//	}
+func NewClientFromEnv() (*Client, error) {
+	opts, err := ClientOptionsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(opts)
+}
+
+// envReader reads prefixed environment variables and remembers the first
+// parse error.
+type envReader struct {
+	lookup func(key string) (string, bool)
+	err    error
+}
+
+// value returns the trimmed value of a variable, if it is set and not empty.
+func (e *envReader) value(name string) (string, bool) {
+	value, ok := e.lookup(EnvPrefix + name)
+	value = strings.TrimSpace(value)
+	return value, ok && value != ""
+}
+
+// fail records a parse error for a variable.
+func (e *envReader) fail(name, value string, err error) {
+	if e.err == nil {
+		e.err = &GoBitpinError{
+			Message: fmt.Sprintf("invalid value %q for %s%s", value, EnvPrefix, name),
+			Err:     err,
+		}
+	}
+}
+
+func (e *envReader) string(name string) string {
+	value, _ := e.value(name)
+	return value
+}
+
+func (e *envReader) bool(name string) bool {
+	value, ok := e.value(name)
+	if !ok {
+		return false
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		e.fail(name, value, err)
+	}
+	return b
+}
+
+func (e *envReader) int(name string) int {
+	value, ok := e.value(name)
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		e.fail(name, value, err)
+	}
+	return n
+}
+
+// duration accepts Go durations such as "1m30s", or a plain number of seconds.
+func (e *envReader) duration(name string) time.Duration {
+	value, ok := e.value(name)
+	if !ok {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		e.fail(name, value, err)
+	}
+	return d
+}
@@ -1,8 +1,19 @@
 package bitpin
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
 )
 
 // GoBitpinError is the base error type for all errors in the SDK
@@ -23,10 +34,40 @@ func (e *GoBitpinError) Unwrap() error {
 	return e.Err
 }
 
+// ErrOrderNotFound is returned when a lookup does not match any order
+var ErrOrderNotFound = &GoBitpinError{Message: "order not found"}
+
+// ErrTickerNotFound is returned when no ticker exists for a symbol
+var ErrTickerNotFound = &GoBitpinError{Message: "ticker not found"}
+
+// ErrMarketNotFound is returned when no market exists for a symbol
+var ErrMarketNotFound = &GoBitpinError{Message: "market not found"}
+
+// ErrWalletNotFound is returned when the user has no wallet for an asset
+var ErrWalletNotFound = &GoBitpinError{Message: "wallet not found"}
+
 // RequestError represents errors that occur during HTTP request creation or sending
 type RequestError struct {
 	GoBitpinError
 	Operation string // e.g., "creating request", "sending request"
+	RequestID string // ID of the request; see RequestIDHeader
+}
+
+// Error returns the message followed by the request ID.
+func (e *RequestError) Error() string {
+	return withRequestIDSuffix(e.GoBitpinError.Error(), e.RequestID)
+}
+
+// AuthError is returned when an authenticated request cannot be sent because
+// the client has no valid tokens and could not obtain them
+type AuthError struct {
+	GoBitpinError
+	RequestID string // ID the request would have been sent with
+}
+
+// Error returns the message followed by the request ID.
+func (e *AuthError) Error() string {
+	return withRequestIDSuffix(e.GoBitpinError.Error(), e.RequestID)
 }
 
 // APIError represents errors returned by the Bitpin API
@@ -34,6 +75,117 @@ type APIError struct {
 	GoBitpinError
 	StatusCode int
 	Details    map[string][]string // Store field-specific errors
+	Method     string              // HTTP method of the failed request
+	URL        string              // URL of the failed request
+	RequestID  string              // request ID reported by the API, or else the one sent
+
+	// OriginalDetails holds the details as sent by the API when Persian
+	// messages were translated; see ClientOptions.TranslateErrors.
+	OriginalDetails map[string][]string
+}
+
+// requestIDHeaders are the response headers that may carry an identifier of the
+// request, in order of preference.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "Request-Id", "Cf-Ray"}
+
+// Error returns the message prefixed by the request and followed by the request
+// ID, which the exchange support needs to trace a failure.
+func (e *APIError) Error() string {
+	msg := e.GoBitpinError.Error()
+	if e.Method != "" {
+		msg = fmt.Sprintf("%s %s: %s", e.Method, e.URL, msg)
+	}
+	return withRequestIDSuffix(msg, e.RequestID)
+}
+
+// withRequestIDSuffix appends a request ID, if any, to an error message.
+func withRequestIDSuffix(msg, requestID string) string {
+	if requestID == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s (request id %s)", msg, requestID)
+}
+
+// FieldErrors returns the messages the API reported for a field. The field name
+// is matched ignoring case, underscores and dashes, so "baseAmount",
+// "base_amount" and "BaseAmount" are equivalent.
+//
+// Example:
+//
+//	var apiErr *bitpin.APIError
+//	if errors.As(err, &apiErr) {
+//	    for _, msg := range apiErr.FieldErrors("price") {
+//	        log.Println("price:", msg)
+//	    }
+//	}
+func (e *APIError) FieldErrors(field string) []string {
+	var messages []string
+	want := normalizeField(field)
+	for key, values := range e.Details {
+		if normalizeField(key) == want {
+			messages = append(messages, values...)
+		}
+	}
+	return messages
+}
+
+// HasCode reports whether the API tagged the error with the given code, as sent
+// in a "code" or "error_code" field. Codes are compared ignoring case. Persian
+// messages also carry the code of their entry in the translation table; see
+// TranslateErrorMessage.
+func (e *APIError) HasCode(code string) bool {
+	details := e.Details
+	if e.OriginalDetails != nil {
+		details = e.OriginalDetails
+	}
+	for key, values := range details {
+		isCode := normalizeField(key) == "code" || normalizeField(key) == "errorcode"
+		for _, value := range values {
+			if isCode && strings.EqualFold(value, code) {
+				return true
+			}
+			if _, translated, ok := TranslateErrorMessage(value); ok && strings.EqualFold(translated, code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeField reduces a field name to lowercase letters and digits.
+func normalizeField(field string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r == ' ' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, field)
+}
+
+// PriceGuardError is returned when an order is aborted because the top of the
+// book moved too far away from the decision price
+type PriceGuardError struct {
+	GoBitpinError
+	DecisionPrice float64
+	CurrentPrice  float64
+	Deviation     float64 // relative move, e.g. 0.01 for 1%
+}
+
+// newAPIError parses an error response and records the request it answers.
+func newAPIError(req *http.Request, resp *http.Response, respBody []byte) *APIError {
+	apiErr := parseErrorResponse(resp.StatusCode, respBody)
+	apiErr.Method = req.Method
+	apiErr.URL = req.URL.String()
+	for _, name := range requestIDHeaders {
+		if id := resp.Header.Get(name); id != "" {
+			apiErr.RequestID = id
+			break
+		}
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = req.Header.Get(RequestIDHeader)
+	}
+	return apiErr
 }
 
 // parseErrorResponse attempts to parse various error response formats from the API
@@ -116,3 +268,160 @@ func formatErrorDetails(details map[string][]string) string {
 	}
 	return msg
 }
+
+// ErrorClass is a coarse classification of an error returned by the SDK that
+// tells callers how to react to it.
+type ErrorClass int
+
+const (
+	// ErrorClassNone is the class of a nil error.
+	ErrorClassNone ErrorClass = iota
+
+	// ErrorClassRetryable marks transient failures: timeouts, network errors,
+	// 5xx responses, 429 Too Many Requests and an open circuit breaker. The same
+	// request may succeed later.
+	ErrorClassRetryable
+
+	// ErrorClassAuth marks missing, expired or rejected credentials.
+	ErrorClassAuth
+
+	// ErrorClassValidation marks requests the API or the SDK rejected as
+	// invalid. Retrying them unchanged fails again.
+	ErrorClassValidation
+
+	// ErrorClassPermanent marks every other failure, such as a missing resource,
+	// a cancelled context or a server certificate that fails verification or
+	// pinning.
+	ErrorClassPermanent
+)
+
+// String returns the name of the class.
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassNone:
+		return "none"
+	case ErrorClassRetryable:
+		return "retryable"
+	case ErrorClassAuth:
+		return "auth"
+	case ErrorClassValidation:
+		return "validation"
+	case ErrorClassPermanent:
+		return "permanent"
+	default:
+		return fmt.Sprintf("ErrorClass(%d)", int(c))
+	}
+}
+
+// ClassifyError returns the class of an error returned by the SDK. Wrapped
+// errors are inspected, so the classification survives fmt.Errorf("%w").
+//
+// Example:
+//
+//	switch bitpin.ClassifyError(err) {
+//	case bitpin.ErrorClassRetryable:
+//	    time.Sleep(backoff)
+//	case bitpin.ErrorClassAuth:
+//	    _, err = client.Authenticate(apiKey, secretKey)
+//	default:
+//	    return err
+//	}
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassNone
+	}
+	if errors.Is(err, context.Canceled) {
+		return ErrorClassPermanent
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) {
+		return ErrorClassRetryable
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return classifyStatus(apiErr.StatusCode)
+	}
+
+	// *url.Error implements net.Error, so the failures that are not network
+	// problems are singled out first.
+	if untrustedServer(err) {
+		return ErrorClassPermanent
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && urlErr.Op == "parse" {
+		return ErrorClassPermanent
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorClassRetryable
+	}
+
+	var authErr *AuthError
+	if errors.As(err, &authErr) || errors.Is(err, ErrAuthDisabled) {
+		return ErrorClassAuth
+	}
+
+	var validationErr *t.ValidationError
+	if errors.As(err, &validationErr) {
+		return ErrorClassValidation
+	}
+
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		switch reqErr.Operation {
+		case "sending request", "reading response":
+			return ErrorClassRetryable
+		case "preparing request parameters", "preparing request body":
+			return ErrorClassValidation
+		}
+	}
+	return ErrorClassPermanent
+}
+
+// untrustedServer reports whether err is caused by a server certificate that
+// failed verification or pinning, which retrying does not fix.
+func untrustedServer(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &verifyErr) || errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr) || errors.Is(err, ErrCertificateNotPinned)
+}
+
+// classifyStatus returns the class of an API error response status.
+func classifyStatus(status int) ErrorClass {
+	switch {
+	case status == http.StatusTooManyRequests, status == http.StatusRequestTimeout, status >= 500:
+		return ErrorClassRetryable
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrorClassAuth
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return ErrorClassValidation
+	default:
+		return ErrorClassPermanent
+	}
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying, such
+// as a timeout, a network error, a 5xx response or 429 Too Many Requests.
+func IsRetryable(err error) bool {
+	return ClassifyError(err) == ErrorClassRetryable
+}
+
+// IsAuthError reports whether err is caused by missing, expired or rejected
+// credentials.
+func IsAuthError(err error) bool {
+	return ClassifyError(err) == ErrorClassAuth
+}
+
+// IsValidationError reports whether err is caused by an invalid request.
+func IsValidationError(err error) bool {
+	return ClassifyError(err) == ErrorClassValidation
+}
+
+// IsPermanent reports whether err is a failure that retrying will not fix and
+// that is neither an auth nor a validation problem.
+func IsPermanent(err error) bool {
+	return ClassifyError(err) == ErrorClassPermanent
+}
@@ -0,0 +1,103 @@
+// Package eventlog provides an append-only event log used to persist the state
+// changes of client-side trading components, so that a restarted process can
+// rebuild its exact state by replaying the log.
+package eventlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Event is a single recorded state change.
+type Event struct {
+	// Seq is the position of the event in the log, starting at 1. It is
+	// assigned by the log on append.
+	Seq uint64 `json:"seq"`
+
+	// Time is when the event was appended.
+	Time time.Time `json:"time"`
+
+	// Stream groups the events of one component or entity, such as
+	// "positions" or "positions/BTC_USDT".
+	Stream string `json:"stream"`
+
+	// Type describes the change, such as "fill_applied".
+	Type string `json:"type"`
+
+	// Data is the JSON-encoded payload of the event.
+	Data json.RawMessage `json:"data"`
+}
+
+// Decode unmarshals the event payload into v.
+func (e Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// Log is an append-only sequence of events.
+type Log interface {
+	// Append records a new event and returns it with Seq and Time set.
+	Append(stream, eventType string, data interface{}) (Event, error)
+
+	// Replay calls fn for every event with a sequence number greater than
+	// after, in order. Replay stops at the first error returned by fn.
+	Replay(after uint64, fn func(Event) error) error
+}
+
+// NewEvent builds an event with its payload encoded as JSON.
+func NewEvent(stream, eventType string, data interface{}) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	return Event{
+		Time:   time.Now(),
+		Stream: stream,
+		Type:   eventType,
+		Data:   raw,
+	}, nil
+}
+
+// MemoryLog is an in-memory Log. It is safe for concurrent use and is mostly
+// useful for tests and short-lived processes.
+type MemoryLog struct {
+	mu     sync.RWMutex
+	events []Event
+}
+
+// NewMemoryLog creates an empty in-memory log.
+func NewMemoryLog() *MemoryLog {
+	return &MemoryLog{}
+}
+
+// Append records a new event.
+func (l *MemoryLog) Append(stream, eventType string, data interface{}) (Event, error) {
+	event, err := NewEvent(stream, eventType, data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	event.Seq = uint64(len(l.events)) + 1
+	l.events = append(l.events, event)
+	return event, nil
+}
+
+// Replay calls fn for every event after the given sequence number.
+func (l *MemoryLog) Replay(after uint64, fn func(Event) error) error {
+	l.mu.RLock()
+	events := l.events
+	l.mu.RUnlock()
+
+	for _, event := range events {
+		if event.Seq <= after {
+			continue
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
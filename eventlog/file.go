@@ -0,0 +1,146 @@
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// FileLog is a Log stored as a JSON Lines file, one event per line. Every
+// append is written and synced before it returns, so acknowledged events
+// survive a crash.
+type FileLog struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	seq  uint64
+}
+
+// OpenFileLog opens or creates the log file at path. Existing events are
+// scanned to continue the sequence numbering, and a partially written last
+// line left behind by a crash is removed.
+func OpenFileLog(path string) (*FileLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+
+	l := &FileLog{path: path, file: file}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	size, err := scanEvents(file, func(event Event) error {
+		l.seq = event.Seq
+		return nil
+	})
+	if err == nil {
+		err = file.Truncate(size)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Append records a new event and syncs it to disk.
+func (l *FileLog) Append(stream, eventType string, data interface{}) (Event, error) {
+	event, err := NewEvent(stream, eventType, data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	event.Seq = l.seq + 1
+	line, err := json.Marshal(event)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode event: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return Event{}, fmt.Errorf("failed to write event: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return Event{}, fmt.Errorf("failed to sync event log: %w", err)
+	}
+	l.seq = event.Seq
+	return event, nil
+}
+
+// Replay calls fn for every event after the given sequence number that was
+// appended before Replay was called. The file is read through its own handle
+// without holding the lock, so fn may append to the log.
+func (l *FileLog) Replay(after uint64, fn func(Event) error) error {
+	l.mu.Lock()
+	last := l.seq
+	l.mu.Unlock()
+	if last <= after {
+		return nil
+	}
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		return fmt.Errorf("failed to read event log: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	_, err = scanEvents(file, func(event Event) error {
+		if event.Seq <= after {
+			return nil
+		}
+		if event.Seq > last {
+			return errReplayDone
+		}
+		return fn(event)
+	})
+	if errors.Is(err, errReplayDone) {
+		return nil
+	}
+	return err
+}
+
+// Close closes the underlying file.
+func (l *FileLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// errReplayDone stops a scan once Replay reached the events appended after it
+// was called.
+var errReplayDone = errors.New("replay done")
+
+// scanEvents reads every event from r and returns the size of the complete
+// lines read. A truncated last line is ignored.
+func scanEvents(r io.Reader, fn func(Event) error) (int64, error) {
+	var size int64
+	var last uint64
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		if err != nil {
+			return size, fmt.Errorf("failed to read event log: %w", err)
+		}
+
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return size, fmt.Errorf("corrupt event after seq %d: %w", last, err)
+		}
+		if err := fn(event); err != nil {
+			return size, err
+		}
+		size += int64(len(line))
+		last = event.Seq
+	}
+}
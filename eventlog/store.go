@@ -0,0 +1,52 @@
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rzabhd80/go-sdk-bitpin/store"
+)
+
+// StoreLog is a Log kept in a named log of a store.Store, so events end up in
+// the same persistence backend as the rest of the SDK state.
+type StoreLog struct {
+	store store.Store
+	name  string
+}
+
+// NewStoreLog creates a Log that appends to the named log of st.
+func NewStoreLog(st store.Store, name string) *StoreLog {
+	return &StoreLog{store: st, name: name}
+}
+
+// Append records a new event. The sequence number is assigned by the store.
+func (l *StoreLog) Append(stream, eventType string, data interface{}) (Event, error) {
+	event, err := NewEvent(stream, eventType, data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	record, err := json.Marshal(event)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode event: %w", err)
+	}
+	seq, err := l.store.Append(context.Background(), l.name, record)
+	if err != nil {
+		return Event{}, err
+	}
+	event.Seq = seq
+	return event, nil
+}
+
+// Replay calls fn for every event after the given sequence number.
+func (l *StoreLog) Replay(after uint64, fn func(Event) error) error {
+	return l.store.Read(context.Background(), l.name, after, func(seq uint64, record []byte) error {
+		var event Event
+		if err := json.Unmarshal(record, &event); err != nil {
+			return fmt.Errorf("corrupt event %d: %w", seq, err)
+		}
+		event.Seq = seq
+		return fn(event)
+	})
+}
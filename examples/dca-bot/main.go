@@ -0,0 +1,88 @@
+// Command dca-bot buys a fixed quote amount of a market on every interval and
+// reports the accumulated position and average cost. The number of completed
+// buys is kept in a file store and survives restarts.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rzabhd80/go-sdk-bitpin/examples/internal/config"
+	"github.com/rzabhd80/go-sdk-bitpin/store"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+func main() {
+	quote := flag.String("quote", "100", "quote amount spent per buy")
+	dir := flag.String("state", os.TempDir()+"/bitpin-dca", "directory for persisted bot state")
+	cfg := config.Load(2 * time.Second)
+	defer cfg.Close()
+
+	client, err := cfg.Client()
+	if err != nil {
+		log.Fatalf("failed to create client: %v", err)
+	}
+
+	st, err := store.NewFile(*dir)
+	if err != nil {
+		log.Fatalf("failed to open state: %v", err)
+	}
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	var spent, bought float64
+	for {
+		order, err := client.CreateOrder(t.CreateOrderParams{
+			Symbol:      cfg.Symbol,
+			Type:        t.TypeMarket,
+			Side:        t.SideBuy,
+			QuoteAmount: *quote,
+		})
+		if err != nil {
+			log.Printf("buy failed: %v", err)
+		} else {
+			base, _ := strconv.ParseFloat(order.DealedBaseAmount, 64)
+			cost, _ := strconv.ParseFloat(order.DealedQuoteAmount, 64)
+			bought += base
+			spent += cost
+
+			count := incrementBuys(ctx, st)
+			log.Printf("buy #%d: order %d filled %s for %s", count, order.Id, order.DealedBaseAmount, order.DealedQuoteAmount)
+			if bought > 0 {
+				log.Printf("session position %.8f, average cost %.2f", bought, spent/bought)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// incrementBuys increments and returns the persisted buy counter.
+func incrementBuys(ctx context.Context, st store.Store) int {
+	count := 0
+	raw, err := st.Get(ctx, "dca/buys")
+	if err == nil {
+		count, _ = strconv.Atoi(string(raw))
+	} else if !errors.Is(err, store.ErrNotFound) {
+		log.Printf("failed to read state: %v", err)
+	}
+
+	count++
+	if err := st.Set(ctx, "dca/buys", []byte(strconv.Itoa(count))); err != nil {
+		log.Printf("failed to save state: %v", err)
+	}
+	return count
+}
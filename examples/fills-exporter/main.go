@@ -0,0 +1,81 @@
+// Command fills-exporter streams the fills of the authenticated user to
+// standard output as CSV. In fake mode it also places a few market orders so
+// there is something to export.
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	bitpin "github.com/rzabhd80/go-sdk-bitpin"
+	"github.com/rzabhd80/go-sdk-bitpin/examples/internal/config"
+	"github.com/rzabhd80/go-sdk-bitpin/export"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+func main() {
+	cfg := config.Load(time.Second)
+	defer cfg.Close()
+
+	client, err := cfg.Client()
+	if err != nil {
+		log.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	stream := client.SubscribeUserData(ctx, bitpin.UserDataStreamOptions{
+		Symbol:   cfg.Symbol,
+		Interval: cfg.Interval,
+	})
+	defer stream.Close()
+
+	if cfg.Fake {
+		go trade(client, cfg.Symbol, cfg.Interval)
+	}
+	go func() {
+		for err := range stream.Errors {
+			log.Printf("stream error: %v", err)
+		}
+	}()
+
+	w, err := export.NewTradeWriter(os.Stdout, export.Options{})
+	if err != nil {
+		log.Fatalf("failed to create CSV writer: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("failed to write CSV header: %v", err)
+	}
+
+	for event := range stream.C {
+		if event.Type != bitpin.UserDataFill {
+			continue
+		}
+		_ = w.Write(event.Fill)
+		if err := w.Flush(); err != nil {
+			log.Fatalf("failed to write CSV: %v", err)
+		}
+	}
+}
+
+// trade places alternating market orders on the fake exchange.
+func trade(client *bitpin.Client, symbol string, interval time.Duration) {
+	for i := 0; i < 5; i++ {
+		time.Sleep(interval)
+		side := t.SideBuy
+		if i%2 == 1 {
+			side = t.SideSell
+		}
+		_, err := client.CreateOrder(t.CreateOrderParams{
+			Symbol:     symbol,
+			Type:       t.TypeMarket,
+			Side:       side,
+			BaseAmount: "0.01",
+		})
+		if err != nil {
+			log.Printf("failed to place order: %v", err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Package config is the configuration loader shared by the example
+// applications. It builds a client either against the real API, using
+// credentials from the environment, or against an in-process fake exchange.
+package config
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	bitpin "github.com/rzabhd80/go-sdk-bitpin"
+	"github.com/rzabhd80/go-sdk-bitpin/bitpintest"
+)
+
+// Config holds the settings common to all examples.
+type Config struct {
+	// Fake runs the example against an in-process fake exchange.
+	Fake bool
+
+	// Duration stops the example after the given time. Zero runs until
+	// interrupted.
+	Duration time.Duration
+
+	// Interval is the polling or action interval of the example.
+	Interval time.Duration
+
+	// Symbol is the market the example works on.
+	Symbol string
+
+	// Exchange is the running fake exchange when Fake is set.
+	Exchange *bitpintest.Server
+}
+
+// Load registers the common flags, parses the command line, and returns the
+// configuration. Examples may register their own flags before calling Load.
+func Load(defaultInterval time.Duration) *Config {
+	cfg := &Config{}
+	flag.BoolVar(&cfg.Fake, "fake", os.Getenv("BITPIN_API_KEY") == "", "run against the in-process fake exchange")
+	flag.DurationVar(&cfg.Duration, "duration", 0, "stop after this duration (0 runs until interrupted)")
+	flag.DurationVar(&cfg.Interval, "interval", defaultInterval, "polling interval")
+	flag.StringVar(&cfg.Symbol, "symbol", "BTC_USDT", "market symbol")
+	flag.Parse()
+	return cfg
+}
+
+// Client creates the API client. In fake mode the fake exchange is started
+// first; otherwise the client is configured from the BITPIN_* environment
+// variables, see bitpin.ClientOptionsFromEnv.
+func (c *Config) Client() (*bitpin.Client, error) {
+	opts := bitpin.ClientOptions{
+		Timeout:     10 * time.Second,
+		AutoRefresh: true,
+	}
+
+	if c.Fake {
+		c.Exchange = bitpintest.NewServer()
+		opts.BaseUrl = c.Exchange.URL
+		opts.ApiKey = bitpintest.ApiKey
+		opts.SecretKey = bitpintest.SecretKey
+	} else {
+		env, err := bitpin.ClientOptionsFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		if env.Timeout == 0 {
+			env.Timeout = opts.Timeout
+		}
+		env.AutoRefresh = true
+		opts = env
+		if opts.ApiKey == "" || opts.SecretKey == "" {
+			return nil, fmt.Errorf("BITPIN_API_KEY and BITPIN_SECRET_KEY must be set, or use -fake")
+		}
+	}
+
+	return bitpin.NewClient(opts)
+}
+
+// Context returns a context that is cancelled on interrupt or when the
+// configured duration has passed.
+func (c *Config) Context() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if c.Duration <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.Duration)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// Close releases the resources held by the configuration.
+func (c *Config) Close() {
+	if c.Exchange != nil {
+		c.Exchange.Close()
+	}
+}
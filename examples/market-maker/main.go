@@ -0,0 +1,87 @@
+// Command market-maker is a skeleton quoting strategy. On every interval it
+// cancels its previous quotes and places a new bid and ask around the mid
+// price of the order book.
+package main
+
+import (
+	"flag"
+	"log"
+	"strconv"
+	"time"
+
+	bitpin "github.com/rzabhd80/go-sdk-bitpin"
+	"github.com/rzabhd80/go-sdk-bitpin/examples/internal/config"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+func main() {
+	spread := flag.Float64("spread", 0.004, "quoted spread as a fraction of the mid price")
+	amount := flag.String("amount", "0.001", "base amount of each quote")
+	cfg := config.Load(3 * time.Second)
+	defer cfg.Close()
+
+	client, err := cfg.Client()
+	if err != nil {
+		log.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	var quotes []int
+	defer func() {
+		cancelAll(client, quotes)
+	}()
+
+	for {
+		cancelAll(client, quotes)
+		quotes = quotes[:0]
+
+		book, err := client.GetOrderBook(cfg.Symbol)
+		if err != nil {
+			log.Printf("failed to fetch order book: %v", err)
+		} else if mid, err := book.MidPrice(); err != nil {
+			log.Printf("no mid price: %v", err)
+		} else {
+			for _, q := range []struct {
+				side  t.OrderSide
+				price float64
+			}{
+				{t.SideBuy, mid * (1 - *spread/2)},
+				{t.SideSell, mid * (1 + *spread/2)},
+			} {
+				order, err := client.CreateOrder(t.CreateOrderParams{
+					Symbol:     cfg.Symbol,
+					Type:       t.TypeLimit,
+					Side:       q.side,
+					Price:      strconv.FormatFloat(q.price, 'f', 2, 64),
+					BaseAmount: *amount,
+				})
+				if err != nil {
+					log.Printf("failed to quote %s: %v", q.side, err)
+					continue
+				}
+				quotes = append(quotes, order.Id)
+				log.Printf("quoted %s %s @ %s (order %d)", q.side, order.BaseAmount, order.Price, order.Id)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cancelAll cancels the given orders, ignoring orders that already closed.
+func cancelAll(client *bitpin.Client, ids []int) {
+	for _, id := range ids {
+		if err := client.CancelOrder(id); err != nil {
+			log.Printf("failed to cancel order %d: %v", id, err)
+		}
+	}
+}
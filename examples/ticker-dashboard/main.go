@@ -0,0 +1,63 @@
+// Command ticker-dashboard prints a refreshing table of market tickers
+// together with the spread of each market's order book.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	bitpin "github.com/rzabhd80/go-sdk-bitpin"
+	"github.com/rzabhd80/go-sdk-bitpin/examples/internal/config"
+)
+
+func main() {
+	cfg := config.Load(5 * time.Second)
+	defer cfg.Close()
+
+	client, err := cfg.Client()
+	if err != nil {
+		log.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := render(client); err != nil {
+			log.Printf("refresh failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// render prints one snapshot of all tickers.
+func render(client *bitpin.Client) error {
+	tickers, err := client.GetTickers()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "\n%s\nSYMBOL\tPRICE\tLOW\tHIGH\tSPREAD\n", time.Now().Format(time.TimeOnly))
+	for _, t := range *tickers {
+		spread := "-"
+		if book, err := client.GetOrderBook(t.Symbol); err == nil {
+			if s, err := book.Spread(); err == nil {
+				spread = fmt.Sprintf("%g", s)
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Symbol, t.Price, t.Low, t.High, spread)
+	}
+	return w.Flush()
+}
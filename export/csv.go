@@ -0,0 +1,236 @@
+// Package export writes user trades and orders in formats suitable for
+// accounting and spreadsheet workflows.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// TimeFormat selects how timestamps are written.
+type TimeFormat int
+
+const (
+	// RFC3339 writes timestamps such as "2025-03-21T10:30:00Z".
+	RFC3339 TimeFormat = iota
+
+	// Jalali writes Solar Hijri timestamps such as "1404/01/01 14:00:00".
+	Jalali
+)
+
+// Options configures a CSV writer.
+type Options struct {
+	// Columns lists the columns to write, in order. Column names are the JSON
+	// field names of the exported type, such as "price" or "created_at".
+	// Defaults to all columns of the type; see TradeColumns and OrderColumns.
+	Columns []string
+
+	// TimeFormat selects the timestamp format. Defaults to RFC3339.
+	TimeFormat TimeFormat
+
+	// Location is the time zone timestamps are converted to. For RFC3339 nil
+	// keeps the time zone of the API response; for Jalali nil means
+	// utils.TehranTime.
+	Location *time.Location
+
+	// NoHeader disables the header row.
+	NoHeader bool
+
+	// Comma is the field delimiter. Defaults to ','.
+	Comma rune
+}
+
+// column extracts a single CSV field from a record.
+type column[T any] func(record *T, ts func(time.Time) string) string
+
+// TradeColumns lists the columns available for user trades, in the default order.
+var TradeColumns = []string{
+	"id", "created_at", "symbol", "side", "price", "base_amount", "quote_amount",
+	"commission", "commission_currency", "order_id", "identifier",
+}
+
+var tradeColumns = map[string]column[t.UserTrade]{
+	"id":                  func(r *t.UserTrade, _ func(time.Time) string) string { return strconv.Itoa(r.Id) },
+	"created_at":          func(r *t.UserTrade, ts func(time.Time) string) string { return ts(r.CreatedAt) },
+	"symbol":              func(r *t.UserTrade, _ func(time.Time) string) string { return r.Symbol },
+	"side":                func(r *t.UserTrade, _ func(time.Time) string) string { return string(r.Side) },
+	"price":               func(r *t.UserTrade, _ func(time.Time) string) string { return r.Price },
+	"base_amount":         func(r *t.UserTrade, _ func(time.Time) string) string { return r.BaseAmount },
+	"quote_amount":        func(r *t.UserTrade, _ func(time.Time) string) string { return r.QuoteAmount },
+	"commission":          func(r *t.UserTrade, _ func(time.Time) string) string { return r.Commission },
+	"commission_currency": func(r *t.UserTrade, _ func(time.Time) string) string { return r.CommissionCurrency },
+	"order_id":            func(r *t.UserTrade, _ func(time.Time) string) string { return strconv.Itoa(r.OrderId) },
+	"identifier":          func(r *t.UserTrade, _ func(time.Time) string) string { return r.Identifier },
+}
+
+// OrderColumns lists the columns available for orders, in the default order.
+var OrderColumns = []string{
+	"id", "created_at", "closed_at", "symbol", "type", "side", "state", "price",
+	"stop_price", "oco_target_price", "base_amount", "quote_amount",
+	"dealed_base_amount", "dealed_quote_amount", "commission", "identifier",
+}
+
+var orderColumns = map[string]column[t.OrderStatus]{
+	"id":         func(r *t.OrderStatus, _ func(time.Time) string) string { return strconv.Itoa(r.Id) },
+	"created_at": func(r *t.OrderStatus, ts func(time.Time) string) string { return ts(r.CreatedAt) },
+	"closed_at": func(r *t.OrderStatus, ts func(time.Time) string) string {
+		if closed, err := time.Parse(time.RFC3339Nano, r.ClosedAt); err == nil {
+			return ts(closed)
+		}
+		return r.ClosedAt
+	},
+	"symbol":              func(r *t.OrderStatus, _ func(time.Time) string) string { return r.Symbol },
+	"type":                func(r *t.OrderStatus, _ func(time.Time) string) string { return string(r.Type) },
+	"side":                func(r *t.OrderStatus, _ func(time.Time) string) string { return string(r.Side) },
+	"state":               func(r *t.OrderStatus, _ func(time.Time) string) string { return string(r.State) },
+	"price":               func(r *t.OrderStatus, _ func(time.Time) string) string { return r.Price },
+	"stop_price":          func(r *t.OrderStatus, _ func(time.Time) string) string { return r.StopPrice },
+	"oco_target_price":    func(r *t.OrderStatus, _ func(time.Time) string) string { return r.OcoTargetPrice },
+	"base_amount":         func(r *t.OrderStatus, _ func(time.Time) string) string { return r.BaseAmount },
+	"quote_amount":        func(r *t.OrderStatus, _ func(time.Time) string) string { return r.QuoteAmount },
+	"dealed_base_amount":  func(r *t.OrderStatus, _ func(time.Time) string) string { return r.DealedBaseAmount },
+	"dealed_quote_amount": func(r *t.OrderStatus, _ func(time.Time) string) string { return r.DealedQuoteAmount },
+	"commission":          func(r *t.OrderStatus, _ func(time.Time) string) string { return r.Commission },
+	"identifier":          func(r *t.OrderStatus, _ func(time.Time) string) string { return r.Identifier },
+}
+
+// CSVWriter streams records of type T as CSV rows. Create one with
+// NewTradeWriter or NewOrderWriter.
+type CSVWriter[T any] struct {
+	w         *csv.Writer
+	columns   []column[T]
+	header    []string
+	needsHead bool
+	ts        func(time.Time) string
+}
+
+// NewTradeWriter returns a writer that streams user trades as CSV to w.
+// It returns an error if Options.Columns contains an unknown column.
+//
+// Example:
+//
+//	cw, err := export.NewTradeWriter(os.Stdout, export.Options{TimeFormat: export.Jalali})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, trade := range *trades {
+//	    _ = cw.Write(&trade)
+//	}
+//	if err := cw.Flush(); err != nil {
+//	    log.Fatal(err)
+//	}
+func NewTradeWriter(w io.Writer, opts Options) (*CSVWriter[t.UserTrade], error) {
+	return newCSVWriter(w, opts, TradeColumns, tradeColumns)
+}
+
+// NewOrderWriter returns a writer that streams orders as CSV to w.
+// It returns an error if Options.Columns contains an unknown column.
+func NewOrderWriter(w io.Writer, opts Options) (*CSVWriter[t.OrderStatus], error) {
+	return newCSVWriter(w, opts, OrderColumns, orderColumns)
+}
+
+func newCSVWriter[T any](w io.Writer, opts Options, defaults []string, available map[string]column[T]) (*CSVWriter[T], error) {
+	names := opts.Columns
+	if len(names) == 0 {
+		names = defaults
+	}
+
+	cw := &CSVWriter[T]{
+		w:         csv.NewWriter(w),
+		header:    append([]string(nil), names...),
+		needsHead: !opts.NoHeader,
+		ts:        timestampFormatter(opts.TimeFormat, opts.Location),
+	}
+	if opts.Comma != 0 {
+		cw.w.Comma = opts.Comma
+	}
+	for _, name := range names {
+		col, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		cw.columns = append(cw.columns, col)
+	}
+	return cw, nil
+}
+
+// Write writes a single record, preceded by the header row on the first call.
+// Rows are buffered; call Flush to write them out.
+func (cw *CSVWriter[T]) Write(record *T) error {
+	if cw.needsHead {
+		if err := cw.w.Write(cw.header); err != nil {
+			return err
+		}
+		cw.needsHead = false
+	}
+
+	row := make([]string, len(cw.columns))
+	for i, col := range cw.columns {
+		row[i] = col(record, cw.ts)
+	}
+	return cw.w.Write(row)
+}
+
+// WriteAll writes all records and flushes the writer.
+func (cw *CSVWriter[T]) WriteAll(records []T) error {
+	for i := range records {
+		if err := cw.Write(&records[i]); err != nil {
+			return err
+		}
+	}
+	return cw.Flush()
+}
+
+// Flush writes any buffered rows, including a pending header, to the
+// underlying writer and reports any write error.
+func (cw *CSVWriter[T]) Flush() error {
+	if cw.needsHead {
+		if err := cw.w.Write(cw.header); err != nil {
+			return err
+		}
+		cw.needsHead = false
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// WriteTrades writes user trades to w as CSV.
+func WriteTrades(w io.Writer, trades t.UserTrades, opts Options) error {
+	cw, err := NewTradeWriter(w, opts)
+	if err != nil {
+		return err
+	}
+	return cw.WriteAll(trades)
+}
+
+// WriteOrders writes orders to w as CSV.
+func WriteOrders(w io.Writer, orders t.OrderStatuses, opts Options) error {
+	cw, err := NewOrderWriter(w, opts)
+	if err != nil {
+		return err
+	}
+	return cw.WriteAll(orders)
+}
+
+// timestampFormatter returns the function used to render timestamps. Zero
+// times are written as empty fields.
+func timestampFormatter(format TimeFormat, loc *time.Location) func(time.Time) string {
+	return func(ts time.Time) string {
+		if ts.IsZero() {
+			return ""
+		}
+		if format == Jalali {
+			return u.FormatJalali(ts, loc)
+		}
+		if loc != nil {
+			ts = ts.In(loc)
+		}
+		return ts.Format(time.RFC3339)
+	}
+}
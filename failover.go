@@ -0,0 +1,208 @@
+package bitpin
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFailoverRecoverAfter is how long an unreachable base URL is skipped
+// when FailoverOptions.RecoverAfter is not set.
+const DefaultFailoverRecoverAfter = 30 * time.Second
+
+// FailoverOptions configures failover from the base URL to mirrors, such as
+// CDN endpoints, when it is unreachable.
+type FailoverOptions struct {
+	// URLs are the mirrors of the base URL, tried in order when the base URL
+	// and the mirrors before them are unreachable.
+	URLs []string
+
+	// RecoverAfter is how long an unreachable URL is skipped. The next request
+	// after that is sent to it again, and requests stay there if it answers.
+	// Defaults to DefaultFailoverRecoverAfter.
+	RecoverAfter time.Duration
+
+	// OnFailover is invoked in its own goroutine whenever requests move to
+	// another URL, e.g. to alert when the primary is down and when it is back.
+	OnFailover func(from, to string)
+}
+
+// failover tracks the health of the base URL and its mirrors. A nil failover
+// sends every request to the base URL.
+type failover struct {
+	opts  FailoverOptions
+	clock Clock
+	urls  []string // the base URL first, then the mirrors
+
+	mu        sync.Mutex
+	downUntil []time.Time // when each URL is tried again after a failure
+	active    int         // the URL that answered the last request
+}
+
+// newFailover creates the failover of the base URL described by opts, or nil
+// if opts is nil or has no mirrors.
+func newFailover(baseUrl string, opts *FailoverOptions, clock Clock) *failover {
+	if opts == nil || len(opts.URLs) == 0 {
+		return nil
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+	f := &failover{opts: *opts, clock: clock}
+	if f.opts.RecoverAfter <= 0 {
+		f.opts.RecoverAfter = DefaultFailoverRecoverAfter
+	}
+	for _, u := range append([]string{baseUrl}, opts.URLs...) {
+		f.urls = append(f.urls, strings.TrimRight(u, "/"))
+	}
+	f.downUntil = make([]time.Time, len(f.urls))
+	return f
+}
+
+// pick returns the index of the first URL that is not skipped, or of the one
+// that recovers first if all of them are, excluding the tried ones.
+func (f *failover) pick(tried []bool) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.clock.Now()
+	best := -1
+	for i, until := range f.downUntil {
+		if tried[i] {
+			continue
+		}
+		if !until.After(now) {
+			return i
+		}
+		if best < 0 || until.Before(f.downUntil[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// report records the outcome of a request sent to the URL at index i.
+func (f *failover) report(i int, reachable bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if reachable {
+		f.downUntil[i] = time.Time{}
+	} else {
+		f.downUntil[i] = f.clock.Now().Add(f.opts.RecoverAfter)
+	}
+	if reachable && i != f.active {
+		from, to := f.urls[f.active], f.urls[i]
+		f.active = i
+		if f.opts.OnFailover != nil {
+			go f.opts.OnFailover(from, to)
+		}
+	}
+}
+
+// current returns the URL that answered the last request.
+func (f *failover) current() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.urls[f.active]
+}
+
+// rebase moves a URL under one of the known base URLs to the URL at index i.
+// Other URLs are returned unchanged.
+func (f *failover) rebase(rawURL string, i int) string {
+	for _, base := range f.urls {
+		if rawURL == base || strings.HasPrefix(rawURL, base+"/") || strings.HasPrefix(rawURL, base+"?") {
+			return f.urls[i] + strings.TrimPrefix(rawURL, base)
+		}
+	}
+	return rawURL
+}
+
+// ActiveBaseURL returns the URL that answered the last request: the base URL,
+// or the mirror the client failed over to. See ClientOptions.Failover.
+func (c *Client) ActiveBaseURL() string {
+	if c.failover == nil {
+		return c.BaseUrl
+	}
+	return c.failover.current()
+}
+
+// do sends req, failing over to the mirrors of the base URL when it cannot be
+// reached. It returns the response and the request that was sent last.
+//
+// A request is only sent to another URL if it is idempotent or if the
+// connection failed before it was sent, so orders are never submitted twice.
+// Responses of any status count as reachable, since mirrors serve the same
+// API.
+func (c *Client) do(req *http.Request) (*http.Response, *http.Request, error) {
+	f := c.failover
+	if f == nil {
+		resp, err := c.HttpClient.Do(req)
+		return resp, req, err
+	}
+
+	tried := make([]bool, len(f.urls))
+	for attempts := 0; ; attempts++ {
+		i := f.pick(tried)
+		tried[i] = true
+		attempt := req
+		if target := f.rebase(req.URL.String(), i); attempts > 0 || target != req.URL.String() {
+			var err error
+			if attempt, err = rebaseRequest(req, target); err != nil {
+				return nil, req, err
+			}
+		}
+		resp, err := c.HttpClient.Do(attempt)
+		if err == nil || req.Context().Err() != nil {
+			if err == nil {
+				f.report(i, true)
+			}
+			return resp, attempt, err
+		}
+		f.report(i, false)
+		if f.pick(tried) < 0 || !replayableRequest(req) || (!idempotent(req.Method) && !dialFailed(err)) {
+			return resp, attempt, err
+		}
+	}
+}
+
+// rebaseRequest returns a copy of req sent to target, with a fresh body.
+func rebaseRequest(req *http.Request, target string) (*http.Request, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	attempt := req.Clone(req.Context())
+	attempt.URL, attempt.Host = u, u.Host
+	if req.GetBody != nil {
+		if attempt.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return attempt, nil
+}
+
+// replayableRequest reports whether the body of req can be sent again, which
+// is not the case for streamed bodies such as multipart forms of plain readers.
+func replayableRequest(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// dialFailed reports whether err happened while connecting, before any of the
+// request was sent.
+func dialFailed(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// idempotent reports whether a request with the given method can be sent
+// again without side effects.
+func idempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
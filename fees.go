@@ -0,0 +1,210 @@
+package bitpin
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// DefaultFeeCurrency is the reporting currency of fee reports when none is
+// given.
+const DefaultFeeCurrency = "USDT"
+
+// FeeReportOptions configures a fee report.
+type FeeReportOptions struct {
+	// Currency is the reporting currency. Defaults to DefaultFeeCurrency.
+	Currency string
+
+	// Window splits the report into time buckets of this size, aligned to
+	// multiples of Window since the Unix epoch in UTC. Zero puts all trades of
+	// a group into one bucket.
+	Window time.Duration
+
+	// Since and Until restrict the report to trades created in [Since, Until).
+	// Zero values leave the range open.
+	Since time.Time
+	Until time.Time
+
+	// GroupBy assigns each trade to a group, such as the strategy that placed
+	// the order. Nil puts all trades into the group "". See
+	// GroupByIdentifierPrefix.
+	GroupBy func(trade t.UserTrade) string
+}
+
+// FeeBucket is the commission paid by a group of trades within a time window.
+type FeeBucket struct {
+	// Group is the group assigned by FeeReportOptions.GroupBy.
+	Group string
+
+	// Start and End delimit the window. Both are zero when the report has no
+	// Window.
+	Start time.Time
+	End   time.Time
+
+	// Trades is the number of trades in the bucket.
+	Trades int
+
+	// ByCurrency is the commission paid per canonical currency.
+	ByCurrency map[string]float64
+
+	// Total is the commission converted to the reporting currency. Commissions
+	// in currencies listed in Unconverted are not included.
+	Total float64
+
+	// Unconverted lists currencies without a conversion path to the reporting
+	// currency.
+	Unconverted []string
+}
+
+// FeeReport summarizes commissions paid, in total and per bucket.
+type FeeReport struct {
+	// Currency is the canonical reporting currency.
+	Currency string
+
+	// Buckets are sorted by group and start time.
+	Buckets []*FeeBucket
+
+	// ByCurrency and Total add up all buckets.
+	ByCurrency map[string]float64
+	Total      float64
+
+	// Unconverted lists currencies without a conversion path to Currency.
+	Unconverted []string
+}
+
+// GroupByIdentifierPrefix returns a GroupBy function that groups trades by the
+// part of their order identifier before the first sep, so that orders
+// identified as "grid-42" and "grid-43" both count towards "grid". Trades
+// without an identifier are grouped under "".
+func GroupByIdentifierPrefix(sep string) func(trade t.UserTrade) string {
+	return func(trade t.UserTrade) string {
+		prefix, _, _ := strings.Cut(trade.Identifier, sep)
+		return prefix
+	}
+}
+
+// NewFeeReport aggregates the commissions of trades by group, time window and
+// currency, and converts them to the reporting currency with the prices of
+// tickers; see Converter.
+//
+// Example:
+//
+//	report := bitpin.NewFeeReport(trades, *markets, *tickers, bitpin.FeeReportOptions{
+//	    Currency: "IRT",
+//	    Window:   24 * time.Hour,
+//	    GroupBy:  bitpin.GroupByIdentifierPrefix("-"),
+//	})
+//	for _, b := range report.Buckets {
+//	    fmt.Printf("%s %s: %.0f IRT\n", b.Start.Format("2006-01-02"), b.Group, b.Total)
+//	}
+func NewFeeReport(trades []t.UserTrade, markets t.Markets, tickers t.Tickers, opts FeeReportOptions) *FeeReport {
+	if opts.Currency == "" {
+		opts.Currency = DefaultFeeCurrency
+	}
+	report := &FeeReport{Currency: u.CanonicalCurrency(opts.Currency), ByCurrency: make(map[string]float64)}
+	conv := NewConverter(markets, tickers)
+
+	type bucketKey struct {
+		group string
+		start time.Time
+	}
+	buckets := make(map[bucketKey]*FeeBucket)
+	for _, trade := range trades {
+		if !opts.Since.IsZero() && trade.CreatedAt.Before(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && !trade.CreatedAt.Before(opts.Until) {
+			continue
+		}
+
+		var key bucketKey
+		if opts.GroupBy != nil {
+			key.group = opts.GroupBy(trade)
+		}
+		if opts.Window > 0 {
+			key.start = trade.CreatedAt.UTC().Truncate(opts.Window)
+		}
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &FeeBucket{Group: key.group, ByCurrency: make(map[string]float64)}
+			if opts.Window > 0 {
+				bucket.Start, bucket.End = key.start, key.start.Add(opts.Window)
+			}
+			buckets[key] = bucket
+		}
+		bucket.Trades++
+
+		commission, err := strconv.ParseFloat(trade.Commission, 64)
+		if err != nil || commission == 0 {
+			continue
+		}
+		currency := u.CanonicalCurrency(trade.CommissionCurrency)
+		bucket.ByCurrency[currency] += commission
+		report.ByCurrency[currency] += commission
+	}
+
+	unconverted := make(map[string]bool)
+	for _, bucket := range buckets {
+		for currency, amount := range bucket.ByCurrency {
+			value, err := conv.Convert(amount, currency, report.Currency)
+			if err != nil {
+				bucket.Unconverted = append(bucket.Unconverted, currency)
+				unconverted[currency] = true
+				continue
+			}
+			bucket.Total += value
+			report.Total += value
+		}
+		sort.Strings(bucket.Unconverted)
+		report.Buckets = append(report.Buckets, bucket)
+	}
+	for currency := range unconverted {
+		report.Unconverted = append(report.Unconverted, currency)
+	}
+	sort.Strings(report.Unconverted)
+	sort.Slice(report.Buckets, func(i, j int) bool {
+		if report.Buckets[i].Group != report.Buckets[j].Group {
+			return report.Buckets[i].Group < report.Buckets[j].Group
+		}
+		return report.Buckets[i].Start.Before(report.Buckets[j].Start)
+	})
+	return report
+}
+
+// FeeReport fetches the fills matching params, markets and tickers, and
+// aggregates the commissions; see NewFeeReport.
+//
+// Example:
+//
+//	report, err := client.FeeReport(ctx, types.GetUserTradesParams{Limit: 500}, bitpin.FeeReportOptions{
+//	    Currency: "IRT",
+//	    Since:    time.Now().AddDate(0, -1, 0),
+//	})
+//	if err == nil {
+//	    fmt.Printf("fees this month: %.0f IRT\n", report.Total)
+//	}
+func (c *Client) FeeReport(ctx context.Context, params t.GetUserTradesParams, opts FeeReportOptions) (*FeeReport, error) {
+	var trades t.UserTrades
+	if err := c.call(ctx, "GET", OpFills, nil, true, params, &trades); err != nil {
+		return nil, err
+	}
+	markets, err := c.GetMarkets()
+	if err != nil {
+		return nil, err
+	}
+	var tickers t.Tickers
+	if err := c.call(ctx, "GET", OpTickers, nil, false, nil, &tickers); err != nil {
+		return nil, err
+	}
+
+	var m t.Markets
+	if markets != nil {
+		m = *markets
+	}
+	return NewFeeReport(trades, m, tickers, opts), nil
+}
@@ -0,0 +1,104 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// PriceGuard protects an order against execution on a stale signal. Before the
+// order is sent, the top of the book is fetched again and compared with the
+// price the trading decision was based on.
+type PriceGuard struct {
+	// DecisionPrice is the price the decision to trade was based on.
+	DecisionPrice string
+
+	// MaxDeviation is the largest accepted move of the touch price away from
+	// DecisionPrice, as a fraction (0.003 means 0.3%).
+	MaxDeviation float64
+
+	// AdverseOnly only aborts when the price moved against the order (up for
+	// buys, down for sells). Favorable moves are accepted.
+	AdverseOnly bool
+}
+
+// CreateGuardedOrder re-checks the top of the book immediately before submitting
+// an order and aborts if the touch price moved more than the guard allows since
+// the decision price. It is intended for market orders, whose execution price is
+// otherwise unbounded.
+//
+// Parameters:
+//   - params: The order to submit.
+//   - guard: The decision price and the accepted deviation.
+//
+// Returns:
+//   - A pointer to an `OrderStatus` struct for the created order.
+//   - A `*PriceGuardError` if the order was aborted, or any error returned by
+//     the order book or order requests.
+//
+// Example:
+//
+//	order, err := client.CreateGuardedOrder(params, bitpin.PriceGuard{
+//	    DecisionPrice: signal.Price,
+//	    MaxDeviation:  0.002,
+//	    AdverseOnly:   true,
+//	})
+//	var guardErr *bitpin.PriceGuardError
+//	if errors.As(err, &guardErr) {
+//	    log.Printf("price moved %.2f%%, order skipped", guardErr.Deviation*100)
+//	}
+func (c *Client) CreateGuardedOrder(params t.CreateOrderParams, guard PriceGuard) (*t.OrderStatus, error) {
+	return c.createGuardedOrder(context.Background(), params, guard)
+}
+
+// createGuardedOrder is the context-aware implementation of `CreateGuardedOrder`.
+func (c *Client) createGuardedOrder(ctx context.Context, params t.CreateOrderParams, guard PriceGuard) (*t.OrderStatus, error) {
+	decision, err := strconv.ParseFloat(guard.DecisionPrice, 64)
+	if err != nil || decision <= 0 {
+		return nil, &GoBitpinError{
+			Message: fmt.Sprintf("invalid decision price %q", guard.DecisionPrice),
+			Err:     err,
+		}
+	}
+
+	book, err := c.GetOrderBookWithContext(ctx, params.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	touch := touchPrice(book, params.Side)
+	current, err := strconv.ParseFloat(touch, 64)
+	if err != nil {
+		return nil, &GoBitpinError{
+			Message: fmt.Sprintf("no usable touch price for %s %s", params.Side, params.Symbol),
+			Err:     err,
+		}
+	}
+
+	move := (current - decision) / decision
+	if strings.EqualFold(string(params.Side), string(t.SideSell)) {
+		move = -move
+	}
+	deviation := math.Abs(move)
+	if guard.AdverseOnly {
+		deviation = math.Max(move, 0)
+	}
+
+	if deviation > guard.MaxDeviation {
+		return nil, &PriceGuardError{
+			GoBitpinError: GoBitpinError{
+				Message: fmt.Sprintf("price moved from %s to %s (%.4f%%), exceeding the %.4f%% guard",
+					guard.DecisionPrice, touch, deviation*100, guard.MaxDeviation*100),
+			},
+			DecisionPrice: decision,
+			CurrentPrice:  current,
+			Deviation:     deviation,
+		}
+	}
+
+	return c.CreateOrderWithContext(ctx, params)
+}
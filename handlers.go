@@ -0,0 +1,142 @@
+package bitpin
+
+import (
+	"hash/fnv"
+	"sync"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// handlerPool runs event handlers on a fixed set of worker goroutines. Every
+// channel is bound to one worker, so the handlers of a channel see its updates
+// in order while different channels are handled in parallel.
+type handlerPool struct {
+	workers int
+	buffer  int
+
+	start  sync.Once
+	queues []chan func()
+	wg     sync.WaitGroup
+}
+
+func newHandlerPool(workers, buffer int) *handlerPool {
+	return &handlerPool{workers: workers, buffer: buffer}
+}
+
+// submit queues fn on the worker bound to channel, starting the workers on
+// first use. It blocks while that worker's queue is full. It must only be
+// called from the stream goroutine.
+func (p *handlerPool) submit(channel StreamChannel, fn func()) {
+	p.start.Do(func() {
+		p.queues = make([]chan func(), p.workers)
+		for i := range p.queues {
+			queue := make(chan func(), p.buffer)
+			p.queues[i] = queue
+			p.wg.Add(1)
+			go func() {
+				defer p.wg.Done()
+				for fn := range queue {
+					fn()
+				}
+			}()
+		}
+	})
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(channel.String()))
+	p.queues[h.Sum32()%uint32(len(p.queues))] <- fn
+}
+
+// stop lets the workers finish the queued handlers and waits for them.
+func (p *handlerPool) stop() {
+	p.start.Do(func() {})
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.wg.Wait()
+}
+
+// onEvent registers handler for channel and runs it on the handler pool.
+func (s *Stream) onEvent(channel StreamChannel, handler func(StreamEvent)) (func(), error) {
+	return s.Subscribe(channel, func(event StreamEvent) {
+		s.pool.submit(channel, func() { handler(event) })
+	})
+}
+
+// OnTicker registers a handler for the ticker of a market.
+//
+// Parameters:
+//   - symbol: The market symbol, e.g. "BTC_IRT".
+//   - handler: Called with the ticker whenever it changes, and once more after
+//     every reconnect.
+//
+// Returns:
+//   - A function that removes the handler.
+//   - An error if the symbol is empty or the stream is closed.
+//
+// Behavior:
+//   - Handlers run on a pool of StreamOptions.Workers goroutines rather than on
+//     the stream goroutine, so a slow handler does not delay polling unless the
+//     queue of its worker is full.
+//   - Updates of one market are handled in order; handlers of different
+//     markets may run concurrently.
+//
+// Example:
+//
+//	remove, err := stream.OnTicker("BTC_IRT", func(ticker *types.Ticker) {
+//	    log.Printf("BTC_IRT: %s", ticker.Price)
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer remove()
+func (s *Stream) OnTicker(symbol string, handler func(ticker *t.Ticker)) (func(), error) {
+	return s.onEvent(StreamChannel{Kind: StreamTicker, Symbol: symbol}, func(event StreamEvent) {
+		handler(event.Ticker)
+	})
+}
+
+// OnTrade registers a handler for the public trades of a market. See OnTicker
+// for how handlers are run.
+func (s *Stream) OnTrade(symbol string, handler func(trade *t.Trade)) (func(), error) {
+	return s.onEvent(StreamChannel{Kind: StreamTrades, Symbol: symbol}, func(event StreamEvent) {
+		handler(event.Trade)
+	})
+}
+
+// OnOrderBook registers a handler for the order book of a market. See OnTicker
+// for how handlers are run.
+func (s *Stream) OnOrderBook(symbol string, handler func(book *t.OrderBook)) (func(), error) {
+	return s.onEvent(StreamChannel{Kind: StreamOrderBook, Symbol: symbol}, func(event StreamEvent) {
+		handler(event.OrderBook)
+	})
+}
+
+// OnDepth registers a handler for the order book deltas of a market. See
+// OnTicker for how handlers are run.
+func (s *Stream) OnDepth(symbol string, handler func(delta *t.OrderBookDelta)) (func(), error) {
+	return s.onEvent(StreamChannel{Kind: StreamDepth, Symbol: symbol}, func(event StreamEvent) {
+		handler(event.Depth)
+	})
+}
+
+// OnOrderUpdate registers a handler for order updates of the authenticated
+// user. An empty symbol covers all markets. See OnTicker for how handlers are
+// run.
+func (s *Stream) OnOrderUpdate(symbol string, handler func(order *t.OrderStatus)) (func(), error) {
+	return s.onEvent(StreamChannel{Kind: StreamUserData, Symbol: symbol}, func(event StreamEvent) {
+		if event.Order != nil {
+			handler(event.Order)
+		}
+	})
+}
+
+// OnFill registers a handler for fills of the authenticated user. An empty
+// symbol covers all markets. See OnTicker for how handlers are run.
+func (s *Stream) OnFill(symbol string, handler func(fill *t.UserTrade)) (func(), error) {
+	return s.onEvent(StreamChannel{Kind: StreamUserData, Symbol: symbol}, func(event StreamEvent) {
+		if event.Fill != nil {
+			handler(event.Fill)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package bitpin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// PingResult describes the outcome of a connectivity check.
+type PingResult struct {
+	// Reachable reports whether the API answered with a successful response.
+	Reachable bool
+
+	// Latency is the round-trip time of the check, including a failed one.
+	Latency time.Duration
+
+	// StatusCode is the HTTP status of the response, or 0 if none arrived.
+	StatusCode int
+
+	// ServerTime is the API's clock at the time of the check, or the zero time
+	// if the API could not be reached.
+	ServerTime time.Time
+
+	// CheckedAt is the local time the check started.
+	CheckedAt time.Time
+}
+
+// pingRequest requests the currencies endpoint, which needs no authentication
+// and supports conditional requests. When currencies are cached, their
+// validators are sent so that the API usually answers 304 Not Modified without
+// a body.
+func (c *Client) pingRequest(ctx context.Context) (*RawResponse, error) {
+	header := http.Header{}
+	if !c.DisableConditionalRequests {
+		c.metadata.mu.Lock()
+		if validators := c.metadata.currencies.validators(); validators != nil {
+			header = validators
+		}
+		c.metadata.mu.Unlock()
+	}
+	return c.requestRaw(ctx, "GET", c.apiURI(ctx, OpCurrencies, nil), false, nil, header)
+}
+
+// Ping checks connectivity to the API with a lightweight public request and
+// reports whether it is reachable and how long the round trip took. It is meant
+// for readiness probes and failover logic; no credentials are needed.
+//
+// Ping goes through the client's circuit breaker, so while the circuit is open
+// it reports the API unreachable without sending a request.
+//
+// Parameters:
+//   - ctx: Bounds the check. Use a short deadline for probes.
+//
+// Returns:
+//   - A `PingResult`, filled in even when the check fails.
+//   - An error describing why the API is unreachable, or nil.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+//	defer cancel()
+//
+//	result, err := client.Ping(ctx)
+//	if err != nil {
+//	    log.Printf("bitpin unreachable after %s: %v", result.Latency, err)
+//	    return
+//	}
+//	log.Printf("bitpin reachable, latency %s", result.Latency)
+func (c *Client) Ping(ctx context.Context) (PingResult, error) {
+	result := PingResult{CheckedAt: time.Now()}
+	raw, err := c.pingRequest(ctx)
+	result.Latency = time.Since(result.CheckedAt)
+	if raw != nil {
+		result.StatusCode = raw.StatusCode
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		result.StatusCode = apiErr.StatusCode
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.Reachable = true
+	result.ServerTime = c.Now()
+	return result, nil
+}
@@ -0,0 +1,228 @@
+// Package indicators computes technical indicators over price series, such as
+// the close prices of types.Candles. Every indicator returns a series aligned
+// with its input: element i is the indicator value at input i, and values
+// before the indicator has enough data are NaN.
+//
+// Example:
+//
+//	closes := candles.Closes()
+//	rsi, err := indicators.RSI(closes, 14)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if last, ok := indicators.Last(rsi); ok && last < 30 {
+//	    fmt.Println("oversold")
+//	}
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidPeriod is returned when a period is not positive or a MACD fast
+// period is not shorter than its slow period.
+var ErrInvalidPeriod = errors.New("indicators: invalid period")
+
+// Last returns the last value of a series that is not NaN.
+func Last(series []float64) (float64, bool) {
+	for i := len(series) - 1; i >= 0; i-- {
+		if !math.IsNaN(series[i]) {
+			return series[i], true
+		}
+	}
+	return 0, false
+}
+
+// nanSeries returns a series of n NaN values.
+func nanSeries(n int) []float64 {
+	series := make([]float64, n)
+	for i := range series {
+		series[i] = math.NaN()
+	}
+	return series
+}
+
+// checkPeriod validates a period.
+func checkPeriod(name string, period int) error {
+	if period <= 0 {
+		return fmt.Errorf("%w: %s must be positive, got %d", ErrInvalidPeriod, name, period)
+	}
+	return nil
+}
+
+// SMA returns the simple moving average: the mean of the last period values.
+// The first period-1 values are NaN.
+func SMA(values []float64, period int) ([]float64, error) {
+	if err := checkPeriod("period", period); err != nil {
+		return nil, err
+	}
+	out := nanSeries(len(values))
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out, nil
+}
+
+// EMA returns the exponential moving average with smoothing 2/(period+1),
+// seeded with the SMA of the first period values. The first period-1 values
+// are NaN. NaN inputs, such as the warm-up of another indicator, are skipped.
+func EMA(values []float64, period int) ([]float64, error) {
+	if err := checkPeriod("period", period); err != nil {
+		return nil, err
+	}
+	return ema(values, period, 2/float64(period+1)), nil
+}
+
+// ema computes an exponential average with factor alpha, seeded with the mean
+// of the first period non-NaN values.
+func ema(values []float64, period int, alpha float64) []float64 {
+	out := nanSeries(len(values))
+	var (
+		seen    int
+		sum     float64
+		current float64
+	)
+	for i, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		seen++
+		switch {
+		case seen < period:
+			sum += v
+			continue
+		case seen == period:
+			current = (sum + v) / float64(period)
+		default:
+			current += alpha * (v - current)
+		}
+		out[i] = current
+	}
+	return out
+}
+
+// RSI returns the relative strength index with Wilder's smoothing, between 0
+// and 100. The first period values are NaN.
+func RSI(values []float64, period int) ([]float64, error) {
+	if err := checkPeriod("period", period); err != nil {
+		return nil, err
+	}
+	out := nanSeries(len(values))
+	if len(values) <= period {
+		return out, nil
+	}
+
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		change := values[i] - values[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+	out[period] = rsi(gain, loss)
+
+	for i := period + 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		up, down := math.Max(change, 0), math.Max(-change, 0)
+		gain = (gain*float64(period-1) + up) / float64(period)
+		loss = (loss*float64(period-1) + down) / float64(period)
+		out[i] = rsi(gain, loss)
+	}
+	return out, nil
+}
+
+// rsi converts average gains and losses into an RSI value.
+func rsi(gain, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// MACDResult holds the three series of a MACD.
+type MACDResult struct {
+	// MACD is the fast EMA minus the slow EMA.
+	MACD []float64
+
+	// Signal is the EMA of MACD.
+	Signal []float64
+
+	// Histogram is MACD minus Signal.
+	Histogram []float64
+}
+
+// MACD returns the moving average convergence divergence, commonly used with
+// periods 12, 26 and 9.
+func MACD(values []float64, fast, slow, signal int) (*MACDResult, error) {
+	for _, p := range []struct {
+		name   string
+		period int
+	}{{"fast", fast}, {"slow", slow}, {"signal", signal}} {
+		if err := checkPeriod(p.name, p.period); err != nil {
+			return nil, err
+		}
+	}
+	if fast >= slow {
+		return nil, fmt.Errorf("%w: fast period %d must be shorter than slow period %d", ErrInvalidPeriod, fast, slow)
+	}
+
+	fastEMA, _ := EMA(values, fast)
+	slowEMA, _ := EMA(values, slow)
+	result := &MACDResult{MACD: nanSeries(len(values)), Histogram: nanSeries(len(values))}
+	for i := range values {
+		result.MACD[i] = fastEMA[i] - slowEMA[i]
+	}
+	result.Signal, _ = EMA(result.MACD, signal)
+	for i := range values {
+		result.Histogram[i] = result.MACD[i] - result.Signal[i]
+	}
+	return result, nil
+}
+
+// BollingerBands holds the three series of Bollinger bands.
+type BollingerBands struct {
+	// Upper is Middle plus k standard deviations.
+	Upper []float64
+
+	// Middle is the SMA.
+	Middle []float64
+
+	// Lower is Middle minus k standard deviations.
+	Lower []float64
+}
+
+// Bollinger returns Bollinger bands around the SMA of period values, k
+// population standard deviations wide, commonly used with period 20 and k 2.
+func Bollinger(values []float64, period int, k float64) (*BollingerBands, error) {
+	middle, err := SMA(values, period)
+	if err != nil {
+		return nil, err
+	}
+	bands := &BollingerBands{Upper: nanSeries(len(values)), Middle: middle, Lower: nanSeries(len(values))}
+	for i := period - 1; i < len(values); i++ {
+		var variance float64
+		for _, v := range values[i-period+1 : i+1] {
+			variance += (v - middle[i]) * (v - middle[i])
+		}
+		deviation := math.Sqrt(variance / float64(period))
+		bands.Upper[i] = middle[i] + k*deviation
+		bands.Lower[i] = middle[i] - k*deviation
+	}
+	return bands, nil
+}
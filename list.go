@@ -0,0 +1,143 @@
+package bitpin
+
+import (
+	"context"
+	"iter"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// ListOrders fetches one page of the order history.
+//
+// Parameters:
+//   - ctx: Controls cancellation and deadlines of the request.
+//   - params: Filters for the orders. Its Offset and Limit are replaced by
+//     those of opts.
+//   - opts: Selects the page. Pass `Page.Next` of the previous page to continue.
+//
+// Returns:
+//   - A pointer to a `Page` of orders, most recent first.
+//   - An error if the request fails.
+//
+// Example:
+//
+//	params := t.GetOrdersHistoryParams{Symbol: "BTC_USDT"}
+//	opts := t.ListOptions{Limit: 50}
+//	for {
+//	    page, err := client.ListOrders(ctx, params, opts)
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    for _, order := range page.Items {
+//	        fmt.Println(order.Id, order.State)
+//	    }
+//	    if !page.HasMore {
+//	        break
+//	    }
+//	    opts = page.Next()
+//	}
+func (c *Client) ListOrders(ctx context.Context, params t.GetOrdersHistoryParams, opts t.ListOptions) (*t.Page[t.OrderStatus], error) {
+	opts = listDefaults(opts)
+	params.Offset, params.Limit = opts.Offset, opts.Limit
+	return listPage[t.OrderStatus](ctx, c, OpOrders, params, opts)
+}
+
+// ListUserTrades fetches one page of the user's trades (fills), most recent
+// first. Its Offset and Limit are replaced by those of opts.
+func (c *Client) ListUserTrades(ctx context.Context, params t.GetUserTradesParams, opts t.ListOptions) (*t.Page[t.UserTrade], error) {
+	opts = listDefaults(opts)
+	params.Offset, params.Limit = opts.Offset, opts.Limit
+	return listPage[t.UserTrade](ctx, c, OpFills, params, opts)
+}
+
+// ListWallets fetches one page of the user's wallets. Its Offset and Limit are
+// replaced by those of opts.
+func (c *Client) ListWallets(ctx context.Context, params t.GetWalletParams, opts t.ListOptions) (*t.Page[t.Wallet], error) {
+	opts = listDefaults(opts)
+	params.Offset, params.Limit = opts.Offset, opts.Limit
+	return listPage[t.Wallet](ctx, c, OpWallets, params, opts)
+}
+
+// OrdersIter iterates over the order history, fetching pages of
+// DefaultListLimit orders as needed. The Offset and Limit of params are
+// ignored.
+//
+// If fetching a page fails, the error is yielded once with a zero order and the
+// iteration ends. Breaking out of the loop stops fetching.
+//
+// Example:
+//
+//	for order, err := range client.OrdersIter(ctx, t.GetOrdersHistoryParams{Symbol: "BTC_USDT"}) {
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    fmt.Println(order.Id, order.State)
+//	}
+func (c *Client) OrdersIter(ctx context.Context, params t.GetOrdersHistoryParams) iter.Seq2[t.OrderStatus, error] {
+	return listIter(func(opts t.ListOptions) (*t.Page[t.OrderStatus], error) {
+		return c.ListOrders(ctx, params, opts)
+	})
+}
+
+// UserTradesIter iterates over the user's trades (fills), most recent first,
+// like OrdersIter.
+func (c *Client) UserTradesIter(ctx context.Context, params t.GetUserTradesParams) iter.Seq2[t.UserTrade, error] {
+	return listIter(func(opts t.ListOptions) (*t.Page[t.UserTrade], error) {
+		return c.ListUserTrades(ctx, params, opts)
+	})
+}
+
+// WalletsIter iterates over the user's wallets, like OrdersIter.
+func (c *Client) WalletsIter(ctx context.Context, params t.GetWalletParams) iter.Seq2[t.Wallet, error] {
+	return listIter(func(opts t.ListOptions) (*t.Page[t.Wallet], error) {
+		return c.ListWallets(ctx, params, opts)
+	})
+}
+
+// listIter yields the items of consecutive pages returned by fetch.
+func listIter[T any](fetch func(opts t.ListOptions) (*t.Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		opts := listDefaults(t.ListOptions{})
+		for {
+			page, err := fetch(opts)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !page.HasMore || len(page.Items) == 0 {
+				return
+			}
+			opts = page.Next()
+		}
+	}
+}
+
+// listDefaults fills in the default page size.
+func listDefaults(opts t.ListOptions) t.ListOptions {
+	if opts.Limit <= 0 {
+		opts.Limit = t.DefaultListLimit
+	}
+	return opts
+}
+
+// listPage fetches the page of an authenticated list endpoint selected by opts.
+func listPage[T any](ctx context.Context, c *Client, op Operation, params interface{}, opts t.ListOptions) (*t.Page[T], error) {
+	var resp *t.PagedResponse[T]
+	var err error
+	if opts.Cursor != "" {
+		resp, err = followPage[T](ctx, c, opts.Cursor, true)
+	} else {
+		resp = new(t.PagedResponse[T])
+		err = c.call(ctx, "GET", op, nil, true, params, resp)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t.NewPage(resp, opts), nil
+}
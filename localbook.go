@@ -0,0 +1,174 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// LocalOrderBook maintains an order book from deltas. Unsequenced deltas, such
+// as those of a Stream, carry the whole book and replace it. Sequenced deltas
+// from other sources must continue the sequence of the previous one; when a
+// gap is detected, the book is rebuilt from a REST snapshot.
+type LocalOrderBook struct {
+	client *Client
+	symbol string
+
+	mu       sync.RWMutex
+	book     *t.OrderBook
+	sequence int64
+	synced   bool
+	resyncs  int
+}
+
+// NewLocalOrderBook creates an empty order book for symbol. A first sequenced
+// delta starting at sequence 1 is applied as is; any other first sequenced
+// delta triggers a resync from the REST API.
+func (c *Client) NewLocalOrderBook(symbol string) *LocalOrderBook {
+	return &LocalOrderBook{client: c, symbol: symbol, book: &t.OrderBook{}}
+}
+
+// Apply applies a delta to the book.
+//
+// Parameters:
+//   - ctx: Used for the REST request when a resync is needed.
+//   - delta: The next update of the book.
+//
+// Returns:
+//   - An error if the delta belongs to another market, is malformed, or a
+//     needed resync failed. After a failed resync the next delta triggers
+//     another one.
+//
+// Behavior:
+//   - An unsequenced delta replaces the book with its Snapshot, or is applied
+//     as is if it has none. The sequence number is not changed.
+//   - Sequenced deltas that end at or before the current sequence are
+//     ignored.
+//   - A delta starting at the next sequence number is applied.
+//   - A delta starting later means updates were missed. The book is replaced
+//     by a fresh snapshot from the REST API, and the sequence continues from
+//     the end of the delta, whose changes the snapshot already contains.
+//
+// Example:
+//
+//	book := client.NewLocalOrderBook("BTC_USDT")
+//	for delta := range depth.C {
+//	    if err := book.Apply(ctx, delta); err != nil {
+//	        log.Printf("order book: %v", err)
+//	        continue
+//	    }
+//	    bid, _ := book.Book().BestBid()
+//	    log.Printf("best bid: %f", bid.Price)
+//	}
+func (b *LocalOrderBook) Apply(ctx context.Context, delta *t.OrderBookDelta) error {
+	if delta.Symbol != "" && !u.SameSymbol(delta.Symbol, b.symbol) {
+		return &GoBitpinError{Message: fmt.Sprintf("delta for %s applied to the %s order book", delta.Symbol, b.symbol)}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !delta.Sequenced() {
+		book := b.book.Clone()
+		if delta.Snapshot != nil {
+			book = delta.Snapshot.Clone()
+		} else if err := book.ApplyDelta(delta); err != nil {
+			return &GoBitpinError{Message: "failed to apply order book delta", Err: err}
+		}
+		b.book, b.synced = book, true
+		return nil
+	}
+	if b.synced && delta.LastSequence <= b.sequence {
+		return nil
+	}
+	if (!b.synced && delta.FirstSequence != 1) || (b.synced && delta.FirstSequence > b.sequence+1) {
+		return b.resyncLocked(ctx, delta.LastSequence)
+	}
+
+	book := b.book.Clone()
+	if err := book.ApplyDelta(delta); err != nil {
+		return &GoBitpinError{Message: "failed to apply order book delta", Err: err}
+	}
+	b.book, b.sequence, b.synced = book, delta.LastSequence, true
+	return nil
+}
+
+// Resync replaces the book with a fresh snapshot from the REST API, keeping the
+// current sequence number.
+func (b *LocalOrderBook) Resync(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.resyncLocked(ctx, b.sequence)
+}
+
+func (b *LocalOrderBook) resyncLocked(ctx context.Context, sequence int64) error {
+	book, err := b.client.GetOrderBookWithContext(ctx, b.symbol)
+	if err != nil {
+		b.synced = false
+		return &GoBitpinError{Message: fmt.Sprintf("failed to resync the %s order book", b.symbol), Err: err}
+	}
+	b.book, b.sequence, b.synced = book, sequence, true
+	b.resyncs++
+	return nil
+}
+
+// Book returns a copy of the current book.
+func (b *LocalOrderBook) Book() *t.OrderBook {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.book.Clone()
+}
+
+// Sequence returns the sequence number of the last applied sequenced delta.
+// It stays zero for the unsequenced deltas of a Stream.
+func (b *LocalOrderBook) Sequence() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.sequence
+}
+
+// Resyncs returns how many times the book was rebuilt from a REST snapshot.
+func (b *LocalOrderBook) Resyncs() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.resyncs
+}
+
+// WatchOrderBook maintains a LocalOrderBook of a market from the deltas of a
+// stream.
+//
+// Returns:
+//   - The maintained book, which is empty until the first delta arrives.
+//   - A function that stops maintaining the book.
+//   - An error if the symbol is empty or the stream is closed.
+//
+// Behavior:
+//   - Deltas are applied on the handler pool of the stream, so a resync does
+//     not delay polling.
+//   - Errors from failed resyncs are passed to StreamOptions.OnError; the next
+//     delta triggers another resync.
+//
+// Example:
+//
+//	book, stop, err := stream.WatchOrderBook(ctx, "BTC_USDT")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer stop()
+//	spread, _ := book.Book().Spread()
+func (s *Stream) WatchOrderBook(ctx context.Context, symbol string) (*LocalOrderBook, func(), error) {
+	book := s.client.NewLocalOrderBook(symbol)
+	channel := StreamChannel{Kind: StreamDepth, Symbol: symbol}
+	stop, err := s.onEvent(channel, func(event StreamEvent) {
+		if err := book.Apply(ctx, event.Depth); err != nil && s.opts.OnError != nil {
+			s.opts.OnError(channel, err)
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return book, stop, nil
+}
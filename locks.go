@@ -0,0 +1,161 @@
+package bitpin
+
+import (
+	"context"
+	"sync"
+
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// maxRememberedOrders bounds the number of order symbols a client records.
+// Orders leave the record when they are cancelled or seen in a terminal
+// state; beyond the bound, arbitrary records are dropped and their orders
+// are looked up again when needed.
+const maxRememberedOrders = 10000
+
+// fifoMutex is a mutual exclusion lock that grants the lock to waiters in the
+// order in which they asked for it.
+type fifoMutex struct {
+	mu      sync.Mutex
+	locked  bool
+	waiters []chan struct{}
+}
+
+// lock acquires the mutex, waiting behind earlier callers. It returns the
+// context error if the context is done before the lock is granted.
+func (m *fifoMutex) lock(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.locked {
+		m.locked = true
+		m.mu.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	m.waiters = append(m.waiters, ch)
+	m.mu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		for i, w := range m.waiters {
+			if w == ch {
+				m.waiters = append(m.waiters[:i], m.waiters[i+1:]...)
+				m.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		m.mu.Unlock()
+		// The lock was handed to us while the context was cancelled.
+		m.unlock()
+		return ctx.Err()
+	}
+}
+
+// unlock releases the mutex, handing it to the longest waiting caller.
+func (m *fifoMutex) unlock() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.waiters) > 0 {
+		next := m.waiters[0]
+		m.waiters = m.waiters[1:]
+		close(next)
+		return
+	}
+	m.locked = false
+}
+
+// symbolLocks serializes order operations per market symbol. Operations on
+// the same symbol run one at a time in arrival order, while operations on
+// different symbols run concurrently. Symbols are compared in canonical form,
+// so "btc-usdt" and "BTC_USDT" share a lock.
+type symbolLocks struct {
+	mu    sync.Mutex
+	locks map[string]*fifoMutex
+
+	// orders maps order IDs to their symbol so that operations that only know
+	// the order ID, such as cancellation, can take the right lock.
+	orders map[int]string
+}
+
+// lock acquires the lock of a symbol and returns a function that releases it.
+// A nil symbolLocks grants every lock at once.
+func (l *symbolLocks) lock(ctx context.Context, symbol string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	symbol = u.CanonicalSymbol(symbol)
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*fifoMutex)
+	}
+	m, ok := l.locks[symbol]
+	if !ok {
+		m = &fifoMutex{}
+		l.locks[symbol] = m
+	}
+	l.mu.Unlock()
+
+	if err := m.lock(ctx); err != nil {
+		return nil, err
+	}
+	return m.unlock, nil
+}
+
+// remember records the symbol of an order, dropping an arbitrary record when
+// maxRememberedOrders is reached.
+func (l *symbolLocks) remember(orderId int, symbol string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.orders == nil {
+		l.orders = make(map[int]string)
+	}
+	if _, ok := l.orders[orderId]; !ok && len(l.orders) >= maxRememberedOrders {
+		for id := range l.orders {
+			delete(l.orders, id)
+			break
+		}
+	}
+	l.orders[orderId] = u.CanonicalSymbol(symbol)
+}
+
+// forget removes the symbol record of an order.
+func (l *symbolLocks) forget(orderId int) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.orders, orderId)
+}
+
+// symbolOf returns the recorded symbol of an order, and whether there is one.
+func (l *symbolLocks) symbolOf(orderId int) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	symbol, ok := l.orders[orderId]
+	return symbol, ok
+}
+
+// orderSymbol returns the symbol whose lock guards operations on an order.
+// Orders that were not created or fetched through this client, or whose
+// record was dropped, are fetched first. If that fails, the empty symbol is
+// returned, which serializes such orders with each other but not with the
+// operations on their market.
+func (c *Client) orderSymbol(ctx context.Context, orderId int) string {
+	if symbol, ok := c.orderLocks.symbolOf(orderId); ok || c.orderLocks == nil {
+		return symbol
+	}
+	order, err := c.GetOrderWithContext(ctx, orderId)
+	if err != nil {
+		return ""
+	}
+	return order.Symbol
+}
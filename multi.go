@@ -0,0 +1,155 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// DefaultFetchConcurrency is the number of parallel requests made by
+// multi-symbol helpers when FetchConcurrency is not set.
+const DefaultFetchConcurrency = 8
+
+// MultiSymbolError is returned by multi-symbol helpers when requests for some
+// symbols failed. The results of the other symbols are still returned.
+type MultiSymbolError struct {
+	GoBitpinError
+	Errors map[string]error // error per failed symbol
+}
+
+// Unwrap returns the errors of all failed symbols, so errors.Is and errors.As
+// match any of them.
+func (e *MultiSymbolError) Unwrap() []error {
+	symbols := make([]string, 0, len(e.Errors))
+	for symbol := range e.Errors {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	errs := make([]error, len(symbols))
+	for i, symbol := range symbols {
+		errs[i] = e.Errors[symbol]
+	}
+	return errs
+}
+
+// GetOrderBooks fetches the order books of several markets in parallel, with at
+// most FetchConcurrency requests in flight.
+//
+// Parameters:
+//   - ctx: Cancels all outstanding requests.
+//   - symbols: The market symbols, such as "BTC_USDT". Duplicates are fetched
+//     once.
+//
+// Returns:
+//   - A map from symbol to order book, holding every symbol that was fetched
+//     successfully.
+//   - A `*MultiSymbolError` holding the error of each failed symbol, or nil if
+//     all requests succeeded. The map is returned in both cases.
+//
+// Example:
+//
+//	books, err := client.GetOrderBooks(ctx, []string{"BTC_USDT", "ETH_USDT", "USDT_IRT"})
+//	var multiErr *bitpin.MultiSymbolError
+//	if errors.As(err, &multiErr) {
+//	    for symbol, err := range multiErr.Errors {
+//	        log.Printf("%s: %v", symbol, err)
+//	    }
+//	}
+//	for symbol, book := range books {
+//	    spread, _ := book.Spread()
+//	    fmt.Printf("%s spread: %f\n", symbol, spread)
+//	}
+func (c *Client) GetOrderBooks(ctx context.Context, symbols []string) (map[string]*t.OrderBook, error) {
+	return fetchEach(ctx, c, symbols, c.GetOrderBookWithContext)
+}
+
+// GetRecentTradesMulti fetches the recent trades of several markets in
+// parallel, with at most FetchConcurrency requests in flight. Results and
+// errors are reported per symbol as in `GetOrderBooks`.
+//
+// Example:
+//
+//	trades, err := client.GetRecentTradesMulti(ctx, []string{"BTC_USDT", "ETH_USDT"})
+//	if err != nil {
+//	    log.Printf("some markets failed: %v", err)
+//	}
+//	for symbol, list := range trades {
+//	    fmt.Printf("%s: %d trades\n", symbol, len(list))
+//	}
+func (c *Client) GetRecentTradesMulti(ctx context.Context, symbols []string) (map[string][]*t.Trade, error) {
+	return fetchEach(ctx, c, symbols, func(ctx context.Context, symbol string) ([]*t.Trade, error) {
+		trades, err := c.GetRecentTradesWithContext(ctx, symbol)
+		if err != nil || trades == nil {
+			return nil, err
+		}
+		return *trades, nil
+	})
+}
+
+// fetchEach calls fetch for every distinct symbol with bounded concurrency and
+// collects the results and errors per symbol.
+func fetchEach[T any](ctx context.Context, c *Client, symbols []string, fetch func(context.Context, string) (T, error)) (map[string]T, error) {
+	limit := c.FetchConcurrency
+	if limit <= 0 {
+		limit = DefaultFetchConcurrency
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]T, len(symbols))
+		errs    = make(map[string]error)
+		seen    = make(map[string]bool, len(symbols))
+		slots   = make(chan struct{}, limit)
+	)
+	for _, symbol := range symbols {
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			errs[symbol] = ctx.Err()
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result, err := fetch(ctx, symbol)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[symbol] = err
+				return
+			}
+			results[symbol] = result
+		}(symbol)
+	}
+	wg.Wait()
+
+	if len(errs) == 0 {
+		return results, nil
+	}
+	failed := make([]string, 0, len(errs))
+	for symbol := range errs {
+		failed = append(failed, symbol)
+	}
+	sort.Strings(failed)
+	return results, &MultiSymbolError{
+		GoBitpinError: GoBitpinError{
+			Message: fmt.Sprintf("%d of %d symbols failed: %s", len(errs), len(seen), strings.Join(failed, ", ")),
+		},
+		Errors: errs,
+	}
+}
@@ -0,0 +1,187 @@
+package bitpin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MultipartForm is a multipart/form-data request body made of form fields and
+// files, for endpoints that take uploads such as documents or bulk imports.
+// Files are streamed from their readers while the request is sent, so large
+// files are not buffered in memory.
+//
+// Pass it to Upload, or as the body of ApiRequestWithContext with a method
+// other than GET.
+type MultipartForm struct {
+	// Fields are the form fields, sent before the files in key order.
+	Fields url.Values
+
+	// Files are the files, sent in order.
+	Files []FormFile
+
+	// starts are the offsets the files were read from, or -1 for files that
+	// cannot be rewound. They are recorded the first time the form is sent.
+	starts []int64
+}
+
+// FormFile is a file of a MultipartForm.
+type FormFile struct {
+	// Field is the name of the form field.
+	Field string
+
+	// FileName is the file name sent with the file.
+	FileName string
+
+	// ContentType is the media type of the file. Defaults to
+	// "application/octet-stream".
+	ContentType string
+
+	// Reader supplies the content of the file. A reader that is also an
+	// io.Seeker, such as an *os.File, is rewound when the request has to be
+	// sent again, after a token renewal or a failover. Other readers can only
+	// be sent once.
+	Reader io.Reader
+}
+
+// quoteEscaper escapes the values of Content-Disposition parameters, like
+// mime/multipart does.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// reader returns the encoded form and its content type. The files are read
+// from the returned reader as it is consumed.
+func (f *MultipartForm) reader() (io.Reader, string, error) {
+	if f.starts == nil {
+		f.starts = make([]int64, len(f.Files))
+		for i, file := range f.Files {
+			f.starts[i] = -1
+			if seeker, ok := file.Reader.(io.Seeker); ok {
+				if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+					f.starts[i] = offset
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	keys := make([]string, 0, len(f.Fields))
+	for key := range f.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range f.Fields[key] {
+			if err := writer.WriteField(key, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	// The part headers are buffered; the file contents are read in between.
+	var parts []io.Reader
+	for _, file := range f.Files {
+		if file.Reader == nil {
+			return nil, "", fmt.Errorf("file %q has no reader", file.Field)
+		}
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(file.Field), quoteEscaper.Replace(file.FileName)))
+		header.Set("Content-Type", contentType)
+		if _, err := writer.CreatePart(header); err != nil {
+			return nil, "", err
+		}
+		parts = append(parts, bytes.NewReader(bytes.Clone(buf.Bytes())), file.Reader)
+		buf.Reset()
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	parts = append(parts, &buf)
+	return io.MultiReader(parts...), writer.FormDataContentType(), nil
+}
+
+// rewindable reports whether every file can be rewound. It is only known
+// once the form has been sent.
+func (f *MultipartForm) rewindable() bool {
+	for _, start := range f.starts {
+		if start < 0 {
+			return false
+		}
+	}
+	return f.starts != nil
+}
+
+// rewind moves every file back to where it was first read from, so the form
+// can be sent again. It reports false if a file cannot be rewound.
+func (f *MultipartForm) rewind() bool {
+	if !f.rewindable() {
+		return false
+	}
+	for i, file := range f.Files {
+		seeker := file.Reader.(io.Seeker)
+		if _, err := seeker.Seek(f.starts[i], io.SeekStart); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Upload sends a multipart/form-data POST request to an endpoint of the API,
+// streaming the files of form, and unmarshals the response into result.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - endpoint: The API endpoint (path) relative to the base URL.
+//   - version: The API version to use. If empty, the default version is used.
+//   - auth: Whether the request requires authentication.
+//   - form: The fields and files to send.
+//   - result: A pointer to a variable where the response body should be
+//     unmarshaled. If nil, the response body is not unmarshaled.
+//
+// Returns:
+//   - An error if a file cannot be read, the request fails or the API answers
+//     with a non-2xx status, in which case it is an `APIError`.
+//
+// Behavior:
+//   - The body is encoded while it is sent, so memory use does not grow with
+//     the size of the files. It is sent with chunked transfer encoding.
+//   - When the request has to be sent again, after a token renewal on 401
+//     Unauthorized or a failover to a mirror, files that implement io.Seeker
+//     are rewound. If a file cannot be rewound, the request is not sent again
+//     and the first error is returned; the renewed tokens are still kept.
+//   - Other methods, such as PUT, can send a form by passing it as the body
+//     of ApiRequestWithContext.
+//
+// Example:
+//
+//	file, err := os.Open("statement.pdf")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer file.Close()
+//
+//	var resp map[string]interface{}
+//	err = client.Upload(ctx, "/usr/documents/", bitpin.Version, true, &bitpin.MultipartForm{
+//	    Fields: url.Values{"kind": {"statement"}},
+//	    Files:  []bitpin.FormFile{{Field: "file", FileName: "statement.pdf", ContentType: "application/pdf", Reader: file}},
+//	}, &resp)
+func (c *Client) Upload(ctx context.Context, endpoint, version string, auth bool, form *MultipartForm, result interface{}) error {
+	return c.ApiRequestWithContext(ctx, "POST", endpoint, version, auth, form, result)
+}
+
+// replayable reports whether a request body can be sent again.
+func replayable(body interface{}) bool {
+	form, ok := body.(*MultipartForm)
+	return !ok || form.rewind()
+}
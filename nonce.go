@@ -0,0 +1,154 @@
+package bitpin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rzabhd80/go-sdk-bitpin/store"
+)
+
+// NonceOptions configures a NonceGenerator.
+type NonceOptions struct {
+	// BlockSize is the number of nonces reserved from the store at once.
+	// Larger blocks need fewer store writes, but nonces are then only
+	// increasing within a process: two processes sharing a store hand out
+	// numbers from different blocks concurrently. A block size of 1 makes
+	// nonces strictly increasing across all processes. Defaults to 1.
+	BlockSize uint64
+
+	// Offset is added to every nonce, e.g. to start above identifiers issued
+	// before the generator was introduced.
+	Offset uint64
+
+	// Prefix is prepended to the identifiers returned by NextIdentifier.
+	Prefix string
+}
+
+// NonceGenerator hands out unique, monotonically increasing numbers that
+// survive restarts. Blocks of numbers are reserved by incrementing a counter
+// of a store.Store that implements store.Counter, which all stores of the
+// store package do, so the store holds a single number per generator. Other
+// stores fall back to appending one record per block to a log, whose sequence
+// numbers are assigned atomically by the backend; that log is never compacted.
+//
+// With a shared SQLite database or Redis server, several processes can use
+// generators with the same name without ever receiving the same number. The
+// memory and file stores are only safe within a single process.
+type NonceGenerator struct {
+	store store.Store
+	key   string // key of the counter and name of the log
+	opts  NonceOptions
+
+	mu     sync.Mutex
+	next   uint64 // next nonce to hand out
+	end    uint64 // end of the reserved block, exclusive
+	legacy uint64 // blocks reserved in the log before the counter was used
+	primed bool   // whether legacy was read
+}
+
+// nonceReservation is the record appended to the nonce log for every block
+// by stores without a counter.
+type nonceReservation struct {
+	Host string    `json:"host,omitempty"`
+	Pid  int       `json:"pid"`
+	Time time.Time `json:"time"`
+}
+
+// NewNonceGenerator creates a generator persisted in st under the given name.
+// Generators with the same name and store share one sequence.
+//
+// Example:
+//
+//	st, _ := store.NewFile("/var/lib/mybot")
+//	nonces := bitpin.NewNonceGenerator(st, "orders", bitpin.NonceOptions{Prefix: "bot-"})
+//	id, err := nonces.NextIdentifier(ctx) // "bot-1", "bot-2", ... across restarts
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	order, err := client.CreateOrder(t.CreateOrderParams{
+//	    Symbol: "BTC_USDT", Type: "limit", Side: "buy",
+//	    BaseAmount: "0.001", Price: "60000", Identifier: id,
+//	})
+func NewNonceGenerator(st store.Store, name string, opts NonceOptions) *NonceGenerator {
+	if opts.BlockSize == 0 {
+		opts.BlockSize = 1
+	}
+	return &NonceGenerator{store: st, key: "nonce/" + name, opts: opts}
+}
+
+// Next returns the next nonce. The first nonce of a new generator is
+// Offset + 1.
+func (g *NonceGenerator) Next(ctx context.Context) (uint64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.next == g.end {
+		if err := g.reserve(ctx); err != nil {
+			return 0, err
+		}
+	}
+	n := g.next
+	g.next++
+	return n, nil
+}
+
+// NextIdentifier returns the next nonce formatted as an order identifier, with
+// the configured prefix.
+func (g *NonceGenerator) NextIdentifier(ctx context.Context) (string, error) {
+	n, err := g.Next(ctx)
+	if err != nil {
+		return "", err
+	}
+	return g.opts.Prefix + strconv.FormatUint(n, 10), nil
+}
+
+// reserve claims the next block of nonces from the store.
+func (g *NonceGenerator) reserve(ctx context.Context) error {
+	seq, err := g.reserveBlock(ctx)
+	if err != nil {
+		return &GoBitpinError{
+			Message: fmt.Sprintf("failed to reserve nonces from %q", g.key),
+			Err:     err,
+		}
+	}
+
+	g.next = g.opts.Offset + (seq-1)*g.opts.BlockSize + 1
+	g.end = g.next + g.opts.BlockSize
+	return nil
+}
+
+// reserveBlock returns the number of the next block, starting at 1.
+func (g *NonceGenerator) reserveBlock(ctx context.Context) (uint64, error) {
+	counter, ok := g.store.(store.Counter)
+	if !ok {
+		host, _ := os.Hostname()
+		record, err := json.Marshal(nonceReservation{Host: host, Pid: os.Getpid(), Time: time.Now()})
+		if err != nil {
+			return 0, err
+		}
+		return g.store.Append(ctx, g.key, record)
+	}
+
+	// Blocks reserved in the log by earlier versions come first. The log is
+	// no longer appended to, so every process reads the same count.
+	if !g.primed {
+		err := g.store.Read(ctx, g.key, 0, func(seq uint64, _ []byte) error {
+			g.legacy = seq
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		g.primed = true
+	}
+	n, err := counter.Increment(ctx, g.key, 1)
+	if err != nil {
+		return 0, err
+	}
+	return g.legacy + n, nil
+}
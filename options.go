@@ -0,0 +1,565 @@
+package bitpin
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures a client created with NewClientWithOptions. New settings
+// are added as new options, so existing code keeps compiling.
+type Option func(opts *ClientOptions) error
+
+// optionFlag identifies a boolean setting of ClientOptions.
+type optionFlag uint16
+
+const (
+	flagAutoRefresh optionFlag = 1 << iota
+	flagDisableCompression
+	flagDisableConditionalRequests
+	flagDetectDrift
+	flagTranslateErrors
+	flagDebug
+	flagTraceRequests
+	flagUseAPIv2
+
+	// allOptionFlags marks every boolean setting as given.
+	allOptionFlags = flagUseAPIv2<<1 - 1
+)
+
+// setFlag sets a boolean setting and records that it was given.
+func (opts *ClientOptions) setFlag(flag optionFlag, field *bool, value bool) {
+	*field = value
+	opts.explicit |= flag
+}
+
+// NewClientWithOptions creates a client from functional options. It is
+// equivalent to NewClient, but validates the combination of options first, so
+// mistakes such as an API key without a secret key, or transport tuning that
+// would be ignored because a custom HTTP client is given, are reported instead
+// of silently accepted.
+//
+// Example:
+//
+//	client, err := bitpin.NewClientWithOptions(
+//	    bitpin.WithAPIKey("your-api-key", "your-secret-key"),
+//	    bitpin.WithTimeout(10*time.Second),
+//	    bitpin.WithAutoRefresh(),
+//	    bitpin.WithMetadataTTL(10*time.Minute),
+//	)
+//	if err != nil {
+//	    log.Fatalf("Failed to create client: %v", err)
+//	}
+func NewClientWithOptions(options ...Option) (*Client, error) {
+	var opts ClientOptions
+	for _, option := range options {
+		if err := option(&opts); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateClientOptions(&opts); err != nil {
+		return nil, err
+	}
+	return NewClient(opts)
+}
+
+// validateClientOptions reports invalid combinations of options.
+func validateClientOptions(opts *ClientOptions) error {
+	invalid := func(format string, args ...interface{}) error {
+		return &GoBitpinError{Message: "invalid client options: " + fmt.Sprintf(format, args...)}
+	}
+
+	if (opts.ApiKey == "") != (opts.SecretKey == "") {
+		return invalid("the API key and secret key must be given together")
+	}
+	if opts.Credentials != nil && opts.ApiKey != "" {
+		return invalid("give either an API key and secret key or a credential provider")
+	}
+	if opts.AutoAuth && opts.ApiKey == "" && opts.Credentials == nil {
+		return invalid("automatic authentication requires an API key and secret key")
+	}
+	if opts.HttpClient != nil {
+		if opts.Timeout != 0 {
+			return invalid("the timeout is ignored with a custom HTTP client, set it on the HTTP client instead")
+		}
+		if opts.Transport != nil || tunesTransport(*opts) {
+			return invalid("transport settings are ignored with a custom HTTP client")
+		}
+	}
+	if opts.Transport != nil && tunesTransport(*opts) {
+		return invalid("connection pool, TLS and dialing settings are ignored with a custom transport")
+	}
+	if opts.DialContext != nil && opts.Resolver != nil {
+		return invalid("the resolver is ignored with a custom dial function")
+	}
+	for host, ip := range opts.PinnedHosts {
+		if net.ParseIP(ip) == nil {
+			return invalid("pinned host %q has no valid IP address", host)
+		}
+	}
+	if opts.BaseUrl != "" {
+		u, err := url.Parse(opts.BaseUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return invalid("base URL %q is not an absolute http or https URL", opts.BaseUrl)
+		}
+	}
+	if opts.Failover != nil {
+		for _, mirror := range opts.Failover.URLs {
+			u, err := url.Parse(mirror)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return invalid("failover URL %q is not an absolute http or https URL", mirror)
+			}
+		}
+	}
+	if opts.disableAuth && (opts.ApiKey != "" || opts.Credentials != nil || opts.AccessToken != "" || opts.AutoAuth) {
+		return invalid("credentials are given, but authentication is disabled")
+	}
+	if opts.OnDrift != nil && !opts.DetectDrift {
+		return invalid("a drift handler requires drift detection")
+	}
+	if opts.ErrorTranslations != nil && !opts.TranslateErrors {
+		return invalid("error translations require TranslateErrors")
+	}
+	if opts.BalanceCheck != nil && !validFeeRate(opts.BalanceCheck.FeeRate) {
+		return invalid("balance check fee rate must be in [0, 1)")
+	}
+	if opts.Retry != nil && opts.Retry.MaxAttempts < 0 {
+		return invalid("retry attempts must not be negative")
+	}
+	return nil
+}
+
+// validFeeRate reports whether rate is a fee fraction in [0, 1). NaN is not.
+func validFeeRate(rate float64) bool {
+	return rate >= 0 && rate < 1
+}
+
+// nonNegative returns an error if d is negative.
+func nonNegative(name string, d time.Duration) error {
+	if d < 0 {
+		return &GoBitpinError{Message: fmt.Sprintf("invalid client options: %s must not be negative", name)}
+	}
+	return nil
+}
+
+// WithClientOptions starts from a ClientOptions struct, for example one loaded
+// with LoadClientOptions or ClientOptionsFromEnv. Options after it override
+// its fields.
+func WithClientOptions(base ClientOptions) Option {
+	return func(opts *ClientOptions) error {
+		*opts = base
+		opts.explicit = allOptionFlags
+		return nil
+	}
+}
+
+// WithAPIKey sets the API credentials used to authenticate.
+func WithAPIKey(apiKey, secretKey string) Option {
+	return func(opts *ClientOptions) error {
+		opts.ApiKey, opts.SecretKey = apiKey, secretKey
+		return nil
+	}
+}
+
+// WithCredentialProvider sets the provider the API credentials are fetched
+// from whenever the client authenticates.
+func WithCredentialProvider(provider CredentialProvider) Option {
+	return func(opts *ClientOptions) error {
+		opts.Credentials = provider
+		return nil
+	}
+}
+
+// WithTokens sets existing access and refresh tokens.
+func WithTokens(accessToken, refreshToken string) Option {
+	return func(opts *ClientOptions) error {
+		opts.AccessToken, opts.RefreshToken = accessToken, refreshToken
+		return nil
+	}
+}
+
+// WithoutAuth disables authentication: authenticated requests fail with
+// ErrAuthDisabled without being sent. Useful for clients that only read public
+// market data.
+func WithoutAuth() Option {
+	return func(opts *ClientOptions) error {
+		opts.disableAuth = true
+		return nil
+	}
+}
+
+// WithTokenStorage persists tokens between runs.
+func WithTokenStorage(storage TokenStorage) Option {
+	return func(opts *ClientOptions) error {
+		opts.TokenStorage = storage
+		return nil
+	}
+}
+
+// WithAutoAuth authenticates automatically when no valid tokens are available.
+func WithAutoAuth() Option {
+	return func(opts *ClientOptions) error {
+		opts.AutoAuth = true
+		return nil
+	}
+}
+
+// WithAutoRefresh refreshes the access token automatically when it expires.
+func WithAutoRefresh() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagAutoRefresh, &opts.AutoRefresh, true)
+		return nil
+	}
+}
+
+// WithoutAutoRefresh turns AutoRefresh off, e.g. for a client derived with
+// WithOverrides.
+func WithoutAutoRefresh() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagAutoRefresh, &opts.AutoRefresh, false)
+		return nil
+	}
+}
+
+// WithBaseURL overrides the API base URL.
+func WithBaseURL(baseUrl string) Option {
+	return func(opts *ClientOptions) error {
+		opts.BaseUrl = baseUrl
+		return nil
+	}
+}
+
+// WithTimeout sets the request timeout of the default HTTP client.
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *ClientOptions) error {
+		opts.Timeout = timeout
+		return nonNegative("timeout", timeout)
+	}
+}
+
+// WithHTTPClient uses a custom HTTP client for all requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *ClientOptions) error {
+		opts.HttpClient = client
+		return nil
+	}
+}
+
+// WithTransport uses a custom transport for the default HTTP client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(opts *ClientOptions) error {
+		opts.Transport = transport
+		return nil
+	}
+}
+
+// WithConnectionPool tunes the keep-alive connection pool of the default
+// transport.
+func WithConnectionPool(maxIdleConnsPerHost int, idleConnTimeout time.Duration) Option {
+	return func(opts *ClientOptions) error {
+		if maxIdleConnsPerHost < 0 {
+			return &GoBitpinError{Message: "invalid client options: maximum idle connections must not be negative"}
+		}
+		opts.MaxIdleConnsPerHost, opts.IdleConnTimeout = maxIdleConnsPerHost, idleConnTimeout
+		return nonNegative("idle connection timeout", idleConnTimeout)
+	}
+}
+
+// WithTLSHandshakeTimeout limits the time spent on TLS handshakes.
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(opts *ClientOptions) error {
+		opts.TLSHandshakeTimeout = timeout
+		return nonNegative("TLS handshake timeout", timeout)
+	}
+}
+
+// WithTLS configures TLS of the default transport, such as a corporate CA
+// bundle, a minimum TLS version or pinned keys.
+func WithTLS(tlsOpts TLSOptions) Option {
+	return func(opts *ClientOptions) error {
+		opts.TLS = &tlsOpts
+		return nil
+	}
+}
+
+// WithDialContext dials the connections of the default transport with dial.
+func WithDialContext(dial DialFunc) Option {
+	return func(opts *ClientOptions) error {
+		opts.DialContext = dial
+		return nil
+	}
+}
+
+// WithResolver resolves host names of the default transport with resolver.
+func WithResolver(resolver *net.Resolver) Option {
+	return func(opts *ClientOptions) error {
+		opts.Resolver = resolver
+		return nil
+	}
+}
+
+// WithPinnedHosts dials the given host names at fixed IP addresses.
+func WithPinnedHosts(hosts map[string]string) Option {
+	return func(opts *ClientOptions) error {
+		opts.PinnedHosts = hosts
+		return nil
+	}
+}
+
+// WithoutCompression stops the client from requesting gzip-compressed
+// responses.
+func WithoutCompression() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagDisableCompression, &opts.DisableCompression, true)
+		return nil
+	}
+}
+
+// WithCompression requests gzip-compressed responses again, undoing
+// WithoutCompression of the parent of a client derived with WithOverrides.
+func WithCompression() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagDisableCompression, &opts.DisableCompression, false)
+		return nil
+	}
+}
+
+// WithMetadataTTL caches markets and currencies for the given duration.
+func WithMetadataTTL(ttl time.Duration) Option {
+	return func(opts *ClientOptions) error {
+		opts.MetadataTTL = ttl
+		return nonNegative("metadata TTL", ttl)
+	}
+}
+
+// WithoutConditionalRequests stops the client from revalidating metadata with
+// ETag and Last-Modified validators.
+func WithoutConditionalRequests() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagDisableConditionalRequests, &opts.DisableConditionalRequests, true)
+		return nil
+	}
+}
+
+// WithConditionalRequests revalidates metadata again, undoing
+// WithoutConditionalRequests of the parent of a client derived with
+// WithOverrides.
+func WithConditionalRequests() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagDisableConditionalRequests, &opts.DisableConditionalRequests, false)
+		return nil
+	}
+}
+
+// WithFetchConcurrency limits the parallel requests of multi-symbol helpers.
+func WithFetchConcurrency(n int) Option {
+	return func(opts *ClientOptions) error {
+		if n < 1 {
+			return &GoBitpinError{Message: "invalid client options: fetch concurrency must be at least 1"}
+		}
+		opts.FetchConcurrency = n
+		return nil
+	}
+}
+
+// WithCircuitBreaker enables the circuit breaker.
+func WithCircuitBreaker(breaker CircuitBreakerOptions) Option {
+	return func(opts *ClientOptions) error {
+		opts.CircuitBreaker = &breaker
+		return nonNegative("circuit breaker open timeout", breaker.OpenTimeout)
+	}
+}
+
+// WithFailover enables failover to mirrors of the base URL.
+func WithFailover(failover FailoverOptions) Option {
+	return func(opts *ClientOptions) error {
+		opts.Failover = &failover
+		return nonNegative("failover recovery delay", failover.RecoverAfter)
+	}
+}
+
+// WithBalanceCheck enables the pre-order balance check.
+func WithBalanceCheck(check BalanceCheckOptions) Option {
+	return func(opts *ClientOptions) error {
+		if !validFeeRate(check.FeeRate) {
+			return &GoBitpinError{Message: "invalid client options: balance check fee rate must be in [0, 1)"}
+		}
+		opts.BalanceCheck = &check
+		return nonNegative("balance check wallet TTL", check.WalletTTL)
+	}
+}
+
+// WithOrderThrottle enables the per-market order throttle.
+func WithOrderThrottle(throttle OrderThrottleOptions) Option {
+	return func(opts *ClientOptions) error {
+		if throttle.MaxOpenOrders < 0 {
+			return &GoBitpinError{Message: "invalid client options: max open orders must not be negative"}
+		}
+		opts.OrderThrottle = &throttle
+		return nonNegative("order throttle interval", throttle.MinInterval)
+	}
+}
+
+// WithRetry retries GET requests that fail with a retryable error.
+func WithRetry(retry RetryOptions) Option {
+	return func(opts *ClientOptions) error {
+		if retry.MaxAttempts < 0 {
+			return &GoBitpinError{Message: "invalid client options: retry attempts must not be negative"}
+		}
+		opts.Retry = &retry
+		return nil
+	}
+}
+
+// WithEgressIPCheck enables the egress IP check of NewClient.
+func WithEgressIPCheck(check EgressIPCheckOptions) Option {
+	return func(opts *ClientOptions) error {
+		opts.EgressIPCheck = &check
+		return nil
+	}
+}
+
+// WithDriftDetection enables the API drift detector. The handler is optional
+// and may be nil.
+func WithDriftDetection(onDrift func(finding DriftFinding)) Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagDetectDrift, &opts.DetectDrift, true)
+		opts.OnDrift = onDrift
+		return nil
+	}
+}
+
+// WithoutDriftDetection turns the API drift detector off.
+func WithoutDriftDetection() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagDetectDrift, &opts.DetectDrift, false)
+		opts.OnDrift = nil
+		return nil
+	}
+}
+
+// WithErrorTranslation replaces Persian messages in API errors with English
+// ones. The optional translations, keyed by a phrase of the Persian message,
+// take precedence over the built-in table.
+func WithErrorTranslation(translations map[string]string) Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagTranslateErrors, &opts.TranslateErrors, true)
+		opts.ErrorTranslations = translations
+		return nil
+	}
+}
+
+// WithoutErrorTranslation keeps the Persian messages of API errors.
+func WithoutErrorTranslation() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagTranslateErrors, &opts.TranslateErrors, false)
+		opts.ErrorTranslations = nil
+		return nil
+	}
+}
+
+// WithDeprecationHandler is invoked every time a deprecated method is called.
+func WithDeprecationHandler(handler func(notice DeprecationNotice)) Option {
+	return func(opts *ClientOptions) error {
+		opts.OnDeprecatedCall = handler
+		return nil
+	}
+}
+
+// WithTokenRefreshHook sets the hook invoked whenever the client obtains new
+// tokens.
+func WithTokenRefreshHook(hook func(event TokenRefreshEvent)) Option {
+	return func(opts *ClientOptions) error {
+		opts.OnTokenRefresh = hook
+		return nil
+	}
+}
+
+// WithRequestHook sets the hook invoked after every request sent to the API.
+func WithRequestHook(hook func(event RequestEvent)) Option {
+	return func(opts *ClientOptions) error {
+		opts.OnRequest = hook
+		return nil
+	}
+}
+
+// WithDebug enables the sanitized request and response dumps of Debug. The
+// logger is optional and may be nil.
+func WithDebug(logger *slog.Logger) Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagDebug, &opts.Debug, true)
+		opts.Logger = logger
+		return nil
+	}
+}
+
+// WithoutDebug turns the request and response dumps of Debug off.
+func WithoutDebug() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagDebug, &opts.Debug, false)
+		return nil
+	}
+}
+
+// WithRequestTracing enables TraceRequests. The trace is optional and may be
+// nil; when given, its hooks are attached to every request.
+func WithRequestTracing(trace *httptrace.ClientTrace) Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagTraceRequests, &opts.TraceRequests, true)
+		opts.ClientTrace = trace
+		return nil
+	}
+}
+
+// WithoutRequestTracing turns TraceRequests off and drops the ClientTrace.
+func WithoutRequestTracing() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagTraceRequests, &opts.TraceRequests, false)
+		opts.ClientTrace = nil
+		return nil
+	}
+}
+
+// WithClock sets the Clock of the client.
+func WithClock(clock Clock) Option {
+	return func(opts *ClientOptions) error {
+		opts.Clock = clock
+		return nil
+	}
+}
+
+// WithAPIv2 enables UseAPIv2, sending the operations listed by V2Operations to
+// the v2 API.
+func WithAPIv2() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagUseAPIv2, &opts.UseAPIv2, true)
+		return nil
+	}
+}
+
+// WithoutAPIv2 turns UseAPIv2 off, sending every operation to the v1 API.
+func WithoutAPIv2() Option {
+	return func(opts *ClientOptions) error {
+		opts.setFlag(flagUseAPIv2, &opts.UseAPIv2, false)
+		return nil
+	}
+}
+
+// WithEndpoint overrides the path and version of an operation.
+func WithEndpoint(op Operation, endpoint Endpoint) Option {
+	return func(opts *ClientOptions) error {
+		if !strings.HasPrefix(endpoint.Path, "/") {
+			return &GoBitpinError{Message: fmt.Sprintf("invalid client options: path of %s must start with a slash", op)}
+		}
+		if opts.Endpoints == nil {
+			opts.Endpoints = make(map[Operation]Endpoint)
+		}
+		opts.Endpoints[op] = endpoint
+		return nil
+	}
+}
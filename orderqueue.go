@@ -0,0 +1,288 @@
+package bitpin
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// Order queue defaults.
+const (
+	// DefaultOrderQueueAccountInterval is the default minimum delay between two
+	// order submissions of the account.
+	DefaultOrderQueueAccountInterval = 100 * time.Millisecond
+
+	// DefaultOrderQueueSymbolInterval is the default minimum delay between two
+	// order submissions for the same symbol.
+	DefaultOrderQueueSymbolInterval = 250 * time.Millisecond
+
+	// DefaultOrderQueueBuffer is the default number of orders that can wait in
+	// an OrderQueue.
+	DefaultOrderQueueBuffer = 1000
+)
+
+var (
+	// ErrOrderQueueClosed is returned when submitting to a closed OrderQueue.
+	ErrOrderQueueClosed = &GoBitpinError{Message: "order queue is closed"}
+
+	// ErrOrderQueueFull is returned when an OrderQueue holds Buffer orders
+	// that were not submitted yet.
+	ErrOrderQueueFull = &GoBitpinError{Message: "order queue is full"}
+)
+
+// OrderQueueOptions configures an OrderQueue.
+type OrderQueueOptions struct {
+	// AccountInterval is the minimum delay between two submissions, across all
+	// symbols. Defaults to DefaultOrderQueueAccountInterval; a negative value
+	// disables the account limit.
+	AccountInterval time.Duration
+
+	// SymbolInterval is the minimum delay between two submissions for the same
+	// symbol. Defaults to DefaultOrderQueueSymbolInterval; a negative value
+	// disables the symbol limit.
+	SymbolInterval time.Duration
+
+	// Buffer is the number of orders that can wait to be submitted. Defaults
+	// to DefaultOrderQueueBuffer.
+	Buffer int
+}
+
+// OrderFuture is the pending result of an order submitted to an OrderQueue.
+type OrderFuture struct {
+	// Params are the parameters the order was submitted with.
+	Params t.CreateOrderParams
+
+	done  chan struct{}
+	order *t.OrderStatus
+	err   error
+}
+
+// Done returns a channel that is closed once the order was created or failed.
+func (f *OrderFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait waits for the order to be created and returns the result of
+// CreateOrder. It returns the context error if ctx is done first; the order
+// stays queued.
+func (f *OrderFuture) Wait(ctx context.Context) (*t.OrderStatus, error) {
+	select {
+	case <-f.done:
+		return f.order, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// resolve records the result and wakes up waiters.
+func (f *OrderFuture) resolve(order *t.OrderStatus, err error) {
+	f.order, f.err = order, err
+	close(f.done)
+}
+
+// OrderQueue submits orders at a controlled rate. Orders are submitted in the
+// order they were queued, no faster than the account and symbol intervals
+// allow, so strategies can queue bursts of orders without tracking rate limits
+// themselves.
+type OrderQueue struct {
+	client *Client
+	opts   OrderQueueOptions
+	ctx    context.Context
+
+	mu      sync.Mutex
+	closed  bool
+	pending int
+
+	in    chan *OrderFuture
+	lanes map[string]chan *OrderFuture
+
+	dispatched chan struct{}
+	workers    sync.WaitGroup
+}
+
+// NewOrderQueue starts an order queue.
+//
+// Parameters:
+//   - ctx: Queued orders fail with the context error once it is done.
+//   - opts: Submission intervals and queue capacity.
+//
+// Returns:
+//   - A pointer to a running `OrderQueue`. Call `Close` to stop it.
+//
+// Behavior:
+//   - Orders are dispatched in the order they were submitted. An order waits
+//     until both AccountInterval has passed since the previous submission and
+//     SymbolInterval has passed since the previous submission for its symbol.
+//   - Orders for the same symbol are created one at a time, in queue order.
+//     Orders for different symbols may be in flight concurrently.
+//
+// Example:
+//
+//	queue := client.NewOrderQueue(ctx, bitpin.OrderQueueOptions{SymbolInterval: time.Second})
+//	defer queue.Close()
+//
+//	var futures []*bitpin.OrderFuture
+//	for _, params := range ladder {
+//	    future, err := queue.Submit(params)
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    futures = append(futures, future)
+//	}
+//	for _, future := range futures {
+//	    order, err := future.Wait(ctx)
+//	    ...
+//	}
+func (c *Client) NewOrderQueue(ctx context.Context, opts OrderQueueOptions) *OrderQueue {
+	if opts.AccountInterval == 0 {
+		opts.AccountInterval = DefaultOrderQueueAccountInterval
+	}
+	if opts.SymbolInterval == 0 {
+		opts.SymbolInterval = DefaultOrderQueueSymbolInterval
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultOrderQueueBuffer
+	}
+
+	q := &OrderQueue{
+		client:     c,
+		opts:       opts,
+		ctx:        ctx,
+		in:         make(chan *OrderFuture, opts.Buffer),
+		lanes:      make(map[string]chan *OrderFuture),
+		dispatched: make(chan struct{}),
+	}
+	go q.dispatch()
+	return q
+}
+
+// Submit queues an order.
+//
+// Returns:
+//   - A future that resolves with the created order or the error of
+//     CreateOrder.
+//   - An error if the parameters are invalid, the queue is full, or the queue
+//     is closed.
+func (q *OrderQueue) Submit(params t.CreateOrderParams) (*OrderFuture, error) {
+	if err := params.Validate(); err != nil {
+		return nil, &GoBitpinError{Message: "order parameters are invalid", Err: err}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil, ErrOrderQueueClosed
+	}
+
+	future := &OrderFuture{Params: params, done: make(chan struct{})}
+	select {
+	case q.in <- future:
+		q.pending++
+		return future, nil
+	default:
+		return nil, ErrOrderQueueFull
+	}
+}
+
+// Pending returns the number of queued orders that were not created yet.
+func (q *OrderQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pending
+}
+
+// Close stops accepting orders and waits until every queued order was
+// submitted, or failed because the queue context is done.
+func (q *OrderQueue) Close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.in)
+	}
+	q.mu.Unlock()
+
+	<-q.dispatched
+	q.workers.Wait()
+}
+
+// dispatch paces queued orders and hands them to the lane of their symbol.
+func (q *OrderQueue) dispatch() {
+	defer close(q.dispatched)
+	defer func() {
+		for _, lane := range q.lanes {
+			close(lane)
+		}
+	}()
+
+	var accountNext time.Time
+	symbolNext := make(map[string]time.Time)
+
+	for future := range q.in {
+		symbol := future.Params.Symbol
+		at := accountNext
+		if next := symbolNext[symbol]; next.After(at) {
+			at = next
+		}
+		if err := q.client.sleepUntil(q.ctx, at); err != nil {
+			q.finish(future, nil, err)
+			continue
+		}
+
+		now := q.client.now()
+		if q.opts.AccountInterval > 0 {
+			accountNext = now.Add(q.opts.AccountInterval)
+		}
+		if q.opts.SymbolInterval > 0 {
+			symbolNext[symbol] = now.Add(q.opts.SymbolInterval)
+		}
+		q.lane(symbol) <- future
+	}
+}
+
+// lane returns the channel of the worker that creates the orders of a symbol,
+// starting the worker on first use.
+func (q *OrderQueue) lane(symbol string) chan *OrderFuture {
+	lane, ok := q.lanes[symbol]
+	if ok {
+		return lane
+	}
+	lane = make(chan *OrderFuture, q.opts.Buffer)
+	q.lanes[symbol] = lane
+	q.workers.Add(1)
+	go func() {
+		defer q.workers.Done()
+		for future := range lane {
+			order, err := q.client.CreateOrderWithContext(q.ctx, future.Params)
+			q.finish(future, order, err)
+		}
+	}()
+	return lane
+}
+
+// finish resolves a future and removes it from the pending count.
+func (q *OrderQueue) finish(future *OrderFuture, order *t.OrderStatus, err error) {
+	q.mu.Lock()
+	q.pending--
+	q.mu.Unlock()
+	future.resolve(order, err)
+}
+
+// sleepUntil waits until at on the Clock of the client, returning the context
+// error if ctx is done first.
+func (c *Client) sleepUntil(ctx context.Context, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	wait := at.Sub(c.now())
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-c.after(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,125 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// GetPage fetches one page of a list endpoint that answers with a
+// {count, next, previous, results} envelope. Endpoints that answer with a bare
+// array are returned as a single page.
+//
+// Parameters:
+//   - ctx: Controls cancellation and deadlines of the request.
+//   - c: The client to send the request with.
+//   - endpoint: The API endpoint relative to the versioned base URL, such as
+//     "/odr/orders/".
+//   - auth: Whether the endpoint requires authentication.
+//   - params: Optional query parameters, encoded like the body of a GET request.
+//
+// Returns:
+//   - A pointer to the `PagedResponse` holding the items, the total count, and
+//     the links to the neighbouring pages.
+//   - An error if the request fails or the response cannot be decoded.
+//
+// Example:
+//
+//	page, err := bitpin.GetPage[t.OrderStatus](ctx, client, "/odr/orders/", true, t.GetOrdersHistoryParams{Symbol: "BTC_USDT"})
+//	for err == nil && page != nil {
+//	    for _, order := range page.Results {
+//	        fmt.Println(order.Id, order.State)
+//	    }
+//	    page, err = bitpin.GetNextPage(ctx, client, page, true)
+//	}
+func GetPage[T any](ctx context.Context, c *Client, endpoint string, auth bool, params interface{}) (*t.PagedResponse[T], error) {
+	var page t.PagedResponse[T]
+	if err := c.ApiRequestWithContext(ctx, "GET", endpoint, Version, auth, params, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetNextPage follows the Next link of page. It returns nil without an error
+// when page is the last page.
+func GetNextPage[T any](ctx context.Context, c *Client, page *t.PagedResponse[T], auth bool) (*t.PagedResponse[T], error) {
+	return followPage[T](ctx, c, page.Next, auth)
+}
+
+// GetPreviousPage follows the Previous link of page. It returns nil without an
+// error when page is the first page.
+func GetPreviousPage[T any](ctx context.Context, c *Client, page *t.PagedResponse[T], auth bool) (*t.PagedResponse[T], error) {
+	return followPage[T](ctx, c, page.Previous, auth)
+}
+
+// followPage fetches the page behind a pagination link. Only the path and
+// query of the link are used; they are resolved against the base URL of the
+// client, so credentials are never sent to a host named by a response or a
+// stored cursor.
+func followPage[T any](ctx context.Context, c *Client, link string, auth bool) (*t.PagedResponse[T], error) {
+	if link == "" {
+		return nil, nil
+	}
+	link, err := c.pageURL(link)
+	if err != nil {
+		return nil, err
+	}
+
+	var page t.PagedResponse[T]
+	var target interface{} = &page
+	finish := func() {}
+	if version, _ := driftEndpoint(link); version == Version2 {
+		_, target, finish = adaptV2(nil, &page)
+	}
+	if err := c.RequestWithContext(ctx, "GET", link, auth, nil, target); err != nil {
+		return nil, err
+	}
+	finish()
+	return &page, nil
+}
+
+// pageURL rebases a pagination link onto the base URL of the client. Absolute
+// links must point to the host of the base URL or of one of its failover
+// mirrors; the API answers with links to the host that served the request.
+func (c *Client) pageURL(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", &GoBitpinError{Message: fmt.Sprintf("invalid pagination link %q", link), Err: err}
+	}
+	if !u.IsAbs() && u.Host == "" && !strings.HasPrefix(u.Path, "/") {
+		return "", &GoBitpinError{Message: fmt.Sprintf("pagination link %q is not absolute", link)}
+	}
+
+	path := u.EscapedPath()
+	if u.IsAbs() || u.Host != "" {
+		bases := []string{c.BaseUrl}
+		if c.failover != nil {
+			bases = c.failover.urls
+		}
+		known := false
+		for _, base := range bases {
+			b, err := url.Parse(base)
+			if err != nil || !strings.EqualFold(b.Hostname(), u.Hostname()) {
+				continue
+			}
+			if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "" {
+				break
+			}
+			known = true
+			if prefix := strings.TrimSuffix(b.EscapedPath(), "/"); prefix != "" && strings.HasPrefix(path, prefix+"/") {
+				path = strings.TrimPrefix(path, prefix)
+			}
+			break
+		}
+		if !known {
+			return "", &GoBitpinError{Message: fmt.Sprintf("pagination link %q does not point to the API", link)}
+		}
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return strings.TrimSuffix(c.BaseUrl, "/") + path, nil
+}
@@ -0,0 +1,290 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// CostBasisMethod selects how sold amounts are matched against purchases.
+type CostBasisMethod string
+
+const (
+	// CostBasisFIFO matches every sale with the oldest remaining purchases.
+	CostBasisFIFO CostBasisMethod = "fifo"
+
+	// CostBasisAverage values every sale at the average cost of the position.
+	CostBasisAverage CostBasisMethod = "average"
+)
+
+// PnLOptions configures a P&L calculation.
+type PnLOptions struct {
+	// Method is the cost-basis method. Defaults to CostBasisFIFO.
+	Method CostBasisMethod
+}
+
+// SymbolPnL is the profit and loss of a single market, in its quote currency.
+type SymbolPnL struct {
+	// Symbol is the canonical market symbol, such as "BTC_USDT".
+	Symbol string
+
+	// Base and Quote are the assets of the market.
+	Base  string
+	Quote string
+
+	// Trades is the number of trades processed.
+	Trades int
+
+	// Bought and Sold are the base amounts bought and sold.
+	Bought float64
+	Sold   float64
+
+	// Position is the base amount still held from the processed trades.
+	Position float64
+
+	// CostBasis is the quote amount paid for Position, including fees.
+	CostBasis float64
+
+	// AverageCost is CostBasis divided by Position, or zero without a
+	// position.
+	AverageCost float64
+
+	// Realized is the profit of the sold amounts: the proceeds net of fees
+	// minus their cost basis.
+	Realized float64
+
+	// MarketPrice is the last price of the market, or zero if no ticker was
+	// given for it.
+	MarketPrice float64
+
+	// Unrealized is the value of Position at MarketPrice minus CostBasis. It is
+	// zero when MarketPrice is unknown.
+	Unrealized float64
+
+	// Fees is the commission paid, converted to the quote currency at the
+	// trade price. Commissions in other currencies are listed in OtherFees.
+	Fees float64
+
+	// OtherFees holds commissions paid in assets other than Base and Quote,
+	// which are not deducted from the P&L.
+	OtherFees map[string]float64
+
+	// Unmatched is the base amount sold without a matching purchase, for
+	// example because the position was built before the first processed
+	// trade. No P&L is realized for it.
+	Unmatched float64
+}
+
+// Total returns the realized plus the unrealized P&L.
+func (p *SymbolPnL) Total() float64 {
+	return p.Realized + p.Unrealized
+}
+
+// PnLReport is the result of a P&L calculation.
+type PnLReport struct {
+	// Method is the cost-basis method used.
+	Method CostBasisMethod
+
+	// Symbols holds one entry per market, sorted by symbol.
+	Symbols []*SymbolPnL
+
+	// Realized and Unrealized add up the P&L of all markets per quote
+	// currency.
+	Realized   map[string]float64
+	Unrealized map[string]float64
+}
+
+// Symbol returns the P&L of a market, or nil if no trade of it was processed.
+func (r *PnLReport) Symbol(symbol string) *SymbolPnL {
+	for _, p := range r.Symbols {
+		if u.SameSymbol(p.Symbol, symbol) {
+			return p
+		}
+	}
+	return nil
+}
+
+// pnlLot is a purchased amount that has not been sold yet.
+type pnlLot struct {
+	amount   float64
+	unitCost float64
+}
+
+// CalculatePnL computes the realized and unrealized P&L of trades per market.
+// Trades are processed in order of their creation time; tickers provide the
+// prices of the unrealized P&L and may be nil.
+//
+// Fees are part of the accounting: a commission paid in the base asset
+// reduces the amount received by a purchase or adds to the amount given up by
+// a sale, and a commission paid in the quote asset adds to the cost of a
+// purchase or reduces the proceeds of a sale.
+//
+// Returns an error if the method is unknown or a trade has malformed amounts.
+//
+// Example:
+//
+//	report, err := bitpin.CalculatePnL(trades, *tickers, bitpin.PnLOptions{Method: bitpin.CostBasisAverage})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, p := range report.Symbols {
+//	    fmt.Printf("%s: realized %.2f, unrealized %.2f %s\n", p.Symbol, p.Realized, p.Unrealized, p.Quote)
+//	}
+func CalculatePnL(trades []t.UserTrade, tickers t.Tickers, opts PnLOptions) (*PnLReport, error) {
+	if opts.Method == "" {
+		opts.Method = CostBasisFIFO
+	}
+	if opts.Method != CostBasisFIFO && opts.Method != CostBasisAverage {
+		return nil, &GoBitpinError{Message: fmt.Sprintf("unknown cost-basis method %q", opts.Method)}
+	}
+
+	sorted := append([]t.UserTrade(nil), trades...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+		}
+		return sorted[i].Id < sorted[j].Id
+	})
+
+	symbols := make(map[string]*SymbolPnL)
+	lots := make(map[string][]pnlLot)
+	for _, trade := range sorted {
+		symbol := u.CanonicalSymbol(trade.Symbol)
+		p, ok := symbols[symbol]
+		if !ok {
+			base, quote, _ := strings.Cut(symbol, "_")
+			p = &SymbolPnL{Symbol: symbol, Base: base, Quote: quote}
+			symbols[symbol] = p
+		}
+		lots[symbol], ok = p.apply(trade, lots[symbol], opts.Method)
+		if !ok {
+			return nil, &GoBitpinError{Message: fmt.Sprintf("trade %d has malformed amounts", trade.Id)}
+		}
+	}
+
+	prices := make(map[string]float64, len(tickers))
+	for _, ticker := range tickers {
+		if price, err := strconv.ParseFloat(ticker.Price, 64); err == nil && price > 0 {
+			prices[u.CanonicalSymbol(ticker.Symbol)] = price
+		}
+	}
+
+	report := &PnLReport{Method: opts.Method, Realized: make(map[string]float64), Unrealized: make(map[string]float64)}
+	for symbol, p := range symbols {
+		if p.Position > 0 {
+			p.AverageCost = p.CostBasis / p.Position
+		}
+		if price, ok := prices[symbol]; ok {
+			p.MarketPrice = price
+			p.Unrealized = p.Position*price - p.CostBasis
+		}
+		report.Realized[p.Quote] += p.Realized
+		report.Unrealized[p.Quote] += p.Unrealized
+		report.Symbols = append(report.Symbols, p)
+	}
+	sort.Slice(report.Symbols, func(i, j int) bool { return report.Symbols[i].Symbol < report.Symbols[j].Symbol })
+	return report, nil
+}
+
+// apply books a trade and returns the remaining lots. It reports false if the
+// trade amounts cannot be parsed.
+func (p *SymbolPnL) apply(trade t.UserTrade, lots []pnlLot, method CostBasisMethod) ([]pnlLot, bool) {
+	base, err := strconv.ParseFloat(trade.BaseAmount, 64)
+	if err != nil {
+		return lots, false
+	}
+	quote, err := strconv.ParseFloat(trade.QuoteAmount, 64)
+	if err != nil {
+		return lots, false
+	}
+	price, _ := strconv.ParseFloat(trade.Price, 64)
+	if price == 0 && base > 0 {
+		price = quote / base
+	}
+
+	var feeBase, feeQuote float64
+	if commission, err := strconv.ParseFloat(trade.Commission, 64); err == nil && commission != 0 {
+		switch u.CanonicalCurrency(trade.CommissionCurrency) {
+		case p.Base:
+			feeBase = commission
+			p.Fees += commission * price
+		case p.Quote, "":
+			feeQuote = commission
+			p.Fees += commission
+		default:
+			if p.OtherFees == nil {
+				p.OtherFees = make(map[string]float64)
+			}
+			p.OtherFees[u.CanonicalCurrency(trade.CommissionCurrency)] += commission
+		}
+	}
+
+	p.Trades++
+	if trade.Side == t.SideBuy {
+		p.Bought += base
+		received := base - feeBase
+		cost := quote + feeQuote
+		if received > 0 {
+			p.Position += received
+			p.CostBasis += cost
+			lots = append(lots, pnlLot{amount: received, unitCost: cost / received})
+		}
+		return lots, true
+	}
+
+	p.Sold += base
+	given := base + feeBase
+	proceeds := quote - feeQuote
+	matched := min(given, p.Position)
+	if given > matched {
+		p.Unmatched += given - matched
+	}
+	if matched <= 0 {
+		return lots, true
+	}
+
+	var cost float64
+	if method == CostBasisAverage {
+		cost = p.CostBasis / p.Position * matched
+	} else {
+		remaining := matched
+		for remaining > 0 && len(lots) > 0 {
+			take := min(remaining, lots[0].amount)
+			cost += take * lots[0].unitCost
+			lots[0].amount -= take
+			remaining -= take
+			if lots[0].amount <= 1e-12 {
+				lots = lots[1:]
+			}
+		}
+	}
+
+	p.Realized += proceeds*matched/given - cost
+	p.Position -= matched
+	p.CostBasis -= cost
+	if p.Position <= 1e-12 {
+		p.Position, p.CostBasis, lots = 0, 0, nil
+	}
+	return lots, true
+}
+
+// CalculatePnL fetches the fills matching params and the current tickers, and
+// computes the P&L; see the CalculatePnL function. Only the fills returned by
+// one request are processed, so set params.Limit high enough to cover the
+// whole history of the position.
+func (c *Client) CalculatePnL(ctx context.Context, params t.GetUserTradesParams, opts PnLOptions) (*PnLReport, error) {
+	var trades t.UserTrades
+	if err := c.call(ctx, "GET", OpFills, nil, true, params, &trades); err != nil {
+		return nil, err
+	}
+	var tickers t.Tickers
+	if err := c.call(ctx, "GET", OpTickers, nil, false, nil, &tickers); err != nil {
+		return nil, err
+	}
+	return CalculatePnL(trades, tickers, opts)
+}
@@ -0,0 +1,266 @@
+package bitpin
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rzabhd80/go-sdk-bitpin/store"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// Portfolio defaults.
+const (
+	// DefaultPortfolioCurrency is the valuation currency of portfolio
+	// snapshots when none is given.
+	DefaultPortfolioCurrency = "USDT"
+
+	// DefaultPortfolioInterval is the default delay between two snapshots of a
+	// PortfolioTracker.
+	DefaultPortfolioInterval = time.Minute
+)
+
+// PortfolioAsset is the holding of a single asset in a PortfolioSnapshot.
+type PortfolioAsset struct {
+	// Asset is the canonical currency code, such as "BTC" or "IRT".
+	Asset string `json:"asset"`
+
+	// Balance is the total amount held, including Frozen.
+	Balance float64 `json:"balance"`
+
+	// Frozen is the amount locked in open orders or withdrawals.
+	Frozen float64 `json:"frozen"`
+
+	// Price is the value of one unit in the snapshot currency. It is zero
+	// when the asset could not be priced.
+	Price float64 `json:"price"`
+
+	// Value is Balance times Price.
+	Value float64 `json:"value"`
+}
+
+// PortfolioSnapshot is the value of all wallets at a point in time.
+type PortfolioSnapshot struct {
+	// Time is when the wallets and prices were fetched.
+	Time time.Time `json:"time"`
+
+	// Currency is the canonical valuation currency.
+	Currency string `json:"currency"`
+
+	// Assets are the non-zero holdings, sorted by descending value.
+	Assets []PortfolioAsset `json:"assets"`
+
+	// Equity is the sum of the values of all priced assets.
+	Equity float64 `json:"equity"`
+
+	// Unpriced lists held assets without a conversion path to Currency. They
+	// are not included in Equity.
+	Unpriced []string `json:"unpriced,omitempty"`
+}
+
+// NewPortfolioSnapshot values wallets in currency using the prices of tickers.
+// Balances of the same asset in different wallets, such as spot and margin,
+// are added up. An empty currency defaults to DefaultPortfolioCurrency.
+//
+// Example:
+//
+//	markets, _ := client.GetMarkets()
+//	tickers, _ := client.GetTickers()
+//	wallets, _ := client.GetWallets(types.GetWalletParams{})
+//	snapshot := bitpin.NewPortfolioSnapshot(*markets, *tickers, *wallets, "IRT", time.Now())
+//	fmt.Printf("equity: %.0f IRT\n", snapshot.Equity)
+func NewPortfolioSnapshot(markets t.Markets, tickers t.Tickers, wallets t.Wallets, currency string, at time.Time) PortfolioSnapshot {
+	if currency == "" {
+		currency = DefaultPortfolioCurrency
+	}
+	snapshot := PortfolioSnapshot{Time: at, Currency: u.CanonicalCurrency(currency)}
+	conv := NewConverter(markets, tickers)
+
+	holdings := make(map[string]*PortfolioAsset)
+	for _, wallet := range wallets {
+		balance, err := strconv.ParseFloat(wallet.Balance, 64)
+		if err != nil {
+			continue
+		}
+		frozen, _ := strconv.ParseFloat(wallet.Frozen, 64)
+		asset := u.CanonicalCurrency(wallet.Asset)
+		holding, ok := holdings[asset]
+		if !ok {
+			holding = &PortfolioAsset{Asset: asset}
+			holdings[asset] = holding
+		}
+		holding.Balance += balance
+		holding.Frozen += frozen
+	}
+
+	for _, holding := range holdings {
+		if holding.Balance == 0 {
+			continue
+		}
+		price, err := conv.Convert(1, holding.Asset, snapshot.Currency)
+		if err != nil {
+			snapshot.Unpriced = append(snapshot.Unpriced, holding.Asset)
+		} else {
+			holding.Price = price
+			holding.Value = holding.Balance * price
+			snapshot.Equity += holding.Value
+		}
+		snapshot.Assets = append(snapshot.Assets, *holding)
+	}
+
+	sort.Slice(snapshot.Assets, func(i, j int) bool {
+		if snapshot.Assets[i].Value != snapshot.Assets[j].Value {
+			return snapshot.Assets[i].Value > snapshot.Assets[j].Value
+		}
+		return snapshot.Assets[i].Asset < snapshot.Assets[j].Asset
+	})
+	sort.Strings(snapshot.Unpriced)
+	return snapshot
+}
+
+// PortfolioSnapshot fetches markets, tickers and wallets and values the
+// wallets in currency; see NewPortfolioSnapshot.
+func (c *Client) PortfolioSnapshot(ctx context.Context, currency string) (*PortfolioSnapshot, error) {
+	markets, err := c.GetMarkets()
+	if err != nil {
+		return nil, err
+	}
+	var tickers t.Tickers
+	if err := c.call(ctx, "GET", OpTickers, nil, false, nil, &tickers); err != nil {
+		return nil, err
+	}
+	var wallets t.Wallets
+	if err := c.call(ctx, "GET", OpWallets, nil, true, t.GetWalletParams{}, &wallets); err != nil {
+		return nil, err
+	}
+
+	var m t.Markets
+	if markets != nil {
+		m = *markets
+	}
+	snapshot := NewPortfolioSnapshot(m, tickers, wallets, currency, c.now())
+	return &snapshot, nil
+}
+
+// PortfolioSink receives the snapshots taken by a PortfolioTracker.
+type PortfolioSink interface {
+	// WriteSnapshot stores a snapshot.
+	WriteSnapshot(ctx context.Context, snapshot PortfolioSnapshot) error
+}
+
+// PortfolioSinkFunc adapts a function to the PortfolioSink interface.
+type PortfolioSinkFunc func(ctx context.Context, snapshot PortfolioSnapshot) error
+
+// WriteSnapshot implements PortfolioSink.
+func (f PortfolioSinkFunc) WriteSnapshot(ctx context.Context, snapshot PortfolioSnapshot) error {
+	return f(ctx, snapshot)
+}
+
+// StorePortfolioSink appends every snapshot as a JSON record to a log of a
+// store.Store, named "portfolio" by default.
+type StorePortfolioSink struct {
+	store store.Store
+	log   string
+}
+
+// NewStorePortfolioSink returns a sink writing to the named log. An empty name
+// defaults to "portfolio".
+func NewStorePortfolioSink(st store.Store, log string) *StorePortfolioSink {
+	if log == "" {
+		log = "portfolio"
+	}
+	return &StorePortfolioSink{store: st, log: log}
+}
+
+// WriteSnapshot implements PortfolioSink.
+func (s *StorePortfolioSink) WriteSnapshot(ctx context.Context, snapshot PortfolioSnapshot) error {
+	record, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	_, err = s.store.Append(ctx, s.log, record)
+	return err
+}
+
+// PortfolioTrackerOptions configures a PortfolioTracker.
+type PortfolioTrackerOptions struct {
+	// Currency is the valuation currency. Defaults to
+	// DefaultPortfolioCurrency.
+	Currency string
+
+	// Interval is the delay between two snapshots. Defaults to
+	// DefaultPortfolioInterval.
+	Interval time.Duration
+
+	// OnError is invoked when a snapshot cannot be taken or stored. When set,
+	// the tracker keeps running after errors; when nil, Run returns the first
+	// error.
+	OnError func(err error)
+}
+
+// PortfolioTracker periodically values the wallets of the account and hands
+// the snapshots to a sink, for example to track an equity curve.
+type PortfolioTracker struct {
+	client *Client
+	sink   PortfolioSink
+	opts   PortfolioTrackerOptions
+}
+
+// NewPortfolioTracker creates a tracker that delivers snapshots to sink.
+//
+// Example:
+//
+//	st, _ := store.NewFile("/var/lib/equity")
+//	tracker := client.NewPortfolioTracker(bitpin.NewStorePortfolioSink(st, ""), bitpin.PortfolioTrackerOptions{
+//	    Currency: "IRT",
+//	    Interval: 15 * time.Minute,
+//	    OnError:  func(err error) { log.Printf("portfolio snapshot: %v", err) },
+//	})
+//	go tracker.Run(ctx)
+func (c *Client) NewPortfolioTracker(sink PortfolioSink, opts PortfolioTrackerOptions) *PortfolioTracker {
+	if opts.Currency == "" {
+		opts.Currency = DefaultPortfolioCurrency
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultPortfolioInterval
+	}
+	return &PortfolioTracker{client: c, sink: sink, opts: opts}
+}
+
+// Capture takes a single snapshot and delivers it to the sink.
+func (p *PortfolioTracker) Capture(ctx context.Context) (*PortfolioSnapshot, error) {
+	snapshot, err := p.client.PortfolioSnapshot(ctx, p.opts.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.sink.WriteSnapshot(ctx, *snapshot); err != nil {
+		return nil, &GoBitpinError{Message: "portfolio sink failed", Err: err}
+	}
+	return snapshot, nil
+}
+
+// Run captures a snapshot every interval until ctx is done. It returns nil when
+// ctx is done, or the first error if OnError is nil.
+func (p *PortfolioTracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := p.Capture(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if p.opts.OnError == nil {
+				return err
+			}
+			p.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package bitpin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rzabhd80/go-sdk-bitpin/eventlog"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// Position is the spot position of a single market, in its quote currency.
+type Position struct {
+	// Symbol is the canonical market symbol, such as "BTC_USDT".
+	Symbol string
+
+	// Base and Quote are the assets of the market.
+	Base  string
+	Quote string
+
+	// Amount is the net base amount acquired through the applied fills.
+	Amount float64
+
+	// AverageEntry is the average cost of Amount, including fees.
+	AverageEntry float64
+
+	// CostBasis is the quote amount paid for Amount.
+	CostBasis float64
+
+	// Realized is the P&L realized by sales, at average cost.
+	Realized float64
+
+	// MarketPrice is the last known price, or zero if none was applied.
+	MarketPrice float64
+
+	// Exposure is the value of Amount at MarketPrice, or CostBasis while the
+	// market price is unknown.
+	Exposure float64
+
+	// Unrealized is Exposure minus CostBasis when MarketPrice is known.
+	Unrealized float64
+
+	// OpenBuy is the quote amount reserved by open buy orders, and OpenSell
+	// the base amount reserved by open sell orders.
+	OpenBuy  float64
+	OpenSell float64
+
+	// UpdatedAt is when the position last changed.
+	UpdatedAt time.Time
+}
+
+// PositionTrackerOptions configures a PositionTracker.
+type PositionTrackerOptions struct {
+	// Log persists applied fills. The tracker replays it on creation, so a
+	// restarted process resumes with the same positions. When nil, positions
+	// are only kept in memory.
+	Log eventlog.Log
+}
+
+// positionState is the accounting of a single market.
+type positionState struct {
+	pnl       *SymbolPnL
+	price     float64
+	orders    map[int]t.OrderStatus
+	updatedAt time.Time
+}
+
+// PositionTracker maintains per-market spot positions from order and fill
+// updates. It is safe for concurrent use, so risk checks can read positions
+// while updates are applied.
+//
+// Fills are booked at average cost and deduplicated by ID, so the same fill
+// may be applied more than once, for example after replaying a log and then
+// polling recent fills.
+type PositionTracker struct {
+	opts PositionTrackerOptions
+
+	mu        sync.RWMutex
+	positions map[string]*positionState
+	fills     map[int]struct{}
+}
+
+// fillApplied is the event type of fills recorded in the log.
+const fillApplied = "fill_applied"
+
+// NewPositionTracker creates a tracker, replaying opts.Log if set.
+//
+// Returns:
+//   - A pointer to a `PositionTracker`.
+//   - An error if the log cannot be replayed.
+//
+// Example:
+//
+//	events, _ := eventlog.OpenFileLog("/var/lib/bot/positions.jsonl")
+//	tracker, err := bitpin.NewPositionTracker(bitpin.PositionTrackerOptions{Log: events})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	stream := client.SubscribeUserData(ctx, bitpin.UserDataStreamOptions{})
+//	go func() {
+//	    for event := range stream.C {
+//	        tracker.Handle(event)
+//	    }
+//	}()
+//
+//	if pos, ok := tracker.Position("BTC_USDT"); ok && pos.Exposure > maxExposure {
+//	    // reduce risk
+//	}
+func NewPositionTracker(opts PositionTrackerOptions) (*PositionTracker, error) {
+	p := &PositionTracker{
+		opts:      opts,
+		positions: make(map[string]*positionState),
+		fills:     make(map[int]struct{}),
+	}
+	if opts.Log == nil {
+		return p, nil
+	}
+
+	err := opts.Log.Replay(0, func(event eventlog.Event) error {
+		if event.Type != fillApplied || !strings.HasPrefix(event.Stream, "positions/") {
+			return nil
+		}
+		var fill t.UserTrade
+		if err := event.Decode(&fill); err != nil {
+			return fmt.Errorf("event %d: %w", event.Seq, err)
+		}
+		return p.applyFill(fill)
+	})
+	if err != nil {
+		return nil, &GoBitpinError{Message: "failed to replay position log", Err: err}
+	}
+	return p, nil
+}
+
+// Handle applies a user data event, as delivered by SubscribeUserData or a
+// Stream.
+func (p *PositionTracker) Handle(event UserDataEvent) error {
+	switch {
+	case event.Fill != nil:
+		return p.ApplyFill(*event.Fill)
+	case event.Order != nil:
+		p.ApplyOrder(*event.Order)
+	}
+	return nil
+}
+
+// ApplyFill books a fill. Fills that were already applied are ignored. When a
+// log is configured, the fill is recorded before it is booked.
+func (p *PositionTracker) ApplyFill(fill t.UserTrade) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.fills[fill.Id]; ok {
+		return nil
+	}
+	if !validFill(fill) {
+		return &GoBitpinError{Message: fmt.Sprintf("fill %d has malformed amounts", fill.Id)}
+	}
+	if p.opts.Log != nil {
+		if _, err := p.opts.Log.Append("positions/"+u.CanonicalSymbol(fill.Symbol), fillApplied, fill); err != nil {
+			return &GoBitpinError{Message: "failed to record fill", Err: err}
+		}
+	}
+	return p.applyFill(fill)
+}
+
+// applyFill books a fill while the caller holds the lock, or during replay.
+func (p *PositionTracker) applyFill(fill t.UserTrade) error {
+	if _, ok := p.fills[fill.Id]; ok {
+		return nil
+	}
+	state := p.state(fill.Symbol)
+	if _, ok := state.pnl.apply(fill, nil, CostBasisAverage); !ok {
+		return &GoBitpinError{Message: fmt.Sprintf("fill %d has malformed amounts", fill.Id)}
+	}
+	p.fills[fill.Id] = struct{}{}
+	state.updatedAt = fill.CreatedAt
+	if state.updatedAt.IsZero() {
+		state.updatedAt = time.Now()
+	}
+	return nil
+}
+
+// validFill reports whether the amounts of a fill can be booked.
+func validFill(fill t.UserTrade) bool {
+	_, errBase := strconv.ParseFloat(fill.BaseAmount, 64)
+	_, errQuote := strconv.ParseFloat(fill.QuoteAmount, 64)
+	return errBase == nil && errQuote == nil
+}
+
+// ApplyOrder records the state of an order. Open orders count towards OpenBuy
+// and OpenSell until an update with a terminal state is applied.
+func (p *PositionTracker) ApplyOrder(order t.OrderStatus) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state := p.state(order.Symbol)
+	if IsTerminalOrderState(order.State) {
+		delete(state.orders, order.Id)
+	} else {
+		state.orders[order.Id] = order
+	}
+	state.updatedAt = time.Now()
+}
+
+// ApplyPrice sets the market price used to value a position.
+func (p *PositionTracker) ApplyPrice(symbol string, price float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state := p.state(symbol)
+	state.price = price
+}
+
+// ApplyTickers sets the market prices of all tracked markets found in tickers.
+func (p *PositionTracker) ApplyTickers(tickers t.Tickers) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, ticker := range tickers {
+		state, ok := p.positions[u.CanonicalSymbol(ticker.Symbol)]
+		if !ok {
+			continue
+		}
+		if price, err := strconv.ParseFloat(ticker.Price, 64); err == nil && price > 0 {
+			state.price = price
+		}
+	}
+}
+
+// Position returns the position of a market. It reports false if nothing was
+// applied for the market.
+func (p *PositionTracker) Position(symbol string) (Position, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	state, ok := p.positions[u.CanonicalSymbol(symbol)]
+	if !ok {
+		return Position{}, false
+	}
+	return state.position(), true
+}
+
+// Positions returns all positions, sorted by symbol.
+func (p *PositionTracker) Positions() []Position {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	positions := make([]Position, 0, len(p.positions))
+	for _, state := range p.positions {
+		positions = append(positions, state.position())
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].Symbol < positions[j].Symbol })
+	return positions
+}
+
+// Exposure returns the total exposure of all markets quoted in quote, for
+// example "USDT".
+func (p *PositionTracker) Exposure(quote string) float64 {
+	quote = u.CanonicalCurrency(quote)
+	var total float64
+	for _, position := range p.Positions() {
+		if position.Quote == quote {
+			total += position.Exposure
+		}
+	}
+	return total
+}
+
+// state returns the accounting of a market, creating it on first use.
+func (p *PositionTracker) state(symbol string) *positionState {
+	symbol = u.CanonicalSymbol(symbol)
+	state, ok := p.positions[symbol]
+	if !ok {
+		base, quote, _ := strings.Cut(symbol, "_")
+		state = &positionState{
+			pnl:    &SymbolPnL{Symbol: symbol, Base: base, Quote: quote},
+			orders: make(map[int]t.OrderStatus),
+		}
+		p.positions[symbol] = state
+	}
+	return state
+}
+
+// position derives the public view of the accounting.
+func (s *positionState) position() Position {
+	pos := Position{
+		Symbol:      s.pnl.Symbol,
+		Base:        s.pnl.Base,
+		Quote:       s.pnl.Quote,
+		Amount:      s.pnl.Position,
+		CostBasis:   s.pnl.CostBasis,
+		Realized:    s.pnl.Realized,
+		MarketPrice: s.price,
+		Exposure:    s.pnl.CostBasis,
+		UpdatedAt:   s.updatedAt,
+	}
+	if pos.Amount > 0 {
+		pos.AverageEntry = pos.CostBasis / pos.Amount
+	}
+	if s.price > 0 {
+		pos.Exposure = pos.Amount * s.price
+		pos.Unrealized = pos.Exposure - pos.CostBasis
+	}
+
+	for _, order := range s.orders {
+		base, _ := strconv.ParseFloat(order.BaseAmount, 64)
+		dealt, _ := strconv.ParseFloat(order.DealedBaseAmount, 64)
+		remaining := max(base-dealt, 0)
+		if order.Side == t.SideSell {
+			pos.OpenSell += remaining
+			continue
+		}
+		if price, err := strconv.ParseFloat(order.Price, 64); err == nil && price > 0 && remaining > 0 {
+			pos.OpenBuy += remaining * price
+		} else if quote, err := strconv.ParseFloat(order.QuoteAmount, 64); err == nil {
+			dealtQuote, _ := strconv.ParseFloat(order.DealedQuoteAmount, 64)
+			pos.OpenBuy += max(quote-dealtQuote, 0)
+		}
+	}
+	return pos
+}
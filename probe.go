@@ -0,0 +1,224 @@
+package bitpin
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// PrecisionProbeParams describes the probe orders used by ProbeMarketPrecision.
+type PrecisionProbeParams struct {
+	// Market is the market metadata to verify, as returned by GetMarkets.
+	Market t.Market
+
+	// Price is a limit buy price that will not be matched, for example well below
+	// the current best bid. Probe orders that the exchange accepts are cancelled
+	// right away.
+	Price string
+
+	// BaseAmount is the base amount used for the probe orders. It must satisfy
+	// the exchange's minimum order size at Price.
+	BaseAmount string
+}
+
+// PrecisionMismatch describes a difference between the declared precision of a
+// market and the precision actually enforced by the exchange.
+type PrecisionMismatch struct {
+	// Field is the order field that was probed, "price" or "base_amount".
+	Field string
+
+	// Declared is the precision from the market metadata.
+	Declared int
+
+	// Detail explains how the exchange behaved.
+	Detail string
+}
+
+// PrecisionReport is the result of probing a single market.
+type PrecisionReport struct {
+	// Symbol is the probed market symbol.
+	Symbol string
+
+	// Mismatches lists every detected difference. It is empty when the exchange
+	// enforces exactly the declared precision.
+	Mismatches []PrecisionMismatch
+}
+
+// ProbeMarketPrecision empirically verifies the declared price and base amount
+// precision of a market by submitting limit orders to the API and inspecting
+// the validation responses.
+//
+// For each field two orders are submitted: one using exactly the declared number
+// of decimals, which the exchange should accept, and one using one extra
+// decimal, which the exchange should reject. Accepted probe orders are cancelled
+// immediately.
+//
+// Probes place real orders, so this method refuses to run when the base URL or
+// any failover mirror is on the production host. Point the client at a
+// sandbox or paper trading environment with `ClientOptions.BaseUrl`.
+//
+// Parameters:
+//   - params: The market to verify and a non-marketable price and amount to
+//     build the probe orders from.
+//
+// Returns:
+//   - A pointer to a `PrecisionReport` listing detected mismatches.
+//   - An error if the client targets production, the market declares a
+//     negative precision, or a probe fails for a reason other than validation.
+//
+// Example:
+//
+//	report, err := client.ProbeMarketPrecision(bitpin.PrecisionProbeParams{
+//	    Market:     market,
+//	    Price:      "100",
+//	    BaseAmount: "1",
+//	})
+//	for _, m := range report.Mismatches {
+//	    log.Printf("%s %s: %s", report.Symbol, m.Field, m.Detail)
+//	}
+func (c *Client) ProbeMarketPrecision(params PrecisionProbeParams) (*PrecisionReport, error) {
+	if c.targetsProduction() {
+		return nil, &GoBitpinError{
+			Message: "precision probes place real orders and must not run against production",
+			Err:     nil,
+		}
+	}
+	if params.Market.PricePrecision < 0 || params.Market.BaseAmountPrecision < 0 {
+		return nil, &GoBitpinError{
+			Message: fmt.Sprintf("market %s declares a negative precision", params.Market.Symbol),
+			Err:     nil,
+		}
+	}
+
+	report := &PrecisionReport{Symbol: params.Market.Symbol}
+
+	fields := []struct {
+		name      string
+		precision int
+		value     string
+		build     func(value string) t.CreateOrderParams
+	}{
+		{
+			name:      "price",
+			precision: params.Market.PricePrecision,
+			value:     params.Price,
+			build: func(value string) t.CreateOrderParams {
+				return probeOrder(params.Market.Symbol, value, params.BaseAmount)
+			},
+		},
+		{
+			name:      "base_amount",
+			precision: params.Market.BaseAmountPrecision,
+			value:     params.BaseAmount,
+			build: func(value string) t.CreateOrderParams {
+				return probeOrder(params.Market.Symbol, params.Price, value)
+			},
+		},
+	}
+
+	for _, f := range fields {
+		exact := withDecimals(f.value, f.precision, false)
+		rejected, err := c.probe(f.build(exact), f.name)
+		if err != nil {
+			return report, err
+		}
+		if rejected {
+			report.Mismatches = append(report.Mismatches, PrecisionMismatch{
+				Field:    f.name,
+				Declared: f.precision,
+				Detail:   fmt.Sprintf("exchange rejected %s with %d decimals", exact, f.precision),
+			})
+		}
+
+		over := withDecimals(f.value, f.precision, true)
+		rejected, err = c.probe(f.build(over), f.name)
+		if err != nil {
+			return report, err
+		}
+		if !rejected {
+			report.Mismatches = append(report.Mismatches, PrecisionMismatch{
+				Field:    f.name,
+				Declared: f.precision,
+				Detail:   fmt.Sprintf("exchange accepted %s with %d decimals", over, f.precision+1),
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// probe submits a probe order and reports whether the exchange rejected it
+// because of the given field. Accepted orders are cancelled.
+func (c *Client) probe(params t.CreateOrderParams, field string) (bool, error) {
+	order, err := c.CreateOrder(params)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == 400 {
+			if _, ok := apiErr.Details[field]; ok {
+				return true, nil
+			}
+		}
+		return false, err
+	}
+
+	if err := c.CancelOrder(order.Id); err != nil {
+		return false, &GoBitpinError{
+			Message: fmt.Sprintf("failed to cancel probe order %d", order.Id),
+			Err:     err,
+		}
+	}
+	return false, nil
+}
+
+// probeOrder builds a limit buy order used for precision probing.
+func probeOrder(symbol, price, baseAmount string) t.CreateOrderParams {
+	return t.CreateOrderParams{
+		Symbol:     symbol,
+		Type:       t.TypeLimit,
+		Side:       t.SideBuy,
+		Price:      price,
+		BaseAmount: baseAmount,
+	}
+}
+
+// targetsProduction reports whether the base URL or a failover mirror of the
+// client is on the host of the production API. URLs that cannot be parsed
+// count as production.
+func (c *Client) targetsProduction() bool {
+	production, _ := url.Parse(BaseUrl)
+	targets := []string{c.BaseUrl}
+	if c.failover != nil {
+		targets = c.failover.urls
+	}
+	for _, target := range targets {
+		u, err := url.Parse(strings.TrimSpace(target))
+		if err != nil || u.Host == "" {
+			return true
+		}
+		if strings.EqualFold(strings.TrimSuffix(u.Hostname(), "."), production.Hostname()) {
+			return true
+		}
+	}
+	return false
+}
+
+// withDecimals rewrites a decimal string to have exactly the given number of
+// decimals, truncating or zero-padding as needed. When extra is true a
+// trailing "1" is appended so the result has one decimal more than allowed.
+func withDecimals(value string, decimals int, extra bool) string {
+	intPart, fracPart, _ := strings.Cut(value, ".")
+	if len(fracPart) > decimals {
+		fracPart = fracPart[:decimals]
+	}
+	fracPart += strings.Repeat("0", decimals-len(fracPart))
+	if extra {
+		fracPart += "1"
+	}
+	if fracPart == "" {
+		return intPart
+	}
+	return intPart + "." + fracPart
+}
@@ -0,0 +1,151 @@
+package bitpin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitStatus is the request budget reported by the API in the rate-limit
+// headers of the most recent response.
+type RateLimitStatus struct {
+	// Known is false until a response with rate-limit headers was received.
+	Known bool
+
+	// Limit is the number of requests allowed per window, or -1 if the API
+	// did not report it.
+	Limit int
+
+	// Remaining is the number of requests left in the current window, or -1
+	// if the API did not report it.
+	Remaining int
+
+	// Reset is when the current window ends and the budget is restored. It is
+	// zero if the API did not report it.
+	Reset time.Time
+
+	// RetryAfter is the wait requested by the API with the last 429 Too Many
+	// Requests response, if any.
+	RetryAfter time.Duration
+
+	// UpdatedAt is when the status was last updated.
+	UpdatedAt time.Time
+
+	// clock is the Clock of the client that received the headers. Nil
+	// selects SystemClock.
+	clock Clock
+}
+
+// Exhausted reports whether no requests are left in the current window. It
+// returns false once the window has reset, as seen by the Clock of the client
+// that received the status, or if the budget is not known.
+func (s RateLimitStatus) Exhausted() bool {
+	if !s.Known || s.Remaining != 0 {
+		return false
+	}
+	now := time.Now()
+	if s.clock != nil {
+		now = s.clock.Now()
+	}
+	return s.Reset.IsZero() || now.Before(s.Reset)
+}
+
+// rateLimitTracker records the rate-limit headers of responses.
+type rateLimitTracker struct {
+	mu     sync.Mutex
+	status RateLimitStatus
+}
+
+// observe updates the status from the headers of a response. Responses without
+// rate-limit headers leave it unchanged.
+func (r *rateLimitTracker) observe(clock Clock, statusCode int, header http.Header) {
+	status, ok := parseRateLimit(clock, statusCode, header)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+// parseRateLimit reads the rate-limit headers of a response received now on
+// clock, which may be nil. It reports false if the response has none and is
+// not a 429 Too Many Requests.
+func parseRateLimit(clock Clock, statusCode int, header http.Header) (RateLimitStatus, bool) {
+	limit, hasLimit := headerInt(header, "X-RateLimit-Limit", "RateLimit-Limit")
+	remaining, hasRemaining := headerInt(header, "X-RateLimit-Remaining", "RateLimit-Remaining")
+	reset, hasReset := headerInt(header, "X-RateLimit-Reset", "RateLimit-Reset")
+	retryAfter, hasRetryAfter := headerInt(header, "Retry-After")
+	if !hasLimit && !hasRemaining && !hasReset && statusCode != http.StatusTooManyRequests {
+		return RateLimitStatus{}, false
+	}
+
+	if clock == nil {
+		clock = SystemClock
+	}
+	now := clock.Now()
+	status := RateLimitStatus{Known: true, Limit: -1, Remaining: -1, UpdatedAt: now, clock: clock}
+	if hasLimit {
+		status.Limit = limit
+	}
+	if hasRemaining {
+		status.Remaining = remaining
+	}
+	if hasReset {
+		status.Reset = resetTime(now, reset)
+	}
+	if statusCode == http.StatusTooManyRequests {
+		status.Remaining = 0
+		if hasRetryAfter {
+			status.RetryAfter = time.Duration(retryAfter) * time.Second
+			if status.Reset.IsZero() {
+				status.Reset = now.Add(status.RetryAfter)
+			}
+		}
+	}
+	return status, true
+}
+
+// headerInt returns the first of the named headers that holds an integer.
+func headerInt(header http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		value := strings.TrimSpace(header.Get(name))
+		if value == "" {
+			continue
+		}
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// resetTime interprets a reset header, which is either a Unix timestamp or a
+// number of seconds until the reset.
+func resetTime(now time.Time, reset int) time.Time {
+	if reset > 1e9 {
+		return time.Unix(int64(reset), 0)
+	}
+	return now.Add(time.Duration(reset) * time.Second)
+}
+
+// RateLimitStatus returns the request budget reported by the most recent
+// response that carried rate-limit headers (X-RateLimit-Limit,
+// X-RateLimit-Remaining and X-RateLimit-Reset, or their RateLimit-*
+// equivalents). A 429 Too Many Requests response marks the budget as
+// exhausted until its Retry-After has passed.
+//
+// Example:
+//
+//	status := client.RateLimitStatus()
+//	if status.Known && status.Remaining >= 0 && status.Remaining < 10 {
+//	    time.Sleep(time.Until(status.Reset)) // back off before hitting 429s
+//	}
+func (c *Client) RateLimitStatus() RateLimitStatus {
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	return c.rateLimit.status
+}
@@ -0,0 +1,236 @@
+package bitpin
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// DefaultReconcilePrecision is the number of decimal places to which filled
+// amounts are compared when none is given.
+const DefaultReconcilePrecision = 8
+
+// ReconcilerOptions configures a Reconciler.
+type ReconcilerOptions struct {
+	// Symbol restricts Fetch to a single market. Empty means all markets.
+	Symbol string
+
+	// TradesLimit is the number of most recent fills fetched by Fetch. Zero
+	// uses the API default.
+	TradesLimit int
+
+	// Precision is the number of decimal places to which the filled amounts of
+	// an order are compared with the sum of its fills. Defaults to
+	// DefaultReconcilePrecision.
+	Precision int
+}
+
+// OrderReconciliation is an order together with the fills matched to it.
+type OrderReconciliation struct {
+	// Order is the order as reported by the API.
+	Order t.OrderStatus
+
+	// Fills are the fills matched to the order, by order ID or identifier.
+	Fills []t.UserTrade
+
+	// FilledBase and FilledQuote are the sums of the fill amounts.
+	FilledBase  string
+	FilledQuote string
+
+	// Mismatch reports whether the filled amounts of the order differ from the
+	// sums of its fills, for example because some fills are missing from the
+	// fetched history.
+	Mismatch bool
+}
+
+// ReconciliationReport is the result of matching fills to orders.
+type ReconciliationReport struct {
+	// Orders are the orders with their matched fills, sorted by order ID.
+	Orders []*OrderReconciliation
+
+	// OrphanFills are fills whose order is unknown.
+	OrphanFills []t.UserTrade
+
+	// MissingIdentifiers are expected identifiers for which no order exists,
+	// meaning the order never reached the exchange.
+	MissingIdentifiers []string
+
+	// UnfilledIdentifiers are expected identifiers whose order exists but has
+	// no matched fill.
+	UnfilledIdentifiers []string
+
+	// Mismatched are the entries of Orders with Mismatch set.
+	Mismatched []*OrderReconciliation
+}
+
+// Clean reports whether every fill matched an order, every expected
+// identifier was found, and no filled amount disagrees with the fills.
+// Unfilled identifiers do not make a report unclean, since resting orders are
+// expected to have no fills.
+func (r *ReconciliationReport) Clean() bool {
+	return len(r.OrphanFills) == 0 && len(r.MissingIdentifiers) == 0 && len(r.Mismatched) == 0
+}
+
+// Reconciler matches fills to orders, for example to verify the state of a
+// strategy after a connectivity outage.
+type Reconciler struct {
+	client *Client
+	opts   ReconcilerOptions
+}
+
+// NewReconciler creates a Reconciler.
+//
+// Example:
+//
+//	rec := client.NewReconciler(bitpin.ReconcilerOptions{Symbol: "BTC_USDT", TradesLimit: 200})
+//	report, err := rec.Fetch(ctx, placedIdentifiers)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if !report.Clean() {
+//	    log.Printf("%d orphan fills, missing orders: %v", len(report.OrphanFills), report.MissingIdentifiers)
+//	}
+func (c *Client) NewReconciler(opts ReconcilerOptions) *Reconciler {
+	if opts.Precision <= 0 {
+		opts.Precision = DefaultReconcilePrecision
+	}
+	return &Reconciler{client: c, opts: opts}
+}
+
+// Reconcile matches fills to orders and checks the expected identifiers.
+//
+// Parameters:
+//   - orders: The known orders.
+//   - trades: The fills to match.
+//   - identifiers: Client-side identifiers of orders that are expected to
+//     exist. May be empty.
+//
+// Behavior:
+//   - A fill is matched to the order with its OrderId, or, if it carries no
+//     order ID, to the order with its Identifier.
+//   - The filled amounts of every order with fills, or with a non-zero filled
+//     amount, are compared with the sums of its matched fills.
+func (r *Reconciler) Reconcile(orders []t.OrderStatus, trades []t.UserTrade, identifiers []string) *ReconciliationReport {
+	report := &ReconciliationReport{}
+
+	byId := make(map[int]*OrderReconciliation, len(orders))
+	byIdentifier := make(map[string]*OrderReconciliation, len(orders))
+	for _, order := range orders {
+		if _, ok := byId[order.Id]; ok {
+			continue
+		}
+		entry := &OrderReconciliation{Order: order}
+		byId[order.Id] = entry
+		if order.Identifier != "" {
+			byIdentifier[order.Identifier] = entry
+		}
+		report.Orders = append(report.Orders, entry)
+	}
+
+	for _, trade := range trades {
+		entry, ok := byId[trade.OrderId]
+		if !ok && trade.OrderId == 0 && trade.Identifier != "" {
+			entry, ok = byIdentifier[trade.Identifier]
+		}
+		if !ok {
+			report.OrphanFills = append(report.OrphanFills, trade)
+			continue
+		}
+		entry.Fills = append(entry.Fills, trade)
+	}
+
+	for _, entry := range report.Orders {
+		base, quote := new(big.Rat), new(big.Rat)
+		for _, fill := range entry.Fills {
+			if amount, err := u.ParseAmount(fill.BaseAmount); err == nil {
+				base.Add(base, amount)
+			}
+			if amount, err := u.ParseAmount(fill.QuoteAmount); err == nil {
+				quote.Add(quote, amount)
+			}
+		}
+		entry.FilledBase = trimRat(base)
+		entry.FilledQuote = trimRat(quote)
+
+		dealt := entry.Order.DealedBaseAmount
+		if dealt == "" {
+			dealt = "0"
+		}
+		if !u.AmountsEqual(entry.FilledBase, dealt, r.opts.Precision) {
+			entry.Mismatch = true
+			report.Mismatched = append(report.Mismatched, entry)
+		}
+	}
+
+	for _, identifier := range identifiers {
+		entry, ok := byIdentifier[identifier]
+		switch {
+		case !ok:
+			report.MissingIdentifiers = append(report.MissingIdentifiers, identifier)
+		case len(entry.Fills) == 0:
+			report.UnfilledIdentifiers = append(report.UnfilledIdentifiers, identifier)
+		}
+	}
+
+	sort.Slice(report.Orders, func(i, j int) bool { return report.Orders[i].Order.Id < report.Orders[j].Order.Id })
+	sort.Slice(report.Mismatched, func(i, j int) bool { return report.Mismatched[i].Order.Id < report.Mismatched[j].Order.Id })
+	return report
+}
+
+// Fetch loads the recent fills, the orders with the expected identifiers and
+// the orders referenced by the fills, and reconciles them.
+//
+// Returns:
+//   - The reconciliation report.
+//   - An error if a request fails. Orders referenced by a fill that the API
+//     reports as not found leave the fill orphaned rather than failing.
+func (r *Reconciler) Fetch(ctx context.Context, identifiers []string) (*ReconciliationReport, error) {
+	var trades t.UserTrades
+	params := t.GetUserTradesParams{Symbol: r.opts.Symbol, Limit: r.opts.TradesLimit}
+	if err := r.client.call(ctx, "GET", OpFills, nil, true, params, &trades); err != nil {
+		return nil, err
+	}
+
+	var orders t.OrderStatuses
+	if len(identifiers) > 0 {
+		params := t.GetOrdersHistoryParams{Symbol: r.opts.Symbol, IdentifiersIn: strings.Join(identifiers, ",")}
+		if err := r.client.call(ctx, "GET", OpOrders, nil, true, params, &orders); err != nil {
+			return nil, err
+		}
+	}
+
+	known := make(map[int]bool, len(orders))
+	for _, order := range orders {
+		known[order.Id] = true
+	}
+	for _, trade := range trades {
+		if trade.OrderId == 0 || known[trade.OrderId] {
+			continue
+		}
+		known[trade.OrderId] = true
+		order, err := r.client.GetOrderWithContext(ctx, trade.OrderId)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *order)
+	}
+
+	return r.Reconcile(orders, trades, identifiers), nil
+}
+
+// trimRat formats a sum of amounts without trailing zeros.
+func trimRat(r *big.Rat) string {
+	s := r.FloatString(18)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,139 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// ErrReplaceAborted is returned by ReplaceOrder when no replacement was
+// submitted because the order was filled before it could be cancelled
+var ErrReplaceAborted = &GoBitpinError{Message: "replace aborted"}
+
+// ReplaceOptions configures ReplaceOrder.
+type ReplaceOptions struct {
+	// SubtractFilled reduces the base amount of the replacement by the amount
+	// the original order filled, so the total exposure stays the same. The
+	// replace is aborted if nothing remains. It only applies to replacements
+	// given by BaseAmount.
+	SubtractFilled bool
+}
+
+// ReplaceResult reports the outcome of a cancel-replace.
+type ReplaceResult struct {
+	// Cancel is the confirmed outcome of the cancel of the original order.
+	Cancel *CancelResult
+
+	// FilledBaseAmount is the base amount the original order filled before it
+	// was cancelled. It is "0" when nothing was filled.
+	FilledBaseAmount string
+
+	// Params are the parameters the replacement was submitted with.
+	Params t.CreateOrderParams
+
+	// Order is the replacement order, or nil if none was submitted or its
+	// submission failed.
+	Order *t.OrderStatus
+
+	// Aborted is true if no replacement was submitted because the original
+	// order was filled, or because nothing remained after SubtractFilled.
+	Aborted bool
+}
+
+// ReplaceOrder cancels an order, waits until the cancel is confirmed, and then
+// submits a replacement.
+//
+// Parameters:
+//   - ctx: Controls how long to wait for the cancel confirmation and the
+//     replacement request.
+//   - orderId: The unique identifier of the order to replace.
+//   - params: The replacement. Empty Symbol, Side, Type and Identifier fields
+//     are taken from the original order.
+//   - opts: Whether to subtract partial fills from the replacement.
+//
+// Returns:
+//   - A pointer to a `ReplaceResult` describing the cancel, the partial fills of
+//     the original order and the replacement. It is returned together with the
+//     error whenever the cancel was confirmed.
+//   - An error wrapping `ErrReplaceAborted` if the original order was filled
+//     before the cancel took effect, or if nothing remains after
+//     SubtractFilled.
+//   - Any error of the cancel confirmation or of the replacement request. If the
+//     replacement fails, the original order stays cancelled.
+//
+// Behavior:
+//   - The cancel is confirmed with `CancelOrderAndConfirm`, so fills that race
+//     the cancel are accounted for before the replacement is submitted.
+//   - The replacement is submitted with `CreateOrder` semantics, including the
+//     local validation and the symbol lock.
+//
+// Example:
+//
+//	result, err := client.ReplaceOrder(ctx, orderId, t.CreateOrderParams{
+//	    Price:      "61000",
+//	    BaseAmount: "0.1",
+//	}, bitpin.ReplaceOptions{SubtractFilled: true})
+//	if errors.Is(err, bitpin.ErrReplaceAborted) {
+//	    log.Printf("order filled %s before it could be replaced", result.FilledBaseAmount)
+//	} else if err != nil {
+//	    log.Fatal(err)
+//	} else {
+//	    log.Printf("replaced by order %d", result.Order.Id)
+//	}
+func (c *Client) ReplaceOrder(ctx context.Context, orderId int, params t.CreateOrderParams, opts ReplaceOptions) (*ReplaceResult, error) {
+	cancel, err := c.CancelOrderAndConfirm(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	original := cancel.Order
+	result := &ReplaceResult{Cancel: cancel, FilledBaseAmount: cancel.FilledBaseAmount}
+	if cancel.Outcome == CancelOutcomeFilled {
+		result.Aborted = true
+		return result, &GoBitpinError{
+			Message: fmt.Sprintf("order %d was filled before it could be replaced", orderId),
+			Err:     ErrReplaceAborted,
+		}
+	}
+
+	if params.Symbol == "" {
+		params.Symbol = original.Symbol
+	}
+	if params.Side == "" {
+		params.Side = original.Side
+	}
+	if params.Type == "" {
+		params.Type = original.Type
+	}
+	if params.Identifier == "" {
+		params.Identifier = original.Identifier
+	}
+
+	if opts.SubtractFilled && params.BaseAmount != "" && !isZeroAmount(result.FilledBaseAmount) {
+		remaining := amountDiff(params.BaseAmount, result.FilledBaseAmount)
+		if isZeroAmount(remaining) {
+			result.Aborted = true
+			return result, &GoBitpinError{
+				Message: fmt.Sprintf("order %d filled %s, nothing remains to replace", orderId, result.FilledBaseAmount),
+				Err:     ErrReplaceAborted,
+			}
+		}
+		if strings.Contains(remaining, ".") {
+			remaining = strings.TrimSuffix(strings.TrimRight(remaining, "0"), ".")
+		}
+		params.BaseAmount = remaining
+	}
+	result.Params = params
+
+	order, err := c.CreateOrderWithContext(ctx, params)
+	if err != nil {
+		return result, &GoBitpinError{
+			Message: fmt.Sprintf("order %d was cancelled but its replacement failed", orderId),
+			Err:     err,
+		}
+	}
+	result.Order = order
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package bitpin
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader is the header that carries the ID of every request sent by
+// the client.
+const RequestIDHeader = "X-Request-Id"
+
+// requestIDKey is the context key of the request ID.
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a context whose requests are sent with the given
+// ID instead of a generated one. Every request made with the context carries
+// the same ID, which lets callers correlate SDK requests with their own logs.
+//
+// Example:
+//
+//	ctx := bitpin.ContextWithRequestID(ctx, "rebalance-42")
+//	err := client.ApiRequestWithContext(ctx, "GET", "/wlt/wallets/", bitpin.Version, true, nil, &wallets)
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set with ContextWithRequestID, or
+// an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDOf returns the request ID carried by an error returned from an API
+// call, or an empty string if err has none. It looks through wrapped errors,
+// so it also works on errors of helpers such as CreateOrder.
+//
+// Example:
+//
+//	if _, err := client.CreateOrder(params); err != nil {
+//	    log.Printf("order failed (request %s): %v", bitpin.RequestIDOf(err), err)
+//	}
+func RequestIDOf(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RequestID
+	}
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.RequestID
+	}
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		return authErr.RequestID
+	}
+	return ""
+}
+
+// RequestEvent describes a request sent to the API, for the OnRequest hook.
+type RequestEvent struct {
+	// RequestID is the ID sent in the RequestIDHeader header. A request retried
+	// after a token renewal is reported twice with the same ID.
+	RequestID string
+
+	// Method is the HTTP method of the request.
+	Method string
+
+	// URL is the URL of the request, including its query.
+	URL string
+
+	// StatusCode is the HTTP status code of the response, or zero if no
+	// response was received.
+	StatusCode int
+
+	// Duration is the time from sending the request until its response was
+	// read.
+	Duration time.Duration
+
+	// Timings breaks Duration down into network phases. It is nil unless
+	// TraceRequests is enabled.
+	Timings *RequestTimings
+
+	// Err is the error of the request: a *RequestError if no response was
+	// received or read, or an *APIError for non-2xx responses.
+	Err error
+}
+
+// withRequestID returns ctx with a generated request ID, unless it already
+// carries one.
+func withRequestID(ctx context.Context) context.Context {
+	if RequestIDFromContext(ctx) != "" {
+		return ctx
+	}
+	return ContextWithRequestID(ctx, newRequestID())
+}
+
+// newRequestID returns a random version 4 UUID.
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// observeRequest reports a request to the OnRequest hook and to the
+// ResponseMeta of its context and, in debug mode, logs its dump. resp is nil if
+// no response was received.
+func (c *Client) observeRequest(req *http.Request, reqBody []byte, sent time.Time, trace *requestTrace, resp *http.Response, respBody []byte, err error) {
+	duration := time.Since(sent)
+	timings := trace.result()
+	c.recordResponseMeta(req, resp, duration, timings)
+	c.dumpRequest(req, reqBody, resp, respBody, duration, timings, err)
+	if c.OnRequest == nil {
+		return
+	}
+	event := RequestEvent{
+		RequestID: req.Header.Get(RequestIDHeader),
+		Method:    req.Method,
+		URL:       req.URL.String(),
+		Duration:  duration,
+		Timings:   timings,
+		Err:       err,
+	}
+	if resp != nil {
+		event.StatusCode = resp.StatusCode
+	}
+	c.OnRequest(event)
+}
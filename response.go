@@ -0,0 +1,35 @@
+package bitpin
+
+import (
+	"net/http"
+
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// RawResponse holds the untouched result of an API call. It is returned by the
+// raw request helpers so callers can work with payloads and headers that the
+// SDK types do not model.
+type RawResponse struct {
+	// StatusCode is the HTTP status code returned by the API.
+	StatusCode int
+
+	// Header contains the response headers as received from the API.
+	Header http.Header
+
+	// Body is the raw response payload, usually JSON.
+	Body []byte
+
+	// RequestID is the ID the request was sent with; see RequestIDHeader.
+	RequestID string
+}
+
+// JSON unmarshals the raw response body into v. Like the typed methods, it
+// tolerates numbers sent as strings and vice versa; see utils.UnmarshalLenient.
+func (r *RawResponse) JSON(v interface{}) error {
+	return u.UnmarshalLenient(r.Body, v)
+}
+
+// String returns the response body as a string.
+func (r *RawResponse) String() string {
+	return string(r.Body)
+}
@@ -0,0 +1,91 @@
+package bitpin
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ResponseMeta describes the HTTP exchange behind a call: its status, headers,
+// rate-limit budget and duration. It is filled in by the client for calls made
+// with a context returned by ContextWithResponseMeta.
+type ResponseMeta struct {
+	// RequestID is the ID sent in the RequestIDHeader header.
+	RequestID string
+
+	// StatusCode is the HTTP status code of the response, or zero if no
+	// response was received.
+	StatusCode int
+
+	// Header holds the headers of the response. It is nil if no response was
+	// received.
+	Header http.Header
+
+	// RateLimit is the budget reported by the rate-limit headers of the
+	// response. Its Known field is false if the response carried none.
+	RateLimit RateLimitStatus
+
+	// Duration is the time from sending the request until its response was
+	// read.
+	Duration time.Duration
+
+	// Timings breaks Duration down into network phases. It is nil unless
+	// TraceRequests is enabled.
+	Timings *RequestTimings
+
+	// Requests is the number of requests the call sent. Helpers such as
+	// CreateOrder may send several, for example to fetch markets first, and a
+	// request is sent again after a token renewal. The other fields describe
+	// the last of them.
+	Requests int
+}
+
+// responseMetaKey is the context key of ContextWithResponseMeta.
+type responseMetaKey struct{}
+
+// responseMetaSink guards a ResponseMeta written by concurrent requests that
+// share a context.
+type responseMetaSink struct {
+	mu   sync.Mutex
+	meta *ResponseMeta
+}
+
+// ContextWithResponseMeta returns a copy of ctx that makes the client record
+// the status, headers, rate-limit budget and duration of the requests made
+// with it into meta. meta is written when each request completes, so it should
+// be read after the call returns.
+//
+// Example:
+//
+//	var meta bitpin.ResponseMeta
+//	ctx := bitpin.ContextWithResponseMeta(context.Background(), &meta)
+//	page, err := client.ListOrders(ctx, t.GetOrdersHistoryParams{}, t.ListOptions{})
+//	log.Printf("status %d in %s, %d requests left", meta.StatusCode, meta.Duration, meta.RateLimit.Remaining)
+func ContextWithResponseMeta(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, &responseMetaSink{meta: meta})
+}
+
+// recordResponseMeta fills in the ResponseMeta carried by the context of req,
+// if any. resp is nil if no response was received.
+func (c *Client) recordResponseMeta(req *http.Request, resp *http.Response, duration time.Duration, timings *RequestTimings) {
+	sink, ok := req.Context().Value(responseMetaKey{}).(*responseMetaSink)
+	if !ok || sink.meta == nil {
+		return
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	meta := sink.meta
+	*meta = ResponseMeta{
+		RequestID: req.Header.Get(RequestIDHeader),
+		Duration:  duration,
+		Timings:   timings,
+		Requests:  meta.Requests + 1,
+	}
+	if resp != nil {
+		meta.StatusCode = resp.StatusCode
+		meta.Header = resp.Header
+		meta.RateLimit, _ = parseRateLimit(c.clock, resp.StatusCode, resp.Header)
+	}
+}
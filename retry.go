@@ -0,0 +1,76 @@
+package bitpin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// DefaultRetryAttempts is the default number of attempts, including the first
+// one, of a request retried by RetryOptions.
+const DefaultRetryAttempts = 3
+
+// DefaultRetryBackoff is the default delay between retried requests.
+var DefaultRetryBackoff Backoff = ExponentialBackoff{
+	Initial: 200 * time.Millisecond,
+	Max:     5 * time.Second,
+	Jitter:  0.2,
+}
+
+// RetryOptions configures automatic retries of requests that fail with a
+// retryable error (see IsRetryable), such as a network error, a 5xx response
+// or 429 Too Many Requests. Only GET, HEAD and OPTIONS requests are retried,
+// since sending an order or a cancel twice may not be safe.
+type RetryOptions struct {
+	// MaxAttempts is the number of attempts including the first one.
+	// Defaults to DefaultRetryAttempts.
+	MaxAttempts int
+
+	// Backoff decides the delay before every retry. The Retry-After of a 429
+	// Too Many Requests response is waited instead when it is longer.
+	// Defaults to DefaultRetryBackoff.
+	Backoff Backoff
+}
+
+// newRetryOptions applies the defaults to opts. It returns nil if retries are
+// disabled.
+func newRetryOptions(opts *RetryOptions) *RetryOptions {
+	if opts == nil {
+		return nil
+	}
+	retry := *opts
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = DefaultRetryAttempts
+	}
+	if retry.Backoff == nil {
+		retry.Backoff = DefaultRetryBackoff
+	}
+	return &retry
+}
+
+// sendRetrying sends a request with sendRaw and retries it as configured by
+// ClientOptions.Retry.
+func (c *Client) sendRetrying(ctx context.Context, method string, url string, auth bool, body interface{}, header http.Header) (*RawResponse, string, error) {
+	raw, used, err := c.sendRaw(ctx, method, url, auth, body, header)
+	if c.retry == nil || !idempotent(method) {
+		return raw, used, err
+	}
+
+	for attempt := 1; attempt < c.retry.MaxAttempts && err != nil && IsRetryable(err); attempt++ {
+		if ctx.Err() != nil || errors.Is(err, ErrCircuitOpen) || !replayable(body) {
+			break
+		}
+		delay := c.retry.Backoff.NextDelay(attempt, err)
+		if raw != nil && raw.StatusCode == http.StatusTooManyRequests {
+			delay = max(delay, c.RateLimitStatus().RetryAfter)
+		}
+		select {
+		case <-ctx.Done():
+			return raw, used, err
+		case <-c.after(delay):
+		}
+		raw, used, err = c.sendRaw(ctx, method, url, auth, body, header)
+	}
+	return raw, used, err
+}
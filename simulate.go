@@ -0,0 +1,185 @@
+package bitpin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// SimulateOptions configures an order simulation.
+type SimulateOptions struct {
+	// FeeRate is the taker fee of the account, as a fraction (0.002 means
+	// 0.2%). The API does not report fee tiers, so fees are zero unless it is
+	// set.
+	FeeRate float64
+}
+
+// Simulation is the estimated outcome of an order executed against the
+// current order book.
+type Simulation struct {
+	// Order is the simulated order.
+	Order t.CreateOrderParams
+
+	// Fill is the estimated immediate fill. For limit orders only levels at or
+	// better than the limit price are consumed.
+	Fill t.FillEstimate
+
+	// Slippage is the relative difference between the average fill price and
+	// the best price, as a fraction.
+	Slippage float64
+
+	// Fee is the estimated fee, charged on the received asset.
+	Fee float64
+
+	// FeeCurrency is the asset the fee is charged in: the base asset for buys
+	// and the quote asset for sells.
+	FeeCurrency string
+
+	// Received is the amount of the received asset after fees: base for buys
+	// and quote for sells.
+	Received float64
+
+	// Spent is the amount of the spent asset: quote for buys and base for
+	// sells.
+	Spent float64
+
+	// Unfilled is the part of the order that cannot fill immediately, in the
+	// unit the order was given in (base, or quote for orders given by
+	// QuoteAmount). For limit orders it would rest on the book; market orders
+	// would exhaust the visible book.
+	Unfilled float64
+}
+
+// SimulateOrder estimates the immediate execution of an order against an order
+// book, without placing it. Market orders and marketable limit orders are
+// supported; stop prices are ignored, the order is simulated as if triggered.
+//
+// Returns an error if the order has no side, no positive BaseAmount or
+// QuoteAmount, a limit order has no valid price, or the book is malformed.
+//
+// Example:
+//
+//	book, _ := client.GetOrderBook("BTC_USDT")
+//	sim, err := bitpin.SimulateOrder(book, t.CreateOrderParams{
+//	    Symbol: "BTC_USDT", Type: "market", Side: "buy", BaseAmount: "0.5",
+//	}, bitpin.SimulateOptions{FeeRate: 0.002})
+func SimulateOrder(book *t.OrderBook, params t.CreateOrderParams, opts SimulateOptions) (*Simulation, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return &GoBitpinError{Message: "cannot simulate order: " + fmt.Sprintf(format, args...)}
+	}
+
+	side := t.OrderSide(strings.ToLower(string(params.Side)))
+	if !side.Valid() {
+		return nil, invalid("invalid side %q", params.Side)
+	}
+
+	byQuote := params.BaseAmount == ""
+	amountText := params.BaseAmount
+	if byQuote {
+		amountText = params.QuoteAmount
+	}
+	amount, err := strconv.ParseFloat(amountText, 64)
+	if err != nil || amount <= 0 {
+		return nil, invalid("a positive base_amount or quote_amount is required")
+	}
+
+	if !strings.Contains(strings.ToLower(string(params.Type)), string(t.TypeMarket)) {
+		limit, err := strconv.ParseFloat(params.Price, 64)
+		if err != nil || limit <= 0 {
+			return nil, invalid("invalid limit price %q", params.Price)
+		}
+		book = marketableBook(book, side, limit)
+	}
+
+	var est t.FillEstimate
+	if byQuote {
+		est, err = book.FillByQuote(oppositeBookSide(side), amount)
+	} else {
+		est, err = book.FillByBase(oppositeBookSide(side), amount)
+	}
+	if err != nil {
+		return nil, &GoBitpinError{Message: "invalid order book", Err: err}
+	}
+
+	sim := &Simulation{Order: params, Fill: est, Slippage: est.Slippage()}
+	filled := est.BaseAmount
+	if byQuote {
+		filled = est.QuoteAmount
+	}
+	sim.Unfilled = max(amount-filled, 0)
+	if est.Complete {
+		sim.Unfilled = 0
+	}
+
+	base, quote, _ := strings.Cut(u.CanonicalSymbol(params.Symbol), "_")
+	if side == t.SideBuy {
+		sim.Fee = est.BaseAmount * opts.FeeRate
+		sim.FeeCurrency = base
+		sim.Received = est.BaseAmount - sim.Fee
+		sim.Spent = est.QuoteAmount
+	} else {
+		sim.Fee = est.QuoteAmount * opts.FeeRate
+		sim.FeeCurrency = quote
+		sim.Received = est.QuoteAmount - sim.Fee
+		sim.Spent = est.BaseAmount
+	}
+	return sim, nil
+}
+
+// Simulate fetches the current order book of the order's market and estimates
+// the fill price, slippage and fees of the order; see SimulateOrder. Nothing is
+// submitted.
+//
+// Example:
+//
+//	sim, err := client.Simulate(t.CreateOrderParams{
+//	    Symbol: "BTC_USDT", Type: "market", Side: "sell", BaseAmount: "2",
+//	}, bitpin.SimulateOptions{FeeRate: 0.002})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if sim.Slippage > 0.005 || sim.Unfilled > 0 {
+//	    log.Printf("too thin: avg %.2f, slippage %.2f%%", sim.Fill.AveragePrice, sim.Slippage*100)
+//	}
+func (c *Client) Simulate(params t.CreateOrderParams, opts SimulateOptions) (*Simulation, error) {
+	book, err := c.GetOrderBook(params.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	return SimulateOrder(book, params, opts)
+}
+
+// marketableBook returns a copy of the book whose side opposite to the order
+// only holds the levels a limit order at the given price would trade with.
+func marketableBook(book *t.OrderBook, side t.OrderSide, limit float64) *t.OrderBook {
+	filtered := &t.OrderBook{Asks: book.Asks, Bids: book.Bids}
+	levels := book.Asks
+	if oppositeBookSide(side) == t.Bids {
+		levels = book.Bids
+	}
+
+	n := 0
+	for _, entry := range levels {
+		if len(entry) == 0 {
+			break
+		}
+		price, err := strconv.ParseFloat(entry[0], 64)
+		if err != nil {
+			break
+		}
+		if (side == t.SideBuy && price > limit) || (side == t.SideSell && price < limit) {
+			break
+		}
+		n++
+	}
+
+	if oppositeBookSide(side) == t.Bids {
+		filtered.Bids = levels[:n]
+	} else {
+		filtered.Asks = levels[:n]
+	}
+	return filtered
+}
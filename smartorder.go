@@ -0,0 +1,306 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// SmartOrderStrategy identifies the execution strategy chosen by SmartOrder.
+type SmartOrderStrategy string
+
+const (
+	// SmartOrderMarket executes the full amount with a single market order.
+	SmartOrderMarket SmartOrderStrategy = "market"
+
+	// SmartOrderLimit places a limit order at the touch (best opposite price).
+	SmartOrderLimit SmartOrderStrategy = "limit"
+
+	// SmartOrderSliced splits the amount into several market orders that are
+	// submitted one after another.
+	SmartOrderSliced SmartOrderStrategy = "sliced"
+)
+
+// SmartOrderParams describes an order to be routed by SmartOrder.
+type SmartOrderParams struct {
+	// Symbol is the trading pair, such as "BTC_USDT".
+	Symbol string
+
+	// Side is either t.SideBuy or t.SideSell.
+	Side t.OrderSide
+
+	// BaseAmount is the amount of the base currency to trade. Slices are
+	// rounded down to the number of decimals used here.
+	BaseAmount string
+
+	// MaxSlippage is the highest accepted difference between the average fill
+	// price and the touch price, as a fraction (0.005 means 0.5%).
+	MaxSlippage float64
+
+	// Slices is the number of orders used for sliced execution. Defaults to 5.
+	Slices int
+
+	// SliceInterval is the delay between two slices. Defaults to one second.
+	SliceInterval time.Duration
+
+	// Identifier is an optional client identifier. Sliced orders get a
+	// "-<n>" suffix.
+	Identifier string
+}
+
+// SmartOrderResult describes how SmartOrder executed an order.
+type SmartOrderResult struct {
+	// Strategy is the execution strategy that was chosen.
+	Strategy SmartOrderStrategy
+
+	// Orders contains every order submitted, in submission order.
+	Orders []*t.OrderStatus
+
+	// TouchPrice is the best opposite price at decision time.
+	TouchPrice float64
+
+	// EstimatedPrice is the estimated average fill price of the full amount at
+	// decision time. It is zero if the book could not absorb the amount.
+	EstimatedPrice float64
+
+	// EstimatedSlippage is the estimated slippage of the full amount at decision
+	// time, as a fraction of the touch price.
+	EstimatedSlippage float64
+}
+
+// SmartOrder executes an order with the strategy that best fits the current
+// order book and the slippage constraint.
+//
+// Parameters:
+//   - ctx: Controls cancellation, including the wait between slices.
+//   - params: The order and the execution constraints.
+//
+// Returns:
+//   - A pointer to a `SmartOrderResult` describing the chosen strategy and the
+//     submitted orders. It is returned even if a later slice fails.
+//   - An error if the order book cannot be fetched or an order is rejected.
+//
+// Behavior:
+//   - The order book is walked to estimate the average fill price of the full
+//     amount. If the book can absorb it within `MaxSlippage`, a single market
+//     order is sent.
+//   - Otherwise, if one slice of the amount fits within `MaxSlippage`, the
+//     amount is executed as `Slices` market orders. The book is re-checked
+//     before every slice; when a slice no longer fits, the remainder is placed
+//     as a limit order at the touch price.
+//   - Otherwise a single limit order at the touch price is placed.
+//
+// Example:
+//
+//	result, err := client.SmartOrder(ctx, bitpin.SmartOrderParams{
+//	    Symbol:      "BTC_USDT",
+//	    Side:        "buy",
+//	    BaseAmount:  "0.5",
+//	    MaxSlippage: 0.002,
+//	})
+//	if err != nil {
+//	    log.Fatalf("Smart order failed: %v", err)
+//	}
+//	log.Printf("executed via %s with %d orders", result.Strategy, len(result.Orders))
+func (c *Client) SmartOrder(ctx context.Context, params SmartOrderParams) (*SmartOrderResult, error) {
+	amount, err := strconv.ParseFloat(params.BaseAmount, 64)
+	if err != nil || amount <= 0 {
+		return nil, &GoBitpinError{
+			Message: fmt.Sprintf("invalid base amount %q", params.BaseAmount),
+			Err:     err,
+		}
+	}
+	if params.Slices <= 0 {
+		params.Slices = 5
+	}
+	if params.SliceInterval <= 0 {
+		params.SliceInterval = time.Second
+	}
+	decimals := decimalPlaces(params.BaseAmount)
+
+	book, err := c.GetOrderBookWithContext(ctx, params.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	full, err := walkBook(book, params.Side, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SmartOrderResult{
+		TouchPrice:        full.BestPrice,
+		EstimatedSlippage: slippageOf(full),
+	}
+	if full.Complete {
+		result.EstimatedPrice = full.AveragePrice
+	}
+
+	if slippageOf(full) <= params.MaxSlippage {
+		result.Strategy = SmartOrderMarket
+		order, err := c.CreateOrderWithContext(ctx, t.CreateOrderParams{
+			Symbol:     params.Symbol,
+			Type:       t.TypeMarket,
+			Side:       params.Side,
+			BaseAmount: params.BaseAmount,
+			Identifier: params.Identifier,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Orders = append(result.Orders, order)
+		return result, nil
+	}
+
+	slice := truncateAmount(amount/float64(params.Slices), decimals)
+	if slice > 0 {
+		probe, err := walkBook(book, params.Side, slice)
+		if err != nil {
+			return nil, err
+		}
+		if slippageOf(probe) <= params.MaxSlippage {
+			result.Strategy = SmartOrderSliced
+			return result, c.executeSlices(ctx, params, amount, slice, decimals, result)
+		}
+	}
+
+	result.Strategy = SmartOrderLimit
+	order, err := c.CreateOrderWithContext(ctx, t.CreateOrderParams{
+		Symbol:     params.Symbol,
+		Type:       t.TypeLimit,
+		Side:       params.Side,
+		Price:      touchPrice(book, params.Side),
+		BaseAmount: params.BaseAmount,
+		Identifier: params.Identifier,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Orders = append(result.Orders, order)
+	return result, nil
+}
+
+// executeSlices submits the sliced market orders for SmartOrder.
+func (c *Client) executeSlices(ctx context.Context, params SmartOrderParams, amount, slice float64, decimals int, result *SmartOrderResult) error {
+	remaining := amount
+	for n := 1; remaining > 0; n++ {
+		if n > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-c.after(params.SliceInterval):
+			}
+		}
+
+		size := slice
+		if n >= params.Slices || size > remaining {
+			size = remaining
+		}
+		sizeStr := formatAmount(size, decimals)
+
+		book, err := c.GetOrderBookWithContext(ctx, params.Symbol)
+		if err != nil {
+			return err
+		}
+		estimate, err := walkBook(book, params.Side, size)
+		if err != nil {
+			return err
+		}
+
+		order := t.CreateOrderParams{
+			Symbol:     params.Symbol,
+			Type:       t.TypeMarket,
+			Side:       params.Side,
+			BaseAmount: sizeStr,
+		}
+		if params.Identifier != "" {
+			order.Identifier = fmt.Sprintf("%s-%d", params.Identifier, n)
+		}
+
+		last := slippageOf(estimate) > params.MaxSlippage
+		if last {
+			// The book moved against us: rest the remainder at the touch.
+			order.Type = t.TypeLimit
+			order.Price = touchPrice(book, params.Side)
+			order.BaseAmount = formatAmount(remaining, decimals)
+		}
+
+		status, err := c.CreateOrderWithContext(ctx, order)
+		if err != nil {
+			return err
+		}
+		result.Orders = append(result.Orders, status)
+
+		if last {
+			return nil
+		}
+		remaining = truncateAmount(remaining-size, decimals)
+	}
+	return nil
+}
+
+// walkBook estimates the fill of a market order of the given base amount by
+// consuming the opposite side of the book level by level.
+func walkBook(book *t.OrderBook, side t.OrderSide, amount float64) (t.FillEstimate, error) {
+	est, err := book.FillByBase(oppositeBookSide(side), amount)
+	if err != nil {
+		return est, &GoBitpinError{Message: "invalid order book", Err: err}
+	}
+	return est, nil
+}
+
+// slippageOf returns the estimated slippage of a fill, or +Inf if the book
+// cannot absorb the full amount.
+func slippageOf(est t.FillEstimate) float64 {
+	if !est.Complete {
+		return math.Inf(1)
+	}
+	return est.Slippage()
+}
+
+// oppositeBookSide returns the side of the book a marketable order of the
+// given order side trades against.
+func oppositeBookSide(side t.OrderSide) t.BookSide {
+	if strings.EqualFold(string(side), string(t.SideSell)) {
+		return t.Bids
+	}
+	return t.Asks
+}
+
+// touchPrice returns the best price on the opposite side of the book, which is
+// the price a marketable order of the given side trades at first.
+func touchPrice(book *t.OrderBook, side t.OrderSide) string {
+	levels := book.Asks
+	if oppositeBookSide(side) == t.Bids {
+		levels = book.Bids
+	}
+	if len(levels) == 0 || len(levels[0]) == 0 {
+		return ""
+	}
+	return levels[0][0]
+}
+
+// decimalPlaces returns the number of digits after the decimal point.
+func decimalPlaces(amount string) int {
+	_, frac, ok := strings.Cut(amount, ".")
+	if !ok {
+		return 0
+	}
+	return len(frac)
+}
+
+// truncateAmount rounds an amount down to the given number of decimals.
+func truncateAmount(amount float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	return math.Floor(amount*scale+1e-9) / scale
+}
+
+// formatAmount formats an amount with a fixed number of decimals.
+func formatAmount(amount float64, decimals int) string {
+	return strconv.FormatFloat(truncateAmount(amount, decimals), 'f', decimals, 64)
+}
@@ -0,0 +1,223 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// File is a Store backed by a directory. Every key is stored in its own file
+// under "kv/", and every log is a file under "logs/" with one base64-encoded
+// record per line. Values are written atomically and logs are synced after
+// every append.
+type File struct {
+	dir string
+
+	mu   sync.Mutex
+	seqs map[string]uint64
+}
+
+// NewFile creates a file store rooted at dir, creating the directory if needed.
+func NewFile(dir string) (*File, error) {
+	for _, sub := range []string{"kv", "logs"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return nil, fmt.Errorf("store: failed to create %s: %w", sub, err)
+		}
+	}
+	return &File{dir: dir, seqs: make(map[string]uint64)}, nil
+}
+
+// Get returns the value stored under key.
+func (f *File) Get(_ context.Context, key string) ([]byte, error) {
+	value, err := os.ReadFile(f.keyPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: failed to read %q: %w", key, err)
+	}
+	return value, nil
+}
+
+// Set stores value under key. The value is written to a temporary file that
+// is renamed into place, so readers never observe a partial value.
+func (f *File) Set(_ context.Context, key string, value []byte) error {
+	tmp, err := os.CreateTemp(filepath.Join(f.dir, "kv"), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("store: failed to write %q: %w", key, err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(value); err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.keyPath(key))
+	}
+	if err != nil {
+		return fmt.Errorf("store: failed to write %q: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key.
+func (f *File) Delete(_ context.Context, key string) error {
+	err := os.Remove(f.keyPath(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("store: failed to delete %q: %w", key, err)
+	}
+	return nil
+}
+
+// Increment adds delta to the counter stored under key. Increments are atomic
+// within the process; like the logs, counters must not be shared between
+// processes.
+func (f *File) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, err := f.Get(ctx, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return 0, err
+	}
+	n, err := parseCounter(key, value)
+	if err != nil {
+		return 0, err
+	}
+	n += delta
+	if err := f.Set(ctx, key, []byte(strconv.FormatUint(n, 10))); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Append adds a record to the named log.
+func (f *File) Append(_ context.Context, log string, record []byte) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	seq, ok := f.seqs[log]
+	if !ok {
+		var err error
+		if seq, err = f.recover(log); err != nil {
+			return 0, err
+		}
+	}
+
+	file, err := os.OpenFile(f.logPath(log), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("store: failed to open log %q: %w", log, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	line := base64.StdEncoding.EncodeToString(record) + "\n"
+	if _, err := file.WriteString(line); err != nil {
+		return 0, fmt.Errorf("store: failed to append to log %q: %w", log, err)
+	}
+	if err := file.Sync(); err != nil {
+		return 0, fmt.Errorf("store: failed to sync log %q: %w", log, err)
+	}
+
+	seq++
+	f.seqs[log] = seq
+	return seq, nil
+}
+
+// Read calls fn for every record after the given sequence number.
+func (f *File) Read(_ context.Context, log string, after uint64, fn func(seq uint64, record []byte) error) error {
+	file, err := os.Open(f.logPath(log))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("store: failed to open log %q: %w", log, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := bufio.NewReader(file)
+	var seq uint64
+	for {
+		line, err := reader.ReadString('\n')
+		if errors.Is(err, io.EOF) {
+			// A line without a trailing newline is a torn write and is ignored.
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("store: failed to read log %q: %w", log, err)
+		}
+
+		seq++
+		if seq <= after {
+			continue
+		}
+		record, err := base64.StdEncoding.DecodeString(line[:len(line)-1])
+		if err != nil {
+			return fmt.Errorf("store: corrupt record %d in log %q: %w", seq, log, err)
+		}
+		if err := fn(seq, record); err != nil {
+			return err
+		}
+	}
+}
+
+// recover counts the records of a log and truncates a torn last line left
+// behind by a crash, so that the next append starts on a fresh line.
+func (f *File) recover(log string) (uint64, error) {
+	file, err := os.OpenFile(f.logPath(log), os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("store: failed to open log %q: %w", log, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var seq uint64
+	var size int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("store: failed to read log %q: %w", log, err)
+		}
+		seq++
+		size += int64(len(line))
+	}
+
+	if err := file.Truncate(size); err != nil {
+		return 0, fmt.Errorf("store: failed to repair log %q: %w", log, err)
+	}
+	return seq, nil
+}
+
+// keyPath returns the file that holds a key. Keys are encoded so that any
+// string is a valid file name.
+func (f *File) keyPath(key string) string {
+	return filepath.Join(f.dir, "kv", base64.RawURLEncoding.EncodeToString([]byte(key)))
+}
+
+// logPath returns the file that holds a log.
+func (f *File) logPath(log string) string {
+	return filepath.Join(f.dir, "logs", base64.RawURLEncoding.EncodeToString([]byte(log))+".log")
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// Memory is an in-memory Store. Its contents are lost when the process exits.
+type Memory struct {
+	mu   sync.RWMutex
+	kv   map[string][]byte
+	logs map[string][][]byte
+}
+
+// NewMemory creates an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{
+		kv:   make(map[string][]byte),
+		logs: make(map[string][][]byte),
+	}
+}
+
+// Get returns the value stored under key.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	value, ok := m.kv[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Set stores value under key.
+func (m *Memory) Set(_ context.Context, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.kv[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete removes key.
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.kv, key)
+	return nil
+}
+
+// Increment adds delta to the counter stored under key.
+func (m *Memory) Increment(_ context.Context, key string, delta uint64) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n, err := parseCounter(key, m.kv[key])
+	if err != nil {
+		return 0, err
+	}
+	n += delta
+	m.kv[key] = []byte(strconv.FormatUint(n, 10))
+	return n, nil
+}
+
+// Append adds a record to the named log.
+func (m *Memory) Append(_ context.Context, log string, record []byte) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs[log] = append(m.logs[log], append([]byte(nil), record...))
+	return uint64(len(m.logs[log])), nil
+}
+
+// Read calls fn for every record after the given sequence number.
+func (m *Memory) Read(_ context.Context, log string, after uint64, fn func(seq uint64, record []byte) error) error {
+	m.mu.RLock()
+	records := m.logs[log]
+	m.mu.RUnlock()
+
+	for i := after; i < uint64(len(records)); i++ {
+		if err := fn(i+1, records[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
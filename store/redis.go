@@ -0,0 +1,305 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisOptions configures a Redis store.
+type RedisOptions struct {
+	// Addr is the "host:port" address of the Redis server.
+	Addr string
+
+	// Password is sent with AUTH when not empty.
+	Password string
+
+	// DB is the database number selected after connecting.
+	DB int
+
+	// Prefix is prepended to every key and log name. Defaults to "bitpin:".
+	Prefix string
+
+	// DialTimeout bounds connection setup. Defaults to five seconds.
+	DialTimeout time.Duration
+
+	// IOTimeout bounds each command when the context has no earlier
+	// deadline, so an unresponsive server cannot block forever. Defaults to
+	// five seconds.
+	IOTimeout time.Duration
+}
+
+// Redis is a Store backed by a Redis server. Keys are stored as strings under
+// "<prefix>kv:<key>" and logs as lists under "<prefix>log:<name>". It speaks
+// the Redis protocol directly over a single connection, which is re-established
+// after network errors.
+type Redis struct {
+	opts RedisOptions
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// redisError is an error reply sent by the server.
+type redisError string
+
+func (e redisError) Error() string {
+	return "store: redis: " + string(e)
+}
+
+// NewRedis creates a Redis store. The connection is opened lazily on first use.
+func NewRedis(opts RedisOptions) *Redis {
+	if opts.Prefix == "" {
+		opts.Prefix = "bitpin:"
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+	if opts.IOTimeout <= 0 {
+		opts.IOTimeout = 5 * time.Second
+	}
+	return &Redis{opts: opts}
+}
+
+// Get returns the value stored under key.
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := r.do(ctx, "GET", r.opts.Prefix+"kv:"+key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrNotFound
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("store: redis: unexpected GET reply %T", reply)
+	}
+	return value, nil
+}
+
+// Set stores value under key.
+func (r *Redis) Set(ctx context.Context, key string, value []byte) error {
+	_, err := r.do(ctx, "SET", r.opts.Prefix+"kv:"+key, value)
+	return err
+}
+
+// Delete removes key.
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	_, err := r.do(ctx, "DEL", r.opts.Prefix+"kv:"+key)
+	return err
+}
+
+// Increment adds delta to the counter stored under key. INCRBY is atomic on
+// the server.
+func (r *Redis) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	reply, err := r.do(ctx, "INCRBY", r.opts.Prefix+"kv:"+key, strconv.FormatUint(delta, 10))
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("store: redis: unexpected INCRBY reply %T", reply)
+	}
+	return uint64(n), nil
+}
+
+// Append adds a record to the named log. RPUSH is atomic on the server, so
+// sequence numbers are unique across all processes sharing the log.
+func (r *Redis) Append(ctx context.Context, log string, record []byte) (uint64, error) {
+	reply, err := r.do(ctx, "RPUSH", r.opts.Prefix+"log:"+log, record)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("store: redis: unexpected RPUSH reply %T", reply)
+	}
+	return uint64(n), nil
+}
+
+// Read calls fn for every record after the given sequence number.
+func (r *Redis) Read(ctx context.Context, log string, after uint64, fn func(seq uint64, record []byte) error) error {
+	reply, err := r.do(ctx, "LRANGE", r.opts.Prefix+"log:"+log, strconv.FormatUint(after, 10), "-1")
+	if err != nil {
+		return err
+	}
+	items, ok := reply.([]interface{})
+	if !ok {
+		return fmt.Errorf("store: redis: unexpected LRANGE reply %T", reply)
+	}
+	for i, item := range items {
+		record, _ := item.([]byte)
+		if err := fn(after+uint64(i)+1, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the connection to the server.
+func (r *Redis) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn == nil {
+		return nil
+	}
+	err := r.conn.Close()
+	r.conn, r.rd = nil, nil
+	return err
+}
+
+// do sends a command and reads its reply. Arguments may be strings or byte
+// slices. Network errors close the connection so the next call reconnects.
+func (r *Redis) do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		if err := r.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := r.roundTrip(ctx, args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		r.disconnect()
+	}
+	return reply, err
+}
+
+// connect dials the server and runs AUTH and SELECT as configured.
+func (r *Redis) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: r.opts.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.opts.Addr)
+	if err != nil {
+		return fmt.Errorf("store: redis: failed to connect: %w", err)
+	}
+	r.conn, r.rd = conn, bufio.NewReader(conn)
+
+	if r.opts.Password != "" {
+		if _, err := r.roundTrip(ctx, "AUTH", r.opts.Password); err != nil {
+			r.disconnect()
+			return err
+		}
+	}
+	if r.opts.DB != 0 {
+		if _, err := r.roundTrip(ctx, "SELECT", strconv.Itoa(r.opts.DB)); err != nil {
+			r.disconnect()
+			return err
+		}
+	}
+	return nil
+}
+
+// disconnect drops the current connection. The caller must hold r.mu.
+func (r *Redis) disconnect() {
+	_ = r.conn.Close()
+	r.conn, r.rd = nil, nil
+}
+
+// roundTrip writes one command and reads one reply on the open connection.
+// It gives up at the deadline of ctx or after IOTimeout, whichever is first,
+// and as soon as ctx is cancelled.
+func (r *Redis) roundTrip(ctx context.Context, args ...interface{}) (reply interface{}, err error) {
+	deadline := time.Now().Add(r.opts.IOTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := r.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	// Cancellation moves the deadline to the past, which unblocks the I/O.
+	conn, cancelled := r.conn, make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(cancelled)
+		_ = conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer func() {
+		if !stop() {
+			<-cancelled
+			if err != nil {
+				err = fmt.Errorf("store: redis: %w", ctx.Err())
+			}
+		}
+	}()
+
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		var b []byte
+		switch v := arg.(type) {
+		case string:
+			b = []byte(v)
+		case []byte:
+			b = v
+		default:
+			return nil, fmt.Errorf("store: redis: unsupported argument %T", arg)
+		}
+		buf = append(buf, "$"+strconv.Itoa(len(b))+"\r\n"...)
+		buf = append(buf, b...)
+		buf = append(buf, "\r\n"...)
+	}
+	if _, err := r.conn.Write(buf); err != nil {
+		return nil, fmt.Errorf("store: redis: failed to send command: %w", err)
+	}
+	return r.readReply()
+}
+
+// readReply parses a single RESP reply.
+func (r *Redis) readReply() (interface{}, error) {
+	line, err := r.rd.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("store: redis: failed to read reply: %w", err)
+	}
+	if len(line) < 3 {
+		return nil, fmt.Errorf("store: redis: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("store: redis: malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r.rd, data); err != nil {
+			return nil, fmt.Errorf("store: redis: failed to read reply: %w", err)
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("store: redis: malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = r.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("store: redis: unknown reply type %q", kind)
+	}
+}
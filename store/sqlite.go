@@ -0,0 +1,175 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// busyAttempts is the number of times a write is tried while the database is
+// locked by another writer.
+const busyAttempts = 10
+
+// SQLite is a Store backed by a SQLite database. It works with any
+// database/sql driver for SQLite, which the application imports and opens
+// itself, for example:
+//
+//	import _ "modernc.org/sqlite"
+//
+//	db, err := sql.Open("sqlite", "bitpin.db")
+//	st, err := store.NewSQLite(ctx, db)
+//
+// Two tables are used: "bitpin_kv" for keys and "bitpin_log" for logs. They
+// are created if they do not exist. SQLite 3.35 or later is required, since
+// appends and increments use RETURNING.
+type SQLite struct {
+	db *sql.DB
+}
+
+// NewSQLite creates a SQLite store on an open database and creates its tables.
+func NewSQLite(ctx context.Context, db *sql.DB) (*SQLite, error) {
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS bitpin_kv (
+			key   TEXT PRIMARY KEY,
+			value BLOB NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS bitpin_log (
+			name   TEXT    NOT NULL,
+			seq    INTEGER NOT NULL,
+			record BLOB    NOT NULL,
+			PRIMARY KEY (name, seq)
+		)`,
+	}
+	for _, stmt := range schema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("store: failed to create schema: %w", err)
+		}
+	}
+	return &SQLite{db: db}, nil
+}
+
+// Get returns the value stored under key.
+func (s *SQLite) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM bitpin_kv WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: failed to read %q: %w", key, err)
+	}
+	return value, nil
+}
+
+// Set stores value under key.
+func (s *SQLite) Set(ctx context.Context, key string, value []byte) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO bitpin_kv (key, value) VALUES (?, ?)
+		 ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+		key, value)
+	if err != nil {
+		return fmt.Errorf("store: failed to write %q: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key.
+func (s *SQLite) Delete(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM bitpin_kv WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("store: failed to delete %q: %w", key, err)
+	}
+	return nil
+}
+
+// Increment adds delta to the counter stored under key. The increment is a
+// single statement, so it is atomic across processes sharing the database.
+func (s *SQLite) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	var value string
+	err := retryBusy(ctx, func() error {
+		return s.db.QueryRowContext(ctx,
+			`INSERT INTO bitpin_kv (key, value) VALUES (?, ?)
+			 ON CONFLICT (key) DO UPDATE SET value = CAST(CAST(value AS INTEGER) + excluded.value AS TEXT)
+			 RETURNING value`,
+			key, strconv.FormatUint(delta, 10)).Scan(&value)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("store: failed to increment %q: %w", key, err)
+	}
+	return parseCounter(key, []byte(value))
+}
+
+// Append adds a record to the named log. The sequence number is assigned by
+// the insert statement itself, so concurrent appenders, including other
+// processes sharing the database, never reuse a number. Appends that find the
+// database locked by another writer are retried.
+func (s *SQLite) Append(ctx context.Context, log string, record []byte) (uint64, error) {
+	var seq uint64
+	err := retryBusy(ctx, func() error {
+		return s.db.QueryRowContext(ctx,
+			`INSERT INTO bitpin_log (name, seq, record)
+			 SELECT ?, COALESCE(MAX(seq), 0) + 1, ? FROM bitpin_log WHERE name = ?
+			 RETURNING seq`,
+			log, record, log).Scan(&seq)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("store: failed to append to log %q: %w", log, err)
+	}
+	return seq, nil
+}
+
+// Read calls fn for every record after the given sequence number.
+func (s *SQLite) Read(ctx context.Context, log string, after uint64, fn func(seq uint64, record []byte) error) error {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT seq, record FROM bitpin_log WHERE name = ? AND seq > ? ORDER BY seq`, log, after)
+	if err != nil {
+		return fmt.Errorf("store: failed to read log %q: %w", log, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var seq uint64
+		var record []byte
+		if err := rows.Scan(&seq, &record); err != nil {
+			return fmt.Errorf("store: failed to read log %q: %w", log, err)
+		}
+		if err := fn(seq, record); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// retryBusy runs a write statement, retrying with a growing delay while the
+// database is locked by another connection or process.
+func retryBusy(ctx context.Context, write func() error) error {
+	delay := 5 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if err == nil || attempt == busyAttempts || !isBusy(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, time.Second)
+	}
+}
+
+// isBusy reports whether err is SQLITE_BUSY or SQLITE_LOCKED. Drivers report
+// these codes in their own error types, but all of them include the message of
+// SQLite.
+func isBusy(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "database is locked") ||
+		strings.Contains(msg, "database table is locked") ||
+		strings.Contains(msg, "sqlite_busy") ||
+		strings.Contains(msg, "sqlite_locked")
+}
@@ -0,0 +1,61 @@
+// Package store defines the persistence interface shared by the SDK subsystems
+// that keep state between runs, such as token storage, event logs, and
+// checkpoints, together with memory, file, SQLite, and Redis implementations.
+//
+// Users configure one Store and pass it to every component that needs
+// persistence, so all state ends up in the same place.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrNotFound is returned by Get when a key does not exist.
+var ErrNotFound = errors.New("store: key not found")
+
+// Store is a key-value store combined with named append-only logs.
+//
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value stored under key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores value under key, replacing any previous value.
+	Set(ctx context.Context, key string, value []byte) error
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// Append adds a record to the end of the named log and returns its
+	// sequence number. Sequence numbers start at 1 and increase by one.
+	Append(ctx context.Context, log string, record []byte) (uint64, error)
+
+	// Read calls fn for every record of the named log with a sequence number
+	// greater than after, in order. Reading a missing log calls fn zero times.
+	// Read stops at the first error returned by fn.
+	Read(ctx context.Context, log string, after uint64, fn func(seq uint64, record []byte) error) error
+}
+
+// Counter is implemented by stores that can increment a number atomically.
+// All stores of this package implement it.
+type Counter interface {
+	// Increment adds delta to the counter stored under key, which starts at
+	// zero, and returns the new value. The counter is stored as a decimal
+	// number, so it can be read with Get.
+	Increment(ctx context.Context, key string, delta uint64) (uint64, error)
+}
+
+// parseCounter parses the stored value of a counter. A missing value is zero.
+func parseCounter(key string, value []byte) (uint64, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("store: %q is not a counter: %w", key, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,621 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// Stream defaults.
+const (
+	// DefaultStreamInterval is the default delay between two polls of a Stream.
+	DefaultStreamInterval = 2 * time.Second
+
+	// DefaultStreamMinBackoff is the default delay before the first reconnect
+	// attempt after the API became unreachable.
+	DefaultStreamMinBackoff = time.Second
+
+	// DefaultStreamMaxBackoff caps the delay between reconnect attempts.
+	DefaultStreamMaxBackoff = 30 * time.Second
+
+	// DefaultStreamTradesLimit is the default number of trades fetched per poll
+	// of a trades channel.
+	DefaultStreamTradesLimit = 100
+
+	// DefaultStreamBuffer is the default capacity of subscription channels.
+	DefaultStreamBuffer = 100
+
+	// DefaultStreamWorkers is the default number of goroutines that run
+	// handlers registered with OnTicker, OnTrade, OnOrderBook, OnOrderUpdate
+	// and OnFill.
+	DefaultStreamWorkers = 4
+)
+
+// ErrStreamClosed is returned when subscribing to a stream that was closed.
+var ErrStreamClosed = &GoBitpinError{Message: "stream is closed"}
+
+// StreamKind identifies the data carried by a stream channel.
+type StreamKind string
+
+const (
+	// StreamTicker delivers the ticker of a market whenever it changes.
+	StreamTicker StreamKind = "ticker"
+
+	// StreamTrades delivers every new public trade of a market, oldest first.
+	StreamTrades StreamKind = "trades"
+
+	// StreamOrderBook delivers the order book of a market whenever it changes.
+	StreamOrderBook StreamKind = "orderbook"
+
+	// StreamDepth delivers the changed levels of the order book of a market
+	// together with the whole book; see LocalOrderBook. The REST API has no
+	// sequence numbers, so the deltas are unsequenced.
+	StreamDepth StreamKind = "depth"
+
+	// StreamUserData delivers order updates and fills of the authenticated
+	// user, like SubscribeUserData. The symbol is optional.
+	StreamUserData StreamKind = "user"
+)
+
+// StreamChannel is a subscribable data feed: a kind of data for a market.
+type StreamChannel struct {
+	Kind   StreamKind
+	Symbol string
+}
+
+// String returns the channel as "kind:symbol", for example "ticker:BTC_USDT".
+func (ch StreamChannel) String() string {
+	return string(ch.Kind) + ":" + ch.Symbol
+}
+
+// StreamEvent is a single update delivered by a Stream. Exactly one of the
+// data fields is set, depending on the kind of the channel.
+type StreamEvent struct {
+	// Channel is the channel the update belongs to.
+	Channel StreamChannel
+
+	// Ticker is set for StreamTicker channels.
+	Ticker *t.Ticker
+
+	// Trade is set for StreamTrades channels.
+	Trade *t.Trade
+
+	// OrderBook is set for StreamOrderBook channels.
+	OrderBook *t.OrderBook
+
+	// Depth is set for StreamDepth channels.
+	Depth *t.OrderBookDelta
+
+	// Order and Fill are set for StreamUserData channels, one at a time.
+	Order *t.OrderStatus
+	Fill  *t.UserTrade
+
+	// Time is the local time at which the update was observed.
+	Time time.Time
+}
+
+// StreamGapReason explains why updates may have been missed.
+type StreamGapReason string
+
+const (
+	// GapDisconnected means the API was unreachable between From and To.
+	// Ticker and order book channels resume with the current state; user data
+	// channels recover the final state of orders but may miss fills.
+	GapDisconnected StreamGapReason = "disconnected"
+
+	// GapTradesSkipped means more trades happened between two polls of a trades
+	// channel than one poll returns, so some were not delivered.
+	GapTradesSkipped StreamGapReason = "trades_skipped"
+)
+
+// StreamGap reports that a channel may have missed updates.
+type StreamGap struct {
+	// Channel is the affected channel.
+	Channel StreamChannel
+
+	// Reason explains the gap.
+	Reason StreamGapReason
+
+	// From and To delimit the period in which updates may be missing.
+	From time.Time
+	To   time.Time
+
+	// LastTradeId is the last trade delivered before the gap, for trades
+	// channels. Fetch the missing trades with GetRecentTradesWithParams and
+	// SinceId, or with a Backfiller.
+	LastTradeId string
+}
+
+// StreamOptions configures a Stream.
+type StreamOptions struct {
+	// Interval is the delay between two polls. Defaults to
+	// DefaultStreamInterval.
+	Interval time.Duration
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between reconnect
+	// attempts. They default to DefaultStreamMinBackoff and
+	// DefaultStreamMaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Backoff replaces the exponential backoff between reconnect attempts.
+	// When set, MinBackoff and MaxBackoff are ignored.
+	Backoff Backoff
+
+	// TradesLimit is the number of trades fetched per poll of a trades
+	// channel. Defaults to DefaultStreamTradesLimit.
+	TradesLimit int
+
+	// Buffer is the capacity of the channels returned by Tickers, Trades,
+	// OrderBooks and UserData. Defaults to DefaultStreamBuffer.
+	Buffer int
+
+	// Workers is the number of goroutines that run handlers registered with
+	// OnTicker, OnTrade, OnOrderBook, OnOrderUpdate and OnFill. Defaults to
+	// DefaultStreamWorkers.
+	Workers int
+
+	// OnGap is invoked when a channel may have missed updates.
+	OnGap func(gap StreamGap)
+
+	// OnReconnect is invoked when the API is reachable again, after the given
+	// number of reconnect attempts and downtime. Every channel is resubscribed
+	// before it is invoked.
+	OnReconnect func(attempts int, downtime time.Duration)
+
+	// OnError is invoked for errors that do not indicate a connection problem,
+	// such as an unknown symbol. The channel stays subscribed. Errors of
+	// WatchOrderBook are reported from the handler pool, so OnError may be
+	// called concurrently.
+	OnError func(channel StreamChannel, err error)
+}
+
+// Stream delivers market data and user data updates to subscribers. Bitpin
+// offers no WebSocket API, so a Stream is not a socket client: it is a managed
+// polling loop over the REST API that shares one request per poll among all
+// subscribers of a channel.
+//
+// There is no connection to keep open. "Disconnected" means that polls fail
+// because the API is unreachable; "reconnecting" means pinging the API with
+// exponential backoff until it answers, after which polling of every active
+// channel resumes and what may have been missed is reported through OnGap.
+type Stream struct {
+	client *Client
+	opts   StreamOptions
+
+	mu          sync.Mutex
+	channels    map[StreamChannel]*streamChannel
+	nextHandler int
+	connected   bool
+	closed      bool
+
+	pool *handlerPool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// streamChannel holds the subscribers of a channel and the state needed to
+// diff consecutive polls. The poll state is only used by the stream goroutine.
+type streamChannel struct {
+	handlers map[int]func(StreamEvent)
+
+	primed    bool // the channel was polled successfully at least once
+	resync    bool // the stream reconnected since the last poll
+	ticker    *t.Ticker
+	book      *t.OrderBook
+	lastTrade string
+	user      *userDataPoller
+}
+
+// NewStream starts a stream. Subscribe to channels with Subscribe; polling
+// only covers subscribed channels.
+//
+// Parameters:
+//   - ctx: The stream stops when the context is done.
+//   - opts: Polling interval, reconnect backoff and callbacks.
+//
+// Returns:
+//   - A pointer to a running `Stream`. Call `Close` to stop it.
+//
+// Behavior:
+//   - Every interval, each subscribed channel is polled once and changes are
+//     passed to its handlers. Tickers of all markets are fetched with a single
+//     request.
+//   - A network error, timeout, 5xx or 429 response marks the stream as
+//     disconnected; there is no socket to reconnect, since the stream polls
+//     the REST API. It then pings the API with exponential backoff and jitter,
+//     or with opts.Backoff if set, until it answers, resubscribes every
+//     channel, invokes OnReconnect and reports a GapDisconnected gap for every
+//     channel.
+//   - After a resubscription, ticker and order book channels deliver their
+//     current state even if it did not change, and trades channels continue
+//     from the last delivered trade.
+//
+// Example:
+//
+//	stream := client.NewStream(ctx, bitpin.StreamOptions{
+//	    OnGap: func(gap bitpin.StreamGap) {
+//	        log.Printf("%s may have missed updates: %s", gap.Channel, gap.Reason)
+//	    },
+//	})
+//	defer stream.Close()
+//
+//	unsubscribe, err := stream.Subscribe(bitpin.StreamChannel{Kind: bitpin.StreamTrades, Symbol: "BTC_USDT"},
+//	    func(event bitpin.StreamEvent) {
+//	        log.Printf("trade %s @ %s", event.Trade.BaseAmount, event.Trade.Price)
+//	    })
+func (c *Client) NewStream(ctx context.Context, opts StreamOptions) *Stream {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultStreamInterval
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = DefaultStreamMinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(DefaultStreamMaxBackoff, opts.MinBackoff)
+	}
+	if opts.Backoff == nil {
+		// MinBackoff doubled for every previous attempt, capped at MaxBackoff,
+		// with up to 20% jitter so that many clients do not reconnect in
+		// lockstep.
+		opts.Backoff = ExponentialBackoff{Initial: opts.MinBackoff, Max: opts.MaxBackoff, Multiplier: 2, Jitter: 0.2}
+	}
+	if opts.TradesLimit <= 0 {
+		opts.TradesLimit = DefaultStreamTradesLimit
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultStreamBuffer
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultStreamWorkers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Stream{
+		client:    c,
+		opts:      opts,
+		channels:  make(map[StreamChannel]*streamChannel),
+		connected: true,
+		pool:      newHandlerPool(opts.Workers, opts.Buffer),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	go s.run(ctx)
+	return s
+}
+
+// Subscribe registers a handler for a channel. Handlers are called from the
+// stream goroutine in the order updates are observed, so they must not block.
+//
+// Returns:
+//   - A function that removes the handler. The channel is no longer polled
+//     once its last handler is removed.
+//   - An error if the channel is invalid or the stream is closed.
+func (s *Stream) Subscribe(channel StreamChannel, handler func(event StreamEvent)) (func(), error) {
+	switch channel.Kind {
+	case StreamTicker, StreamTrades, StreamOrderBook, StreamDepth:
+		if channel.Symbol == "" {
+			return nil, &GoBitpinError{Message: fmt.Sprintf("%s channel requires a symbol", channel.Kind)}
+		}
+	case StreamUserData:
+	default:
+		return nil, &GoBitpinError{Message: fmt.Sprintf("unknown stream kind %q", channel.Kind)}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrStreamClosed
+	}
+	state, ok := s.channels[channel]
+	if !ok {
+		state = &streamChannel{handlers: make(map[int]func(StreamEvent))}
+		s.channels[channel] = state
+	}
+	id := s.nextHandler
+	s.nextHandler++
+	state.handlers[id] = handler
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(state.handlers, id)
+			if len(state.handlers) == 0 && s.channels[channel] == state {
+				delete(s.channels, channel)
+			}
+		})
+	}, nil
+}
+
+// Channels returns the channels that currently have subscribers.
+func (s *Stream) Channels() []StreamChannel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	channels := make([]StreamChannel, 0, len(s.channels))
+	for channel := range s.channels {
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// Connected reports whether the last poll reached the API.
+func (s *Stream) Connected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connected
+}
+
+// Close stops the stream and waits for its goroutine and pending handlers to
+// exit.
+func (s *Stream) Close() {
+	s.once.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+		s.cancel()
+	})
+	<-s.done
+}
+
+// run polls the subscribed channels until ctx is done.
+func (s *Stream) run(ctx context.Context) {
+	defer close(s.done)
+	defer s.pool.stop()
+
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.poll(ctx); err != nil && ctx.Err() == nil {
+			s.reconnect(ctx, s.client.now(), err)
+			ticker.Reset(s.opts.Interval)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll polls every subscribed channel once. It stops at the first error that
+// indicates a connection problem and returns it.
+func (s *Stream) poll(ctx context.Context) error {
+	s.mu.Lock()
+	channels := make(map[StreamChannel]*streamChannel, len(s.channels))
+	for channel, state := range s.channels {
+		channels[channel] = state
+	}
+	s.mu.Unlock()
+
+	var tickers t.Tickers
+	for channel := range channels {
+		if channel.Kind != StreamTicker {
+			continue
+		}
+		if err := s.client.call(ctx, "GET", OpTickers, nil, false, nil, &tickers); err != nil {
+			return s.fail(channel, err)
+		}
+		break
+	}
+
+	for channel, state := range channels {
+		var err error
+		switch channel.Kind {
+		case StreamTicker:
+			s.pollTicker(channel, state, tickers)
+		case StreamTrades:
+			err = s.pollTrades(ctx, channel, state)
+		case StreamOrderBook:
+			err = s.pollOrderBook(ctx, channel, state)
+		case StreamDepth:
+			err = s.pollDepth(ctx, channel, state)
+		case StreamUserData:
+			err = s.pollUserData(ctx, channel, state)
+		}
+		if err != nil {
+			if err := s.fail(channel, err); err != nil {
+				return err
+			}
+			continue
+		}
+		state.primed, state.resync = true, false
+	}
+	return nil
+}
+
+// fail returns err if it indicates a connection problem, and reports it to
+// OnError otherwise.
+func (s *Stream) fail(channel StreamChannel, err error) error {
+	if IsRetryable(err) {
+		return err
+	}
+	if s.opts.OnError != nil {
+		s.opts.OnError(channel, err)
+	}
+	return nil
+}
+
+func (s *Stream) pollTicker(channel StreamChannel, state *streamChannel, tickers t.Tickers) {
+	for i := range tickers {
+		ticker := tickers[i]
+		if !u.SameSymbol(ticker.Symbol, channel.Symbol) {
+			continue
+		}
+		if state.ticker == nil || *state.ticker != ticker || state.resync {
+			state.ticker = &ticker
+			s.dispatch(channel, StreamEvent{Ticker: &ticker})
+		}
+		return
+	}
+}
+
+func (s *Stream) pollTrades(ctx context.Context, channel StreamChannel, state *streamChannel) error {
+	params := t.GetRecentTradesParams{Limit: s.opts.TradesLimit, SinceId: state.lastTrade}
+	trades, err := s.client.getRecentTradesWithParams(ctx, channel.Symbol, params)
+	if err != nil {
+		return err
+	}
+	if len(trades) == 0 {
+		return nil
+	}
+
+	// Trades arrive newest first.
+	newest := trades[0].Id
+	if !state.primed {
+		state.lastTrade = newest
+		return nil
+	}
+	if len(trades) >= s.opts.TradesLimit && s.opts.OnGap != nil {
+		now := s.client.now()
+		s.opts.OnGap(StreamGap{Channel: channel, Reason: GapTradesSkipped, From: now.Add(-s.opts.Interval), To: now, LastTradeId: state.lastTrade})
+	}
+	for i := len(trades) - 1; i >= 0; i-- {
+		if state.lastTrade != "" && !tradeIdAfter(trades[i].Id, state.lastTrade) {
+			continue
+		}
+		s.dispatch(channel, StreamEvent{Trade: trades[i]})
+	}
+	state.lastTrade = newest
+	return nil
+}
+
+func (s *Stream) pollOrderBook(ctx context.Context, channel StreamChannel, state *streamChannel) error {
+	book, err := s.client.GetOrderBookWithContext(ctx, channel.Symbol)
+	if err != nil {
+		return err
+	}
+	if state.book == nil || !booksEqual(state.book, book) || state.resync {
+		state.book = book
+		s.dispatch(channel, StreamEvent{OrderBook: book})
+	}
+	return nil
+}
+
+func (s *Stream) pollDepth(ctx context.Context, channel StreamChannel, state *streamChannel) error {
+	book, err := s.client.GetOrderBookWithContext(ctx, channel.Symbol)
+	if err != nil {
+		return err
+	}
+	asks, bids := t.DiffOrderBooks(state.book, book)
+	state.book = book
+	if len(asks) == 0 && len(bids) == 0 && !state.resync {
+		return nil
+	}
+	// Polls are not numbered by the API, so every delta carries the whole
+	// book and replaces it rather than relying on a sequence.
+	s.dispatch(channel, StreamEvent{Depth: &t.OrderBookDelta{
+		Symbol:   channel.Symbol,
+		Asks:     asks,
+		Bids:     bids,
+		Snapshot: book.Clone(),
+	}})
+	return nil
+}
+
+func (s *Stream) pollUserData(ctx context.Context, channel StreamChannel, state *streamChannel) error {
+	if state.user == nil {
+		opts := UserDataStreamOptions{Symbol: channel.Symbol, TradesLimit: s.opts.TradesLimit}
+		state.user = newUserDataPoller(s.client, opts, func(ctx context.Context, event UserDataEvent) bool {
+			s.dispatch(channel, StreamEvent{Order: event.Order, Fill: event.Fill, Time: event.Time})
+			return ctx.Err() == nil
+		})
+	}
+	return state.user.poll(ctx)
+}
+
+// dispatch passes an event to the current handlers of a channel.
+func (s *Stream) dispatch(channel StreamChannel, event StreamEvent) {
+	event.Channel = channel
+	if event.Time.IsZero() {
+		event.Time = s.client.now()
+	}
+
+	s.mu.Lock()
+	state := s.channels[channel]
+	var handlers []func(StreamEvent)
+	if state != nil {
+		handlers = make([]func(StreamEvent), 0, len(state.handlers))
+		for _, handler := range state.handlers {
+			handlers = append(handlers, handler)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// reconnect pings the API with backoff until it answers, then marks every
+// channel for resubscription and reports the gap. err is the error that
+// disconnected the stream. Nothing is re-dialed: polling simply resumes.
+func (s *Stream) reconnect(ctx context.Context, since time.Time, err error) {
+	s.mu.Lock()
+	s.connected = false
+	s.mu.Unlock()
+
+	attempts := 0
+	for {
+		attempts++
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.client.after(s.opts.Backoff.NextDelay(attempts, err)):
+		}
+		if _, err = s.client.Ping(ctx); err == nil {
+			break
+		}
+	}
+
+	now := s.client.now()
+	s.mu.Lock()
+	s.connected = true
+	channels := make([]StreamChannel, 0, len(s.channels))
+	for channel, state := range s.channels {
+		state.resync = true
+		channels = append(channels, channel)
+	}
+	s.mu.Unlock()
+
+	if s.opts.OnReconnect != nil {
+		s.opts.OnReconnect(attempts, now.Sub(since))
+	}
+	if s.opts.OnGap != nil {
+		for _, channel := range channels {
+			s.opts.OnGap(StreamGap{Channel: channel, Reason: GapDisconnected, From: since, To: now})
+		}
+	}
+}
+
+// booksEqual reports whether two order books have the same levels.
+func booksEqual(a, b *t.OrderBook) bool {
+	return levelsEqual(a.Asks, b.Asks) && levelsEqual(a.Bids, b.Bids)
+}
+
+func levelsEqual(a, b [][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
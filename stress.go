@@ -0,0 +1,369 @@
+package bitpin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// DefaultStressCurrency is the valuation currency of stress tests when none is
+// given.
+const DefaultStressCurrency = "USDT"
+
+// StressScenario is a set of hypothetical price shocks.
+type StressScenario struct {
+	// Name identifies the scenario in the results, such as "weekend crash".
+	Name string
+
+	// Shocks maps an asset or a market symbol to a relative price change, as a
+	// fraction (-0.2 means -20%). An asset shock, such as "BTC", moves the
+	// price of the asset against every other asset: markets quoted in it move
+	// inversely. A market shock, such as "USDT_IRT", moves only the last
+	// price of that market. Shocks on the same market multiply.
+	Shocks map[string]float64
+}
+
+// RiskLimits are the thresholds a stressed portfolio is checked against. Zero
+// values disable a limit.
+type RiskLimits struct {
+	// MinEquity is the lowest acceptable equity, in the valuation currency.
+	MinEquity float64
+
+	// MaxDrawdown is the largest acceptable loss as a fraction of the current
+	// equity (0.25 means 25%).
+	MaxDrawdown float64
+}
+
+// StressOptions configures a stress test.
+type StressOptions struct {
+	// Currency is the asset equity is expressed in. Defaults to
+	// DefaultStressCurrency.
+	Currency string
+
+	// Limits are checked against the stressed equity.
+	Limits RiskLimits
+
+	// IgnoreOpenOrders values the wallets only. By default, open limit orders
+	// whose price is crossed by the shocked market price are assumed to fill at
+	// their limit price, which is the usual way resting orders lose money in a
+	// sharp move.
+	IgnoreOpenOrders bool
+}
+
+// Holdings are the balances and open orders a stress test revalues.
+type Holdings struct {
+	// Wallets are the balances, such as those returned by GetWallets. The
+	// Balance of a wallet already includes its frozen amount.
+	Wallets t.Wallets
+
+	// OpenOrders are the resting orders, such as those returned by
+	// GetOpenOrders.
+	OpenOrders t.OrderStatuses
+}
+
+// AssetStress is the position in a single asset before and after the shocks.
+type AssetStress struct {
+	// Asset is the canonical asset code.
+	Asset string
+
+	// Amount is the current balance.
+	Amount float64
+
+	// ShockedAmount is the balance after the open orders assumed to fill.
+	ShockedAmount float64
+
+	// Value and ShockedValue are Amount and ShockedAmount in the valuation
+	// currency, at current and shocked prices.
+	Value        float64
+	ShockedValue float64
+}
+
+// StressResult is the outcome of a single scenario.
+type StressResult struct {
+	// Scenario is the name of the scenario.
+	Scenario string
+
+	// Currency is the valuation currency.
+	Currency string
+
+	// Equity is the current value of the holdings.
+	Equity float64
+
+	// ShockedEquity is the value of the holdings under the scenario.
+	ShockedEquity float64
+
+	// PnL is ShockedEquity minus Equity.
+	PnL float64
+
+	// Drawdown is the loss as a fraction of Equity. It is negative when the
+	// scenario is profitable.
+	Drawdown float64
+
+	// EquityMargin is ShockedEquity minus RiskLimits.MinEquity: how much more
+	// could be lost before the equity limit is breached. Zero if the limit is
+	// not set.
+	EquityMargin float64
+
+	// DrawdownMargin is RiskLimits.MaxDrawdown minus Drawdown. Zero if the
+	// limit is not set.
+	DrawdownMargin float64
+
+	// Breaches describes each risk limit the scenario breaches.
+	Breaches []string
+
+	// Assets is the per-asset breakdown, sorted by asset.
+	Assets []AssetStress
+
+	// FilledOrders are the ids of the open orders assumed to fill.
+	FilledOrders []int
+
+	// Unpriced lists assets without a conversion path to Currency. They are
+	// left out of the equity.
+	Unpriced []string
+}
+
+// Breached reports whether the scenario breaches any risk limit.
+func (r *StressResult) Breached() bool {
+	return len(r.Breaches) > 0
+}
+
+// StressTest revalues holdings under each scenario. Prices are taken from the
+// tickers and conversions follow the same paths as the Converter.
+//
+// Returns an error if a scenario shocks an unknown asset or market, or moves a
+// price by -100% or more.
+//
+// Example:
+//
+//	results, err := bitpin.StressTest(*markets, *tickers, holdings, []bitpin.StressScenario{
+//	    {Name: "BTC crash", Shocks: map[string]float64{"BTC": -0.2, "USDT_IRT": 0.1}},
+//	}, bitpin.StressOptions{Currency: "IRT", Limits: bitpin.RiskLimits{MaxDrawdown: 0.15}})
+func StressTest(markets t.Markets, tickers t.Tickers, holdings Holdings, scenarios []StressScenario, opts StressOptions) ([]*StressResult, error) {
+	if opts.Currency == "" {
+		opts.Currency = DefaultStressCurrency
+	}
+
+	current := NewConverter(markets, tickers)
+	results := make([]*StressResult, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		shocked, err := shockTickers(markets, tickers, scenario)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, stressScenario(current, NewConverter(markets, shocked), shocked, holdings, scenario.Name, opts))
+	}
+	return results, nil
+}
+
+// StressTest fetches markets, tickers, wallets and open orders and revalues them
+// under each scenario; see the StressTest function.
+//
+// Example:
+//
+//	results, err := client.StressTest([]bitpin.StressScenario{
+//	    {Name: "BTC -20%", Shocks: map[string]float64{"BTC": -0.2}},
+//	    {Name: "Toman devaluation", Shocks: map[string]float64{"USDT_IRT": 0.1}},
+//	}, bitpin.StressOptions{Currency: "IRT", Limits: bitpin.RiskLimits{MinEquity: 5e8}})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, r := range results {
+//	    fmt.Printf("%s: %.0f -> %.0f %s, breached: %v\n",
+//	        r.Scenario, r.Equity, r.ShockedEquity, r.Currency, r.Breached())
+//	}
+func (c *Client) StressTest(scenarios []StressScenario, opts StressOptions) ([]*StressResult, error) {
+	markets, err := c.GetMarkets()
+	if err != nil {
+		return nil, err
+	}
+	tickers, err := c.GetTickers()
+	if err != nil {
+		return nil, err
+	}
+	wallets, err := c.GetWallets(t.GetWalletParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	holdings := Holdings{Wallets: *wallets}
+	if !opts.IgnoreOpenOrders {
+		orders, err := c.GetOpenOrders(t.GetOrdersHistoryParams{})
+		if err != nil {
+			return nil, err
+		}
+		holdings.OpenOrders = *orders
+	}
+	return StressTest(*markets, *tickers, holdings, scenarios, opts)
+}
+
+// shockTickers returns a copy of tickers with the scenario shocks applied to
+// their prices.
+func shockTickers(markets t.Markets, tickers t.Tickers, scenario StressScenario) (t.Tickers, error) {
+	bySymbol := make(map[string]t.Market, len(markets))
+	assets := make(map[string]bool)
+	for _, market := range markets {
+		bySymbol[u.CanonicalSymbol(market.Symbol)] = market
+		assets[u.CanonicalCurrency(market.Base)] = true
+		assets[u.CanonicalCurrency(market.Quote)] = true
+	}
+
+	marketShocks := make(map[string]float64)
+	assetShocks := make(map[string]float64)
+	for key, change := range scenario.Shocks {
+		if change <= -1 {
+			return nil, &GoBitpinError{
+				Message: fmt.Sprintf("scenario %q: shock of %s must be above -100%%", scenario.Name, key),
+			}
+		}
+		if _, ok := bySymbol[u.CanonicalSymbol(key)]; ok {
+			marketShocks[u.CanonicalSymbol(key)] = change
+		} else if assets[u.CanonicalCurrency(key)] {
+			assetShocks[u.CanonicalCurrency(key)] = change
+		} else {
+			return nil, &GoBitpinError{
+				Message: fmt.Sprintf("scenario %q: unknown asset or market %q", scenario.Name, key),
+			}
+		}
+	}
+
+	shocked := make(t.Tickers, len(tickers))
+	for i, ticker := range tickers {
+		shocked[i] = ticker
+		symbol := u.CanonicalSymbol(ticker.Symbol)
+		market, ok := bySymbol[symbol]
+		price, err := strconv.ParseFloat(ticker.Price, 64)
+		if !ok || err != nil {
+			continue
+		}
+
+		factor := 1.0
+		if change, ok := marketShocks[symbol]; ok {
+			factor *= 1 + change
+		}
+		if change, ok := assetShocks[u.CanonicalCurrency(market.Base)]; ok {
+			factor *= 1 + change
+		}
+		if change, ok := assetShocks[u.CanonicalCurrency(market.Quote)]; ok {
+			factor /= 1 + change
+		}
+		shocked[i].Price = strconv.FormatFloat(price*factor, 'f', -1, 64)
+	}
+	return shocked, nil
+}
+
+// stressScenario values the holdings with the current and shocked converters.
+func stressScenario(current, shocked *Converter, shockedTickers t.Tickers, holdings Holdings, name string, opts StressOptions) *StressResult {
+	result := &StressResult{Scenario: name, Currency: u.CanonicalCurrency(opts.Currency)}
+
+	amounts := make(map[string]float64)
+	for _, wallet := range holdings.Wallets {
+		balance, err := strconv.ParseFloat(wallet.Balance, 64)
+		if err != nil {
+			continue
+		}
+		amounts[u.CanonicalCurrency(wallet.Asset)] += balance
+	}
+
+	shockedAmounts := make(map[string]float64, len(amounts))
+	for asset, amount := range amounts {
+		shockedAmounts[asset] = amount
+	}
+	if !opts.IgnoreOpenOrders {
+		prices := make(map[string]float64, len(shockedTickers))
+		for _, ticker := range shockedTickers {
+			if price, err := strconv.ParseFloat(ticker.Price, 64); err == nil {
+				prices[u.CanonicalSymbol(ticker.Symbol)] = price
+			}
+		}
+		for _, order := range holdings.OpenOrders {
+			if fillOnShock(order, prices, shockedAmounts) {
+				result.FilledOrders = append(result.FilledOrders, order.Id)
+			}
+		}
+	}
+
+	for asset := range shockedAmounts {
+		line := AssetStress{Asset: asset, Amount: amounts[asset], ShockedAmount: shockedAmounts[asset]}
+		before, err := current.Convert(line.Amount, asset, result.Currency)
+		after, err2 := shocked.Convert(line.ShockedAmount, asset, result.Currency)
+		if err != nil || err2 != nil {
+			if line.Amount != 0 || line.ShockedAmount != 0 {
+				result.Unpriced = append(result.Unpriced, asset)
+			}
+			continue
+		}
+		line.Value, line.ShockedValue = before, after
+		result.Equity += before
+		result.ShockedEquity += after
+		result.Assets = append(result.Assets, line)
+	}
+	sort.Slice(result.Assets, func(i, j int) bool { return result.Assets[i].Asset < result.Assets[j].Asset })
+	sort.Strings(result.Unpriced)
+
+	result.PnL = result.ShockedEquity - result.Equity
+	if result.Equity != 0 {
+		result.Drawdown = -result.PnL / result.Equity
+	}
+
+	limits := opts.Limits
+	if limits.MinEquity != 0 {
+		result.EquityMargin = result.ShockedEquity - limits.MinEquity
+		if result.EquityMargin < 0 {
+			result.Breaches = append(result.Breaches, fmt.Sprintf("equity %.2f %s below minimum %.2f",
+				result.ShockedEquity, result.Currency, limits.MinEquity))
+		}
+	}
+	if limits.MaxDrawdown != 0 {
+		result.DrawdownMargin = limits.MaxDrawdown - result.Drawdown
+		if result.DrawdownMargin < 0 {
+			result.Breaches = append(result.Breaches, fmt.Sprintf("drawdown %.2f%% above maximum %.2f%%",
+				result.Drawdown*100, limits.MaxDrawdown*100))
+		}
+	}
+	return result
+}
+
+// fillOnShock applies the remaining amount of an open limit order to amounts if
+// the shocked price crosses its limit price, and reports whether it did.
+func fillOnShock(order t.OrderStatus, prices map[string]float64, amounts map[string]float64) bool {
+	price, ok := prices[u.CanonicalSymbol(order.Symbol)]
+	limit, err := strconv.ParseFloat(order.Price, 64)
+	if !ok || err != nil || limit <= 0 {
+		return false
+	}
+	total, err := strconv.ParseFloat(order.BaseAmount, 64)
+	if err != nil {
+		return false
+	}
+	dealt, _ := strconv.ParseFloat(order.DealedBaseAmount, 64)
+	remaining := total - dealt
+	if remaining <= 0 {
+		return false
+	}
+
+	base, quote, ok := strings.Cut(u.CanonicalSymbol(order.Symbol), "_")
+	if !ok {
+		return false
+	}
+	switch order.Side {
+	case t.SideBuy:
+		if price > limit {
+			return false
+		}
+		amounts[base] += remaining
+		amounts[quote] -= remaining * limit
+	case t.SideSell:
+		if price < limit {
+			return false
+		}
+		amounts[base] -= remaining
+		amounts[quote] += remaining * limit
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package bitpin
+
+import (
+	"context"
+	"sync"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// Subscription delivers the updates of a single stream channel on a Go
+// channel.
+//
+// The channel is bounded by StreamOptions.Buffer. When the consumer falls
+// behind, the oldest buffered update is discarded to make room for the newest
+// one, so the stream never blocks on a slow consumer; Dropped reports how many
+// updates were discarded.
+type Subscription[T any] struct {
+	// C delivers the updates. It is closed when the subscription is closed,
+	// its context is done, or the stream stops.
+	C <-chan T
+
+	ch          chan T
+	unsubscribe func()
+	stop        chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int
+}
+
+// Close unsubscribes from the channel and closes C. It is safe to call more
+// than once.
+func (s *Subscription[T]) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.unsubscribe()
+	close(s.stop)
+	close(s.ch)
+}
+
+// Dropped returns the number of updates discarded because the buffer was full.
+func (s *Subscription[T]) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// push buffers an update, discarding the oldest one when the buffer is full.
+func (s *Subscription[T]) push(value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	for {
+		select {
+		case s.ch <- value:
+			return
+		default:
+		}
+		select {
+		case <-s.ch:
+			s.dropped++
+		default:
+		}
+	}
+}
+
+// subscribe registers a channel-backed handler for channel that converts every
+// event with convert.
+func subscribe[T any](ctx context.Context, s *Stream, channel StreamChannel, convert func(StreamEvent) T) (*Subscription[T], error) {
+	return subscribeFunc(ctx, s, channel, func(event StreamEvent, push func(T)) {
+		push(convert(event))
+	})
+}
+
+// subscribeFunc registers a channel-backed handler for channel that passes
+// every event to handle, which may push any number of values.
+func subscribeFunc[T any](ctx context.Context, s *Stream, channel StreamChannel, handle func(event StreamEvent, push func(T))) (*Subscription[T], error) {
+	ch := make(chan T, s.opts.Buffer)
+	sub := &Subscription[T]{C: ch, ch: ch, stop: make(chan struct{})}
+
+	unsubscribe, err := s.Subscribe(channel, func(event StreamEvent) {
+		handle(event, sub.push)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sub.unsubscribe = unsubscribe
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.done:
+		case <-sub.stop:
+			return
+		}
+		sub.Close()
+	}()
+	return sub, nil
+}
+
+// Tickers subscribes to the ticker of a market.
+//
+// Parameters:
+//   - ctx: The subscription is closed when the context is done.
+//   - symbol: The market symbol, e.g. "BTC_IRT".
+//
+// Returns:
+//   - A `Subscription` delivering the ticker whenever it changes, and once more
+//     after every reconnect.
+//   - An error if the symbol is empty or the stream is closed.
+//
+// Example:
+//
+//	sub, err := stream.Tickers(ctx, "BTC_IRT")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer sub.Close()
+//	for ticker := range sub.C {
+//	    log.Printf("BTC_IRT: %s", ticker.Price)
+//	}
+func (s *Stream) Tickers(ctx context.Context, symbol string) (*Subscription[*t.Ticker], error) {
+	channel := StreamChannel{Kind: StreamTicker, Symbol: symbol}
+	return subscribe(ctx, s, channel, func(event StreamEvent) *t.Ticker { return event.Ticker })
+}
+
+// Trades subscribes to the public trades of a market. Trades are delivered
+// oldest first; trades that happened before the subscription are not
+// delivered.
+func (s *Stream) Trades(ctx context.Context, symbol string) (*Subscription[*t.Trade], error) {
+	channel := StreamChannel{Kind: StreamTrades, Symbol: symbol}
+	return subscribe(ctx, s, channel, func(event StreamEvent) *t.Trade { return event.Trade })
+}
+
+// OrderBooks subscribes to the order book of a market. A full snapshot is
+// delivered whenever the book changes, and once more after every reconnect.
+func (s *Stream) OrderBooks(ctx context.Context, symbol string) (*Subscription[*t.OrderBook], error) {
+	channel := StreamChannel{Kind: StreamOrderBook, Symbol: symbol}
+	return subscribe(ctx, s, channel, func(event StreamEvent) *t.OrderBook { return event.OrderBook })
+}
+
+// Depth subscribes to the order book deltas of a market. The deltas are
+// unsequenced and each carries the whole book in Snapshot, so a LocalOrderBook
+// fed with them is current even if deltas were discarded by a full buffer.
+func (s *Stream) Depth(ctx context.Context, symbol string) (*Subscription[*t.OrderBookDelta], error) {
+	channel := StreamChannel{Kind: StreamDepth, Symbol: symbol}
+	return subscribe(ctx, s, channel, func(event StreamEvent) *t.OrderBookDelta { return event.Depth })
+}
+
+// UserData subscribes to order updates and fills of the authenticated user,
+// with the same semantics as SubscribeUserData. An empty symbol covers all
+// markets.
+func (s *Stream) UserData(ctx context.Context, symbol string) (*Subscription[UserDataEvent], error) {
+	channel := StreamChannel{Kind: StreamUserData, Symbol: symbol}
+	return subscribe(ctx, s, channel, func(event StreamEvent) UserDataEvent {
+		if event.Fill != nil {
+			return UserDataEvent{Type: UserDataFill, Fill: event.Fill, Time: event.Time}
+		}
+		return UserDataEvent{Type: UserDataOrderUpdate, Order: event.Order, Time: event.Time}
+	})
+}
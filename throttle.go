@@ -0,0 +1,141 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// ErrOrderThrottled is returned without sending the order when the order
+// throttle rejects it
+var ErrOrderThrottled = &GoBitpinError{Message: "order throttled"}
+
+// ThrottleReason is the guard of the order throttle that rejected an order.
+type ThrottleReason string
+
+const (
+	// ThrottleOpenOrders means the market already has the maximum number of
+	// open orders.
+	ThrottleOpenOrders ThrottleReason = "open_orders"
+
+	// ThrottleInterval means the previous order on the market was submitted
+	// less than the minimum interval ago.
+	ThrottleInterval ThrottleReason = "interval"
+)
+
+// OrderThrottledError describes an order rejected by the order throttle. It
+// wraps ErrOrderThrottled.
+type OrderThrottledError struct {
+	GoBitpinError
+
+	// Symbol is the market of the order.
+	Symbol string
+
+	// Reason is the guard that rejected the order.
+	Reason ThrottleReason
+
+	// OpenOrders is the number of open orders found on the market. It is only
+	// set for ThrottleOpenOrders.
+	OpenOrders int
+
+	// RetryAfter is how long until the market accepts orders again. It is only
+	// set for ThrottleInterval.
+	RetryAfter time.Duration
+}
+
+// OrderThrottleOptions configures the per-market guards of CreateOrder. They
+// protect against strategy loops that submit orders faster than intended.
+// Both guards are local to the client; orders placed by other clients are only
+// seen through the open-order count.
+type OrderThrottleOptions struct {
+	// MaxOpenOrders is the maximum number of open orders per market. The open
+	// orders are fetched before every order, so the guard adds one request per
+	// order. Zero disables it.
+	MaxOpenOrders int
+
+	// MinInterval is the minimum time between two orders submitted on the same
+	// market. Zero disables it.
+	MinInterval time.Duration
+}
+
+// orderThrottle enforces OrderThrottleOptions. A nil throttle accepts every
+// order.
+type orderThrottle struct {
+	opts  OrderThrottleOptions
+	clock Clock
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// newOrderThrottle returns a throttle, or nil if opts is nil.
+func newOrderThrottle(opts *OrderThrottleOptions, clock Clock) *orderThrottle {
+	if opts == nil {
+		return nil
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &orderThrottle{opts: *opts, clock: clock, last: make(map[string]time.Time)}
+}
+
+// check returns an OrderThrottledError if the order may not be submitted yet.
+// It must be called while holding the lock of the symbol, so that the open
+// orders cannot change through this client before the order is recorded.
+func (o *orderThrottle) check(ctx context.Context, c *Client, params t.CreateOrderParams) error {
+	if o == nil {
+		return nil
+	}
+	symbol := u.CanonicalSymbol(params.Symbol)
+
+	if o.opts.MinInterval > 0 {
+		o.mu.Lock()
+		last, ok := o.last[symbol]
+		o.mu.Unlock()
+		if wait := o.opts.MinInterval - o.clock.Now().Sub(last); ok && wait > 0 {
+			return &OrderThrottledError{
+				GoBitpinError: GoBitpinError{
+					Message: fmt.Sprintf("%s accepts the next order in %s", symbol, wait.Round(time.Millisecond)),
+					Err:     ErrOrderThrottled,
+				},
+				Symbol:     symbol,
+				Reason:     ThrottleInterval,
+				RetryAfter: wait,
+			}
+		}
+	}
+
+	if o.opts.MaxOpenOrders > 0 {
+		var open t.OrderStatuses
+		query := t.GetOrdersHistoryParams{Symbol: params.Symbol, State: t.StateActive, Limit: o.opts.MaxOpenOrders}
+		if err := c.call(ctx, "GET", OpOrders, nil, true, query, &open); err != nil {
+			return &GoBitpinError{Message: "failed to fetch open orders for the order throttle", Err: err}
+		}
+		if len(open) >= o.opts.MaxOpenOrders {
+			return &OrderThrottledError{
+				GoBitpinError: GoBitpinError{
+					Message: fmt.Sprintf("%s has %d open orders, the maximum is %d", symbol, len(open), o.opts.MaxOpenOrders),
+					Err:     ErrOrderThrottled,
+				},
+				Symbol:     symbol,
+				Reason:     ThrottleOpenOrders,
+				OpenOrders: len(open),
+			}
+		}
+	}
+	return nil
+}
+
+// record notes that an order was submitted on a market.
+func (o *orderThrottle) record(symbol string) {
+	if o == nil || o.opts.MinInterval <= 0 {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.last[u.CanonicalSymbol(symbol)] = o.clock.Now()
+}
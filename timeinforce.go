@@ -0,0 +1,65 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// ErrFillOrKill is returned when a fill-or-kill order cannot be filled
+// completely
+var ErrFillOrKill = &GoBitpinError{Message: "fill-or-kill order not filled"}
+
+// emulatesTimeInForce reports whether the client must cancel the remainder of
+// an order after submitting it.
+func emulatesTimeInForce(params t.CreateOrderParams) bool {
+	return params.TimeInForce == t.TimeInForceIOC || params.TimeInForce == t.TimeInForceFOK
+}
+
+// checkFillOrKill simulates a fill-or-kill order against the current order
+// book and fails if the book cannot fill it completely.
+func (c *Client) checkFillOrKill(ctx context.Context, params t.CreateOrderParams) error {
+	book, err := c.GetOrderBookWithContext(ctx, params.Symbol)
+	if err != nil {
+		return err
+	}
+	sim, err := SimulateOrder(book, params, SimulateOptions{})
+	if err != nil {
+		return err
+	}
+	if sim.Unfilled > 0 {
+		return &GoBitpinError{
+			Message: fmt.Sprintf("order not submitted, the order book leaves %s unfilled", strconv.FormatFloat(sim.Unfilled, 'f', -1, 64)),
+			Err:     ErrFillOrKill,
+		}
+	}
+	return nil
+}
+
+// enforceTimeInForce cancels the remainder of an IOC or FOK order that was just
+// submitted and returns the order in its terminal state. A FOK order that did
+// not fill completely is returned together with an error wrapping
+// ErrFillOrKill; its partial fills cannot be undone.
+func (c *Client) enforceTimeInForce(ctx context.Context, order *t.OrderStatus, params t.CreateOrderParams) (*t.OrderStatus, error) {
+	if IsTerminalOrderState(order.State) {
+		return order, nil
+	}
+
+	result, err := c.CancelOrderAndConfirm(ctx, order.Id)
+	if err != nil {
+		return order, &GoBitpinError{
+			Message: fmt.Sprintf("failed to cancel the remainder of %s order %d", params.TimeInForce, order.Id),
+			Err:     err,
+		}
+	}
+
+	if params.TimeInForce == t.TimeInForceFOK && result.Outcome != CancelOutcomeFilled {
+		return result.Order, &GoBitpinError{
+			Message: fmt.Sprintf("order %d was cancelled after filling %s", order.Id, result.FilledBaseAmount),
+			Err:     ErrFillOrKill,
+		}
+	}
+	return result.Order, nil
+}
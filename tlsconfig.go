@@ -0,0 +1,167 @@
+package bitpin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrCertificateNotPinned is returned when the API presents a certificate
+// chain that contains none of the pinned keys
+var ErrCertificateNotPinned = &GoBitpinError{Message: "certificate chain does not match any pinned key"}
+
+// TLSOptions configures TLS for the default transport, without building an
+// entire http.Client. The settings are applied on top of Config, or on top of
+// the Go defaults if Config is nil.
+type TLSOptions struct {
+	// Config is the base configuration. It is cloned, not modified.
+	Config *tls.Config
+
+	// CAFile is the path of a PEM bundle of CA certificates to trust in
+	// addition to the system roots, for example the CA of a corporate proxy.
+	CAFile string
+
+	// CACertificates is a PEM bundle of CA certificates to trust in addition
+	// to the system roots and CAFile.
+	CACertificates []byte
+
+	// MinVersion is the minimum TLS version, such as tls.VersionTLS12.
+	MinVersion uint16
+
+	// PinnedKeys are SHA-256 hashes of the subject public key info of trusted
+	// certificates, base64 or hex encoded; a "sha256/" prefix is allowed. The
+	// connection fails with ErrCertificateNotPinned unless a certificate of
+	// the verified chain matches one of them. Pin an intermediate or a backup
+	// key as well, so a certificate renewal does not break the client.
+	PinnedKeys []string
+}
+
+// newTLSConfig builds the TLS configuration of the default transport, or
+// returns nil if opts is nil.
+func newTLSConfig(opts *TLSOptions) (*tls.Config, error) {
+	if opts == nil {
+		return nil, nil
+	}
+	invalid := func(format string, args ...interface{}) error {
+		return &GoBitpinError{Message: "invalid TLS options: " + fmt.Sprintf(format, args...)}
+	}
+
+	config := &tls.Config{}
+	if opts.Config != nil {
+		config = opts.Config.Clone()
+	}
+	if opts.MinVersion != 0 {
+		config.MinVersion = opts.MinVersion
+	}
+
+	bundle := opts.CACertificates
+	if opts.CAFile != "" {
+		data, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, &GoBitpinError{Message: "failed to read CA file", Err: err}
+		}
+		bundle = append(append(append([]byte(nil), data...), '\n'), bundle...)
+	}
+	if len(bytes.TrimSpace(bundle)) > 0 {
+		roots := config.RootCAs
+		if roots == nil {
+			roots, _ = x509.SystemCertPool()
+		}
+		if roots == nil {
+			roots = x509.NewCertPool()
+		} else {
+			roots = roots.Clone()
+		}
+		if !roots.AppendCertsFromPEM(bundle) {
+			return nil, invalid("no certificates found in the CA bundle")
+		}
+		config.RootCAs = roots
+	}
+
+	if len(opts.PinnedKeys) > 0 {
+		pins := make(map[[sha256.Size]byte]bool, len(opts.PinnedKeys))
+		for _, pin := range opts.PinnedKeys {
+			sum, err := parsePin(pin)
+			if err != nil {
+				return nil, invalid("pinned key %q: %v", pin, err)
+			}
+			pins[sum] = true
+		}
+		verify := config.VerifyConnection
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			if verify != nil {
+				if err := verify(state); err != nil {
+					return err
+				}
+			}
+			return verifyPins(state, pins)
+		}
+	}
+	return config, nil
+}
+
+// parsePin decodes a base64 or hex SHA-256 pin.
+func parsePin(pin string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	pin = strings.TrimPrefix(strings.TrimSpace(pin), "sha256/")
+	decoded, err := base64.StdEncoding.DecodeString(pin)
+	if err != nil || len(decoded) != sha256.Size {
+		decoded, err = hex.DecodeString(strings.ReplaceAll(pin, ":", ""))
+	}
+	if err != nil || len(decoded) != sha256.Size {
+		return sum, fmt.Errorf("not a base64 or hex SHA-256 hash")
+	}
+	copy(sum[:], decoded)
+	return sum, nil
+}
+
+// verifyPins checks the verified chains against the pinned keys. Unverified
+// certificates the server sent along are ignored, since anyone can present a
+// public certificate. When verification is skipped with InsecureSkipVerify
+// there are no verified chains, and only the leaf can match a pin: the
+// handshake proves the server holds its key, but not the keys of the rest.
+func verifyPins(state tls.ConnectionState, pins map[[sha256.Size]byte]bool) error {
+	var certs []*x509.Certificate
+	for _, chain := range state.VerifiedChains {
+		certs = append(certs, chain...)
+	}
+	if len(state.VerifiedChains) == 0 && len(state.PeerCertificates) > 0 {
+		certs = state.PeerCertificates[:1]
+	}
+	for _, cert := range certs {
+		if pins[sha256.Sum256(cert.RawSubjectPublicKeyInfo)] {
+			return nil
+		}
+	}
+	server := state.ServerName
+	if server == "" {
+		server = "the server"
+	}
+	return &GoBitpinError{
+		Message: fmt.Sprintf("%s presented an unpinned certificate", server),
+		Err:     ErrCertificateNotPinned,
+	}
+}
+
+// parseTLSVersion parses a TLS version such as "1.2".
+func parseTLSVersion(version string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(version), "tls") {
+	case "":
+		return 0, nil
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unknown TLS version %q", version)
+}
@@ -0,0 +1,169 @@
+package bitpin
+
+import (
+	"time"
+
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// TokenClaims are the claims of a token issued by the API, read without
+// verifying its signature.
+type TokenClaims struct {
+	// Type is the token type, "access" or "refresh".
+	Type string
+
+	// Id is the unique identifier of the token (its jti claim).
+	Id string
+
+	// UserId is the user the token was issued to.
+	UserId int
+
+	// ApiCredentialId is the API key the token was issued for.
+	ApiCredentialId int
+
+	// IPs are the addresses the token is restricted to. It is empty if the
+	// token is not restricted.
+	IPs []string
+
+	// ExpiresAt is when the token expires.
+	ExpiresAt time.Time
+
+	// ExpiresIn is the time left until the token expires on the API's clock,
+	// or a negative duration if it has expired.
+	ExpiresIn time.Duration
+}
+
+// Expired reports whether the token had expired when the claims were read.
+func (tc TokenClaims) Expired() bool {
+	return tc.ExpiresIn < 0
+}
+
+// TokenInfo describes the tokens currently held by a client.
+type TokenInfo struct {
+	// Access holds the claims of the access token, or nil if the client has
+	// none.
+	Access *TokenClaims
+
+	// Refresh holds the claims of the refresh token, or nil if the client has
+	// none.
+	Refresh *TokenClaims
+}
+
+// TokenInfo returns the claims of the access and refresh tokens of the client,
+// so applications can display the session status or schedule work before the
+// tokens expire.
+//
+// Returns:
+//   - A pointer to a `TokenInfo` holding the claims of each token. A token the
+//     client does not hold is reported as nil.
+//   - An error if a token cannot be decoded.
+//
+// Behavior:
+//   - Tokens are decoded without verifying their signature, like the expiry
+//     checks of AutoRefresh.
+//   - ExpiresIn is measured against `Now`, so it accounts for the offset
+//     between the local clock and the API clock.
+//   - Clients derived with WithOverrides report the tokens they share with
+//     their parent.
+//
+// Example:
+//
+//	info, err := client.TokenInfo()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if info.Access != nil {
+//	    fmt.Printf("user %d, session expires in %s\n", info.Access.UserId, info.Access.ExpiresIn.Round(time.Second))
+//	}
+func (c *Client) TokenInfo() (*TokenInfo, error) {
+	access, refresh := c.tokens()
+	now := c.Now()
+
+	var info TokenInfo
+	var err error
+	if info.Access, err = tokenClaims(access, now); err != nil {
+		return nil, &GoBitpinError{Message: "failed to decode access token", Err: err}
+	}
+	if info.Refresh, err = tokenClaims(refresh, now); err != nil {
+		return nil, &GoBitpinError{Message: "failed to decode refresh token", Err: err}
+	}
+	return &info, nil
+}
+
+// tokenClaims decodes a token, returning nil for an empty token.
+func tokenClaims(token string, now time.Time) (*TokenClaims, error) {
+	if token == "" {
+		return nil, nil
+	}
+	decoded, err := u.DecodeJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Unix(int64(decoded.Exp), 0)
+	return &TokenClaims{
+		Type:            decoded.TokenType,
+		Id:              decoded.Jti,
+		UserId:          decoded.UserId,
+		ApiCredentialId: decoded.ApiCredentialId,
+		IPs:             decoded.Ip,
+		ExpiresAt:       expiresAt,
+		ExpiresIn:       expiresAt.Sub(now),
+	}, nil
+}
+
+// TokenRefreshReason tells how the tokens of a client changed.
+type TokenRefreshReason string
+
+const (
+	// TokenAuthenticated means new tokens were obtained with the API key and
+	// secret key.
+	TokenAuthenticated TokenRefreshReason = "authenticate"
+
+	// TokenRefreshed means the access token was renewed with the refresh
+	// token.
+	TokenRefreshed TokenRefreshReason = "refresh"
+)
+
+// TokenRefreshEvent describes a change of the tokens of a client, for the
+// OnTokenRefresh hook.
+type TokenRefreshEvent struct {
+	// Reason tells whether the client authenticated or refreshed.
+	Reason TokenRefreshReason
+
+	// Previous holds the claims of the tokens before the change. Its fields
+	// are nil for tokens the client did not hold, or that could not be
+	// decoded.
+	Previous TokenInfo
+
+	// Current holds the claims of the new tokens.
+	Current TokenInfo
+
+	// AccessToken and RefreshToken are the new tokens, for applications that
+	// persist them themselves. Treat them as secrets.
+	AccessToken  string
+	RefreshToken string
+
+	// Time is when the tokens changed, on the API's clock.
+	Time time.Time
+}
+
+// notifyTokenRefresh invokes OnTokenRefresh after the tokens changed from
+// previousAccess and previousRefresh to the current tokens of the client.
+func (c *Client) notifyTokenRefresh(reason TokenRefreshReason, previousAccess, previousRefresh string) {
+	if c.OnTokenRefresh == nil {
+		return
+	}
+	now := c.Now()
+	access, refresh := c.tokens()
+	event := TokenRefreshEvent{
+		Reason:       reason,
+		AccessToken:  access,
+		RefreshToken: refresh,
+		Time:         now,
+	}
+	event.Previous.Access, _ = tokenClaims(previousAccess, now)
+	event.Previous.Refresh, _ = tokenClaims(previousRefresh, now)
+	event.Current.Access, _ = tokenClaims(access, now)
+	event.Current.Refresh, _ = tokenClaims(refresh, now)
+	c.OnTokenRefresh(event)
+}
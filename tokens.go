@@ -0,0 +1,280 @@
+package bitpin
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rzabhd80/go-sdk-bitpin/store"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// TokenStorage persists the access and refresh tokens of a client between runs,
+// so a restarted process can reuse its session instead of authenticating again.
+type TokenStorage interface {
+	// LoadTokens returns the stored tokens, or nil if none are stored.
+	LoadTokens() (*t.AuthenticationResponse, error)
+
+	// SaveTokens stores the given tokens, replacing any previous ones.
+	SaveTokens(tokens *t.AuthenticationResponse) error
+}
+
+// StoreTokenStorage is a TokenStorage that keeps the tokens as JSON under a
+// single key of a store.Store.
+type StoreTokenStorage struct {
+	store store.Store
+	key   string
+}
+
+// NewStoreTokenStorage creates a TokenStorage backed by st. If key is empty,
+// "tokens" is used.
+//
+// Example:
+//
+//	st, _ := store.NewFile("/var/lib/mybot")
+//	client, err := bitpin.NewClient(bitpin.ClientOptions{
+//	    ApiKey:       "your-api-key",
+//	    SecretKey:    "your-secret-key",
+//	    TokenStorage: bitpin.NewStoreTokenStorage(st, ""),
+//	})
+func NewStoreTokenStorage(st store.Store, key string) *StoreTokenStorage {
+	if key == "" {
+		key = "tokens"
+	}
+	return &StoreTokenStorage{store: st, key: key}
+}
+
+// LoadTokens returns the stored tokens, or nil if none are stored.
+func (s *StoreTokenStorage) LoadTokens() (*t.AuthenticationResponse, error) {
+	raw, err := s.store.Get(context.Background(), s.key)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens t.AuthenticationResponse
+	if err := json.Unmarshal(raw, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// SaveTokens stores the given tokens.
+func (s *StoreTokenStorage) SaveTokens(tokens *t.AuthenticationResponse) error {
+	raw, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	return s.store.Set(context.Background(), s.key, raw)
+}
+
+// ErrTokenDecryption is returned by EncryptedTokenStorage when the stored
+// tokens cannot be decrypted, because the key is wrong or the data was altered
+var ErrTokenDecryption = &GoBitpinError{Message: "failed to decrypt stored tokens"}
+
+// encryptedTokensVersion prefixes the data written by EncryptedTokenStorage, so
+// the format can change without misreading older files.
+const encryptedTokensVersion byte = 1
+
+// EncryptedTokenStorage is a TokenStorage that encrypts the tokens with
+// AES-GCM before writing them under a single key of a store.Store, so tokens
+// copied off the disk or out of a backup cannot be used without the key.
+type EncryptedTokenStorage struct {
+	store store.Store
+	key   string
+	aead  cipher.AEAD
+}
+
+// NewEncryptedTokenStorage creates a TokenStorage backed by st that encrypts
+// the tokens with secret, which must be a 16, 24 or 32 byte AES key. If key is
+// empty, "tokens" is used.
+//
+// Parameters:
+//   - st: The store the encrypted tokens are written to, such as a
+//     `store.NewFile` directory.
+//   - key: The key of the store the tokens are kept under.
+//   - secret: The AES key. Use 32 random bytes, and keep it outside the store:
+//     in a secret manager, an environment variable or the OS keychain (see
+//     `KeyringTokenKey`).
+//
+// Returns:
+//   - A pointer to an `EncryptedTokenStorage`.
+//   - An error if secret is not a valid AES key.
+//
+// Behavior:
+//   - Every save uses a fresh random nonce. The store key is authenticated
+//     along with the tokens, so an encrypted value cannot be moved to another
+//     key.
+//   - Loading tokens that cannot be decrypted returns `ErrTokenDecryption`,
+//     which NewClient reports as a failure to load the stored tokens.
+//
+// Example:
+//
+//	st, _ := store.NewFile("/var/lib/mybot")
+//	secret, _ := base64.StdEncoding.DecodeString(os.Getenv("BITPIN_TOKEN_KEY"))
+//	storage, err := bitpin.NewEncryptedTokenStorage(st, "", secret)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client, err := bitpin.NewClientWithOptions(
+//	    bitpin.WithAPIKey("your-api-key", "your-secret-key"),
+//	    bitpin.WithTokenStorage(storage),
+//	)
+func NewEncryptedTokenStorage(st store.Store, key string, secret []byte) (*EncryptedTokenStorage, error) {
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, &GoBitpinError{Message: "invalid token encryption key", Err: err}
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, &GoBitpinError{Message: "invalid token encryption key", Err: err}
+	}
+	if key == "" {
+		key = "tokens"
+	}
+	return &EncryptedTokenStorage{store: st, key: key, aead: aead}, nil
+}
+
+// LoadTokens decrypts and returns the stored tokens, or nil if none are
+// stored.
+func (s *EncryptedTokenStorage) LoadTokens() (*t.AuthenticationResponse, error) {
+	sealed, err := s.store.Get(context.Background(), s.key)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(sealed) < 1+nonceSize || sealed[0] != encryptedTokensVersion {
+		return nil, ErrTokenDecryption
+	}
+	nonce, ciphertext := sealed[1:1+nonceSize], sealed[1+nonceSize:]
+	raw, err := s.aead.Open(nil, nonce, ciphertext, []byte(s.key))
+	if err != nil {
+		return nil, ErrTokenDecryption
+	}
+
+	var tokens t.AuthenticationResponse
+	if err := json.Unmarshal(raw, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// SaveTokens encrypts and stores the given tokens.
+func (s *EncryptedTokenStorage) SaveTokens(tokens *t.AuthenticationResponse) error {
+	raw, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	sealed := make([]byte, 1+s.aead.NonceSize())
+	sealed[0] = encryptedTokensVersion
+	if _, err := rand.Read(sealed[1:]); err != nil {
+		return err
+	}
+	sealed = s.aead.Seal(sealed, sealed[1:], raw, []byte(s.key))
+	return s.store.Set(context.Background(), s.key, sealed)
+}
+
+// KeyringTokenKey reads the key of an EncryptedTokenStorage from the keyring
+// of the operating system, where it is stored base64-encoded as the generic
+// password of service and account. It supports the same systems as
+// KeyringCredentials.
+//
+// Example:
+//
+//	// Once: head -c 32 /dev/urandom | base64 | secret-tool store --label=bitpin service bitpin account token_key
+//	secret, err := bitpin.KeyringTokenKey(ctx, "bitpin", "token_key")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	storage, err := bitpin.NewEncryptedTokenStorage(st, "", secret)
+func KeyringTokenKey(ctx context.Context, service, account string) ([]byte, error) {
+	encoded, err := keyringPassword(ctx, service, account)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, &GoBitpinError{Message: fmt.Sprintf("keyring entry %s/%s is not base64", service, account), Err: err}
+	}
+	return secret, nil
+}
+
+// loadTokens fills empty client tokens from the token storage.
+func (c *Client) loadTokens() error {
+	if c.TokenStorage == nil {
+		return nil
+	}
+
+	tokens, err := c.TokenStorage.LoadTokens()
+	if err != nil {
+		return &GoBitpinError{
+			Message: "failed to load stored tokens",
+			Err:     err,
+		}
+	}
+	if tokens == nil {
+		return nil
+	}
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.AccessToken == "" {
+		c.AccessToken = tokens.Access
+	}
+	if c.RefreshToken == "" {
+		c.RefreshToken = tokens.Refresh
+	}
+	return nil
+}
+
+// saveTokens writes the current client tokens to the token storage.
+func (c *Client) saveTokens() error {
+	if c.TokenStorage == nil {
+		return nil
+	}
+
+	access, refresh := c.tokens()
+	err := c.TokenStorage.SaveTokens(&t.AuthenticationResponse{
+		Access:  access,
+		Refresh: refresh,
+	})
+	if err != nil {
+		return &GoBitpinError{
+			Message: "failed to save tokens",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// tokens returns the access and refresh tokens the client authenticates with,
+// which are those of its owner for clients derived with WithOverrides.
+func (c *Client) tokens() (access, refresh string) {
+	owner := c.tokenOwner()
+	owner.tokenMu.RLock()
+	defer owner.tokenMu.RUnlock()
+	return owner.AccessToken, owner.RefreshToken
+}
+
+// setTokens replaces the tokens of the client and returns the previous ones.
+// An empty refresh token keeps the current one.
+func (c *Client) setTokens(access, refresh string) (previousAccess, previousRefresh string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	previousAccess, previousRefresh = c.AccessToken, c.RefreshToken
+	c.AccessToken = access
+	if refresh != "" {
+		c.RefreshToken = refresh
+	}
+	return previousAccess, previousRefresh
+}
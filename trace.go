@@ -0,0 +1,118 @@
+package bitpin
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// RequestTimings breaks the latency of a request down into network phases. It
+// is reported in RequestEvent.Timings when TraceRequests is enabled. Phases
+// that did not happen, such as DNS and connecting on a reused connection, are
+// zero.
+type RequestTimings struct {
+	// DNS is the time spent resolving the host name.
+	DNS time.Duration
+
+	// Connect is the time spent establishing the TCP connection.
+	Connect time.Duration
+
+	// TLSHandshake is the time spent on the TLS handshake.
+	TLSHandshake time.Duration
+
+	// TimeToFirstByte is the time from sending the request until the first
+	// byte of the response arrived, including the phases above.
+	TimeToFirstByte time.Duration
+
+	// ConnReused reports whether an idle keep-alive connection was reused.
+	ConnReused bool
+}
+
+// requestTrace records the timings of a single request. The hooks may be
+// called from several goroutines.
+type requestTrace struct {
+	start time.Time
+
+	mu           sync.Mutex
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	timings      RequestTimings
+}
+
+// traceRequest attaches the timing hooks and the configured ClientTrace to a
+// request. It returns a nil trace if TraceRequests is disabled.
+func (c *Client) traceRequest(req *http.Request) (*http.Request, *requestTrace) {
+	if c.ClientTrace == nil && !c.TraceRequests {
+		return req, nil
+	}
+	ctx := req.Context()
+	if c.ClientTrace != nil {
+		ctx = httptrace.WithClientTrace(ctx, c.ClientTrace)
+	}
+	if !c.TraceRequests {
+		return req.WithContext(ctx), nil
+	}
+
+	rt := &requestTrace{start: time.Now()}
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			rt.mu.Lock()
+			defer rt.mu.Unlock()
+			rt.timings.ConnReused = info.Reused
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			rt.mu.Lock()
+			defer rt.mu.Unlock()
+			rt.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			rt.mu.Lock()
+			defer rt.mu.Unlock()
+			rt.timings.DNS = time.Since(rt.dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			rt.mu.Lock()
+			defer rt.mu.Unlock()
+			if rt.connectStart.IsZero() {
+				rt.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			rt.mu.Lock()
+			defer rt.mu.Unlock()
+			if err == nil && rt.timings.Connect == 0 {
+				rt.timings.Connect = time.Since(rt.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			rt.mu.Lock()
+			defer rt.mu.Unlock()
+			rt.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			rt.mu.Lock()
+			defer rt.mu.Unlock()
+			rt.timings.TLSHandshake = time.Since(rt.tlsStart)
+		},
+		GotFirstResponseByte: func() {
+			rt.mu.Lock()
+			defer rt.mu.Unlock()
+			rt.timings.TimeToFirstByte = time.Since(rt.start)
+		},
+	})
+	return req.WithContext(ctx), rt
+}
+
+// result returns the recorded timings, or nil for a nil trace.
+func (rt *requestTrace) result() *RequestTimings {
+	if rt == nil {
+		return nil
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	timings := rt.timings
+	return &timings
+}
@@ -0,0 +1,128 @@
+package bitpin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// errorTranslation maps Persian phrases used in API error messages to a
+// normalized English message and an error code.
+type errorTranslation struct {
+	Code    string
+	English string
+	Persian []string
+}
+
+// errorTranslations is the built-in translation table. A message is translated
+// by the entry with the longest phrase it contains, so specific entries such as
+// "order not found" win over "not found".
+var errorTranslations = []errorTranslation{
+	{Code: "insufficient_balance", English: "Insufficient balance.", Persian: []string{"موجودی کافی نیست", "موجودی ناکافی", "موجودی کافی ندارید"}},
+	{Code: "required", English: "This field is required.", Persian: []string{"این فیلد لازم است", "این مقدار لازم است", "این فیلد الزامی است"}},
+	{Code: "blank", English: "This field may not be blank.", Persian: []string{"نمی تواند خالی باشد"}},
+	{Code: "invalid_number", English: "A valid number is required.", Persian: []string{"یک عدد معتبر", "عدد معتبر نیست"}},
+	{Code: "not_found", English: "Not found.", Persian: []string{"یافت نشد"}},
+	{Code: "order_not_found", English: "Order not found.", Persian: []string{"سفارش یافت نشد", "سفارش مورد نظر یافت نشد"}},
+	{Code: "market_not_found", English: "Market not found.", Persian: []string{"بازار یافت نشد", "بازار مورد نظر یافت نشد"}},
+	{Code: "market_closed", English: "Market is not active.", Persian: []string{"بازار غیرفعال", "بازار بسته است"}},
+	{Code: "not_authenticated", English: "Authentication credentials were not provided.", Persian: []string{"اطلاعات برای اعتبارسنجی ارسال نشده", "اطلاعات احراز هویت ارسال نشده"}},
+	{Code: "token_not_valid", English: "Token is invalid or expired.", Persian: []string{"توکن نامعتبر", "توکن منقضی شده"}},
+	{Code: "invalid_credentials", English: "Invalid API key or secret key.", Persian: []string{"کلید نامعتبر", "کلید api نامعتبر"}},
+	{Code: "permission_denied", English: "You do not have permission to perform this action.", Persian: []string{"اجازه انجام این", "دسترسی ندارید"}},
+	{Code: "throttled", English: "Request was throttled.", Persian: []string{"درخواست محدود شده", "تعداد درخواست ها بیش از حد"}},
+	{Code: "min_order_amount", English: "Order amount is below the market minimum.", Persian: []string{"حداقل مقدار سفارش", "کمتر از حداقل"}},
+	{Code: "invalid_price", English: "Invalid price.", Persian: []string{"قیمت نامعتبر", "قیمت وارد شده معتبر نیست"}},
+	{Code: "invalid_amount", English: "Invalid amount.", Persian: []string{"مقدار نامعتبر", "مقدار وارد شده معتبر نیست"}},
+	{Code: "server_error", English: "Internal server error.", Persian: []string{"خطای داخلی سرور"}},
+}
+
+// TranslateErrorMessage translates a Persian API error message into English
+// using the built-in translation table and returns the error code of the
+// matching entry. Messages that are not Persian, or that no entry matches, are
+// returned unchanged with ok set to false.
+//
+// Example:
+//
+//	english, code, ok := bitpin.TranslateErrorMessage("موجودی کافی نیست")
+//	// english == "Insufficient balance.", code == "insufficient_balance", ok == true
+func TranslateErrorMessage(msg string) (english string, code string, ok bool) {
+	return translateErrorMessage(msg, nil)
+}
+
+// translateErrorMessage is TranslateErrorMessage with additional translations
+// that take precedence over the built-in table.
+func translateErrorMessage(msg string, extra map[string]string) (string, string, bool) {
+	if !isPersian(msg) {
+		return msg, "", false
+	}
+	normalized := normalizePersian(msg)
+
+	phrases := make([]string, 0, len(extra))
+	for phrase := range extra {
+		phrases = append(phrases, phrase)
+	}
+	sort.Slice(phrases, func(i, j int) bool { return len(phrases[i]) > len(phrases[j]) })
+	for _, phrase := range phrases {
+		if strings.Contains(normalized, normalizePersian(phrase)) {
+			return extra[phrase], "", true
+		}
+	}
+
+	var best *errorTranslation
+	bestLen := 0
+	for i := range errorTranslations {
+		for _, phrase := range errorTranslations[i].Persian {
+			if len(phrase) > bestLen && strings.Contains(normalized, normalizePersian(phrase)) {
+				best, bestLen = &errorTranslations[i], len(phrase)
+			}
+		}
+	}
+	if best == nil {
+		return msg, "", false
+	}
+	return best.English, best.Code, true
+}
+
+// isPersian reports whether s contains Arabic-script letters.
+func isPersian(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Arabic, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePersian folds the spelling variants found in Persian text: Arabic
+// yeh and kaf, zero-width non-joiners, repeated spaces, case, and trailing
+// punctuation.
+func normalizePersian(s string) string {
+	s = strings.NewReplacer("ي", "ی", "ك", "ک", "‌", " ", "‏", "").Replace(s)
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ToLower(strings.TrimRight(s, ".!۔ "))
+}
+
+// translate replaces Persian messages in the error details with English ones
+// and rebuilds the error message. The original details are kept in
+// OriginalDetails.
+func (e *APIError) translate(extra map[string]string) {
+	translated := make(map[string][]string, len(e.Details))
+	changed := false
+	for field, messages := range e.Details {
+		out := make([]string, len(messages))
+		for i, msg := range messages {
+			english, _, ok := translateErrorMessage(msg, extra)
+			out[i] = english
+			changed = changed || ok
+		}
+		translated[field] = out
+	}
+	if !changed {
+		return
+	}
+	e.OriginalDetails = e.Details
+	e.Details = translated
+	e.Message = fmt.Sprintf("API error (status %d): %s", e.StatusCode, formatErrorDetails(translated))
+}
@@ -0,0 +1,49 @@
+package types
+
+import "time"
+
+// Candle is an OHLCV bar aggregated from trades or price snapshots. Candles are
+// built locally by the SDK, so prices and volumes are parsed into float64 for
+// use in research and indicator calculations.
+type Candle struct {
+	// Start is the opening time of the bar. For time bars it is aligned to the
+	// bar interval; for other bar types it is the time of the first trade.
+	Start time.Time `json:"start"`
+
+	// End is the closing time of the bar. For time bars it is Start plus the
+	// interval; for other bar types it is the time of the last trade.
+	End time.Time `json:"end"`
+
+	// Open is the price of the first trade in the bar.
+	Open float64 `json:"open"`
+
+	// High is the highest traded price in the bar.
+	High float64 `json:"high"`
+
+	// Low is the lowest traded price in the bar.
+	Low float64 `json:"low"`
+
+	// Close is the price of the last trade in the bar.
+	Close float64 `json:"close"`
+
+	// Volume is the traded base amount.
+	Volume float64 `json:"volume"`
+
+	// QuoteVolume is the traded quote amount.
+	QuoteVolume float64 `json:"quote_volume"`
+
+	// Trades is the number of trades or snapshots aggregated into the bar.
+	Trades int `json:"trades"`
+}
+
+// Candles represents a series of candles, oldest first.
+type Candles []Candle
+
+// Closes returns the close prices of the candles, oldest first.
+func (c Candles) Closes() []float64 {
+	closes := make([]float64, len(c))
+	for i, candle := range c {
+		closes[i] = candle.Close
+	}
+	return closes
+}
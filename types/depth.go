@@ -0,0 +1,169 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// OrderBookDelta is an incremental update of an order book. Each level of Asks
+// and Bids replaces the amount at its price; an amount of zero removes the
+// level.
+//
+// Deltas from a sequenced source are numbered: a delta covers the sequence
+// numbers FirstSequence to LastSequence, and the next delta of the same book
+// starts at LastSequence+1. A delta that starts later means updates were
+// missed. Sources without sequence numbers, such as the polling Stream of the
+// bitpin package, leave them zero and set Snapshot instead.
+type OrderBookDelta struct {
+	// Symbol is the market the delta belongs to.
+	Symbol string `json:"symbol"`
+
+	// FirstSequence and LastSequence delimit the updates covered by the delta.
+	FirstSequence int64 `json:"first_sequence"`
+	LastSequence  int64 `json:"last_sequence"`
+
+	// Asks and Bids are the changed levels as ["price", "amount"] pairs.
+	Asks [][]string `json:"asks"`
+	Bids [][]string `json:"bids"`
+
+	// Snapshot is the whole book after the delta, for unsequenced deltas. It
+	// is nil for sequenced ones.
+	Snapshot *OrderBook `json:"snapshot,omitempty"`
+}
+
+// Sequenced reports whether the delta carries sequence numbers.
+func (d *OrderBookDelta) Sequenced() bool {
+	return d.FirstSequence != 0 || d.LastSequence != 0
+}
+
+// Empty reports whether the delta changes no level.
+func (d *OrderBookDelta) Empty() bool {
+	return len(d.Asks) == 0 && len(d.Bids) == 0
+}
+
+// String returns a short description of the delta, e.g. "BTC_USDT #4-5 (3 asks, 1 bids)".
+func (d *OrderBookDelta) String() string {
+	return fmt.Sprintf("%s #%d-%d (%d asks, %d bids)", d.Symbol, d.FirstSequence, d.LastSequence, len(d.Asks), len(d.Bids))
+}
+
+// DiffOrderBooks returns the levels that turn prev into next, as used in an
+// OrderBookDelta. A nil prev is treated as an empty book.
+func DiffOrderBooks(prev, next *OrderBook) (asks, bids [][]string) {
+	if prev == nil {
+		prev = &OrderBook{}
+	}
+	return diffSide(prev.Asks, next.Asks), diffSide(prev.Bids, next.Bids)
+}
+
+// diffSide returns the changed levels of one side, in the order of next
+// followed by removed levels.
+func diffSide(prev, next [][]string) [][]string {
+	old := make(map[float64]string, len(prev))
+	for _, entry := range prev {
+		if price, ok := levelPrice(entry); ok {
+			old[price] = entry[1]
+		}
+	}
+
+	var changes [][]string
+	for _, entry := range next {
+		price, ok := levelPrice(entry)
+		if !ok {
+			continue
+		}
+		amount, seen := old[price]
+		delete(old, price)
+		if !seen || amount != entry[1] {
+			changes = append(changes, []string{entry[0], entry[1]})
+		}
+	}
+	for _, entry := range prev {
+		if price, ok := levelPrice(entry); ok {
+			if _, removed := old[price]; removed {
+				changes = append(changes, []string{entry[0], "0"})
+			}
+		}
+	}
+	return changes
+}
+
+// ApplyDelta applies the levels of a delta to the book, keeping asks sorted
+// from the lowest price up and bids from the highest price down. Sequence
+// numbers are not checked; see LocalOrderBook in the bitpin package for a book
+// that validates them.
+func (o *OrderBook) ApplyDelta(delta *OrderBookDelta) error {
+	asks, err := applySide(Asks, o.Asks, delta.Asks)
+	if err != nil {
+		return err
+	}
+	bids, err := applySide(Bids, o.Bids, delta.Bids)
+	if err != nil {
+		return err
+	}
+	o.Asks, o.Bids = asks, bids
+	return nil
+}
+
+// applySide merges changes into the levels of one side.
+func applySide(side BookSide, levels, changes [][]string) ([][]string, error) {
+	if len(changes) == 0 {
+		return levels, nil
+	}
+
+	byPrice := make(map[float64][]string, len(levels)+len(changes))
+	for _, entry := range levels {
+		if price, ok := levelPrice(entry); ok {
+			byPrice[price] = entry
+		}
+	}
+	for _, entry := range changes {
+		level, err := parseLevel(side, entry)
+		if err != nil {
+			return nil, err
+		}
+		if level.Amount == 0 {
+			delete(byPrice, level.Price)
+			continue
+		}
+		byPrice[level.Price] = []string{entry[0], entry[1]}
+	}
+
+	prices := make([]float64, 0, len(byPrice))
+	for price := range byPrice {
+		prices = append(prices, price)
+	}
+	if side == Asks {
+		sort.Float64s(prices)
+	} else {
+		sort.Sort(sort.Reverse(sort.Float64Slice(prices)))
+	}
+
+	merged := make([][]string, len(prices))
+	for i, price := range prices {
+		merged[i] = byPrice[price]
+	}
+	return merged, nil
+}
+
+// levelPrice parses the price of an entry, reporting false for malformed
+// entries.
+func levelPrice(entry []string) (float64, bool) {
+	if len(entry) < 2 {
+		return 0, false
+	}
+	price, err := strconv.ParseFloat(entry[0], 64)
+	return price, err == nil
+}
+
+// Clone returns a deep copy of the book.
+func (o *OrderBook) Clone() *OrderBook {
+	clone := &OrderBook{Asks: make([][]string, len(o.Asks)), Bids: make([][]string, len(o.Bids))}
+	for i, entry := range o.Asks {
+		clone.Asks[i] = append([]string(nil), entry...)
+	}
+	for i, entry := range o.Bids {
+		clone.Bids[i] = append([]string(nil), entry...)
+	}
+	return clone
+}
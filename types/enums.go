@@ -0,0 +1,141 @@
+package types
+
+import (
+	"slices"
+	"strings"
+)
+
+// OrderSide is the direction of an order or trade.
+type OrderSide string
+
+// Order sides.
+const (
+	SideBuy  OrderSide = "buy"
+	SideSell OrderSide = "sell"
+)
+
+// orderSides are the order sides known to the SDK.
+var orderSides = []OrderSide{SideBuy, SideSell}
+
+// OrderSideValues returns the order sides known to the SDK.
+func OrderSideValues() []OrderSide {
+	return slices.Clone(orderSides)
+}
+
+// Valid reports whether s is a side known to the SDK.
+func (s OrderSide) Valid() bool {
+	return slices.Contains(orderSides, s)
+}
+
+// Opposite returns the other side: SideSell for SideBuy and vice versa. Unknown
+// sides are returned unchanged.
+func (s OrderSide) Opposite() OrderSide {
+	switch s {
+	case SideBuy:
+		return SideSell
+	case SideSell:
+		return SideBuy
+	default:
+		return s
+	}
+}
+
+// OrderType is the type of an order.
+type OrderType string
+
+// Order types.
+const (
+	TypeLimit     OrderType = "limit"
+	TypeMarket    OrderType = "market"
+	TypeStopLimit OrderType = "stop_limit"
+	TypeOCO       OrderType = "oco"
+)
+
+// orderTypes are the order types known to the SDK.
+var orderTypes = []OrderType{TypeLimit, TypeMarket, TypeStopLimit, TypeOCO}
+
+// OrderTypeValues returns the order types known to the SDK.
+func OrderTypeValues() []OrderType {
+	return slices.Clone(orderTypes)
+}
+
+// Valid reports whether o is an order type known to the SDK.
+func (o OrderType) Valid() bool {
+	return slices.Contains(orderTypes, o)
+}
+
+// TimeInForce is how long an order stays on the book. The API does not accept a
+// time in force, so the SDK emulates IOC and FOK; see CreateOrderParams.
+type TimeInForce string
+
+// Time-in-force values.
+const (
+	// TimeInForceGTC keeps the order on the book until it is filled or
+	// cancelled. It is the behavior of the API and the default.
+	TimeInForceGTC TimeInForce = "GTC"
+
+	// TimeInForceIOC fills what it can immediately and cancels the rest.
+	TimeInForceIOC TimeInForce = "IOC"
+
+	// TimeInForceFOK fills completely and immediately, or not at all.
+	TimeInForceFOK TimeInForce = "FOK"
+)
+
+// timesInForce are the time-in-force values known to the SDK.
+var timesInForce = []TimeInForce{TimeInForceGTC, TimeInForceIOC, TimeInForceFOK}
+
+// TimeInForceValues returns the time-in-force values known to the SDK.
+func TimeInForceValues() []TimeInForce {
+	return slices.Clone(timesInForce)
+}
+
+// Valid reports whether f is a time in force known to the SDK.
+func (f TimeInForce) Valid() bool {
+	return slices.Contains(timesInForce, f)
+}
+
+// OrderState is the lifecycle state of an order.
+type OrderState string
+
+// Order states. The API spells the cancelled state "canceled"; "cancelled" is
+// also recognized by IsTerminal.
+const (
+	StateActive   OrderState = "active"
+	StateOpen     OrderState = "open"
+	StatePending  OrderState = "pending"
+	StateClosed   OrderState = "closed"
+	StateFilled   OrderState = "filled"
+	StateDone     OrderState = "done"
+	StateCanceled OrderState = "canceled"
+	StateExpired  OrderState = "expired"
+	StateRejected OrderState = "rejected"
+)
+
+// orderStates are the order states known to the SDK, including the
+// "cancelled" spelling.
+var orderStates = []OrderState{
+	StateActive, StateOpen, StatePending, StateClosed, StateFilled, StateDone,
+	StateCanceled, "cancelled", StateExpired, StateRejected,
+}
+
+// OrderStateValues returns the order states known to the SDK. Besides the
+// constants it includes "cancelled", which Valid and IsTerminal accept as well.
+func OrderStateValues() []OrderState {
+	return slices.Clone(orderStates)
+}
+
+// Valid reports whether s is an order state known to the SDK.
+func (s OrderState) Valid() bool {
+	return slices.Contains(orderStates, s)
+}
+
+// IsTerminal reports whether an order in state s can no longer change, i.e. it
+// was filled or cancelled. The comparison ignores case.
+func (s OrderState) IsTerminal() bool {
+	switch OrderState(strings.ToLower(string(s))) {
+	case StateClosed, StateFilled, StateDone, StateCanceled, "cancelled", StateExpired, StateRejected:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,25 @@
+package types
+
+import u "github.com/rzabhd80/go-sdk-bitpin/utils"
+
+// FormatAmount formats a base amount for order submission, truncated to the
+// market's BaseAmountPrecision and in plain decimal notation.
+//
+// Example:
+//
+//	params.BaseAmount = market.FormatAmount(budget / price)
+func (m Market) FormatAmount(amount float64) string {
+	return u.TruncateAmount(amount, m.BaseAmountPrecision)
+}
+
+// FormatQuoteAmount formats a quote amount for order submission, truncated to
+// the market's QuoteAmountPrecision.
+func (m Market) FormatQuoteAmount(amount float64) string {
+	return u.TruncateAmount(amount, m.QuoteAmountPrecision)
+}
+
+// FormatPrice formats a price for order submission, truncated to the market's
+// PricePrecision.
+func (m Market) FormatPrice(price float64) string {
+	return u.TruncateAmount(price, m.PricePrecision)
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // Currency represents a cryptocurrency or fiat currency with its attributes.
 // This struct is typically used to model currencies in trading systems or
 // exchanges.
@@ -60,6 +62,14 @@ type Market struct {
 	// amount of the quote asset in transactions. For example, a precision of 2
 	// allows values like 123.45 USDT.
 	QuoteAmountPrecision int `json:"quote_amount_precision"`
+
+	// MinBaseAmount is the smallest base amount accepted for an order in this
+	// market, such as "0.0001". It is empty if the API does not report it.
+	MinBaseAmount string `json:"min_base_amount,omitempty"`
+
+	// MinQuoteAmount is the smallest order value in the quote asset, such as
+	// "5" USDT. It is empty if the API does not report it.
+	MinQuoteAmount string `json:"min_quote_amount,omitempty"`
 }
 
 // Ticker represents real-time market data for a specific trading symbol,
@@ -90,6 +100,28 @@ type Ticker struct {
 	Timestamp float64 `json:"timestamp"`
 }
 
+// GetTickersParams represents the parameters used to fetch tickers.
+// It includes optional filters for narrowing down the results.
+type GetTickersParams struct {
+	// Symbol restricts the response to a single market, such as "BTC_USDT".
+	// This field is optional.
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// GetRecentTradesParams represents the parameters used to fetch the recent
+// trades of a market. All fields are optional.
+type GetRecentTradesParams struct {
+	// Limit specifies the maximum number of trades to return.
+	Limit int `json:"limit,omitempty"`
+
+	// SinceId only returns trades with an ID greater than this one. It is used
+	// for incremental syncing by passing the last ID seen.
+	SinceId string `json:"since_id,omitempty"`
+
+	// Since only returns trades executed after this Unix timestamp (in seconds).
+	Since int64 `json:"since,omitempty"`
+}
+
 // OrderBook represents the state of an order book for a specific trading market,
 // including the current asks (sell orders) and bids (buy orders).
 type OrderBook struct {
@@ -133,7 +165,11 @@ type Trade struct {
 
 	// Side indicates the direction of the trade, either "buy" or "sell", from the
 	// perspective of the taker (the trader who initiated the market order).
-	Side string `json:"side"`
+	Side OrderSide `json:"side"`
+
+	// CreatedAt is the time the trade was executed. It is zero if the API did
+	// not report it.
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // Currencies represents a collection of Currency objects.
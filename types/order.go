@@ -14,10 +14,10 @@ type OrderStatus struct {
 	Symbol string `json:"symbol"`
 
 	// Type indicates the type of the order, such as "limit" or "market".
-	Type string `json:"type"`
+	Type OrderType `json:"type"`
 
 	// Side specifies the direction of the order, either "buy" or "sell".
-	Side string `json:"side"`
+	Side OrderSide `json:"side"`
 
 	// BaseAmount represents the amount of the base currency involved in the order.
 	// For example, in a BTC_USDT market, this would represent the amount of BTC.
@@ -46,7 +46,7 @@ type OrderStatus struct {
 
 	// State indicates the current state of the order, such as "open", "closed",
 	// "cancelled", or "pending".
-	State string `json:"state"`
+	State OrderState `json:"state"`
 
 	// CreatedAt is the timestamp when the order was created. It is represented as
 	// a time.Time object for accurate time management.
@@ -82,10 +82,10 @@ type CreateOrderParams struct {
 	Symbol string `json:"symbol"`
 
 	// Type specifies the type of order, such as "limit" or "market".
-	Type string `json:"type"`
+	Type OrderType `json:"type"`
 
 	// Side indicates whether the order is a "buy" or "sell".
-	Side string `json:"side"`
+	Side OrderSide `json:"side"`
 
 	// BaseAmount specifies the amount of the base currency for the order. It is
 	// optional and required for certain order types.
@@ -110,6 +110,13 @@ type CreateOrderParams struct {
 	// Identifier is an optional unique identifier for the order, often used for
 	// client-side tracking or reconciliation.
 	Identifier string `json:"identifier,omitempty"`
+
+	// TimeInForce is emulated by the client and not sent to the API. Empty
+	// means TimeInForceGTC. IOC and FOK are only valid for limit and market
+	// orders: the order is submitted, its fills are checked right away, and
+	// the remainder is cancelled. FOK orders are also checked against the order
+	// book first and not submitted if it cannot fill them.
+	TimeInForce TimeInForce `json:"-"`
 }
 
 // GetOrdersHistoryParams represents the parameters used to fetch a historical
@@ -121,15 +128,15 @@ type GetOrdersHistoryParams struct {
 
 	// Side specifies whether to fetch "buy" or "sell" orders. This field is
 	// optional and used for filtering.
-	Side string `json:"side,omitempty"`
+	Side OrderSide `json:"side,omitempty"`
 
 	// State indicates the state of the orders, such as "open", "closed", or
 	// "cancelled". This field is optional.
-	State string `json:"state,omitempty"`
+	State OrderState `json:"state,omitempty"`
 
 	// Type specifies the type of the orders, such as "limit" or "market". This
 	// field is optional and used for filtering.
-	Type string `json:"type,omitempty"`
+	Type OrderType `json:"type,omitempty"`
 
 	// Identifier is an optional unique identifier for filtering orders.
 	Identifier string `json:"identifier,omitempty"`
@@ -194,7 +201,7 @@ type UserTrade struct {
 
 	// Side indicates whether the trade was a "buy" or "sell" from the user's
 	// perspective.
-	Side string `json:"side"`
+	Side OrderSide `json:"side"`
 
 	// CommissionCurrency specifies the currency in which the commission was charged.
 	// For example, "BTC" or "USDT".
@@ -218,7 +225,7 @@ type GetUserTradesParams struct {
 
 	// Side specifies whether to fetch "buy" or "sell" trades. This field is
 	// optional and used for filtering.
-	Side string `json:"side,omitempty"`
+	Side OrderSide `json:"side,omitempty"`
 
 	// Offset is the starting index for paginated results. This field is optional
 	// and used for pagination.
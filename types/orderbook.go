@@ -0,0 +1,378 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ErrEmptyBookSide is returned by OrderBook helpers when the requested side of
+// the book has no levels.
+var ErrEmptyBookSide = errors.New("order book side is empty")
+
+// BookSide selects one side of an OrderBook.
+type BookSide string
+
+const (
+	// Bids is the buy side of the book, sorted from the highest price down.
+	Bids BookSide = "bids"
+
+	// Asks is the sell side of the book, sorted from the lowest price up.
+	Asks BookSide = "asks"
+)
+
+// PriceLevel is a parsed order book entry.
+type PriceLevel struct {
+	// Price is the price of the level.
+	Price float64
+
+	// Amount is the base amount available at Price.
+	Amount float64
+}
+
+// Levels parses one side of the book into price levels, keeping the order
+// returned by the API. Malformed entries result in an error.
+func (o *OrderBook) Levels(side BookSide) ([]PriceLevel, error) {
+	raw := o.side(side)
+	levels := make([]PriceLevel, 0, len(raw))
+	for _, entry := range raw {
+		level, err := parseLevel(side, entry)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// side returns the raw entries of one side of the book.
+func (o *OrderBook) side(side BookSide) [][]string {
+	if side == Asks {
+		return o.Asks
+	}
+	return o.Bids
+}
+
+// best returns the first level of a side.
+func (o *OrderBook) best(side BookSide) (PriceLevel, error) {
+	raw := o.side(side)
+	if len(raw) == 0 {
+		return PriceLevel{}, fmt.Errorf("%s: %w", side, ErrEmptyBookSide)
+	}
+	return parseLevel(side, raw[0])
+}
+
+// parseLevel parses a single ["price", "amount"] entry.
+func parseLevel(side BookSide, entry []string) (PriceLevel, error) {
+	if len(entry) < 2 {
+		return PriceLevel{}, fmt.Errorf("malformed %s entry: %v", side, entry)
+	}
+	price, err := strconv.ParseFloat(entry[0], 64)
+	if err != nil {
+		return PriceLevel{}, fmt.Errorf("invalid %s price %q: %w", side, entry[0], err)
+	}
+	amount, err := strconv.ParseFloat(entry[1], 64)
+	if err != nil {
+		return PriceLevel{}, fmt.Errorf("invalid %s amount %q: %w", side, entry[1], err)
+	}
+	return PriceLevel{Price: price, Amount: amount}, nil
+}
+
+// BestBid returns the highest bid.
+//
+// Example:
+//
+//	bid, err := orderBook.BestBid()
+//	if err == nil {
+//	    fmt.Printf("best bid %f for %f\n", bid.Price, bid.Amount)
+//	}
+func (o *OrderBook) BestBid() (PriceLevel, error) {
+	return o.best(Bids)
+}
+
+// BestAsk returns the lowest ask.
+func (o *OrderBook) BestAsk() (PriceLevel, error) {
+	return o.best(Asks)
+}
+
+// Spread returns the difference between the best ask and the best bid.
+func (o *OrderBook) Spread() (float64, error) {
+	bid, err := o.BestBid()
+	if err != nil {
+		return 0, err
+	}
+	ask, err := o.BestAsk()
+	if err != nil {
+		return 0, err
+	}
+	return ask.Price - bid.Price, nil
+}
+
+// MidPrice returns the average of the best ask and the best bid.
+func (o *OrderBook) MidPrice() (float64, error) {
+	bid, err := o.BestBid()
+	if err != nil {
+		return 0, err
+	}
+	ask, err := o.BestAsk()
+	if err != nil {
+		return 0, err
+	}
+	return (ask.Price + bid.Price) / 2, nil
+}
+
+// TotalDepth returns the base amount available on one side of the book within
+// withinPct percent of the best price of that side. For example, a withinPct of
+// 1 sums all asks priced at most 1% above the best ask. A withinPct of zero or
+// less sums the whole side.
+//
+// Example:
+//
+//	depth, err := orderBook.TotalDepth(types.Bids, 0.5)
+//	// depth is the base amount bid within 0.5% of the best bid
+func (o *OrderBook) TotalDepth(side BookSide, withinPct float64) (float64, error) {
+	levels, err := o.Levels(side)
+	if err != nil {
+		return 0, err
+	}
+	if len(levels) == 0 {
+		return 0, fmt.Errorf("%s: %w", side, ErrEmptyBookSide)
+	}
+
+	best := levels[0].Price
+	limit := best * (1 + withinPct/100)
+	if side == Bids {
+		limit = best * (1 - withinPct/100)
+	}
+
+	var total float64
+	for _, level := range levels {
+		if withinPct > 0 {
+			if side == Asks && level.Price > limit {
+				break
+			}
+			if side == Bids && level.Price < limit {
+				break
+			}
+		}
+		total += level.Amount
+	}
+	return total, nil
+}
+
+// FillEstimate is the result of walking one side of an order book to fill a
+// target amount, as done by a market order.
+type FillEstimate struct {
+	// AveragePrice is the volume-weighted average execution price of the
+	// obtainable amount. It is zero when nothing can be filled.
+	AveragePrice float64
+
+	// BestPrice is the price of the first level consumed.
+	BestPrice float64
+
+	// WorstPrice is the price of the last level consumed.
+	WorstPrice float64
+
+	// BaseAmount is the base amount that can be filled.
+	BaseAmount float64
+
+	// QuoteAmount is the quote amount exchanged for BaseAmount.
+	QuoteAmount float64
+
+	// Levels is the number of price levels consumed.
+	Levels int
+
+	// Complete reports whether the book holds enough liquidity to fill the
+	// full target amount.
+	Complete bool
+}
+
+// Slippage returns the relative difference between the average and the best
+// price, as a fraction. It is zero when nothing can be filled.
+func (e FillEstimate) Slippage() float64 {
+	if e.BestPrice == 0 || e.BaseAmount == 0 {
+		return 0
+	}
+	return math.Abs(e.AveragePrice-e.BestPrice) / e.BestPrice
+}
+
+// FillByBase estimates filling a target base amount against one side of the
+// book. Use Asks to estimate a buy and Bids to estimate a sell.
+//
+// Example:
+//
+//	est, err := orderBook.FillByBase(types.Asks, 0.5)
+//	if err == nil && est.Complete {
+//	    fmt.Printf("buying 0.5 costs %f at an average of %f\n", est.QuoteAmount, est.AveragePrice)
+//	}
+func (o *OrderBook) FillByBase(side BookSide, baseAmount float64) (FillEstimate, error) {
+	return o.fill(side, baseAmount, false)
+}
+
+// FillByQuote estimates spending (for Asks) or receiving (for Bids) a target
+// quote amount against one side of the book.
+//
+// Example:
+//
+//	est, err := orderBook.FillByQuote(types.Asks, 1000)
+//	// est.BaseAmount is how much base 1000 quote buys
+func (o *OrderBook) FillByQuote(side BookSide, quoteAmount float64) (FillEstimate, error) {
+	return o.fill(side, quoteAmount, true)
+}
+
+// fill walks the levels of a side until the target amount, in base or quote
+// units, is reached.
+func (o *OrderBook) fill(side BookSide, target float64, quote bool) (FillEstimate, error) {
+	var est FillEstimate
+	if target <= 0 {
+		return est, fmt.Errorf("target amount must be positive, got %v", target)
+	}
+
+	levels, err := o.Levels(side)
+	if err != nil {
+		return est, err
+	}
+
+	for i, level := range levels {
+		if i == 0 {
+			est.BestPrice = level.Price
+		}
+
+		base := level.Amount
+		if quote {
+			if remaining := target - est.QuoteAmount; base*level.Price > remaining {
+				base = remaining / level.Price
+			}
+		} else if remaining := target - est.BaseAmount; base > remaining {
+			base = remaining
+		}
+
+		est.BaseAmount += base
+		est.QuoteAmount += base * level.Price
+		est.WorstPrice = level.Price
+		est.Levels++
+
+		filled := est.BaseAmount
+		if quote {
+			filled = est.QuoteAmount
+		}
+		if filled >= target*(1-1e-12) {
+			est.Complete = true
+			break
+		}
+	}
+
+	if est.BaseAmount > 0 {
+		est.AveragePrice = est.QuoteAmount / est.BaseAmount
+	}
+	return est, nil
+}
+
+// BookVolume is the liquidity of the top levels of one side of a book.
+type BookVolume struct {
+	// Levels is the number of levels included.
+	Levels int
+
+	// Base is the base amount of the levels.
+	Base float64
+
+	// Quote is the quote value of the levels, price times amount.
+	Quote float64
+}
+
+// VWAP returns the volume-weighted average price of the levels, or zero if
+// they hold no volume.
+func (v BookVolume) VWAP() float64 {
+	if v.Base == 0 {
+		return 0
+	}
+	return v.Quote / v.Base
+}
+
+// TopVolume returns the liquidity of the best levels of one side. A levels
+// value of zero or less includes the whole side.
+//
+// Example:
+//
+//	bids, err := orderBook.TopVolume(types.Bids, 5)
+//	// bids.Base is the base amount bid on the five best levels
+func (o *OrderBook) TopVolume(side BookSide, levels int) (BookVolume, error) {
+	parsed, err := o.Levels(side)
+	if err != nil {
+		return BookVolume{}, err
+	}
+	if len(parsed) == 0 {
+		return BookVolume{}, fmt.Errorf("%s: %w", side, ErrEmptyBookSide)
+	}
+	if levels > 0 && levels < len(parsed) {
+		parsed = parsed[:levels]
+	}
+	volume := BookVolume{Levels: len(parsed)}
+	for _, level := range parsed {
+		volume.Base += level.Amount
+		volume.Quote += level.Amount * level.Price
+	}
+	return volume, nil
+}
+
+// topVolumes returns the liquidity of the best levels of both sides.
+func (o *OrderBook) topVolumes(levels int) (bids, asks BookVolume, err error) {
+	if bids, err = o.TopVolume(Bids, levels); err != nil {
+		return
+	}
+	asks, err = o.TopVolume(Asks, levels)
+	return
+}
+
+// Imbalance returns the volume imbalance of the best levels, between -1 and 1:
+// (bid volume - ask volume) / (bid volume + ask volume), in base units. Positive
+// values mean more buying interest. A levels value of zero or less includes
+// the whole book.
+//
+// Example:
+//
+//	imbalance, err := orderBook.Imbalance(10)
+//	if err == nil && imbalance > 0.3 {
+//	    fmt.Println("bids dominate the top of the book")
+//	}
+func (o *OrderBook) Imbalance(levels int) (float64, error) {
+	bids, asks, err := o.topVolumes(levels)
+	if err != nil {
+		return 0, err
+	}
+	if bids.Base+asks.Base == 0 {
+		return 0, nil
+	}
+	return (bids.Base - asks.Base) / (bids.Base + asks.Base), nil
+}
+
+// WeightedMid returns the depth-weighted mid price of the best levels: the
+// volume-weighted average prices of both sides, each weighted by the volume of
+// the opposite side. The price is pulled towards the side with less volume,
+// where the next trade is more likely to move the market. With levels set to
+// 1 it is the micro-price of the best bid and ask.
+func (o *OrderBook) WeightedMid(levels int) (float64, error) {
+	bids, asks, err := o.topVolumes(levels)
+	if err != nil {
+		return 0, err
+	}
+	if bids.Base+asks.Base == 0 {
+		return (bids.VWAP() + asks.VWAP()) / 2, nil
+	}
+	return (bids.VWAP()*asks.Base + asks.VWAP()*bids.Base) / (bids.Base + asks.Base), nil
+}
+
+// LiquidityRatio returns the bid volume divided by the ask volume of the best
+// levels, in quote terms. Values above 1 mean more liquidity on the bid side.
+// It returns an error if the ask side of the levels holds no volume.
+func (o *OrderBook) LiquidityRatio(levels int) (float64, error) {
+	bids, asks, err := o.topVolumes(levels)
+	if err != nil {
+		return 0, err
+	}
+	if asks.Quote == 0 {
+		return 0, fmt.Errorf("%s: no volume in the top %d levels", Asks, asks.Levels)
+	}
+	return bids.Quote / asks.Quote, nil
+}
@@ -0,0 +1,86 @@
+package types
+
+// DefaultListLimit is the page size used when ListOptions.Limit is not set.
+const DefaultListLimit = 100
+
+// ListOptions selects a page of a list endpoint. The same options are used for
+// orders, trades and wallets.
+type ListOptions struct {
+	// Limit is the maximum number of items per page. Defaults to
+	// DefaultListLimit.
+	Limit int
+
+	// Offset is the number of items to skip.
+	Offset int
+
+	// Cursor is the NextCursor of a previous page. When set, the page it points
+	// to is fetched and Offset is ignored. Only its path and query are used;
+	// the page is fetched from the base URL of the client.
+	Cursor string
+}
+
+// Page is a page of items returned by a list endpoint.
+type Page[T any] struct {
+	// Items holds the items of this page.
+	Items []T
+
+	// HasMore reports whether another page may follow.
+	HasMore bool
+
+	// NextOffset is the offset of the item following this page.
+	NextOffset int
+
+	// NextCursor is the link to the next page, if the API returned one.
+	NextCursor string
+
+	// Total is the total number of items across all pages, or zero if the API
+	// does not report it.
+	Total int
+
+	// Limit is the page size the page was requested with.
+	Limit int
+}
+
+// Next returns the options selecting the page after p.
+//
+// Example:
+//
+//	opts := types.ListOptions{Limit: 50}
+//	for {
+//	    page, err := client.ListOrders(ctx, params, opts)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    process(page.Items)
+//	    if !page.HasMore {
+//	        break
+//	    }
+//	    opts = page.Next()
+//	}
+func (p *Page[T]) Next() ListOptions {
+	return ListOptions{Limit: p.Limit, Offset: p.NextOffset, Cursor: p.NextCursor}
+}
+
+// NewPage converts a PagedResponse fetched with opts into a Page.
+//
+// Envelopes report more pages through their next link. Bare arrays carry no
+// pagination data, so a full page, holding opts.Limit items, is assumed to be
+// followed by another one.
+func NewPage[T any](resp *PagedResponse[T], opts ListOptions) *Page[T] {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultListLimit
+	}
+	page := &Page[T]{
+		Items:      resp.Results,
+		NextOffset: opts.Offset + len(resp.Results),
+		NextCursor: resp.Next,
+		Limit:      opts.Limit,
+	}
+	if resp.bare {
+		page.HasMore = len(resp.Results) >= opts.Limit
+	} else {
+		page.HasMore = resp.Next != ""
+		page.Total = resp.Count
+	}
+	return page
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// PagedResponse is a page of a list endpoint that answers with a
+// {count, next, previous, results} envelope.
+//
+// It also decodes endpoints that answer with a bare JSON array, which is then
+// treated as a single page holding every item, so code written against
+// PagedResponse keeps working whichever format the API uses.
+type PagedResponse[T any] struct {
+	// Count is the total number of items across all pages, as reported by the
+	// API. For a bare array it is the length of the array.
+	Count int `json:"count"`
+
+	// Next is the URL of the next page, or empty on the last page.
+	Next string `json:"next"`
+
+	// Previous is the URL of the previous page, or empty on the first page.
+	Previous string `json:"previous"`
+
+	// Results holds the items of this page.
+	Results []T `json:"results"`
+
+	// bare is set when the page was decoded from a bare array.
+	bare bool
+}
+
+// HasNext reports whether there is a page after this one.
+func (p *PagedResponse[T]) HasNext() bool {
+	return p.Next != ""
+}
+
+// HasPrevious reports whether there is a page before this one.
+func (p *PagedResponse[T]) HasPrevious() bool {
+	return p.Previous != ""
+}
+
+// UnmarshalJSON decodes an envelope or a bare array. Items are decoded
+// leniently, as described by utils.UnmarshalLenient.
+func (p *PagedResponse[T]) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var results []T
+		if err := u.UnmarshalLenient(trimmed, &results); err != nil {
+			return err
+		}
+		*p = PagedResponse[T]{Count: len(results), Results: results, bare: true}
+		return nil
+	}
+
+	var envelope struct {
+		Count    int             `json:"count"`
+		Next     string          `json:"next"`
+		Previous string          `json:"previous"`
+		Results  json.RawMessage `json:"results"`
+	}
+	if err := u.UnmarshalLenient(trimmed, &envelope); err != nil {
+		return err
+	}
+
+	var results []T
+	if len(envelope.Results) > 0 {
+		if err := u.UnmarshalLenient(envelope.Results, &results); err != nil {
+			return err
+		}
+	}
+	*p = PagedResponse[T]{
+		Count:    envelope.Count,
+		Next:     envelope.Next,
+		Previous: envelope.Previous,
+		Results:  results,
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package types
+
+import "time"
+
+// The types in this file describe the schema of the v2 API. The client
+// converts them to and from their v1 counterparts, so code written against
+// Market, OrderStatus and CreateOrderParams keeps working when a client is
+// switched to the v2 API.
+
+// MarketStatusActive is the v2 status of a market that can be traded.
+const MarketStatusActive = "active"
+
+// MarketV2 is a market as returned by the v2 markets endpoint. Precisions and
+// order limits are grouped into nested objects, and the tradable flag is
+// replaced by a status.
+type MarketV2 struct {
+	// Symbol is the unique identifier of the market, such as "BTC_USDT".
+	Symbol string `json:"symbol"`
+
+	// Name is the human-readable name of the market.
+	Name string `json:"name"`
+
+	// Base is the base asset of the market, such as "BTC".
+	Base string `json:"base"`
+
+	// Quote is the quote asset of the market, such as "USDT".
+	Quote string `json:"quote"`
+
+	// Status is MarketStatusActive for markets that can be traded. Other
+	// values, such as "halted", mean the market is not tradable.
+	Status string `json:"status"`
+
+	// Precision holds the number of decimal places of prices and amounts.
+	Precision MarketV2Precision `json:"precision"`
+
+	// Limits holds the minimum order size.
+	Limits MarketV2Limits `json:"limits"`
+}
+
+// MarketV2Precision holds the precisions of a v2 market.
+type MarketV2Precision struct {
+	Price       int `json:"price"`
+	BaseAmount  int `json:"base_amount"`
+	QuoteAmount int `json:"quote_amount"`
+}
+
+// MarketV2Limits holds the order limits of a v2 market.
+type MarketV2Limits struct {
+	MinBaseAmount  string `json:"min_base_amount,omitempty"`
+	MinQuoteAmount string `json:"min_quote_amount,omitempty"`
+}
+
+// Market converts the market to the v1 schema.
+func (m MarketV2) Market() Market {
+	return Market{
+		Symbol:               m.Symbol,
+		Name:                 m.Name,
+		Base:                 m.Base,
+		Quote:                m.Quote,
+		Tradable:             m.Status == MarketStatusActive,
+		PricePrecision:       m.Precision.Price,
+		BaseAmountPrecision:  m.Precision.BaseAmount,
+		QuoteAmountPrecision: m.Precision.QuoteAmount,
+		MinBaseAmount:        m.Limits.MinBaseAmount,
+		MinQuoteAmount:       m.Limits.MinQuoteAmount,
+	}
+}
+
+// OrderStatusV2 is an order as returned by the v2 order endpoints. It renames
+// several fields of OrderStatus.
+type OrderStatusV2 struct {
+	Id             int        `json:"id"`
+	Symbol         string     `json:"symbol"`
+	Type           OrderType  `json:"type"`
+	Side           OrderSide  `json:"side"`
+	BaseAmount     string     `json:"base_amount"`
+	QuoteAmount    string     `json:"quote_amount"`
+	Price          string     `json:"price"`
+	StopPrice      string     `json:"stop_price"`
+	OcoTargetPrice string     `json:"oco_target_price"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ClosedAt       string     `json:"closed_at"`
+	Commission     string     `json:"fee"`
+	State          OrderState `json:"status"`
+
+	// ClientOrderId is the Identifier of the v1 schema.
+	ClientOrderId string `json:"client_order_id"`
+
+	// FilledBaseAmount is the DealedBaseAmount of the v1 schema.
+	FilledBaseAmount string `json:"filled_base_amount"`
+
+	// FilledQuoteAmount is the DealedQuoteAmount of the v1 schema.
+	FilledQuoteAmount string `json:"filled_quote_amount"`
+
+	// CancelRequested is the ReqToCancel of the v1 schema.
+	CancelRequested bool `json:"cancel_requested"`
+}
+
+// OrderStatus converts the order to the v1 schema.
+func (o OrderStatusV2) OrderStatus() OrderStatus {
+	return OrderStatus{
+		Id:                o.Id,
+		Symbol:            o.Symbol,
+		Type:              o.Type,
+		Side:              o.Side,
+		BaseAmount:        o.BaseAmount,
+		QuoteAmount:       o.QuoteAmount,
+		Price:             o.Price,
+		StopPrice:         o.StopPrice,
+		OcoTargetPrice:    o.OcoTargetPrice,
+		Identifier:        o.ClientOrderId,
+		State:             o.State,
+		CreatedAt:         o.CreatedAt,
+		ClosedAt:          o.ClosedAt,
+		DealedBaseAmount:  o.FilledBaseAmount,
+		DealedQuoteAmount: o.FilledQuoteAmount,
+		ReqToCancel:       o.CancelRequested,
+		Commission:        o.Commission,
+	}
+}
+
+// CreateOrderParamsV2 is the body of a v2 order request. It sends the
+// Identifier of CreateOrderParams as client_order_id.
+type CreateOrderParamsV2 struct {
+	Symbol         string    `json:"symbol"`
+	Type           OrderType `json:"type"`
+	Side           OrderSide `json:"side"`
+	BaseAmount     string    `json:"base_amount,omitempty"`
+	QuoteAmount    string    `json:"quote_amount,omitempty"`
+	Price          string    `json:"price,omitempty"`
+	StopPrice      string    `json:"stop_price,omitempty"`
+	OcoTargetPrice string    `json:"oco_target_price,omitempty"`
+	ClientOrderId  string    `json:"client_order_id,omitempty"`
+}
+
+// V2 converts the parameters to the body of a v2 order request.
+func (p CreateOrderParams) V2() CreateOrderParamsV2 {
+	return CreateOrderParamsV2{
+		Symbol:         p.Symbol,
+		Type:           p.Type,
+		Side:           p.Side,
+		BaseAmount:     p.BaseAmount,
+		QuoteAmount:    p.QuoteAmount,
+		Price:          p.Price,
+		StopPrice:      p.StopPrice,
+		OcoTargetPrice: p.OcoTargetPrice,
+		ClientOrderId:  p.Identifier,
+	}
+}
@@ -0,0 +1,194 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// ValidationError lists the problems found when validating request parameters
+// locally, before they are sent to the API. Fields uses the API's field names,
+// like APIError.Details.
+type ValidationError struct {
+	// Fields maps each invalid field to the problems found with it.
+	Fields map[string][]string
+}
+
+// Error lists the invalid fields in alphabetical order.
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, strings.Join(e.Fields[name], "; ")))
+	}
+	return "invalid parameters: " + strings.Join(parts, ", ")
+}
+
+// add records a problem with a field.
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	if e.Fields == nil {
+		e.Fields = make(map[string][]string)
+	}
+	e.Fields[field] = append(e.Fields[field], fmt.Sprintf(format, args...))
+}
+
+// Validate checks the parameters for the field combinations required by their
+// order type, so mistakes are reported before a request is sent:
+//
+//   - symbol, side and type are required and must be known values.
+//   - limit orders need a price and an amount.
+//   - market orders need exactly one of base_amount and quote_amount.
+//   - stop_limit orders need a stop_price and a price; oco orders need a price,
+//     a stop_price and an oco_target_price.
+//   - Every amount and price that is given must be a positive decimal.
+//   - time_in_force must be a known value; IOC and FOK need a limit or market
+//     order.
+//
+// Returns a *ValidationError listing every problem found, or nil.
+//
+// Example:
+//
+//	params := types.CreateOrderParams{Symbol: "BTC_USDT", Type: types.TypeLimit, Side: types.SideBuy}
+//	if err := params.Validate(); err != nil {
+//	    fmt.Println(err) // invalid parameters: base_amount: ..., price: This field is required.
+//	}
+func (p CreateOrderParams) Validate() error {
+	var err ValidationError
+	const required = "This field is required."
+
+	if p.Symbol == "" {
+		err.add("symbol", required)
+	}
+	if p.Side == "" {
+		err.add("side", required)
+	} else if !p.Side.Valid() {
+		err.add("side", "%q is not a valid side.", p.Side)
+	}
+
+	for field, value := range map[string]string{
+		"base_amount":      p.BaseAmount,
+		"quote_amount":     p.QuoteAmount,
+		"price":            p.Price,
+		"stop_price":       p.StopPrice,
+		"oco_target_price": p.OcoTargetPrice,
+	} {
+		if value == "" {
+			continue
+		}
+		if n, parseErr := strconv.ParseFloat(value, 64); parseErr != nil || n <= 0 {
+			err.add(field, "%q is not a positive number.", value)
+		}
+	}
+
+	hasAmount := p.BaseAmount != "" || p.QuoteAmount != ""
+	switch p.Type {
+	case "":
+		err.add("type", required)
+	case TypeMarket:
+		if p.BaseAmount != "" && p.QuoteAmount != "" {
+			err.add("base_amount", "Only one of base_amount and quote_amount may be given for a market order.")
+		}
+	case TypeLimit, TypeStopLimit, TypeOCO:
+		if p.Price == "" {
+			err.add("price", required)
+		}
+		if p.Type != TypeLimit && p.StopPrice == "" {
+			err.add("stop_price", required)
+		}
+		if p.Type == TypeOCO && p.OcoTargetPrice == "" {
+			err.add("oco_target_price", required)
+		}
+	default:
+		err.add("type", "%q is not a valid order type.", p.Type)
+	}
+	if !hasAmount {
+		err.add("base_amount", "One of base_amount and quote_amount is required.")
+	}
+
+	switch p.TimeInForce {
+	case "", TimeInForceGTC:
+	case TimeInForceIOC, TimeInForceFOK:
+		if p.Type != TypeLimit && p.Type != TypeMarket {
+			err.add("time_in_force", "%s is only supported for limit and market orders.", p.TimeInForce)
+		}
+	default:
+		err.add("time_in_force", "%q is not a valid time in force.", p.TimeInForce)
+	}
+
+	if err.Fields != nil {
+		return &err
+	}
+	return nil
+}
+
+// ValidateMarket checks the parameters against the minimum order size of a
+// market, so orders the exchange would reject as too small fail locally. The
+// order value of a limit order given by base amount is its base amount times
+// its price. Minimums the market does not report are not checked, and neither
+// is the value of a market order given by base amount, which depends on the
+// book.
+//
+// Returns a *ValidationError listing every problem found, or nil.
+//
+// Example:
+//
+//	market, _ := client.GetMarket("BTC_USDT")
+//	if err := params.ValidateMarket(*market); err != nil {
+//	    fmt.Println(err) // invalid parameters: quote_amount: The order value 2 is below the minimum of 5 USDT.
+//	}
+func (p CreateOrderParams) ValidateMarket(m Market) error {
+	var err ValidationError
+
+	if minBase, ok := parsePositive(m.MinBaseAmount); ok {
+		if base, ok := parsePositive(p.BaseAmount); ok && base.Cmp(minBase) < 0 {
+			err.add("base_amount", "The amount %s is below the minimum of %s %s.", p.BaseAmount, m.MinBaseAmount, m.Base)
+		}
+	}
+
+	if minQuote, ok := parsePositive(m.MinQuoteAmount); ok {
+		value, field := parseOrderValue(p)
+		if value != nil && value.Cmp(minQuote) < 0 {
+			err.add(field, "The order value %s is below the minimum of %s %s.", value.FloatString(m.QuoteAmountPrecision), m.MinQuoteAmount, m.Quote)
+		}
+	}
+
+	if err.Fields != nil {
+		return &err
+	}
+	return nil
+}
+
+// parseOrderValue returns the order value in the quote asset and the field it
+// is derived from, or nil if it cannot be known before the order executes.
+func parseOrderValue(p CreateOrderParams) (*big.Rat, string) {
+	if quote, ok := parsePositive(p.QuoteAmount); ok {
+		return quote, "quote_amount"
+	}
+	base, okBase := parsePositive(p.BaseAmount)
+	price, okPrice := parsePositive(p.Price)
+	if !okBase || !okPrice || p.Type == TypeMarket {
+		return nil, ""
+	}
+	return new(big.Rat).Mul(base, price), "base_amount"
+}
+
+// parsePositive parses a decimal amount and reports whether it is positive.
+func parsePositive(amount string) (*big.Rat, bool) {
+	if amount == "" {
+		return nil, false
+	}
+	r, err := u.ParseAmount(amount)
+	if err != nil || r.Sign() <= 0 {
+		return nil, false
+	}
+	return r, true
+}
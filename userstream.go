@@ -0,0 +1,308 @@
+package bitpin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// DefaultUserDataInterval is the default polling interval of a UserDataStream.
+const DefaultUserDataInterval = 2 * time.Second
+
+// maxFinalStateAttempts is the number of polls that try to fetch the final
+// state of an order that left the active list before it is dropped.
+const maxFinalStateAttempts = 3
+
+// UserDataEventType identifies the kind of a UserDataEvent.
+type UserDataEventType string
+
+const (
+	// UserDataOrderUpdate is emitted when an order is first seen or its state,
+	// filled amount, or cancel request flag changes.
+	UserDataOrderUpdate UserDataEventType = "order"
+
+	// UserDataFill is emitted for every new user trade (fill).
+	UserDataFill UserDataEventType = "fill"
+)
+
+// UserDataEvent is a single update delivered by a UserDataStream.
+type UserDataEvent struct {
+	// Type is the kind of event.
+	Type UserDataEventType
+
+	// Order is set for UserDataOrderUpdate events.
+	Order *t.OrderStatus
+
+	// Fill is set for UserDataFill events.
+	Fill *t.UserTrade
+
+	// Time is the local time at which the update was observed.
+	Time time.Time
+}
+
+// UserDataStreamOptions configures a UserDataStream.
+type UserDataStreamOptions struct {
+	// Symbol restricts the stream to a single market. Empty means all markets.
+	Symbol string
+
+	// Interval is the delay between two polls. Defaults to
+	// DefaultUserDataInterval.
+	Interval time.Duration
+
+	// TradesLimit is the number of most recent fills fetched per poll. It must be
+	// large enough to cover all fills that can happen within one interval.
+	// Defaults to 50.
+	TradesLimit int
+
+	// Buffer is the capacity of the event channel. Defaults to 100.
+	Buffer int
+}
+
+// UserDataStream pushes order status changes and new fills of the authenticated
+// user to a channel. It is backed by a managed long-poll loop over the order and
+// fill endpoints.
+type UserDataStream struct {
+	// C delivers order updates and fills. It is closed when the stream stops.
+	C <-chan UserDataEvent
+
+	// Errors receives polling errors. Errors are dropped when nobody reads them;
+	// the stream keeps polling after an error.
+	Errors <-chan error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Close stops the stream and waits for the polling goroutine to exit.
+func (s *UserDataStream) Close() {
+	s.once.Do(s.cancel)
+	<-s.done
+}
+
+// SubscribeUserData starts a stream of order updates and fills for the
+// authenticated user.
+//
+// Parameters:
+//   - ctx: The stream stops when the context is done.
+//   - opts: Symbol filter, polling interval, and buffer sizes.
+//
+// Returns:
+//   - A pointer to a running `UserDataStream`. Call `Close` to stop it.
+//
+// Behavior:
+//   - Every interval, active orders and the most recent fills are fetched.
+//   - Active orders are reported when first seen and whenever their state,
+//     filled amount, or cancel request flag changes.
+//   - Orders that leave the active list are fetched once more and reported with
+//     their final state. If that fails, the error is reported and the fetch is
+//     retried on the next polls; after three failed attempts the order is
+//     dropped without a final update.
+//   - Fills are polled even when the orders could not be.
+//   - Fills that exist when the stream starts are not reported; every later fill
+//     is reported exactly once.
+//
+// Example:
+//
+//	stream := client.SubscribeUserData(ctx, bitpin.UserDataStreamOptions{Symbol: "BTC_USDT"})
+//	defer stream.Close()
+//	for event := range stream.C {
+//	    switch event.Type {
+//	    case bitpin.UserDataFill:
+//	        log.Printf("fill: %s @ %s", event.Fill.BaseAmount, event.Fill.Price)
+//	    case bitpin.UserDataOrderUpdate:
+//	        log.Printf("order %d: %s", event.Order.Id, event.Order.State)
+//	    }
+//	}
+func (c *Client) SubscribeUserData(ctx context.Context, opts UserDataStreamOptions) *UserDataStream {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultUserDataInterval
+	}
+	if opts.TradesLimit <= 0 {
+		opts.TradesLimit = 50
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 100
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	events := make(chan UserDataEvent, opts.Buffer)
+	errs := make(chan error, 1)
+	stream := &UserDataStream{
+		C:      events,
+		Errors: errs,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	poller := newUserDataPoller(c, opts, func(ctx context.Context, event UserDataEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+
+	go func() {
+		defer close(stream.done)
+		defer close(events)
+
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+
+		for {
+			if err := poller.poll(ctx); err != nil && ctx.Err() == nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return stream
+}
+
+// userDataPoller holds the state needed to diff consecutive polls.
+type userDataPoller struct {
+	client *Client
+	opts   UserDataStreamOptions
+
+	// deliver hands an event to the consumer, returning false if the context
+	// was cancelled first.
+	deliver func(ctx context.Context, event UserDataEvent) bool
+
+	// orders holds the last observed version of every active order.
+	orders map[int]t.OrderStatus
+
+	// failures counts the failed attempts to fetch the final state of orders
+	// that left the active list.
+	failures map[int]int
+
+	// fills holds the IDs returned by the previous fills poll. It is nil until
+	// the first successful poll.
+	fills map[int]struct{}
+}
+
+// newUserDataPoller creates a poller that hands its events to deliver.
+func newUserDataPoller(c *Client, opts UserDataStreamOptions, deliver func(ctx context.Context, event UserDataEvent) bool) *userDataPoller {
+	return &userDataPoller{
+		client:   c,
+		opts:     opts,
+		deliver:  deliver,
+		orders:   make(map[int]t.OrderStatus),
+		failures: make(map[int]int),
+	}
+}
+
+// poll fetches orders and fills once and emits the differences. Fills are
+// polled even if the orders could not be.
+func (p *userDataPoller) poll(ctx context.Context) error {
+	ordersErr := p.pollOrders(ctx)
+	fillsErr := p.pollFills(ctx)
+	return errors.Join(ordersErr, fillsErr)
+}
+
+func (p *userDataPoller) pollOrders(ctx context.Context) error {
+	var active t.OrderStatuses
+	params := t.GetOrdersHistoryParams{Symbol: p.opts.Symbol, State: t.StateActive}
+	if err := p.client.call(ctx, "GET", OpOrders, nil, true, params, &active); err != nil {
+		return err
+	}
+
+	seen := make(map[int]struct{}, len(active))
+	for i := range active {
+		order := active[i]
+		seen[order.Id] = struct{}{}
+		prev, ok := p.orders[order.Id]
+		if !ok || orderChanged(prev, order) {
+			p.orders[order.Id] = order
+			if !p.emit(ctx, UserDataEvent{Type: UserDataOrderUpdate, Order: &order}) {
+				return nil
+			}
+		}
+	}
+
+	var errs []error
+	for id := range p.orders {
+		if _, ok := seen[id]; ok {
+			delete(p.failures, id)
+			continue
+		}
+		order, err := p.client.GetOrderWithContext(ctx, id)
+		if err != nil {
+			p.failures[id]++
+			if p.failures[id] < maxFinalStateAttempts {
+				errs = append(errs, fmt.Errorf("failed to fetch final state of order %d: %w", id, err))
+				continue
+			}
+			delete(p.orders, id)
+			delete(p.failures, id)
+			errs = append(errs, fmt.Errorf("failed to fetch final state of order %d, giving up after %d attempts: %w", id, maxFinalStateAttempts, err))
+			continue
+		}
+		delete(p.orders, id)
+		delete(p.failures, id)
+		if !p.emit(ctx, UserDataEvent{Type: UserDataOrderUpdate, Order: order}) {
+			return nil
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *userDataPoller) pollFills(ctx context.Context) error {
+	var trades t.UserTrades
+	params := t.GetUserTradesParams{Symbol: p.opts.Symbol, Limit: p.opts.TradesLimit}
+	if err := p.client.call(ctx, "GET", OpFills, nil, true, params, &trades); err != nil {
+		return err
+	}
+
+	initial := p.fills == nil
+	current := make(map[int]struct{}, len(trades))
+	for i := range trades {
+		current[trades[i].Id] = struct{}{}
+	}
+
+	if !initial {
+		// Emit oldest first; the API returns the most recent fills first.
+		for i := len(trades) - 1; i >= 0; i-- {
+			if _, ok := p.fills[trades[i].Id]; ok {
+				continue
+			}
+			fill := trades[i]
+			if !p.emit(ctx, UserDataEvent{Type: UserDataFill, Fill: &fill}) {
+				return nil
+			}
+		}
+	}
+
+	p.fills = current
+	return nil
+}
+
+// emit delivers an event, returning false if the context was cancelled first.
+func (p *userDataPoller) emit(ctx context.Context, event UserDataEvent) bool {
+	event.Time = p.client.now()
+	return p.deliver(ctx, event)
+}
+
+// orderChanged reports whether two versions of an order differ in a way that
+// is relevant to user data subscribers.
+func orderChanged(prev, next t.OrderStatus) bool {
+	return prev.State != next.State ||
+		prev.DealedBaseAmount != next.DealedBaseAmount ||
+		prev.DealedQuoteAmount != next.DealedQuoteAmount ||
+		prev.ReqToCancel != next.ReqToCancel
+}
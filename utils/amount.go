@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ParseAmount parses a decimal amount string, such as "0.0150" or "-12", into
+// an exact rational number. Surrounding whitespace is ignored.
+func ParseAmount(amount string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	return r, nil
+}
+
+// CompareAmounts compares two decimal amounts with a tolerance derived from
+// precision, the number of decimal places of the asset. Amounts that differ by
+// less than half a unit in the last decimal place are equal, so "1.50" and "1.5"
+// are always equal, and "0.123" and "0.12" are equal at precision 2. A negative
+// precision compares the amounts exactly.
+//
+// It returns -1 if a < b, 0 if a and b are equal within the tolerance, and +1
+// if a > b, or an error if either amount is not a valid decimal number.
+//
+// Example:
+//
+//	cmp, _ := CompareAmounts("0.123", "0.12", 2) // 0
+//	cmp, _ = CompareAmounts("0.123", "0.12", 3)  // 1
+func CompareAmounts(a, b string, precision int) (int, error) {
+	x, err := ParseAmount(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := ParseAmount(b)
+	if err != nil {
+		return 0, err
+	}
+
+	diff := new(big.Rat).Sub(x, y)
+	if precision >= 0 {
+		// Half a unit in the last place: 5 / 10^(precision+1).
+		tolerance := new(big.Rat).SetFrac(big.NewInt(5), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision+1)), nil))
+		if new(big.Rat).Abs(diff).Cmp(tolerance) < 0 {
+			return 0, nil
+		}
+	}
+	return diff.Sign(), nil
+}
+
+// AmountsEqual reports whether two decimal amounts are equal within the
+// tolerance of the given precision; see CompareAmounts. Unlike a string
+// comparison it ignores trailing zeros and formatting differences. Amounts that
+// are not valid decimal numbers are only equal if the strings are identical.
+//
+// Example:
+//
+//	AmountsEqual("0.0100", "0.01", 8)     // true
+//	AmountsEqual("1.00049", "1.0005", 3)  // true
+//	AmountsEqual("1.0004", "1.0006", 4)   // false
+func AmountsEqual(a, b string, precision int) bool {
+	cmp, err := CompareAmounts(a, b, precision)
+	if err != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	return cmp == 0
+}
+
+// TruncateAmount formats amount in plain decimal notation with at most
+// precision decimal places, rounding toward zero. Trailing zeros are removed,
+// so the result never uses scientific notation or more decimals than allowed.
+// Non-finite amounts yield an empty string.
+//
+// The amount is truncated on its shortest decimal representation rather than
+// by scaling, so values such as 0.29 are not turned into 0.28 by binary
+// floating-point error.
+//
+// Example:
+//
+//	TruncateAmount(0.123456789, 6) // "0.123456"
+//	TruncateAmount(1e-7, 8)        // "0.0000001"
+//	TruncateAmount(42.0, 2)        // "42"
+func TruncateAmount(amount float64, precision int) string {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return ""
+	}
+	s := strconv.FormatFloat(amount, 'f', -1, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+	if precision < 0 {
+		precision = 0
+	}
+	if len(frac) > precision {
+		frac = frac[:precision]
+	}
+	frac = strings.TrimRight(frac, "0")
+
+	if frac == "" {
+		if whole == "-0" {
+			return "0"
+		}
+		return whole
+	}
+	return whole + "." + frac
+}
@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Iranian quote currency codes that may appear across endpoints.
+const (
+	// CurrencyIRT is the Toman code used by Bitpin markets, such as "BTC_IRT".
+	CurrencyIRT = "IRT"
+
+	// CurrencyTMN is an alternative Toman code. It is equivalent to IRT.
+	CurrencyTMN = "TMN"
+
+	// CurrencyIRR is the Iranian Rial. One Toman equals ten Rials.
+	CurrencyIRR = "IRR"
+)
+
+// CanonicalCurrency returns the canonical form of a currency code. Codes are
+// upper-cased and trimmed, and TMN is mapped to IRT. Rial (IRR) is kept as is,
+// since it is a different unit.
+//
+// Example:
+//
+//	CanonicalCurrency("tmn") // "IRT"
+//	CanonicalCurrency("IRR") // "IRR"
+//	CanonicalCurrency("usdt") // "USDT"
+func CanonicalCurrency(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == CurrencyTMN {
+		return CurrencyIRT
+	}
+	return code
+}
+
+// CanonicalSymbol returns a market symbol with its base and quote currency codes
+// canonicalized, for example "btc_tmn" becomes "BTC_IRT". The "-" and "/"
+// separators are accepted and replaced with "_", so "btc/usdt" becomes
+// "BTC_USDT". Symbols without a separator are only upper-cased.
+func CanonicalSymbol(symbol string) string {
+	symbol = strings.NewReplacer("-", "_", "/", "_").Replace(strings.TrimSpace(symbol))
+	base, quote, ok := strings.Cut(symbol, "_")
+	if !ok {
+		return strings.ToUpper(symbol)
+	}
+	return CanonicalCurrency(base) + "_" + CanonicalCurrency(quote)
+}
+
+// SameSymbol reports whether two symbols denote the same market, regardless of
+// case, separator, or Toman code. For example "BTC_USDT", "btc-usdt" and
+// "BTCUSDT" are the same market, and so are "USDT_IRT" and "usdt/tmn".
+func SameSymbol(a, b string) bool {
+	return symbolKey(a) == symbolKey(b)
+}
+
+// symbolKey returns the canonical symbol without a separator.
+func symbolKey(symbol string) string {
+	return strings.ReplaceAll(CanonicalSymbol(symbol), "_", "")
+}
+
+// IsIranianCurrency reports whether the code is Toman (IRT or TMN) or Rial (IRR).
+func IsIranianCurrency(code string) bool {
+	switch CanonicalCurrency(code) {
+	case CurrencyIRT, CurrencyIRR:
+		return true
+	default:
+		return false
+	}
+}
+
+// SameCurrency reports whether two codes denote the same currency and unit.
+// IRT and TMN are the same currency, while IRT and IRR are not, because their
+// amounts differ by a factor of ten.
+func SameCurrency(a, b string) bool {
+	return CanonicalCurrency(a) == CanonicalCurrency(b)
+}
+
+// ConvertIranianAmount converts a decimal amount between Toman and Rial units.
+// Converting between two codes of the same unit returns the amount unchanged.
+//
+// Returns an error if either code is not an Iranian currency or the amount is
+// not a plain decimal number, such as "-12.50". Exponents and fractions like
+// "1e3" or "1/2" are rejected.
+//
+// Example:
+//
+//	ConvertIranianAmount("1250.5", "IRT", "IRR") // "12505"
+//	ConvertIranianAmount("12505", "IRR", "TMN")  // "1250.5"
+func ConvertIranianAmount(amount, from, to string) (string, error) {
+	if !IsIranianCurrency(from) || !IsIranianCurrency(to) {
+		return "", fmt.Errorf("cannot convert %s to %s: not an Iranian currency", from, to)
+	}
+	if !plainDecimal(amount) {
+		return "", fmt.Errorf("invalid amount %q", amount)
+	}
+
+	from, to = CanonicalCurrency(from), CanonicalCurrency(to)
+	switch {
+	case from == to:
+		return amount, nil
+	case from == CurrencyIRT:
+		return shiftDecimal(amount, 1), nil
+	default:
+		return shiftDecimal(amount, -1), nil
+	}
+}
+
+// CompareIranianAmounts compares two amounts that may be expressed in different
+// Iranian units. It returns -1 if a < b, 0 if a == b, and +1 if a > b after
+// converting both amounts to Toman.
+//
+// Example:
+//
+//	cmp, _ := CompareIranianAmounts("100", "IRT", "1000", "IRR") // 0
+func CompareIranianAmounts(a, aCurrency, b, bCurrency string) (int, error) {
+	aToman, err := ConvertIranianAmount(a, aCurrency, CurrencyIRT)
+	if err != nil {
+		return 0, err
+	}
+	bToman, err := ConvertIranianAmount(b, bCurrency, CurrencyIRT)
+	if err != nil {
+		return 0, err
+	}
+
+	x, ok := new(big.Rat).SetString(aToman)
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", a)
+	}
+	y, ok := new(big.Rat).SetString(bToman)
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", b)
+	}
+	return x.Cmp(y), nil
+}
+
+// plainDecimal reports whether amount is an optionally signed decimal number
+// made of digits and at most one decimal point, with at least one digit.
+func plainDecimal(amount string) bool {
+	if strings.HasPrefix(amount, "-") || strings.HasPrefix(amount, "+") {
+		amount = amount[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(amount, ".")
+	if intPart == "" && fracPart == "" {
+		return false
+	}
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// shiftDecimal moves the decimal point of a plain decimal string by the given
+// number of places, to the right for positive values and to the left for
+// negative ones. Leading and trailing zeros are trimmed from the result.
+func shiftDecimal(amount string, places int) string {
+	sign := ""
+	if strings.HasPrefix(amount, "-") || strings.HasPrefix(amount, "+") {
+		if amount[0] == '-' {
+			sign = "-"
+		}
+		amount = amount[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(amount, ".")
+	digits := intPart + fracPart
+	point := len(intPart) + places
+
+	for point > len(digits) {
+		digits += "0"
+	}
+	for point < 0 {
+		digits = "0" + digits
+		point++
+	}
+
+	intPart = strings.TrimLeft(digits[:point], "0")
+	fracPart = strings.TrimRight(digits[point:], "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if fracPart == "" {
+		if intPart == "0" {
+			return "0"
+		}
+		return sign + intPart
+	}
+	return sign + intPart + "." + fracPart
+}
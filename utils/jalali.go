@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// TehranTime is the fixed UTC+03:30 offset used in Iran since daylight saving
+// time was abolished in 2022. It does not depend on the system time zone data.
+var TehranTime = time.FixedZone("IRST", 3*60*60+30*60)
+
+// ToJalali converts the calendar date of t, in its own location, to the
+// Jalali (Solar Hijri) calendar used in Iran.
+//
+// Example:
+//
+//	ToJalali(time.Date(2025, 3, 21, 0, 0, 0, 0, time.UTC)) // 1404, 1, 1
+func ToJalali(t time.Time) (year, month, day int) {
+	gy, gm, gd := t.Year(), int(t.Month()), t.Day()
+
+	daysBeforeMonth := [...]int{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334}
+	gy2 := gy
+	if gm > 2 {
+		gy2 = gy + 1
+	}
+	days := 355666 + 365*gy + (gy2+3)/4 - (gy2+99)/100 + (gy2+399)/400 + gd + daysBeforeMonth[gm-1]
+
+	year = -1595 + 33*(days/12053)
+	days %= 12053
+	year += 4 * (days / 1461)
+	days %= 1461
+	if days > 365 {
+		year += (days - 1) / 365
+		days = (days - 1) % 365
+	}
+	if days < 186 {
+		return year, 1 + days/31, 1 + days%31
+	}
+	return year, 7 + (days-186)/30, 1 + (days-186)%30
+}
+
+// FormatJalali formats t as a Jalali date and time in the layout
+// "1403/07/25 14:30:00". The time is converted to loc first; a nil loc means
+// TehranTime.
+func FormatJalali(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = TehranTime
+	}
+	t = t.In(loc)
+	year, month, day := ToJalali(t)
+	return fmt.Sprintf("%04d/%02d/%02d %02d:%02d:%02d", year, month, day, t.Hour(), t.Minute(), t.Second())
+}
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnmarshalLenient decodes JSON like json.Unmarshal, but tolerates scalar
+// values whose JSON type does not match the Go type they are decoded into.
+// The API is not consistent about quoting numbers, so a price may arrive as
+// "123.4" in one response and as 123.4 in another.
+//
+// Supported Behavior:
+//   - Numbers are accepted for string fields and kept in their original
+//     notation.
+//   - Numeric strings are accepted for integer and float fields. Empty strings
+//     leave the field at its zero value.
+//   - Integral floats such as 5.0 are accepted for integer fields.
+//   - "true", "false", "1", "0", 1 and 0 are accepted for bool fields.
+//   - Unix timestamps in seconds or milliseconds are accepted for `time.Time`
+//     fields.
+//   - Numbers decoded into interface{} values, such as the values of a
+//     map[string]interface{}, are kept as json.Number instead of float64, so
+//     amounts with many digits, common in IRT markets, are never rounded.
+//     Numbers decoded into string fields keep their exact notation.
+//
+// Documents that decode cleanly are decoded only once; the lenient conversion
+// runs only after a type mismatch.
+//
+// When v points to a slice and the document is a paginated envelope such as
+// {"count": 2, "next": null, "previous": null, "results": [...]}, the results
+// array is decoded into the slice and the pagination fields are ignored. Use
+// types.PagedResponse to keep them.
+//
+// Parameters:
+//   - data: The JSON document.
+//   - v: A pointer to the value to decode into.
+//
+// Returns:
+//   - The error of json.Unmarshal if the document is malformed, or if a
+//     mismatched value cannot be converted.
+//
+// Example:
+//
+//	var ticker types.Ticker
+//	err := UnmarshalLenient([]byte(`{"price": 123.4, "timestamp": "1700000000"}`), &ticker)
+//	// ticker.Price == "123.4", ticker.Timestamp == 1700000000
+func UnmarshalLenient(data []byte, v interface{}) error {
+	if results, ok := envelopeResults(data, v); ok {
+		data = results
+	}
+
+	err := decodeNumbers(data, v)
+	var typeErr *json.UnmarshalTypeError
+	if err == nil || !errors.As(err, &typeErr) {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if decoder.Decode(&doc) != nil {
+		return err
+	}
+	fixed, marshalErr := json.Marshal(coerceJSON(doc, reflect.TypeOf(v)))
+	if marshalErr != nil {
+		return err
+	}
+
+	target := reflect.ValueOf(v).Elem()
+	target.Set(reflect.Zero(target.Type()))
+	return decodeNumbers(fixed, v)
+}
+
+// decodeNumbers decodes a single JSON document like json.Unmarshal, but keeps
+// numbers decoded into interface{} values as json.Number.
+func decodeNumbers(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid data after top-level value at offset %d", decoder.InputOffset())
+	}
+	return nil
+}
+
+// envelopeResults returns the results array of a paginated envelope when v
+// points to a slice that does not decode JSON itself.
+func envelopeResults(data []byte, v interface{}) (json.RawMessage, bool) {
+	typ := reflect.TypeOf(v)
+	if typ == nil || typ.Kind() != reflect.Pointer || typ.Elem().Kind() != reflect.Slice || typ.Implements(jsonUnmarshalerType) {
+		return nil, false
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, false
+	}
+
+	var envelope struct {
+		Results json.RawMessage `json:"results"`
+	}
+	if json.Unmarshal(trimmed, &envelope) != nil {
+		return nil, false
+	}
+	results := bytes.TrimSpace(envelope.Results)
+	if len(results) == 0 || results[0] != '[' {
+		return nil, false
+	}
+	return results, true
+}
+
+// jsonUnmarshalerType is the reflect type of json.Unmarshaler.
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// coerceJSON converts the scalars of a generic JSON document to the JSON types
+// expected by typ.
+func coerceJSON(doc interface{}, typ reflect.Type) interface{} {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == timeType {
+		return coerceTime(doc)
+	}
+	if typ.Implements(jsonUnmarshalerType) || reflect.PointerTo(typ).Implements(jsonUnmarshalerType) {
+		return doc
+	}
+
+	switch value := doc.(type) {
+	case []interface{}:
+		if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+			return doc
+		}
+		for i, item := range value {
+			value[i] = coerceJSON(item, typ.Elem())
+		}
+		return value
+
+	case map[string]interface{}:
+		switch typ.Kind() {
+		case reflect.Map:
+			for key, item := range value {
+				value[key] = coerceJSON(item, typ.Elem())
+			}
+		case reflect.Struct:
+			for key, item := range value {
+				if field, ok := jsonField(typ, key); ok {
+					value[key] = coerceJSON(item, field.Type)
+				}
+			}
+		}
+		return value
+
+	case json.Number:
+		switch typ.Kind() {
+		case reflect.String:
+			return value.String()
+		case reflect.Bool:
+			return value.String() != "0"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return integralNumber(value)
+		}
+		return value
+
+	case string:
+		s := strings.TrimSpace(value)
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if s == "" {
+				return nil
+			}
+			if _, err := strconv.ParseFloat(s, 64); err == nil {
+				return integralNumber(json.Number(s))
+			}
+		case reflect.Float32, reflect.Float64:
+			if s == "" {
+				return nil
+			}
+			if _, err := strconv.ParseFloat(s, 64); err == nil {
+				return json.Number(s)
+			}
+		case reflect.Bool:
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b
+			}
+		}
+		return value
+
+	case bool:
+		if typ.Kind() == reflect.String {
+			return strconv.FormatBool(value)
+		}
+		return value
+	}
+	return doc
+}
+
+// integralNumber rewrites integral numbers in float notation, such as "5.0" or
+// "1e3", as integers. Other numbers are returned unchanged.
+func integralNumber(n json.Number) json.Number {
+	if _, err := n.Int64(); err == nil {
+		return n
+	}
+	f, err := n.Float64()
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > 1<<53 {
+		return n
+	}
+	return json.Number(strconv.FormatInt(int64(f), 10))
+}
+
+// coerceTime converts a Unix timestamp in seconds or milliseconds, given as a
+// number or a numeric string, to an RFC 3339 string.
+func coerceTime(doc interface{}) interface{} {
+	var s string
+	switch value := doc.(type) {
+	case json.Number:
+		s = value.String()
+	case string:
+		s = strings.TrimSpace(value)
+	default:
+		return doc
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return doc
+	}
+	if f > 1e12 {
+		return time.UnixMilli(int64(f)).UTC().Format(time.RFC3339Nano)
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC().Format(time.RFC3339Nano)
+}
+
+// jsonField finds the struct field that a JSON key decodes into, matching the
+// name part of the json tag exactly, or else case-insensitively, like
+// encoding/json.
+func jsonField(typ reflect.Type, key string) (reflect.StructField, bool) {
+	var fold reflect.StructField
+	found := false
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if name == key {
+			return field, true
+		}
+		if !found && strings.EqualFold(name, key) {
+			fold, found = field, true
+		}
+	}
+	return fold, found
+}
@@ -52,6 +52,13 @@ func (j JWT) IsExpired() bool {
 	return j.Exp < int(time.Now().Unix())
 }
 
+// IsExpiredAt checks whether the JWT has expired at the given time, such as the
+// current time on the issuer's clock. Returns true if the token's expiration
+// time is earlier than now.
+func (j JWT) IsExpiredAt(now time.Time) bool {
+	return j.Exp < int(now.Unix())
+}
+
 // IsExpiredIn checks whether the JWT will expire within the specified duration from now.
 // Takes a time.Duration as input and returns true if the token will expire in the given timeframe.
 func (j JWT) IsExpiredIn(t time.Duration) bool {
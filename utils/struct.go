@@ -5,42 +5,84 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+// ParamEncoder is implemented by request parameters that encode themselves
+// into a query string instead of relying on StructToURLParams' reflection, for
+// endpoints with unusual formats such as comma-joined lists or "key[]" arrays.
+//
+// Example:
+//
+//	type TradesParams struct {
+//	    Symbols []string
+//	    Limit   int
+//	}
+//
+//	func (p TradesParams) EncodeValues(values url.Values) error {
+//	    values.Set("symbols", strings.Join(p.Symbols, ","))
+//	    if p.Limit > 0 {
+//	        values.Set("limit", strconv.Itoa(p.Limit))
+//	    }
+//	    return nil
+//	}
+type ParamEncoder interface {
+	// EncodeValues adds the parameters to values.
+	EncodeValues(values url.Values) error
+}
+
 // StructToURLParams converts a struct to a URL-encoded query string.
 //
-// This function uses the `json` struct tags as parameter keys and excludes
-// fields with empty or zero values. It supports various data types, including
-// slices, arrays, integers, floats, booleans, and strings.
+// This function uses the `url` or `json` struct tags as parameter keys and
+// excludes fields with empty or zero values. It supports various data types,
+// including slices, arrays, integers, floats, booleans, strings, pointers, and
+// `time.Time`.
 //
 // Supported Behavior:
-//   - Fields with `json` tags are used as keys. Fields without tags or with
-//     `json:"-"` are ignored.
-//   - Zero values (e.g., empty strings, 0 for integers, 0.0 for floats) are omitted.
+//   - Inputs implementing ParamEncoder, with a value or a pointer receiver,
+//     encode themselves and the rules below do not apply. They need not be
+//     structs.
+//   - The `url` tag, if present, overrides the `json` tag. Only the name part
+//     of a tag is used, so `json:"symbol,omitempty"` yields the key "symbol".
+//     Fields without tags or with a tag of "-" are ignored.
+//   - Zero values (e.g., empty strings, 0 for integers, 0.0 for floats, the
+//     zero time) are omitted.
+//   - Pointer fields are omitted when nil and always sent otherwise, even if
+//     they point to a zero value. Use them to send 0, false or "" on purpose.
+//   - `time.Time` values are formatted as RFC 3339. The tag options "unix"
+//     and "unixms" send Unix seconds or milliseconds instead, for example
+//     `url:"since,unix"`.
 //   - Slices and arrays are converted to multiple key-value pairs.
 //
 // Parameters:
-//   - inputStruct: The input struct to be converted into URL parameters. It
-//     must be of kind `struct`.
+//   - inputStruct: The input struct, or a pointer to it, to be converted into
+//     URL parameters. A nil pointer yields an empty query string.
 //
 // Returns:
 //   - A URL-encoded query string as a `string`.
-//   - An `error` if the input is not a struct or if any other issue occurs.
+//   - An `error` if the input is not a struct, if its EncodeValues method
+//     fails, or if any other issue occurs.
 //
 // Example:
 //
 //	type MyStruct struct {
-//	    Name    string   `json:"name"`
-//	    Age     int      `json:"age"`
-//	    Tags    []string `json:"tags"`
-//	    IsAdmin bool     `json:"is_admin"`
+//	    Name    string    `json:"name"`
+//	    Age     int       `json:"age"`
+//	    Tags    []string  `json:"tags"`
+//	    IsAdmin bool      `json:"is_admin"`
+//	    Offset  *int      `json:"offset,omitempty"`
+//	    Since   time.Time `url:"since,unix"`
 //	}
 //
+//	zero := 0
 //	data := MyStruct{
 //	    Name:    "John",
 //	    Age:     30,
 //	    Tags:    []string{"golang", "developer"},
 //	    IsAdmin: true,
+//	    Offset:  &zero,
 //	}
 //
 //	query, err := StructToURLParams(data)
@@ -48,64 +90,168 @@ import (
 //	    log.Fatal(err)
 //	}
 //	fmt.Println(query)
-//	// Output: name=John&age=30&tags=golang&tags=developer&is_admin=true
+//	// Output: age=30&is_admin=true&name=John&offset=0&tags=golang&tags=developer
 //
 // Limitations:
-//   - Only fields with `json` tags are considered.
+//   - Only fields with `url` or `json` tags are considered.
 //   - Non-struct input will result in an error.
 func StructToURLParams(inputStruct interface{}) (string, error) {
 	values := url.Values{}
 
-	// Get the type and value of the input struct
+	// Get the value of the input struct, following a pointer
 	v := reflect.ValueOf(inputStruct)
-	t := reflect.TypeOf(inputStruct)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	// Let the input encode itself if it knows how
+	if encoder, ok := paramEncoder(inputStruct, v); ok {
+		if err := encoder.EncodeValues(values); err != nil {
+			return "", err
+		}
+		return values.Encode(), nil
+	}
 
 	// Ensure the input is a struct
-	if t.Kind() != reflect.Struct {
+	if v.Kind() != reflect.Struct {
 		return "", fmt.Errorf("input must be a struct")
 	}
 
-	// Iterate through the struct fields
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		value := v.Field(i)
+	// Iterate through the tagged fields
+	for _, field := range urlFieldsOf(v.Type()) {
+		value := v.Field(field.index)
 
-		// Use the "json" tag if available; otherwise, use the field name
-		key := field.Tag.Get("json")
-		if key == "" || key == "-" {
-			continue // Skip fields without a "json" tag or explicitly ignored
-		}
-
-		// Skip zero values
-		if !value.IsValid() || value.IsZero() {
-			continue
+		// Pointers are sent whenever they are set, even to a zero value
+		if field.pointer {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		} else if value.IsZero() {
+			continue // Skip zero values
 		}
 
-		// Handle different kinds of fields
-		switch value.Kind() {
-		case reflect.Slice, reflect.Array:
-			if value.Len() > 0 { // Only add non-empty slices/arrays
-				for j := 0; j < value.Len(); j++ {
-					values.Add(key, fmt.Sprintf("%v", value.Index(j).Interface()))
+		// Slices and arrays become repeated keys
+		if field.repeated {
+			for j := 0; j < value.Len(); j++ {
+				elem := value.Index(j)
+				if elem.Kind() == reflect.Pointer {
+					if elem.IsNil() {
+						continue
+					}
+					elem = elem.Elem()
 				}
+				values.Add(field.key, formatURLValue(elem, field.options))
 			}
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			if value.Int() != 0 { // Skip zero values
-				values.Add(key, strconv.FormatInt(value.Int(), 10))
-			}
-		case reflect.Float32, reflect.Float64:
-			if value.Float() != 0 { // Skip zero values
-				values.Add(key, strconv.FormatFloat(value.Float(), 'f', -1, 64))
-			}
-		case reflect.Bool:
-			values.Add(key, strconv.FormatBool(value.Bool())) // Always add booleans
-		default:
-			if value.String() != "" { // Skip empty strings
-				values.Add(key, fmt.Sprintf("%v", value.Interface()))
-			}
+			continue
 		}
+		values.Add(field.key, formatURLValue(value, field.options))
 	}
 
 	// Encode and return the URL parameters
 	return values.Encode(), nil
 }
+
+// paramEncoder returns the ParamEncoder of input, whose value is v. A value
+// whose EncodeValues method has a pointer receiver is encoded through a
+// pointer to a copy of it.
+func paramEncoder(input interface{}, v reflect.Value) (ParamEncoder, bool) {
+	if encoder, ok := input.(ParamEncoder); ok {
+		return encoder, true
+	}
+	if !v.IsValid() || v.CanAddr() || !reflect.PointerTo(v.Type()).Implements(paramEncoderType) {
+		return nil, false
+	}
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	return ptr.Interface().(ParamEncoder), true
+}
+
+// paramEncoderType is the reflect type of ParamEncoder.
+var paramEncoderType = reflect.TypeOf((*ParamEncoder)(nil)).Elem()
+
+// urlField is the metadata of a struct field sent as a query parameter.
+type urlField struct {
+	index    int    // index of the field in the struct
+	key      string // parameter name
+	options  string // tag options, such as "unix"
+	pointer  bool   // the field is a pointer
+	repeated bool   // the field, or its pointee, is a slice or array sent as repeated keys
+}
+
+// urlFields caches the tagged fields of every struct type passed to
+// StructToURLParams, keyed by reflect.Type, so tags are parsed once per type.
+var urlFields sync.Map
+
+// urlFieldsOf returns the tagged fields of the struct type t.
+func urlFieldsOf(t reflect.Type) []urlField {
+	if cached, ok := urlFields.Load(t); ok {
+		return cached.([]urlField)
+	}
+
+	var fields []urlField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, options := urlTag(field)
+		if key == "" || key == "-" {
+			continue // Skip fields without a tag or explicitly ignored
+		}
+
+		typ := field.Type
+		pointer := typ.Kind() == reflect.Pointer
+		if pointer {
+			typ = typ.Elem()
+		}
+		repeated := (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) && typ.Elem().Kind() != reflect.Uint8
+		fields = append(fields, urlField{index: i, key: key, options: options, pointer: pointer, repeated: repeated})
+	}
+
+	cached, _ := urlFields.LoadOrStore(t, fields)
+	return cached.([]urlField)
+}
+
+// urlTag returns the parameter name and the options of a field, taken from the
+// `url` tag or else from the `json` tag.
+func urlTag(field reflect.StructField) (string, string) {
+	tag, ok := field.Tag.Lookup("url")
+	if !ok {
+		tag = field.Tag.Get("json")
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	return name, options
+}
+
+// timeType is the reflect type of time.Time.
+var timeType = reflect.TypeOf(time.Time{})
+
+// formatURLValue formats a single query parameter value.
+func formatURLValue(value reflect.Value, options string) string {
+	if value.Type() == timeType {
+		tm := value.Interface().(time.Time)
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case "unix":
+				return strconv.FormatInt(tm.Unix(), 10)
+			case "unixms":
+				return strconv.FormatInt(tm.UnixMilli(), 10)
+			}
+		}
+		return tm.Format(time.RFC3339)
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	default:
+		return fmt.Sprintf("%v", value.Interface())
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// benchmarkParams resembles the list filters of the API.
+type benchmarkParams struct {
+	Symbol     string    `json:"symbol"`
+	Side       string    `json:"side,omitempty"`
+	State      string    `json:"state,omitempty"`
+	Identifier []string  `json:"identifiers_in,omitempty"`
+	Offset     *int      `json:"offset,omitempty"`
+	Limit      int       `json:"limit,omitempty"`
+	Since      time.Time `url:"since,unix"`
+	Ignored    string
+}
+
+// BenchmarkStructToURLParams compares encoding with the field metadata of the
+// type cached (warm) and rebuilt for every call (cold).
+func BenchmarkStructToURLParams(b *testing.B) {
+	offset := 20
+	params := benchmarkParams{
+		Symbol:     "BTC_USDT",
+		Side:       "buy",
+		State:      "active",
+		Identifier: []string{"a1", "b2"},
+		Offset:     &offset,
+		Limit:      50,
+		Since:      time.Unix(1700000000, 0),
+	}
+
+	b.Run("cold", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			urlFields.Clear()
+			if _, err := StructToURLParams(params); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("warm", func(b *testing.B) {
+		b.ReportAllocs()
+		if _, err := StructToURLParams(params); err != nil {
+			b.Fatal(err)
+		}
+		for b.Loop() {
+			if _, err := StructToURLParams(params); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package bitpin
+
+import (
+	"context"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// Version2 is the newer API version. It serves the operations listed by
+// V2Operations.
+const Version2 = "v2"
+
+// v2Endpoints lists the operations available in the v2 API.
+var v2Endpoints = map[Operation]Endpoint{
+	OpMarkets: {Path: "/mkt/markets/", Version: Version2},
+	OpOrders:  {Path: "/odr/orders/", Version: Version2},
+	OpOrder:   {Path: "/odr/orders/{id}/", Version: Version2},
+}
+
+// V2Operations returns the operations that are served by the v2 API when it
+// is enabled. Other operations keep using their v1 endpoint.
+func V2Operations() []Operation {
+	return []Operation{OpMarkets, OpOrders, OpOrder}
+}
+
+// apiVersionKey is the context key of ContextWithAPIVersion.
+type apiVersionKey struct{}
+
+// ContextWithAPIVersion returns a copy of ctx that selects the API version of
+// the requests made with it, overriding UseAPIv2 of the client for a single
+// call. version is Version or Version2.
+//
+// Example:
+//
+//	// Try the v2 order endpoints for a single call on a v1 client.
+//	ctx := bitpin.ContextWithAPIVersion(context.Background(), bitpin.Version2)
+//	page, err := client.ListOrders(ctx, t.GetOrdersHistoryParams{Symbol: "BTC_USDT"}, t.ListOptions{})
+func ContextWithAPIVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, apiVersionKey{}, version)
+}
+
+// usesV2 reports whether requests made with ctx go to the v2 API.
+func (c *Client) usesV2(ctx context.Context) bool {
+	if version, ok := ctx.Value(apiVersionKey{}).(string); ok {
+		return version == Version2
+	}
+	return c.UseAPIv2
+}
+
+// route returns the endpoint of an operation for a request made with ctx.
+// Operations with an endpoint overridden by WithEndpoint keep it, whatever
+// version is selected.
+func (c *Client) route(ctx context.Context, op Operation) Endpoint {
+	endpoint := c.Endpoint(op)
+	if !c.usesV2(ctx) || endpoint != defaultEndpoints[op] {
+		return endpoint
+	}
+	if v2, ok := v2Endpoints[op]; ok {
+		return v2
+	}
+	return endpoint
+}
+
+// adaptV2 converts a request body and a result from the v1 schema, used by the
+// methods of the client, to the v2 schema. finish converts the decoded v2
+// result back and must be called after a successful request. Values without a
+// v2 counterpart are returned unchanged.
+func adaptV2(body interface{}, result interface{}) (interface{}, interface{}, func()) {
+	switch params := body.(type) {
+	case t.CreateOrderParams:
+		body = params.V2()
+	case *t.CreateOrderParams:
+		body = params.V2()
+	}
+
+	switch r := result.(type) {
+	case *[]t.Market:
+		var v2 []t.MarketV2
+		return body, &v2, func() {
+			*r = make([]t.Market, len(v2))
+			for i, market := range v2 {
+				(*r)[i] = market.Market()
+			}
+		}
+	case *t.OrderStatus:
+		var v2 t.OrderStatusV2
+		return body, &v2, func() { *r = v2.OrderStatus() }
+	case **t.OrderStatus:
+		var v2 t.OrderStatusV2
+		return body, &v2, func() {
+			order := v2.OrderStatus()
+			*r = &order
+		}
+	case *t.OrderStatuses:
+		var v2 []t.OrderStatusV2
+		return body, &v2, func() { *r = orderStatusesV1(v2) }
+	case **t.OrderStatuses:
+		var v2 []t.OrderStatusV2
+		return body, &v2, func() {
+			orders := t.OrderStatuses(orderStatusesV1(v2))
+			*r = &orders
+		}
+	case *t.PagedResponse[t.OrderStatus]:
+		var v2 t.PagedResponse[t.OrderStatusV2]
+		return body, &v2, func() {
+			*r = t.PagedResponse[t.OrderStatus]{
+				Count:    v2.Count,
+				Next:     v2.Next,
+				Previous: v2.Previous,
+				Results:  orderStatusesV1(v2.Results),
+			}
+		}
+	}
+	return body, result, func() {}
+}
+
+// orderStatusesV1 converts v2 orders to the v1 schema.
+func orderStatusesV1(orders []t.OrderStatusV2) []t.OrderStatus {
+	if orders == nil {
+		return nil
+	}
+	converted := make([]t.OrderStatus, len(orders))
+	for i, order := range orders {
+		converted[i] = order.OrderStatus()
+	}
+	return converted
+}
+
+// requestVersion sends a request to an endpoint of the given version and
+// decodes the response into result, converting the body and the result when
+// the version is Version2.
+func (c *Client) requestVersion(ctx context.Context, method, path, version string, auth bool, body interface{}, result interface{}) error {
+	if version != Version2 {
+		return c.ApiRequestWithContext(ctx, method, path, version, auth, body, result)
+	}
+	body, target, finish := adaptV2(body, result)
+	if result == nil {
+		target = nil
+	}
+	if err := c.ApiRequestWithContext(ctx, method, path, version, auth, body, target); err != nil {
+		return err
+	}
+	finish()
+	return nil
+}
@@ -0,0 +1,108 @@
+// Package bitpin is version 2 of the Go Bitpin SDK.
+//
+// The v2 API groups the operations of the exchange by area, takes a
+// context.Context in every method that performs I/O and returns lists as
+// values instead of pointers to slices:
+//
+//	client, err := bitpin.NewClient(bitpin.ClientOptions{ApiKey: key, SecretKey: secret})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	markets, err := client.Market.List(ctx)
+//	order, err := client.Orders.Get(ctx, 123456)
+//	wallet, err := client.Wallets.Get(ctx, "USDT", "spot")
+//
+// The v2 client is built on the v1 client of github.com/rzabhd80/go-sdk-bitpin,
+// so both share their options, types and behavior. Functionality without a v2
+// counterpart yet is reached through Client.V1. See docs/V2_MIGRATION_PLAN.md
+// in the repository for the status of the migration and the bitpin-migrate
+// command for rewriting v1 call sites.
+package bitpin
+
+import (
+	bitpin "github.com/rzabhd80/go-sdk-bitpin"
+)
+
+// ClientOptions configures a Client. It is the same type as in v1.
+type ClientOptions = bitpin.ClientOptions
+
+// Option configures the ClientOptions of NewClientWithOptions. The With...
+// options of the v1 package are used as is.
+type Option = bitpin.Option
+
+// GoBitpinError and APIError are the error types of the SDK.
+type (
+	GoBitpinError = bitpin.GoBitpinError
+	APIError      = bitpin.APIError
+)
+
+// Errors wrapped by lookups that find nothing.
+var (
+	ErrMarketNotFound = bitpin.ErrMarketNotFound
+	ErrTickerNotFound = bitpin.ErrTickerNotFound
+	ErrWalletNotFound = bitpin.ErrWalletNotFound
+	ErrOrderNotFound  = bitpin.ErrOrderNotFound
+)
+
+// Client is a Bitpin API client. Its operations are grouped into services.
+type Client struct {
+	// Market serves public market data: markets, tickers, order books and
+	// trades.
+	Market *MarketService
+
+	// Orders places, cancels and looks up orders of the user.
+	Orders *OrderService
+
+	// Wallets reads the wallets of the user.
+	Wallets *WalletService
+
+	v1 *bitpin.Client
+}
+
+// NewClient creates a client with the given options. It accepts the same
+// options and fails for the same reasons as NewClient of v1.
+func NewClient(opts ClientOptions) (*Client, error) {
+	client, err := bitpin.NewClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(client), nil
+}
+
+// NewClientWithOptions creates a client configured by functional options, such
+// as bitpin.WithAPIKey of v1.
+func NewClientWithOptions(options ...Option) (*Client, error) {
+	client, err := bitpin.NewClientWithOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(client), nil
+}
+
+// Wrap returns a v2 client that sends its requests through an existing v1
+// client, so code can be migrated one call site at a time while sharing a
+// single session, rate limiter and cache.
+func Wrap(client *bitpin.Client) *Client {
+	return &Client{
+		Market:  &MarketService{client: client},
+		Orders:  &OrderService{client: client},
+		Wallets: &WalletService{client: client},
+		v1:      client,
+	}
+}
+
+// V1 returns the underlying v1 client, for functionality that has no v2
+// counterpart yet.
+func (c *Client) V1() *bitpin.Client {
+	return c.v1
+}
+
+// value dereferences the list returned by a v1 method. A nil list is returned
+// as an empty one.
+func value[T any](list *T, err error) (T, error) {
+	var zero T
+	if err != nil || list == nil {
+		return zero, err
+	}
+	return *list, nil
+}
@@ -0,0 +1,11 @@
+module github.com/rzabhd80/go-sdk-bitpin/v2
+
+go 1.25.0
+
+require github.com/rzabhd80/go-sdk-bitpin v0.0.0
+
+require github.com/golang-jwt/jwt/v4 v4.5.2 // indirect
+
+// The v2 module is developed against the v1 module of this repository. The
+// requirement is switched to a tagged v1 release before v2 is published.
+replace github.com/rzabhd80/go-sdk-bitpin => ../
@@ -0,0 +1,62 @@
+package bitpin
+
+import (
+	"context"
+
+	bitpin "github.com/rzabhd80/go-sdk-bitpin"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// MarketService serves public market data. It does not need credentials.
+type MarketService struct {
+	client *bitpin.Client
+}
+
+// Currencies returns the currencies listed on the exchange.
+func (s *MarketService) Currencies(ctx context.Context) (t.Currencies, error) {
+	return value(s.client.GetCurrenciesWithContext(ctx))
+}
+
+// List returns the markets of the exchange. It is served from the metadata
+// cache when ClientOptions.MetadataTTL is set.
+//
+// Example:
+//
+//	markets, err := client.Market.List(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, market := range markets {
+//	    fmt.Println(market.Symbol)
+//	}
+func (s *MarketService) List(ctx context.Context) (t.Markets, error) {
+	return value(s.client.GetMarketsWithContext(ctx))
+}
+
+// Get returns a single market. The symbol is matched like GetMarket of v1
+// does, so "btc-usdt" finds "BTC_USDT". It returns an error wrapping
+// ErrMarketNotFound if no market matches.
+func (s *MarketService) Get(ctx context.Context, symbol string) (*t.Market, error) {
+	return s.client.GetMarketWithContext(ctx, symbol)
+}
+
+// Tickers returns the tickers of all markets.
+func (s *MarketService) Tickers(ctx context.Context) (t.Tickers, error) {
+	return value(s.client.GetTickersWithContext(ctx))
+}
+
+// Ticker returns the ticker of a single market. It returns an error wrapping
+// ErrTickerNotFound if the market has no ticker.
+func (s *MarketService) Ticker(ctx context.Context, symbol string) (*t.Ticker, error) {
+	return s.client.GetTickerWithContext(ctx, symbol)
+}
+
+// OrderBook returns the order book of a market.
+func (s *MarketService) OrderBook(ctx context.Context, symbol string) (*t.OrderBook, error) {
+	return s.client.GetOrderBookWithContext(ctx, symbol)
+}
+
+// Trades returns the recent trades of a market.
+func (s *MarketService) Trades(ctx context.Context, symbol string) ([]*t.Trade, error) {
+	return value(s.client.GetRecentTradesWithContext(ctx, symbol))
+}
@@ -0,0 +1,63 @@
+package bitpin
+
+import (
+	"context"
+
+	bitpin "github.com/rzabhd80/go-sdk-bitpin"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// OrderService places, cancels and looks up orders of the user. It needs
+// credentials.
+type OrderService struct {
+	client *bitpin.Client
+}
+
+// Create places an order. Orders on the same symbol are serialized, like
+// CreateOrder of v1.
+//
+// Example:
+//
+//	order, err := client.Orders.Create(ctx, t.CreateOrderParams{
+//	    Symbol:     "BTC_USDT",
+//	    Type:       t.TypeLimit,
+//	    Side:       t.SideBuy,
+//	    BaseAmount: "0.001",
+//	    Price:      "60000",
+//	})
+func (s *OrderService) Create(ctx context.Context, params t.CreateOrderParams) (*t.OrderStatus, error) {
+	return s.client.CreateOrderWithContext(ctx, params)
+}
+
+// Cancel requests the cancellation of an order. Use CancelOrderAndConfirm of
+// the V1 client to wait for the final state of the order.
+func (s *OrderService) Cancel(ctx context.Context, orderId int) error {
+	return s.client.CancelOrderWithContext(ctx, orderId)
+}
+
+// Get returns a single order by its ID.
+func (s *OrderService) Get(ctx context.Context, orderId int) (*t.OrderStatus, error) {
+	return s.client.GetOrderWithContext(ctx, orderId)
+}
+
+// GetByIdentifier returns the order placed with the given client-side
+// identifier. It returns an error wrapping ErrOrderNotFound if there is none.
+func (s *OrderService) GetByIdentifier(ctx context.Context, identifier string) (*t.OrderStatus, error) {
+	return s.client.GetOrderByIdentifierWithContext(ctx, identifier)
+}
+
+// History returns the orders matching params.
+func (s *OrderService) History(ctx context.Context, params t.GetOrdersHistoryParams) (t.OrderStatuses, error) {
+	return value(s.client.GetOrdersHistoryWithContext(ctx, params))
+}
+
+// Open returns the active orders matching params. The state filter of params
+// is ignored.
+func (s *OrderService) Open(ctx context.Context, params t.GetOrdersHistoryParams) (t.OrderStatuses, error) {
+	return value(s.client.GetOpenOrdersWithContext(ctx, params))
+}
+
+// Trades returns the fills of the user matching params.
+func (s *OrderService) Trades(ctx context.Context, params t.GetUserTradesParams) (t.UserTrades, error) {
+	return value(s.client.GetUserTradesWithContext(ctx, params))
+}
@@ -0,0 +1,25 @@
+package bitpin
+
+import (
+	"context"
+
+	bitpin "github.com/rzabhd80/go-sdk-bitpin"
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// WalletService reads the wallets of the user. It needs credentials.
+type WalletService struct {
+	client *bitpin.Client
+}
+
+// List returns the wallets matching params.
+func (s *WalletService) List(ctx context.Context, params t.GetWalletParams) (t.Wallets, error) {
+	return value(s.client.GetWalletsWithContext(ctx, params))
+}
+
+// Get returns the wallet of an asset. If service is empty, the wallet of any
+// service is returned, preferring the spot wallet. It returns an error
+// wrapping ErrWalletNotFound if the user has no such wallet.
+func (s *WalletService) Get(ctx context.Context, asset string, service string) (*t.Wallet, error) {
+	return s.client.GetWalletWithContext(ctx, asset, service)
+}
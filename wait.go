@@ -0,0 +1,139 @@
+package bitpin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+)
+
+// Default polling settings used by WaitForOrder.
+const (
+	// DefaultWaitInterval is the initial delay between two order status polls.
+	DefaultWaitInterval = time.Second
+
+	// DefaultWaitMaxInterval caps the delay between polls when backoff is used.
+	DefaultWaitMaxInterval = 30 * time.Second
+)
+
+// WaitOptions configures how WaitForOrder polls the order status.
+type WaitOptions struct {
+	// Interval is the delay between polls. The first poll is sent right away,
+	// so an order that is already terminal returns without waiting.
+	// Defaults to DefaultWaitInterval.
+	Interval time.Duration
+
+	// Multiplier grows the interval after every poll that did not observe a
+	// change. Values less than or equal to 1 keep the interval constant.
+	Multiplier float64
+
+	// MaxInterval caps the interval when Multiplier is used.
+	// Defaults to DefaultWaitMaxInterval.
+	MaxInterval time.Duration
+
+	// OnPartialFill is invoked every time the filled amount of the order changes
+	// while the order is still open.
+	OnPartialFill func(order *t.OrderStatus)
+}
+
+// IsTerminalOrderState reports whether an order in the given state can no
+// longer change, i.e. it was filled or cancelled.
+func IsTerminalOrderState(state t.OrderState) bool {
+	return state.IsTerminal()
+}
+
+// WaitForOrder polls the status of an order until it reaches a terminal state
+// (filled or cancelled) or the context is done.
+//
+// Parameters:
+//   - ctx: Controls how long to wait. Cancel it or give it a deadline to stop
+//     waiting.
+//   - orderId: The unique identifier of the order to wait for.
+//   - opts: Polling interval, backoff, and an optional partial fill callback.
+//
+// Returns:
+//   - A pointer to the last observed `OrderStatus`. It is returned together with
+//     the context error when waiting stops early, and is nil if the order was
+//     never fetched.
+//   - An error if a poll fails or the context is done before the order reaches a
+//     terminal state.
+//
+// Behavior:
+//   - Polls `/odr/orders/<orderId>/` right away and then after every interval.
+//   - When `Multiplier` is greater than 1 the interval grows after every poll in
+//     which the order did not change, and resets when it does.
+//   - Invokes `OnPartialFill` whenever `DealedBaseAmount` changes while the
+//     order is still open.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//	defer cancel()
+//	order, err := client.WaitForOrder(ctx, 123456, bitpin.WaitOptions{
+//	    Interval:   500 * time.Millisecond,
+//	    Multiplier: 1.5,
+//	    OnPartialFill: func(o *t.OrderStatus) {
+//	        log.Printf("filled %s of %s", o.DealedBaseAmount, o.BaseAmount)
+//	    },
+//	})
+func (c *Client) WaitForOrder(ctx context.Context, orderId int, opts WaitOptions) (*t.OrderStatus, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultWaitMaxInterval
+	}
+
+	var last *t.OrderStatus
+	delay := interval
+	wake := c.after(0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return last, &GoBitpinError{
+				Message: fmt.Sprintf("stopped waiting for order %d", orderId),
+				Err:     ctx.Err(),
+			}
+		case <-wake:
+		}
+
+		order, err := c.GetOrderWithContext(ctx, orderId)
+		if err != nil {
+			return last, err
+		}
+
+		if IsTerminalOrderState(order.State) {
+			return order, nil
+		}
+
+		changed := last == nil || last.DealedBaseAmount != order.DealedBaseAmount
+		if changed && opts.OnPartialFill != nil && !isZeroAmount(order.DealedBaseAmount) {
+			opts.OnPartialFill(order)
+		}
+		last = order
+
+		if changed {
+			delay = interval
+		} else if opts.Multiplier > 1 {
+			delay = time.Duration(float64(delay) * opts.Multiplier)
+			if delay > maxInterval {
+				delay = maxInterval
+			}
+		}
+		wake = c.after(delay)
+	}
+}
+
+// isZeroAmount reports whether a decimal amount string is empty or zero.
+func isZeroAmount(amount string) bool {
+	if amount == "" {
+		return true
+	}
+	f, err := strconv.ParseFloat(amount, 64)
+	return err == nil && f == 0
+}
@@ -0,0 +1,253 @@
+package bitpin
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/rzabhd80/go-sdk-bitpin/types"
+	u "github.com/rzabhd80/go-sdk-bitpin/utils"
+)
+
+// DefaultWalletWatchInterval is the default polling interval of a WalletWatcher.
+const DefaultWalletWatchInterval = 10 * time.Second
+
+// WalletEventType identifies the kind of a WalletEvent.
+type WalletEventType string
+
+const (
+	// WalletDeposit is emitted when the balance of a wallet increases, or a
+	// wallet with a balance appears.
+	WalletDeposit WalletEventType = "deposit"
+
+	// WalletBalanceDecreased is emitted when the balance of a wallet decreases.
+	WalletBalanceDecreased WalletEventType = "balance_decreased"
+
+	// WalletFrozenChanged is emitted when the frozen amount of a wallet changes,
+	// for example when an order is placed or cancelled.
+	WalletFrozenChanged WalletEventType = "frozen_changed"
+)
+
+// WalletEvent is a single change reported by a WalletWatcher.
+type WalletEvent struct {
+	// Type is the kind of change.
+	Type WalletEventType
+
+	// Previous is the wallet as seen by the previous poll. It is zero for
+	// wallets that appeared since.
+	Previous t.Wallet
+
+	// Current is the wallet as seen by the latest poll.
+	Current t.Wallet
+
+	// Change is the signed difference of the balance for WalletDeposit and
+	// WalletBalanceDecreased events, and of the frozen amount for
+	// WalletFrozenChanged events.
+	Change string
+
+	// Time is the local time at which the change was observed.
+	Time time.Time
+}
+
+// WalletWatcherOptions configures a WalletWatcher.
+type WalletWatcherOptions struct {
+	// Assets restricts the watcher to these assets. Empty means all assets.
+	Assets []string
+
+	// Service restricts the watcher to one service, such as "spot". Empty means
+	// all services.
+	Service string
+
+	// Interval is the delay between two polls. Defaults to
+	// DefaultWalletWatchInterval.
+	Interval time.Duration
+
+	// Buffer is the capacity of the event channel. Defaults to 100.
+	Buffer int
+}
+
+// WalletWatcher pushes wallet balance changes of the authenticated user to a
+// channel. It is backed by a polling loop over the wallets endpoint.
+type WalletWatcher struct {
+	// C delivers balance changes. It is closed when the watcher stops.
+	C <-chan WalletEvent
+
+	// Errors receives polling errors. Errors are dropped when nobody reads them;
+	// the watcher keeps polling after an error.
+	Errors <-chan error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Close stops the watcher and waits for the polling goroutine to exit.
+func (w *WalletWatcher) Close() {
+	w.once.Do(w.cancel)
+	<-w.done
+}
+
+// WatchWallets starts watching the wallet balances of the authenticated user.
+//
+// Parameters:
+//   - ctx: The watcher stops when the context is done.
+//   - opts: Asset and service filters, polling interval, and buffer size.
+//
+// Returns:
+//   - A pointer to a running `WalletWatcher`. Call `Close` to stop it.
+//
+// Behavior:
+//   - Every interval, the wallets are fetched and compared with the previous
+//     poll. The first poll only records the balances.
+//   - A higher balance is reported as `WalletDeposit`, a lower one as
+//     `WalletBalanceDecreased`, and a different frozen amount as
+//     `WalletFrozenChanged`. One poll may report several events per wallet.
+//   - Wallets are identified by asset and service.
+//
+// Example:
+//
+//	watcher := client.WatchWallets(ctx, bitpin.WalletWatcherOptions{Assets: []string{"USDT"}})
+//	defer watcher.Close()
+//	for event := range watcher.C {
+//	    if event.Type == bitpin.WalletDeposit {
+//	        log.Printf("received %s %s", event.Change, event.Current.Asset)
+//	    }
+//	}
+func (c *Client) WatchWallets(ctx context.Context, opts WalletWatcherOptions) *WalletWatcher {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWalletWatchInterval
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 100
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	events := make(chan WalletEvent, opts.Buffer)
+	errs := make(chan error, 1)
+	watcher := &WalletWatcher{
+		C:      events,
+		Errors: errs,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(watcher.done)
+		defer close(events)
+
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+
+		var previous map[string]t.Wallet
+		for {
+			current, err := c.pollWallets(ctx, opts)
+			if err != nil {
+				if ctx.Err() == nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			} else {
+				if previous != nil {
+					for _, event := range diffWallets(previous, current, c.now()) {
+						select {
+						case events <- event:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+				previous = current
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return watcher
+}
+
+// pollWallets fetches the watched wallets, keyed by walletKey.
+func (c *Client) pollWallets(ctx context.Context, opts WalletWatcherOptions) (map[string]t.Wallet, error) {
+	var wallets t.Wallets
+	params := t.GetWalletParams{Assets: opts.Assets, Service: opts.Service}
+	if err := c.call(ctx, "GET", OpWallets, nil, true, params, &wallets); err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]t.Wallet, len(wallets))
+	for _, wallet := range wallets {
+		if opts.Service != "" && !strings.EqualFold(wallet.Service, opts.Service) {
+			continue
+		}
+		current[walletKey(wallet)] = wallet
+	}
+	return current, nil
+}
+
+// walletKey identifies a wallet by asset and service.
+func walletKey(wallet t.Wallet) string {
+	return u.CanonicalCurrency(wallet.Asset) + "/" + strings.ToLower(wallet.Service)
+}
+
+// diffWallets returns the events describing the changes from previous to
+// current, ordered by asset and service. Amounts that cannot be parsed are
+// skipped.
+func diffWallets(previous, current map[string]t.Wallet, at time.Time) []WalletEvent {
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var events []WalletEvent
+	for _, key := range keys {
+		wallet := current[key]
+		prev, seen := previous[key]
+		balance, frozen := "0", "0"
+		if seen {
+			balance, frozen = prev.Balance, prev.Frozen
+		}
+
+		if change, ok := amountChange(balance, wallet.Balance); ok {
+			event := WalletEvent{Type: WalletDeposit, Previous: prev, Current: wallet, Change: trimRat(change), Time: at}
+			if change.Sign() < 0 {
+				event.Type = WalletBalanceDecreased
+			}
+			events = append(events, event)
+		}
+		if change, ok := amountChange(frozen, wallet.Frozen); ok {
+			events = append(events, WalletEvent{Type: WalletFrozenChanged, Previous: prev, Current: wallet, Change: trimRat(change), Time: at})
+		}
+	}
+	return events
+}
+
+// amountChange returns next minus prev, and whether it is a valid non-zero
+// difference. Empty amounts count as zero.
+func amountChange(prev, next string) (*big.Rat, bool) {
+	if prev == "" {
+		prev = "0"
+	}
+	if next == "" {
+		next = "0"
+	}
+	a, err := u.ParseAmount(prev)
+	if err != nil {
+		return nil, false
+	}
+	b, err := u.ParseAmount(next)
+	if err != nil {
+		return nil, false
+	}
+	change := new(big.Rat).Sub(b, a)
+	return change, change.Sign() != 0
+}
@@ -0,0 +1,328 @@
+package bitpin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// yamlPlain is an unquoted scalar. Its type is only decided when it is
+// converted for a target field by resolveYAML, so "12345" stays a string for
+// string fields and is a number for numeric ones.
+type yamlPlain string
+
+// parseYAML parses the subset of YAML used by configuration files: nested block
+// mappings, block sequences and single-line flow sequences of scalars, and
+// comments. Sequences of mappings, flow mappings, anchors and multi-line
+// strings are not supported.
+//
+// Mappings are returned as map[string]interface{}, sequences as []interface{},
+// quoted scalars as string and unquoted scalars as yamlPlain.
+func parseYAML(data []byte) (map[string]interface{}, error) {
+	type frame struct {
+		indent int
+		m      map[string]interface{} // the mapping, or nil for a sequence
+		parent map[string]interface{} // the mapping holding a sequence
+		key    string                 // the key of a sequence in parent
+	}
+	root := make(map[string]interface{})
+	stack := []frame{{indent: -1, m: root}}
+	pending := "" // key of a mapping that expects nested lines
+	pendingIndent := 0
+
+	for i, line := range strings.Split(string(data), "\n") {
+		lineNo := i + 1
+		line = strings.TrimRight(stripYAMLComment(line), " \r")
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") || strings.Contains(line[:len(line)-len(trimmed)], "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", lineNo)
+		}
+		indent := len(line) - len(trimmed)
+		if stack[0].indent == -1 {
+			stack[0].indent = indent
+		}
+		item := trimmed == "-" || strings.HasPrefix(trimmed, "- ")
+
+		if pending != "" {
+			parent := stack[len(stack)-1].m
+			switch {
+			case item && indent >= pendingIndent:
+				// Sequences may be indented at the level of their key.
+				parent[pending] = []interface{}{}
+				stack = append(stack, frame{indent: indent, parent: parent, key: pending})
+			case indent > pendingIndent:
+				child := make(map[string]interface{})
+				parent[pending] = child
+				stack = append(stack, frame{indent: indent, m: child})
+			default:
+				parent[pending] = nil
+			}
+			pending = ""
+		}
+		for len(stack) > 1 && indent < stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		if top := stack[len(stack)-1]; top.m == nil && indent == top.indent && !item {
+			stack = stack[:len(stack)-1]
+		}
+		top := stack[len(stack)-1]
+		if indent != top.indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", lineNo)
+		}
+
+		if top.m == nil {
+			value := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			if value == "" || yamlMappingEntry(value) {
+				return nil, fmt.Errorf("line %d: sequences of mappings are not supported", lineNo)
+			}
+			scalar, err := yamlScalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			top.parent[top.key] = append(top.parent[top.key].([]interface{}), scalar)
+			continue
+		}
+		if item {
+			return nil, fmt.Errorf("line %d: unexpected sequence item", lineNo)
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || (value != "" && value[0] != ' ') {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+		key, err := yamlScalarString(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if _, dup := top.m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", lineNo, key)
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			pending, pendingIndent = key, indent
+			continue
+		}
+		scalar, err := yamlScalar(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		top.m[key] = scalar
+	}
+	if pending != "" {
+		stack[len(stack)-1].m[pending] = nil
+	}
+	return root, nil
+}
+
+// yamlMappingEntry reports whether an unquoted sequence item is a "key: value"
+// entry rather than a scalar.
+func yamlMappingEntry(value string) bool {
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") || strings.HasPrefix(value, "[") {
+		return false
+	}
+	return strings.Contains(value, ": ") || strings.HasSuffix(value, ":")
+}
+
+// stripYAMLComment removes a trailing comment that is not inside quotes.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else if ch == '\\' && quote == '"' {
+				i++
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// yamlScalar converts a value into a quoted string, an unquoted yamlPlain
+// scalar, or a flow sequence.
+func yamlScalar(value string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'"):
+		return yamlScalarString(value)
+	case strings.HasPrefix(value, "["):
+		return yamlFlowSequence(value)
+	case strings.HasPrefix(value, "{"):
+		return nil, fmt.Errorf("flow mappings are not supported")
+	case strings.HasPrefix(value, "&") || strings.HasPrefix(value, "*"):
+		return nil, fmt.Errorf("anchors and aliases are not supported")
+	case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+		return nil, fmt.Errorf("multi-line strings are not supported")
+	}
+	return yamlPlain(value), nil
+}
+
+// yamlFlowSequence parses a single-line flow sequence of scalars, such as
+// `[a, "b", 'c']`.
+func yamlFlowSequence(value string) ([]interface{}, error) {
+	if !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("unterminated flow sequence %s", value)
+	}
+	body := strings.TrimSpace(value[1 : len(value)-1])
+	items := []interface{}{}
+	if body == "" {
+		return items, nil
+	}
+
+	var quote byte
+	start := 0
+	for i := 0; i <= len(body); i++ {
+		if i < len(body) {
+			switch ch := body[i]; {
+			case quote != 0:
+				if ch == quote {
+					quote = 0
+				} else if ch == '\\' && quote == '"' {
+					i++
+				}
+				continue
+			case ch == '"' || ch == '\'':
+				quote = ch
+				continue
+			case ch == '[' || ch == ']' || ch == '{' || ch == '}':
+				return nil, fmt.Errorf("nested flow collections are not supported")
+			case ch != ',':
+				continue
+			}
+		}
+		item := strings.TrimSpace(body[start:i])
+		start = i + 1
+		if item == "" {
+			if i == len(body) {
+				break // a trailing comma
+			}
+			return nil, fmt.Errorf("empty item in flow sequence %s", value)
+		}
+		scalar, err := yamlScalar(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, scalar)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated string in flow sequence %s", value)
+	}
+	return items, nil
+}
+
+// yamlScalarString unquotes a single- or double-quoted string. Unquoted
+// strings are returned as is.
+func yamlScalarString(value string) (string, error) {
+	switch {
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		var s string
+		if err := json.Unmarshal([]byte(value), &s); err != nil {
+			return "", fmt.Errorf("invalid quoted string %s", value)
+		}
+		return s, nil
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), nil
+	case strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'"):
+		return "", fmt.Errorf("unterminated string %s", value)
+	}
+	return value, nil
+}
+
+// resolveYAML converts parsed YAML into values that encode to JSON for the
+// target type. Unquoted scalars of string fields stay strings; elsewhere they
+// become null, booleans, numbers or strings. Keys without a matching field
+// are kept, so the JSON decoder can report them.
+func resolveYAML(node interface{}, target reflect.Type) interface{} {
+	for target != nil && target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+
+	switch node := node.(type) {
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			resolved[key] = resolveYAML(value, yamlFieldType(target, key))
+		}
+		return resolved
+	case []interface{}:
+		var elem reflect.Type
+		if target != nil && (target.Kind() == reflect.Slice || target.Kind() == reflect.Array) {
+			elem = target.Elem()
+		}
+		resolved := make([]interface{}, len(node))
+		for i, value := range node {
+			resolved[i] = resolveYAML(value, elem)
+		}
+		return resolved
+	case yamlPlain:
+		if target != nil && target.Kind() == reflect.String && !yamlNull(string(node)) {
+			return string(node)
+		}
+		return yamlPlainValue(string(node))
+	}
+	return node
+}
+
+// yamlFieldType returns the type of the value stored under key in a struct or
+// map of the given type, or nil if it is unknown.
+func yamlFieldType(target reflect.Type, key string) reflect.Type {
+	if target == nil {
+		return nil
+	}
+	switch target.Kind() {
+	case reflect.Map:
+		return target.Elem()
+	case reflect.Struct:
+		for i := 0; i < target.NumField(); i++ {
+			field := target.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" {
+				name = field.Name
+			}
+			if strings.EqualFold(name, key) {
+				return field.Type
+			}
+		}
+	}
+	return nil
+}
+
+// yamlNull reports whether an unquoted scalar is null.
+func yamlNull(value string) bool {
+	switch value {
+	case "null", "Null", "NULL", "~":
+		return true
+	}
+	return false
+}
+
+// yamlPlainValue converts an unquoted scalar into nil, a bool, a number or a
+// string.
+func yamlPlainValue(value string) interface{} {
+	if yamlNull(value) {
+		return nil
+	}
+	switch value {
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}